@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/node"
+)
+
+// pushConfig sends the config at configPath to the node.ConfigEndpoint of the node at nodeURL
+func pushConfig(ctx context.Context, nodeURL, token, configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimSuffix(nodeURL, "/")+node.ConfigEndpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(node.ConfigPushTokenHeader, token)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach the node: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return fmt.Errorf("the node at %s doesn't accept configs, start it with 'wunderctl node start --accept-config-push'", nodeURL)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("config rejected: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// pushConfigToNode pushes the config and logs the outcome, a failed push keeps the
+// node serving its previous config and must not stop the dev loop
+func pushConfigToNode(ctx context.Context, nodeURL, configPath string) {
+	if err := pushConfig(ctx, nodeURL, os.Getenv(configPushTokenEnv), configPath); err != nil {
+		if ctx.Err() == nil {
+			log.Error("could not push config to node", zap.String("nodeUrl", nodeURL), zap.Error(err))
+		}
+		return
+	}
+	log.Info("pushed config to node", zap.String("nodeUrl", nodeURL))
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/bundler"
+	"github.com/wundergraph/wundergraph/pkg/files"
+)
+
+var bundleAnalyzeLimit int
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Subcommand to work with the WunderGraph bundles",
+}
+
+var bundleAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Summarizes the largest inputs of each bundle",
+	Long: `Summarizes the largest inputs of each bundle from the esbuild metafiles.
+Metafiles are only written when running 'wunderctl up --metafile'.`,
+	Example: `wunderctl bundle analyze --limit 10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+
+		metafiles, err := bundler.FindMetafiles(wunderGraphDir)
+		if err != nil {
+			return err
+		}
+		if len(metafiles) == 0 {
+			return fmt.Errorf("no metafiles found in %s, run 'wunderctl up --metafile' first", wunderGraphDir)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, path := range metafiles {
+			metafile, err := bundler.ReadMetafile(path)
+			if err != nil {
+				return fmt.Errorf("could not read metafile %s: %w", path, err)
+			}
+			fmt.Fprintf(w, "%s\n", bundler.BundlerNameFromMetafile(path))
+			fmt.Fprintf(w, "  IN BUNDLE\tSOURCE\tINPUT\n")
+			for _, input := range metafile.LargestInputs(bundleAnalyzeLimit) {
+				fmt.Fprintf(w, "  %s\t%s\t%s\n",
+					units.HumanSize(float64(input.BytesInOutput)),
+					units.HumanSize(float64(input.Bytes)),
+					input.Path,
+				)
+			}
+			fmt.Fprintln(w)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	bundleAnalyzeCmd.Flags().IntVar(&bundleAnalyzeLimit, "limit", 20, "maximum number of inputs to print per bundle, 0 prints all")
+	bundleCmd.AddCommand(bundleAnalyzeCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/introspectioncache"
+)
+
+var cacheVerifyFix bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Subcommand to work with the local caches",
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Checks the integrity of the introspection cache",
+	Long: `Checks that every entry of the introspection cache can be used by the config runner, e.g. that
+no entry was truncated by an interrupted write or a full disk. The config runner drops corrupted
+entries and introspects their sources again, --fix removes them right away.`,
+	Example: `wunderctl cache verify --fix`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+		dir := introspectioncache.Dir(wunderGraphDir)
+		entries, corrupted, err := introspectioncache.Verify(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range corrupted {
+			fmt.Printf("corrupted: %s: %v\n", entry.Key, entry.Err)
+			if cacheVerifyFix {
+				if err := introspectioncache.Remove(dir, entry.Key); err != nil {
+					return err
+				}
+			}
+		}
+		fmt.Printf("checked %d introspection cache entries, %d corrupted\n", entries, len(corrupted))
+		if len(corrupted) == 0 {
+			return nil
+		}
+		if cacheVerifyFix {
+			fmt.Printf("removed %d corrupted entries, their sources are introspected on the next build\n", len(corrupted))
+			return nil
+		}
+		return fmt.Errorf("introspection cache has %d corrupted entries, run 'wunderctl cache verify --fix' to remove them", len(corrupted))
+	},
+}
+
+func init() {
+	cacheVerifyCmd.Flags().BoolVar(&cacheVerifyFix, "fix", false, "removes corrupted entries")
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/clientverify"
+	"github.com/wundergraph/wundergraph/pkg/files"
+)
+
+var (
+	clientVerifyNodeURL string
+	clientVerifyClient  string
+)
+
+var clientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Subcommand to work with the generated clients",
+}
+
+var clientVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Checks that the generated TypeScript client matches the running node",
+	Long: `Compares the schema the TypeScript client was generated with to the one served by the
+node, using its introspection, and the operations of the client to the ones of the config the
+node loads. Mismatches mean the client is stale or was generated incorrectly, regenerate it
+with 'wunderctl generate' or 'wunderctl up'.`,
+	Example: `wunderctl client verify --node-url http://localhost:9991`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+		clientPath := clientVerifyClient
+		if !filepath.IsAbs(clientPath) {
+			clientPath = filepath.Join(wunderGraphDir, clientPath)
+		}
+		clientOperations, err := clientverify.LoadClientOperations(clientPath)
+		if err != nil {
+			return err
+		}
+		clientSchema, err := clientverify.LoadSchema(filepath.Join(wunderGraphDir, "generated", "wundergraph.schema.graphql"))
+		if err != nil {
+			return err
+		}
+		graphConfig, err := helpers.LoadConfig(filepath.Join(wunderGraphDir, "generated", configJsonFilename))
+		if err != nil {
+			return err
+		}
+		client := &http.Client{Timeout: 10 * time.Second}
+		nodeSchema, err := clientverify.FetchSchema(cmd.Context(), client, clientVerifyNodeURL)
+		if err != nil {
+			return fmt.Errorf("could not introspect the node at %s, is it running? %w", clientVerifyNodeURL, err)
+		}
+
+		mismatches := append(clientverify.DiffSchemas(clientSchema, nodeSchema), clientverify.DiffOperations(clientOperations, graphConfig)...)
+		for _, mismatch := range mismatches {
+			fmt.Println(mismatch)
+		}
+		if len(mismatches) != 0 {
+			return fmt.Errorf("generated client doesn't match the node, %d mismatches", len(mismatches))
+		}
+		fmt.Printf("%s matches the node, %d operations\n", clientVerifyClient, len(clientOperations))
+		return nil
+	},
+}
+
+func init() {
+	clientVerifyCmd.Flags().StringVar(&clientVerifyNodeURL, "node-url", "http://localhost:9991", "URL of the running node")
+	clientVerifyCmd.Flags().StringVar(&clientVerifyClient, "client", filepath.Join("generated", "client.ts"), "path of the generated client, relative to the WunderGraph dir")
+	clientCmd.AddCommand(clientVerifyCmd)
+	rootCmd.AddCommand(clientCmd)
+}
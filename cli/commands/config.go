@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/configdiff"
+	"github.com/wundergraph/wundergraph/pkg/configschema"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Subcommand to work with the generated WunderGraph config",
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints the JSON Schema of the generated config",
+	Long: `Prints the JSON Schema of the generated wundergraph.config.json, the file loaded by the node.
+Editors use it to validate and autocomplete the config, reference it via "$schema" or the
+JSON schema settings of your editor.`,
+	Example: `wunderctl config schema > wundergraph.config.schema.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := configschema.WunderGraphConfiguration().JSON()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	},
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff old.json new.json",
+	Short: "Prints what changed between two generated configs",
+	Long: `Compares two generated wundergraph.config.json files by their meaning instead of their JSON.
+Lists added (+), removed (-) and changed (~) datasources, operations, routes, authentication
+and other api settings. Changed entries name the fields that differ.`,
+	Example: `wunderctl config diff old/wundergraph.config.json .wundergraph/generated/wundergraph.config.json`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldConfig, err := helpers.LoadConfig(args[0])
+		if err != nil {
+			return err
+		}
+		newConfig, err := helpers.LoadConfig(args[1])
+		if err != nil {
+			return err
+		}
+		changes := configdiff.Diff(oldConfig, newConfig)
+		if len(changes) == 0 {
+			_, err = fmt.Fprintln(os.Stdout, "no changes")
+			return err
+		}
+		return configdiff.Write(os.Stdout, changes)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configDiffCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/wundergraph/wundergraph/cli/helpers"
 	"github.com/wundergraph/wundergraph/pkg/bundler"
+	"github.com/wundergraph/wundergraph/pkg/depgraph"
 	"github.com/wundergraph/wundergraph/pkg/files"
 	"github.com/wundergraph/wundergraph/pkg/operations"
 	"github.com/wundergraph/wundergraph/pkg/scriptrunner"
@@ -19,8 +20,10 @@ import (
 )
 
 var (
-	generateAndPublish bool
-	offline            bool
+	generateAndPublish    bool
+	offline               bool
+	generateMaxBundleSize string
+	generateRegistry      string
 )
 
 // generateCmd represents the generate command
@@ -38,6 +41,12 @@ var generateCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		var maxBundleSize int64
+		if generateMaxBundleSize != "" {
+			if maxBundleSize, err = bundler.ParseByteSize(generateMaxBundleSize); err != nil {
+				return fmt.Errorf("invalid --max-bundle-size: %w", err)
+			}
+		}
 		// only validate if the file exists
 		_, err = files.CodeFilePath(wunderGraphDir, configEntryPointFilename)
 		if err != nil {
@@ -51,13 +60,23 @@ var generateCmd = &cobra.Command{
 
 		configOutFile := filepath.Join("generated", "bundle", "config.js")
 
+		var dataSourceRegistryEnv []string
+		if generateRegistry != "" {
+			dataSourceRegistryPath, err := dataSourceRegistry(generateRegistry)
+			if err != nil {
+				return err
+			}
+			dataSourceRegistryEnv = append(dataSourceRegistryEnv, fmt.Sprintf("%s=%s", dataSourceRegistryEnvKey, dataSourceRegistryPath))
+		}
+
 		configRunner := scriptrunner.NewScriptRunner(&scriptrunner.Config{
-			Name:          "config-runner",
-			Executable:    "node",
-			ScriptArgs:    []string{configOutFile},
-			AbsWorkingDir: wunderGraphDir,
-			Logger:        log,
-			ScriptEnv: append(
+			Name:             "config-runner",
+			Executable:       "node",
+			ScriptArgs:       []string{configOutFile},
+			AbsWorkingDir:    wunderGraphDir,
+			Logger:           log,
+			StructuredOutput: !rootFlags.PrettyLogs,
+			ScriptEnv: append(append(
 				helpers.CliEnv(rootFlags),
 				// Run scripts in prod mode
 				"NODE_ENV=production",
@@ -66,7 +85,7 @@ var generateCmd = &cobra.Command{
 				fmt.Sprintf("WG_ENABLE_INTROSPECTION_OFFLINE=%t", offline),
 				fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir),
 				fmt.Sprintf("%s=%s", wunderctlBinaryPathEnvKey, wunderctlBinaryPath()),
-			),
+			), dataSourceRegistryEnv...),
 		})
 		defer func() {
 			log.Debug("Stopping config-runner")
@@ -79,7 +98,7 @@ var generateCmd = &cobra.Command{
 			}
 		}()
 
-		var onAfterBuild func() error
+		var onAfterBuild func(context.Context) error
 
 		if codeServerFilePath != "" {
 			serverOutFile := filepath.Join(wunderGraphDir, "generated", "bundle", "server.js")
@@ -101,7 +120,10 @@ var generateCmd = &cobra.Command{
 					AbsWorkingDir: wunderGraphDir,
 					OutDir:        generatedBundleOutDir,
 					Logger:        log,
-					OnAfterBundle: func() error {
+					DisableCache:  disableCache,
+					MaxSize:       maxBundleSize,
+					FailOnMaxSize: true,
+					OnAfterBundle: func(context.Context) error {
 						log.Debug("Webhooks bundled!", zap.String("bundlerName", "webhooks-bundler"))
 						return nil
 					},
@@ -115,11 +137,20 @@ var generateCmd = &cobra.Command{
 				EntryPoints:   []string{serverEntryPointFilename},
 				OutFile:       serverOutFile,
 				Logger:        log,
+				DisableCache:  disableCache,
+				MaxSize:       maxBundleSize,
+				FailOnMaxSize: true,
 			})
 
-			onAfterBuild = func() error {
+			onAfterBuild = func(context.Context) error {
 
 				if files.DirectoryExists(operationsDir) {
+					if err := operations.Validate(wunderGraphDir, nil, nil); err != nil {
+						return err
+					}
+					if err := depgraph.CheckImportCycles(wunderGraphDir, nil); err != nil {
+						return err
+					}
 					operationsPaths, err := operations.GetPaths(wunderGraphDir)
 					if err != nil {
 						return err
@@ -138,6 +169,9 @@ var generateCmd = &cobra.Command{
 						AbsWorkingDir: wunderGraphDir,
 						OutDir:        generatedBundleOutDir,
 						Logger:        log,
+						DisableCache:  disableCache,
+						MaxSize:       maxBundleSize,
+						FailOnMaxSize: true,
 					})
 					err = operationsBundler.Bundle()
 					if err != nil {
@@ -175,7 +209,14 @@ var generateCmd = &cobra.Command{
 
 		} else {
 			log.Info("hooks EntryPoint not found, skipping", zap.String("file", serverEntryPointFilename))
-			onAfterBuild = func() error {
+			onAfterBuild = func(context.Context) error {
+				if err := operations.Validate(wunderGraphDir, nil, nil); err != nil {
+					return err
+				}
+				if err := depgraph.CheckImportCycles(wunderGraphDir, nil); err != nil {
+					return err
+				}
+
 				<-configRunner.Run(ctx)
 
 				if !configRunner.Successful() {
@@ -197,6 +238,9 @@ var generateCmd = &cobra.Command{
 			EntryPoints:   []string{configEntryPointFilename},
 			OutFile:       configOutFile,
 			Logger:        log,
+			DisableCache:  disableCache,
+			MaxSize:       maxBundleSize,
+			FailOnMaxSize: true,
 			IgnorePaths: []string{
 				"generated",
 				"node_modules",
@@ -212,6 +256,8 @@ var generateCmd = &cobra.Command{
 
 func init() {
 	generateCmd.Flags().BoolVarP(&generateAndPublish, "publish", "p", false, "publish the generated API immediately")
+	generateCmd.Flags().StringVar(&generateMaxBundleSize, "max-bundle-size", "", "fails if a bundle is larger than this size, e.g. 2MB, and lists its largest inputs")
 	generateCmd.Flags().BoolVar(&offline, "offline", false, "disables loading resources from the network")
+	generateCmd.Flags().StringVar(&generateRegistry, "datasource-registry", "", "adds the data sources of a JSON file, e.g. generated by another tool, to the ones of the config, see wunderctl up --datasource-registry")
 	rootCmd.AddCommand(generateCmd)
 }
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/depgraph"
+	"github.com/wundergraph/wundergraph/pkg/files"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Prints the dependency graph of operations, fragments, datasources and webhooks",
+	Long: `Prints how operations, fragments, datasources and webhooks relate to each other.
+Requires a generated config, run 'wunderctl generate' or 'wunderctl up' first.`,
+	Example: `wunderctl graph --format dot | dot -Tsvg > graph.svg`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+
+		graphConfig, err := helpers.LoadConfig(filepath.Join(wunderGraphDir, "generated", configJsonFilename))
+		if err != nil {
+			return err
+		}
+
+		graph, err := depgraph.Build(wunderGraphDir, graphConfig)
+		if err != nil {
+			return err
+		}
+
+		switch graphFormat {
+		case "dot":
+			return graph.WriteDOT(os.Stdout)
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			if rootFlags.Pretty {
+				encoder.SetIndent("", "  ")
+			}
+			return encoder.Encode(graph)
+		default:
+			return fmt.Errorf("unknown format %q, use dot or json", graphFormat)
+		}
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "output format, either dot or json")
+	rootCmd.AddCommand(graphCmd)
+}
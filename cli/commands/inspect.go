@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/node"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Subcommand to inspect the generated config without starting the node",
+	Long: `Loads, validates and plans the generated config like the node, but doesn't bind any listener.
+Requires a generated config, run 'wunderctl generate' or 'wunderctl up' first.`,
+}
+
+var inspectOperationsCmd = &cobra.Command{
+	Use:     "operations",
+	Short:   "Lists the operations of the config",
+	Example: `wunderctl inspect operations`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLoadedNode(cmd, func(loaded *node.LoadedNode, w *tabwriter.Writer) {
+			fmt.Fprintf(w, "NAME\tTYPE\tPATH\tINTERNAL\n")
+			for _, operation := range loaded.Operations() {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", operation.Name, operation.OperationType, operation.Path, operation.Internal)
+			}
+		})
+	},
+}
+
+var inspectDataSourcesCmd = &cobra.Command{
+	Use:     "datasources",
+	Short:   "Lists the datasources of the config with their root fields",
+	Example: `wunderctl inspect datasources`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLoadedNode(cmd, func(loaded *node.LoadedNode, w *tabwriter.Writer) {
+			fmt.Fprintf(w, "ID\tKIND\tROOT FIELDS\n")
+			for _, dataSource := range loaded.DataSources() {
+				var rootFields []string
+				for _, rootNode := range dataSource.RootNodes {
+					for _, fieldName := range rootNode.FieldNames {
+						rootFields = append(rootFields, rootNode.TypeName+"."+fieldName)
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", dataSource.Id, dataSource.Kind, strings.Join(rootFields, ", "))
+			}
+		})
+	},
+}
+
+var inspectRoutesCmd = &cobra.Command{
+	Use:     "routes",
+	Short:   "Lists the HTTP routes the node would serve",
+	Example: `wunderctl inspect routes`,
+	RunE:    runRoutes,
+}
+
+// runRoutes prints the routes of the generated config with their purpose, routes that never
+// receive requests because a route registered before matches them are marked as shadowed
+func runRoutes(cmd *cobra.Command, args []string) error {
+	return withLoadedNode(cmd, func(loaded *node.LoadedNode, w *tabwriter.Writer) {
+		fmt.Fprintf(w, "METHODS\tPATH\tPURPOSE\n")
+		for _, route := range loaded.Routes {
+			methods := "*"
+			if len(route.Methods) != 0 {
+				methods = strings.Join(route.Methods, ",")
+			}
+			purpose := route.Purpose
+			if route.Shadowed {
+				purpose += " (shadowed)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", methods, route.Path, strings.TrimSpace(purpose))
+		}
+	})
+}
+
+// withLoadedNode loads the generated config of the WunderGraph directory and prints
+// the output of fn as table
+func withLoadedNode(cmd *cobra.Command, fn func(loaded *node.LoadedNode, w *tabwriter.Writer)) error {
+	wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+	if err != nil {
+		return err
+	}
+
+	loaded, err := node.LoadOnly(cmd.Context(), filepath.Join(wunderGraphDir, "generated", configJsonFilename), node.WithDevMode())
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	defer loaded.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fn(loaded, w)
+	return w.Flush()
+}
+
+func init() {
+	inspectCmd.AddCommand(inspectOperationsCmd)
+	inspectCmd.AddCommand(inspectDataSourcesCmd)
+	inspectCmd.AddCommand(inspectRoutesCmd)
+	rootCmd.AddCommand(inspectCmd)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/wundergraph/wundergraph/pkg/files"
 	"github.com/wundergraph/wundergraph/pkg/loadoperations"
+	"github.com/wundergraph/wundergraph/pkg/operations"
 )
 
 const LoadOperationsCmdName = "loadoperations"
@@ -33,6 +34,8 @@ var loadoperationsCmd = &cobra.Command{
 		}
 
 		loader := loadoperations.NewLoader(args[0], args[1], args[2])
+		loader.Exclude(operations.ExcludedFromEnv()...)
+		loader.UseGraphQLExtensions(operations.GraphQLExtensionsFromEnv()...)
 		out, err := loader.Load(rootFlags.Pretty)
 		if err != nil {
 			return err
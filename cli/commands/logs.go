@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+const logsFollowPollInterval = 250 * time.Millisecond
+
+var (
+	logsRequestID string
+	logsFollow    bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Shows the logs of a single request",
+	Long: `Shows all lines of the node, hooks server and config runner logs belonging to a request.
+The logs are written by 'wunderctl up' to generated/logs/` + logging.DevLogFilename + `.`,
+	Example: `wunderctl logs --request 67b77eab-d1a5-4cd8-b908-8443f24502b6 --follow`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+
+		logFilePath := logging.DevLogFilePath(wunderGraphDir)
+		f, err := os.Open(logFilePath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("log file %s not found, start 'wunderctl up' first", logFilePath)
+			}
+			return err
+		}
+		defer f.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return logging.CopyRequestLines(ctx, f, os.Stdout, logsRequestID, logsFollow, logsFollowPollInterval)
+	},
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsRequestID, "request", "", "ID of the request to show the logs for")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep watching the log file for new lines")
+	_ = logsCmd.MarkFlagRequired("request")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -21,6 +21,12 @@ import (
 	"github.com/wundergraph/wundergraph/pkg/wgpb"
 )
 
+// configPushTokenEnv holds the token protecting node.ConfigEndpoint, it's read by
+// 'wunderctl node start --accept-config-push' and 'wunderctl up --attach'
+const configPushTokenEnv = "WG_CONFIG_PUSH_TOKEN"
+
+var acceptConfigPush bool
+
 var nodeCmd = &cobra.Command{
 	Use:   "node",
 	Short: "Subcommand to work with WunderGraph node",
@@ -69,6 +75,7 @@ func init() {
 	rootCmd.AddCommand(nodeCmd)
 
 	nodeStartCmd.Flags().IntVar(&shutdownAfterIdle, "shutdown-after-idle", 0, "shuts down the server after given seconds in idle when no requests have been served")
+	nodeStartCmd.Flags().BoolVar(&acceptConfigPush, "accept-config-push", false, "accepts configs pushed by 'wunderctl up --attach', protected by the token in "+configPushTokenEnv)
 }
 
 func NewWunderGraphNode(ctx context.Context) (*node.Node, error) {
@@ -150,6 +157,14 @@ func StartWunderGraphNode(n *node.Node, opts ...Option) error {
 		}))
 	}
 
+	if acceptConfigPush {
+		token := os.Getenv(configPushTokenEnv)
+		if token == "" {
+			return fmt.Errorf("--accept-config-push requires a token in %s", configPushTokenEnv)
+		}
+		nodeOpts = append(nodeOpts, node.WithConfigPush(token))
+	}
+
 	if options.hooksServerHealthCheck {
 		nodeOpts = append(nodeOpts, node.WithHooksServerHealthCheck(time.Duration(healthCheckTimeout)*time.Second))
 	}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/processes"
+)
+
+var psCmd = &cobra.Command{
+	Use:   "ps",
+	Short: "Lists the running processes started by wunderctl up",
+	Long: `Lists the processes started by 'wunderctl up' of all WunderGraph directories: the
+wunderctl processes running the node, the config runners and the hooks server, with their PIDs and ports.
+Processes left behind by a crash can be stopped with 'wunderctl kill'.`,
+	Example: `wunderctl ps`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry, err := newProcessRegistry()
+		if err != nil {
+			return err
+		}
+		running, err := registry.List()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "PID\tPARENT\tNAME\tPORTS\tSTARTED\tDIRECTORY\n")
+		for _, p := range running {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", p.PID, p.ParentPID, p.Name, formatPorts(p.Ports), p.StartedAt.Format(time.RFC3339), p.WunderGraphDir)
+		}
+		return w.Flush()
+	},
+}
+
+var killCmd = &cobra.Command{
+	Use:   "kill [pid...]",
+	Short: "Stops processes started by wunderctl up",
+	Long: `Stops the processes listed by 'wunderctl ps', or only those with the given PIDs.
+Runners are stopped before the wunderctl processes that started them.`,
+	Example: `wunderctl kill
+wunderctl kill 4711`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pids := map[int]bool{}
+		for _, arg := range args {
+			pid, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid pid %q", arg)
+			}
+			pids[pid] = true
+		}
+		registry, err := newProcessRegistry()
+		if err != nil {
+			return err
+		}
+		running, err := registry.List()
+		if err != nil {
+			return err
+		}
+		var targets []processes.Process
+		for _, p := range running {
+			if p.PID == os.Getpid() || len(pids) != 0 && !pids[p.PID] {
+				continue
+			}
+			delete(pids, p.PID)
+			targets = append(targets, p)
+		}
+		for pid := range pids {
+			return fmt.Errorf("process %d isn't tracked, see 'wunderctl ps'", pid)
+		}
+		// the children first, otherwise wunderctl might restart them while shutting down
+		var failed int
+		for _, children := range []bool{true, false} {
+			for _, p := range targets {
+				if (p.Name != upProcessName) != children {
+					continue
+				}
+				if err := registry.Stop(p); err != nil {
+					log.Error("could not stop process", zap.Int("pid", p.PID), zap.String("name", p.Name), zap.Error(err))
+					failed++
+					continue
+				}
+				fmt.Printf("stopped %s (pid %d)\n", p.Name, p.PID)
+			}
+		}
+		if failed != 0 {
+			return fmt.Errorf("could not stop %d of %d processes", failed, len(targets))
+		}
+		return nil
+	},
+}
+
+const upProcessName = "wunderctl up"
+
+func newProcessRegistry() (*processes.Registry, error) {
+	dir, err := helpers.ProcessesDir()
+	if err != nil {
+		return nil, err
+	}
+	return processes.NewRegistry(dir), nil
+}
+
+// trackProcess registers the wunderctl process with the port of the node in the config at
+// configJsonPath and returns a function that unregisters it
+func trackProcess(registry *processes.Registry, name, wunderGraphDir, configJsonPath string) func() {
+	p := processes.Process{
+		PID:            os.Getpid(),
+		ParentPID:      os.Getppid(),
+		Name:           name,
+		WunderGraphDir: wunderGraphDir,
+		StartedAt:      time.Now(),
+	}
+	if port, err := helpers.NodePortFromConfig(configJsonPath); err == nil {
+		p.Ports = append(p.Ports, port)
+	}
+	if err := registry.Register(p); err != nil {
+		log.Debug("could not track process", zap.Error(err))
+	}
+	return func() {
+		_ = registry.Unregister(p.PID)
+	}
+}
+
+func formatPorts(ports []int) string {
+	if len(ports) == 0 {
+		return "-"
+	}
+	formatted := make([]string, len(ports))
+	for i, port := range ports {
+		formatted[i] = strconv.Itoa(port)
+	}
+	return strings.Join(formatted, ",")
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+	rootCmd.AddCommand(killCmd)
+}
@@ -2,13 +2,14 @@ package commands
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -27,6 +28,12 @@ const (
 	serverEntryPointFilename = "wundergraph.server.ts"
 
 	wunderctlBinaryPathEnvKey = "WUNDERCTL_BINARY_PATH"
+	// clientOutDirEnvKey makes the config runner write the TypeScript client to an additional directory
+	clientOutDirEnvKey = "WG_CLIENT_OUT_DIR"
+	// dataSourceRegistryEnvKey holds the path of a JSON file with data sources the config runner adds to the config
+	dataSourceRegistryEnvKey = "WG_DATASOURCE_REGISTRY"
+	// configDebugEnvKey enables the debug logs of the SDK in the config runners
+	configDebugEnvKey = "WG_CONFIG_DEBUG"
 
 	defaultNodeGracefulTimeoutSeconds = 10
 )
@@ -86,7 +93,7 @@ var rootCmd = &cobra.Command{
 			New(rootFlags.PrettyLogs, rootFlags.DebugMode, logLevel).
 			With(zap.String("component", "@wundergraph/wunderctl"))
 
-		err = godotenv.Load(DotEnvFile)
+		err = helpers.LoadEnvFiles(DotEnvFile)
 		if err != nil {
 			if _, ok := err.(*fs.PathError); ok {
 				log.Debug("starting without env file")
@@ -184,13 +191,23 @@ func Execute(buildInfo node.BuildInfo, githubAuthDemo node.GitHubAuthDemo) {
 
 	defer func() {
 		// In case of a panic or error we want to flush the telemetry data
-		if r := recover(); r != nil || err != nil {
-			FlushTelemetry()
+		r := recover()
+		if r != nil {
+			// os.Exit skips printing the panic, its cause must not be lost
+			if log != nil {
+				log.Error("recovered from panic", zap.Any("panic", r), zap.Stack("stack"))
+			} else {
+				fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", r, debug.Stack())
+			}
+		}
+		if log != nil {
+			_ = log.Sync()
+		}
+		FlushTelemetry()
+		if r != nil || err != nil {
 			os.Exit(1)
-		} else {
-			FlushTelemetry()
-			os.Exit(0)
 		}
+		os.Exit(0)
 	}()
 
 	BuildInfo = buildInfo
@@ -227,6 +244,19 @@ func wunderctlBinaryPath() string {
 	return path
 }
 
+// dataSourceRegistry returns the absolute path of the data source registry at path, it must exist
+// since the config runner fails without it
+func dataSourceRegistry(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if !files.FileExists(absPath) {
+		return "", fmt.Errorf("data source registry %s not found", path)
+	}
+	return absPath, nil
+}
+
 func init() {
 	_, isTelemetryDisabled := os.LookupEnv("WG_TELEMETRY_DISABLED")
 	_, isTelemetryDebugEnabled := os.LookupEnv("WG_TELEMETRY_DEBUG")
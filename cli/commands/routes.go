@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Lists the HTTP routes the node serves with their purpose",
+	Long: `Lists every route the node serves with the generated config: operations, webhooks, the GraphQL
+endpoint and playground, authentication, file uploads, the internal API of the hooks and the health
+checks. Routes shadowed by a route registered before them are marked, they never receive requests.
+Requires a generated config, run 'wunderctl generate' or 'wunderctl up' first.`,
+	Example: `wunderctl routes`,
+	Args:    cobra.NoArgs,
+	RunE:    runRoutes,
+}
+
+func init() {
+	rootCmd.AddCommand(routesCmd)
+}
@@ -72,6 +72,7 @@ func startWunderGraphServer(ctx context.Context) error {
 		ServerScriptFile:  serverScriptFile,
 		Production:        true,
 		Env:               helpers.CliEnv(rootFlags),
+		StructuredOutput:  !rootFlags.PrettyLogs,
 	}
 
 	hookServerRunner := helpers.NewServerRunner(log, srvCfg)
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/node"
+)
+
+var setVarNodeURL string
+
+var setVarCmd = &cobra.Command{
+	Use:   "set-var KEY=VALUE...",
+	Short: "Changes configuration variables of the running dev node",
+	Long: `Changes the values of configuration variables read from environment variables in the
+config served by 'wunderctl up', without regenerating the config. The node reloads its config
+with the new values, like after a rebuild this restarts the server and closes open connections
+and subscriptions. Changes are kept in memory only and are lost when the node restarts. The node
+only accepts changes from the local machine, unless it serves the dev UI with --dev-ui-allow-remote.`,
+	Example: `wunderctl set-var API_URL=http://localhost:4000`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values := make(map[string]string, len(args))
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok || name == "" {
+				return fmt.Errorf("invalid variable %q, expected KEY=VALUE", arg)
+			}
+			values[name] = value
+		}
+		body, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(cmd.Context(), http.MethodPatch, strings.TrimSuffix(setVarNodeURL, "/")+node.ConfigVariablesEndpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("could not reach the node, is 'wunderctl up' running? %w", err)
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusAccepted:
+		case http.StatusNotFound, http.StatusMethodNotAllowed:
+			return fmt.Errorf("the node at %s doesn't accept variable changes, they are only available with 'wunderctl up'", setVarNodeURL)
+		default:
+			data, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("could not set variables: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+		}
+		for _, arg := range args {
+			name, _, _ := strings.Cut(arg, "=")
+			fmt.Printf("set %s\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	setVarCmd.Flags().StringVar(&setVarNodeURL, "node-url", "http://localhost:9991", "URL of the node started by 'wunderctl up'")
+	rootCmd.AddCommand(setVarCmd)
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/introspectioncache"
+)
+
+const defaultSnapshotFilename = "introspection.snapshot.json"
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Subcommand to work with snapshots of the introspection cache",
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save [file]",
+	Short: "Saves the introspection cache to a snapshot file",
+	Long: `Saves the introspected schemas of all upstreams from the introspection cache to a single,
+portable file, ` + defaultSnapshotFilename + ` by default. Start 'wunderctl up --from-snapshot <file>'
+to develop against the snapshot without reaching any upstream.`,
+	Example: `wunderctl snapshot save upstreams.snapshot.json`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+		snapshotPath := defaultSnapshotFilename
+		if len(args) == 1 {
+			snapshotPath = args[0]
+		}
+		snapshot, err := introspectioncache.ReadSnapshot(introspectioncache.Dir(wunderGraphDir))
+		if err != nil {
+			return err
+		}
+		if len(snapshot.Entries) == 0 {
+			return fmt.Errorf("introspection cache is empty, run 'wunderctl up' or 'wunderctl generate' first")
+		}
+		if err := snapshot.WriteFile(snapshotPath); err != nil {
+			return err
+		}
+		fmt.Printf("saved %d introspection cache entries to %s\n", len(snapshot.Entries), snapshotPath)
+		return nil
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/pkg/bundler"
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/node"
+	"github.com/wundergraph/wundergraph/pkg/operationtest"
+)
+
+var testParallel int
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Runs the operation tests against the generated config",
+	Long: `Runs the test files in ` + operationtest.DirectoryName + `/**/*` + operationtest.FileSuffix + ` against a node loaded in-process from the
+generated config, without binding any listener. Every test file default exports a list of cases,
+each calling a query or mutation with its own input and comparing the response with the expected
+one. Cases run in parallel. Requires a generated config, run 'wunderctl generate' or 'wunderctl up'
+first. Operations using hooks or TypeScript operations require a running hooks server.`,
+	Example: `wunderctl test --parallel 8`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+		paths, err := operationtest.GetPaths(wunderGraphDir)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no tests found, add test files to %s", filepath.Join(wunderGraphDir, operationtest.DirectoryName))
+		}
+
+		testsBundler := bundler.NewBundler(bundler.Config{
+			Name:          "tests-bundler",
+			EntryPoints:   paths,
+			AbsWorkingDir: wunderGraphDir,
+			OutDir:        filepath.Join("generated", "bundle"),
+			Logger:        log,
+			DisableCache:  disableCache,
+		})
+		if err := testsBundler.Bundle(); err != nil {
+			return err
+		}
+		cases, err := operationtest.Load(cmd.Context(), wunderGraphDir, paths)
+		if err != nil {
+			return err
+		}
+
+		loaded, err := node.LoadOnly(cmd.Context(), filepath.Join(wunderGraphDir, "generated", configJsonFilename), node.WithDevMode())
+		if err != nil {
+			return fmt.Errorf("could not load config: %w", err)
+		}
+		defer loaded.Close()
+
+		failed := 0
+		for _, result := range operationtest.Run(cmd.Context(), cases, testParallel, loaded.Execute) {
+			status := "PASS"
+			if result.Err != nil {
+				status = "FAIL"
+				failed++
+			}
+			fmt.Printf("%s %s > %s (%s)\n", status, result.Case.File, result.Case.Name, result.Duration.Round(100*time.Microsecond))
+			if result.Err != nil {
+				fmt.Printf("    %s\n", result.Err)
+			}
+		}
+		fmt.Printf("\n%d passed, %d failed\n", len(cases)-failed, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d of %d tests failed", failed, len(cases))
+		}
+		return nil
+	},
+}
+
+func init() {
+	testCmd.Flags().IntVar(&testParallel, "parallel", runtime.NumCPU(), "number of cases to run at the same time")
+	rootCmd.AddCommand(testCmd)
+}
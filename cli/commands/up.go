@@ -1,31 +1,122 @@
 package commands
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
 	"github.com/wundergraph/wundergraph/pkg/bundler"
+	"github.com/wundergraph/wundergraph/pkg/depgraph"
+	"github.com/wundergraph/wundergraph/pkg/engineconfigloader"
 	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/introspectioncache"
+	"github.com/wundergraph/wundergraph/pkg/logging"
 	"github.com/wundergraph/wundergraph/pkg/node"
 	"github.com/wundergraph/wundergraph/pkg/operations"
+	"github.com/wundergraph/wundergraph/pkg/otlp"
+	"github.com/wundergraph/wundergraph/pkg/ratelimit"
+	"github.com/wundergraph/wundergraph/pkg/responsecache"
 	"github.com/wundergraph/wundergraph/pkg/scriptrunner"
 	"github.com/wundergraph/wundergraph/pkg/telemetry"
+	"github.com/wundergraph/wundergraph/pkg/tracing"
 	"github.com/wundergraph/wundergraph/pkg/watcher"
 	"github.com/wundergraph/wundergraph/pkg/webhooks"
 )
 
 const UpCmdName = "up"
 
-var upCmdPrettyLogging bool
+var (
+	upCmdPrettyLogging bool
+	upCmdWatchPaths    []string
+	upCmdAuthAs        string
+
+	upCmdPersistedQueries  bool
+	upCmdMetafile          bool
+	upCmdMounts            []string
+	upCmdCacheResponses    bool
+	upCmdCacheTTL          time.Duration
+	upCmdInjectLatency     []string
+	upCmdSchedules         []string
+	upCmdUpstreamHeaders   []string
+	upCmdWatchBackend      string
+	upCmdExplain           string
+	upCmdDumpEventsOnExit  bool
+	upCmdTraceFile         string
+	upCmdExcludeWebhooks   []string
+	upCmdExcludeOperations []string
+	upCmdStrictEnv         bool
+	upCmdRebuildOnSwitch   bool
+	upCmdServerTimeouts    node.ServerTimeouts
+	upCmdVerboseBundler    bool
+	upCmdNoPlayground      bool
+	upCmdPlaygroundPath    string
+	upCmdWarmPlans         bool
+	upCmdRateLimits        []string
+	upCmdFromSnapshot      string
+	upCmdAttach            string
+	upCmdSSE               bool
+	upCmdResponseHeaders   []string
+	upCmdPersistSessions   bool
+	upCmdForceConfig       bool
+	upCmdPreStart          []string
+	upCmdOverrides         string
+	upCmdTLSCert           string
+	upCmdTLSKey            string
+	upCmdReloadCooldown    time.Duration
+	upCmdTransforms        []string
+	upCmdClientOut         string
+	upCmdLogRequests       string
+	upCmdPackageManager    string
+	upCmdMaxBundleSize     string
+	upCmdOperationTimeouts []string
+	upCmdLazySources       bool
+	upCmdPrintRunnerEnv    bool
+	upCmdPinSources        []string
+	upCmdUpstreamProxies   []string
+	upCmdRecord            string
+	upCmdDebugConfig       bool
+	upCmdOTelEndpoint      string
+	upCmdRetryInitialBuild int
+	upCmdOperationExts     []string
+	upCmdReplay            string
+	upCmdDevUI             bool
+	upCmdDevUIAllowRemote  bool
+	upCmdHeaderRoutes      []string
+	upCmdSkipNodeCheck     bool
+	upCmdOperationOverride string
+	upCmdListenAddrs       []string
+	upCmdHTTP2             bool
+	upCmdSourceRegistry    string
+	upCmdFeatureFlags      []string
+	upCmdPlaygroundHeaders []string
+	upCmdPlaygroundQuery   string
+	upCmdTargetEnv         string
+	upCmdPort              int
+	upCmdUpstreamBase      string
+	upCmdReadOnly          bool
+	upCmdRunnerCwd         string
+)
 
 // upCmd represents the up command
 var upCmd = &cobra.Command{
@@ -37,11 +128,37 @@ var upCmd = &cobra.Command{
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
+		// crashed receives the first panic or error the dev loop can't recover from
+		crashed := make(chan error, 1)
+		crash := func(err error) {
+			select {
+			case crashed <- err:
+			default:
+			}
+			cancel()
+		}
+
+		if err := watcher.SetDefaultBackend(upCmdWatchBackend); err != nil {
+			return err
+		}
+
 		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
 		if err != nil {
 			return err
 		}
 
+		layoutDir := wunderGraphDir
+		if layoutDir == "" {
+			layoutDir = _wunderGraphDirConfig
+		}
+		for _, finding := range files.ValidateWunderGraphDir(layoutDir) {
+			if finding.Severity == files.SeverityError {
+				log.Error(finding.String())
+			} else {
+				log.Warn(finding.String())
+			}
+		}
+
 		// only validate if the file exists
 		_, err = files.CodeFilePath(wunderGraphDir, configEntryPointFilename)
 		if err != nil {
@@ -60,6 +177,39 @@ var upCmd = &cobra.Command{
 		)
 		defer stop()
 
+		// all components additionally log into a well-defined file, see 'wunderctl logs'
+		var devLogWriter io.Writer
+		devLogFile, err := logging.CreateLogFile(logging.DevLogFilePath(wunderGraphDir))
+		if err != nil {
+			log.Warn("could not create log file", zap.Error(err))
+		} else {
+			defer devLogFile.Close()
+			devLogWriter = devLogFile
+			log = logging.TeeToWriter(log, devLogFile)
+		}
+
+		var eventLog *logging.EventLog
+		if upCmdDumpEventsOnExit || upCmdDevUI {
+			// the dev UI shows the events of the run as well
+			eventLog = logging.NewEventLog(logging.DefaultEventLogSize)
+			log = logging.TeeToEventLog(log, eventLog)
+		}
+		if upCmdDumpEventsOnExit {
+			defer dumpEventLog(eventLog, logging.LastRunLogFilePath(wunderGraphDir))
+		}
+
+		var tracer *tracing.Recorder
+		if upCmdTraceFile != "" {
+			tracer = tracing.NewRecorder()
+			defer func() {
+				if err := tracer.WriteFile(upCmdTraceFile); err != nil {
+					log.Error("could not write trace file", zap.String("file", upCmdTraceFile), zap.Error(err))
+					return
+				}
+				log.Info("trace written, open it in chrome://tracing or https://ui.perfetto.dev", zap.String("file", upCmdTraceFile))
+			}()
+		}
+
 		log.Info("Starting WunderNode",
 			zap.String("version", BuildInfo.Version),
 			zap.String("commit", BuildInfo.Commit),
@@ -67,7 +217,46 @@ var upCmd = &cobra.Command{
 			zap.String("builtBy", BuildInfo.BuiltBy),
 		)
 
-		introspectionCacheDir := filepath.Join(wunderGraphDir, "cache", "introspection")
+		if !upCmdSkipNodeCheck {
+			// the runners fail with confusing errors on unsupported versions
+			version, err := helpers.CheckNodeVersion(ctx)
+			if err != nil {
+				return fmt.Errorf("%w, pass --skip-node-check to start anyway", err)
+			}
+			log.Debug("Node.js version supported", zap.String("version", version.String()))
+		}
+
+		if len(upCmdPreStart) != 0 {
+			preStartEnv := append(helpers.CliEnv(rootFlags), fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir))
+			for _, command := range upCmdPreStart {
+				if err := runPreStartCommand(ctx, command, preStartEnv); err != nil {
+					return err
+				}
+			}
+		}
+
+		introspectionCacheDir := introspectioncache.Dir(wunderGraphDir)
+
+		// with a snapshot the config is built from the cache only and the poller never runs
+		var snapshotEnv []string
+		if upCmdFromSnapshot != "" {
+			if disableCache {
+				return fmt.Errorf("--from-snapshot seeds the introspection cache and can't be combined with --no-cache")
+			}
+			snapshot, err := introspectioncache.LoadSnapshot(upCmdFromSnapshot)
+			if err != nil {
+				return err
+			}
+			if err := snapshot.Restore(introspectionCacheDir); err != nil {
+				return fmt.Errorf("could not restore snapshot %s: %w", upCmdFromSnapshot, err)
+			}
+			log.Warn("developing against an introspection snapshot, upstreams are not introspected",
+				zap.String("snapshot", upCmdFromSnapshot),
+				zap.Time("createdAt", snapshot.CreatedAt),
+				zap.Int("entries", len(snapshot.Entries)),
+			)
+			snapshotEnv = append(snapshotEnv, "WG_ENABLE_INTROSPECTION_OFFLINE=true")
+		}
 
 		configJsonPath := filepath.Join(wunderGraphDir, "generated", configJsonFilename)
 		webhooksDir := filepath.Join(wunderGraphDir, webhooks.WebhookDirectoryName)
@@ -76,43 +265,254 @@ var upCmd = &cobra.Command{
 		operationsDir := filepath.Join(wunderGraphDir, operations.DirectoryName)
 		generatedBundleOutDir := filepath.Join("generated", "bundle")
 
-		if port, err := helpers.ServerPortFromConfig(configJsonPath); err == nil {
-			helpers.KillExistingHooksProcess(port, log)
+		// the config and the hooks server resolve imports from runnerDir, outputs stay in the
+		// WunderGraph directory
+		runnerDir := wunderGraphDir
+		if upCmdRunnerCwd != "" {
+			if runnerDir, err = helpers.RunnerWorkingDir(upCmdRunnerCwd); err != nil {
+				return err
+			}
+			log.Info("running the config and the hooks server in", zap.String("dir", runnerDir))
+		}
+
+		// a free port is bound once, the node serves on its listener across config reloads
+		var (
+			nodePort     uint16
+			nodeListener net.Listener
+			portFile     string
+		)
+		if cmd.Flags().Changed("port") {
+			if upCmdPort < 0 || upCmdPort > math.MaxUint16 {
+				return fmt.Errorf("invalid --port %d", upCmdPort)
+			}
+			nodePort = uint16(upCmdPort)
+			if nodePort == 0 {
+				if nodeListener, err = net.Listen("tcp", "localhost:0"); err != nil {
+					return fmt.Errorf("could not select a free port: %w", err)
+				}
+				defer nodeListener.Close()
+				nodePort = uint16(nodeListener.Addr().(*net.TCPAddr).Port)
+			}
+			nodeURL := fmt.Sprintf("http://localhost:%d", nodePort)
+			// the config and the hooks server take the port of the node from the environment
+			for name, value := range map[string]string{
+				"WG_NODE_PORT":       strconv.Itoa(int(nodePort)),
+				"WG_NODE_URL":        nodeURL,
+				"WG_PUBLIC_NODE_URL": nodeURL,
+			} {
+				if err := os.Setenv(name, value); err != nil {
+					return err
+				}
+			}
+			// the node writes the port file once it listens, one left by a crash is stale
+			portFile = filepath.Join(wunderGraphDir, "generated", "port")
+			_ = os.Remove(portFile)
+			defer func() {
+				_ = os.Remove(portFile)
+			}()
+			log.Info(fmt.Sprintf("node port: %d", nodePort),
+				zap.String("nodeUrl", nodeURL),
+				zap.String("health", nodeURL+"/health"),
+				zap.String("portFile", portFile),
+			)
+		}
+
+		hooksServerPort, err := helpers.ServerPortFromConfig(configJsonPath)
+		if err == nil {
+			helpers.KillExistingHooksProcess(hooksServerPort, log)
+		}
+
+		// processes are tracked so 'wunderctl ps' and 'wunderctl kill' find them after a crash
+		processRegistry, err := newProcessRegistry()
+		if err != nil {
+			log.Debug("could not track processes", zap.Error(err))
+		} else {
+			untrack := trackProcess(processRegistry, upProcessName, wunderGraphDir, configJsonPath)
+			defer untrack()
+		}
+
+		// the config runners and the hooks server share the compile cache of node,
+		// only the first process compiles the modules they have in common
+		var nodeCompileCacheEnv []string
+		compileCacheDir := helpers.NodeCompileCacheDir(wunderGraphDir)
+		if !disableCache {
+			if err := os.MkdirAll(compileCacheDir, os.ModePerm); err != nil {
+				log.Warn("could not create node compile cache", zap.String("dir", compileCacheDir), zap.Error(err))
+			} else {
+				nodeCompileCacheEnv = append(nodeCompileCacheEnv, fmt.Sprintf("%s=%s", helpers.NodeCompileCacheEnvKey, compileCacheDir))
+			}
+		}
+
+		var maxBundleSize int64
+		if upCmdMaxBundleSize != "" {
+			if maxBundleSize, err = bundler.ParseByteSize(upCmdMaxBundleSize); err != nil {
+				return fmt.Errorf("invalid --max-bundle-size: %w", err)
+			}
+		}
+
+		var target *bundler.Target
+		if upCmdTargetEnv != "" {
+			if target, err = bundler.ParseTarget(upCmdTargetEnv); err != nil {
+				return err
+			}
+			log.Info("building for target env",
+				zap.String("targetEnv", target.Name),
+				zap.Bool("minify", target.Minify),
+				zap.Bool("sourcemap", target.Sourcemap),
+				zap.String("nodeEnv", target.NodeEnv),
+			)
+		}
+
+		// bundles keep packages external, node resolves them like the package manager laid them out
+		packageResolution, err := bundler.ResolvePackageManager(wunderGraphDir, bundler.PackageManager(upCmdPackageManager))
+		if err != nil {
+			return err
+		}
+		log.Debug("resolving packages",
+			zap.String("packageManager", string(packageResolution.PackageManager)),
+			zap.String("root", packageResolution.Root),
+		)
+		nodeEnv := append(append([]string(nil), nodeCompileCacheEnv...), packageResolution.NodeEnv(os.Getenv("NODE_OPTIONS"))...)
+		if target != nil {
+			nodeEnv = append(nodeEnv, target.Env()...)
+		}
+
+		// the flags selecting what the config runner loads are passed to it in the environment,
+		// excluded operations and webhooks are neither bundled nor part of the generated config
+		var configRunnerFlagEnv []string
+		if len(upCmdExcludeOperations) != 0 {
+			log.Warn("excluding operations", zap.Strings("operations", upCmdExcludeOperations))
+			configRunnerFlagEnv = append(configRunnerFlagEnv, fmt.Sprintf("%s=%s", operations.ExcludeEnvKey, strings.Join(upCmdExcludeOperations, ",")))
+		}
+		if len(upCmdExcludeWebhooks) != 0 {
+			log.Warn("excluding webhooks", zap.Strings("webhooks", upCmdExcludeWebhooks))
+			configRunnerFlagEnv = append(configRunnerFlagEnv, fmt.Sprintf("%s=%s", webhooks.ExcludeEnvKey, strings.Join(upCmdExcludeWebhooks, ",")))
+		}
+		graphqlExtensions, err := operations.ParseGraphQLExtensions(upCmdOperationExts)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("operation-extensions") {
+			configRunnerFlagEnv = append(configRunnerFlagEnv, fmt.Sprintf("%s=%s", operations.ExtensionsEnvKey, strings.Join(graphqlExtensions, ",")))
+		}
+		if len(upCmdPinSources) != 0 {
+			log.Info("pinning the introspection of sources", zap.Strings("sources", upCmdPinSources))
+			configRunnerFlagEnv = append(configRunnerFlagEnv, fmt.Sprintf("%s=%s", introspectioncache.PinEnvKey, strings.Join(upCmdPinSources, ",")))
+		}
+		var dataSourceRegistryPath string
+		if upCmdSourceRegistry != "" {
+			if dataSourceRegistryPath, err = dataSourceRegistry(upCmdSourceRegistry); err != nil {
+				return err
+			}
+			log.Info("loading data sources from registry", zap.String("path", dataSourceRegistryPath))
+			configRunnerFlagEnv = append(configRunnerFlagEnv, fmt.Sprintf("%s=%s", dataSourceRegistryEnvKey, dataSourceRegistryPath))
+		}
+
+		var clientOutEnv []string
+		if upCmdClientOut != "" {
+			clientOutDir := upCmdClientOut
+			if !filepath.IsAbs(clientOutDir) {
+				clientOutDir = filepath.Join(wunderGraphDir, clientOutDir)
+			}
+			if err := os.MkdirAll(clientOutDir, os.ModePerm); err != nil {
+				return fmt.Errorf("could not create --client-out directory: %w", err)
+			}
+			clientOutEnv = append(clientOutEnv, fmt.Sprintf("%s=%s", clientOutDirEnvKey, clientOutDir))
+		}
+
+		// only the config runners log verbosely, the node and the hooks server keep their log level
+		var configDebugEnv []string
+		if upCmdDebugConfig {
+			configDebugEnv = append(configDebugEnv, configDebugEnvKey+"=true")
 		}
 
+		configRunnerEnv := append(append(helpers.CliEnv(rootFlags),
+			"WG_PRETTY_GRAPHQL_VALIDATION_ERRORS=true",
+			fmt.Sprintf("WG_ENABLE_INTROSPECTION_CACHE=%t", !disableCache),
+			fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir),
+			fmt.Sprintf("%s=%s", wunderctlBinaryPathEnvKey, wunderctlBinaryPath()),
+		), append(append(append(append(nodeEnv, configRunnerFlagEnv...), snapshotEnv...), clientOutEnv...), configDebugEnv...)...)
+
 		configRunner := scriptrunner.NewScriptRunner(&scriptrunner.Config{
 			Name:          "config-runner",
 			Executable:    "node",
-			AbsWorkingDir: wunderGraphDir,
-			ScriptArgs:    []string{configOutFile},
+			AbsWorkingDir: runnerDir,
+			ScriptArgs:    []string{filepath.Join(wunderGraphDir, configOutFile)},
 			Logger:        log,
-			ScriptEnv: append(helpers.CliEnv(rootFlags),
-				"WG_PRETTY_GRAPHQL_VALIDATION_ERRORS=true",
-				fmt.Sprintf("WG_ENABLE_INTROSPECTION_CACHE=%t", !disableCache),
-				fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir),
-				fmt.Sprintf("%s=%s", wunderctlBinaryPathEnvKey, wunderctlBinaryPath()),
-			),
+			LogWriter:     devLogWriter,
+			ScriptEnv:     configRunnerEnv,
+			Registry:      processRegistry,
+			// without pretty logging the SDK logs JSON, other output of the runners is logged as JSON too
+			StructuredOutput: !rootFlags.PrettyLogs,
 		})
 
+		configWatchPaths := []*watcher.WatchPath{
+			{Path: filepath.Join(wunderGraphDir, "operations"), Optional: true},
+			{Path: filepath.Join(wunderGraphDir, "fragments"), Optional: true},
+			// all webhook filenames are stored in the config
+			// we are going to create HTTP routes on the node for all of them
+			{Path: webhooksDir, Optional: true},
+			{Path: operationsDir, Optional: true},
+			// a new cache entry is generated as soon as the introspection "poller" detects a change in the API dependencies
+			// in that case we want to rerun the script to build a new config
+			{Path: introspectionCacheDir},
+		}
+
+		if dataSourceRegistryPath != "" {
+			// the registry is read by the config runner, changes rebuild the config
+			configWatchPaths = append(configWatchPaths, &watcher.WatchPath{Path: dataSourceRegistryPath})
+		}
+
+		// additional user provided paths e.g. hand maintained files that are read by the config
+		// but are not imported, so esbuild doesn't know about them
+		for _, watchPath := range helpers.WatchPaths(wunderGraphDir, upCmdWatchPaths) {
+			log.Debug("Watching additional path", zap.String("path", watchPath))
+			configWatchPaths = append(configWatchPaths, &watcher.WatchPath{Path: watchPath})
+		}
+
+		// the config is only regenerated if any of its inputs changed since the last
+		// successful run, the environment of the config runner includes the one of wunderctl.
+		// Everything the config watcher rebuilds for is an input.
+		configInputs := helpers.ConfigInputs{
+			Paths: append([]string{
+				filepath.Join(wunderGraphDir, configOutFile),
+				filepath.Join(wunderGraphDir, generatedBundleOutDir, operations.DirectoryName),
+			}, helpers.WatchedPaths(configWatchPaths)...),
+			Env: append(append([]string(nil), configRunnerEnv...), "WG_CLI_VERSION="+BuildInfo.Version),
+		}
+		useConfigInputsHash := !disableCache && !upCmdForceConfig
+
+		configIntrospectionRunnerEnv := append(append(helpers.CliEnv(rootFlags),
+			// this environment variable starts the config runner in "Polling Mode"
+			"WG_DATA_SOURCE_POLLING_MODE=true",
+			fmt.Sprintf("WG_ENABLE_INTROSPECTION_CACHE=%t", !disableCache),
+			fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir),
+			fmt.Sprintf("%s=%s", wunderctlBinaryPathEnvKey, wunderctlBinaryPath()),
+		), append(append(nodeEnv, configRunnerFlagEnv...), configDebugEnv...)...)
+
 		// responsible for executing the config in "polling" mode
 		configIntrospectionRunner := scriptrunner.NewScriptRunner(&scriptrunner.Config{
-			Name:          "config-introspection-runner",
-			Executable:    "node",
-			AbsWorkingDir: wunderGraphDir,
-			ScriptArgs:    []string{configOutFile},
-			Logger:        log,
-			ScriptEnv: append(helpers.CliEnv(rootFlags),
-				// this environment variable starts the config runner in "Polling Mode"
-				"WG_DATA_SOURCE_POLLING_MODE=true",
-				fmt.Sprintf("WG_ENABLE_INTROSPECTION_CACHE=%t", !disableCache),
-				fmt.Sprintf("WG_DIR_ABS=%s", wunderGraphDir),
-				fmt.Sprintf("%s=%s", wunderctlBinaryPathEnvKey, wunderctlBinaryPath()),
-			),
+			Name:             "config-introspection-runner",
+			Executable:       "node",
+			AbsWorkingDir:    runnerDir,
+			ScriptArgs:       []string{filepath.Join(wunderGraphDir, configOutFile)},
+			Logger:           log,
+			LogWriter:        devLogWriter,
+			ScriptEnv:        configIntrospectionRunnerEnv,
+			Registry:         processRegistry,
+			StructuredOutput: !rootFlags.PrettyLogs,
 		})
 
+		if upCmdPrintRunnerEnv {
+			helpers.LogRunnerEnv(log, "config-runner", configRunnerEnv)
+			helpers.LogRunnerEnv(log, "config-introspection-runner", configIntrospectionRunnerEnv)
+		}
+
 		var hookServerRunner *scriptrunner.ScriptRunner
 		var webhooksBundler *bundler.Bundler
-		var onAfterBuild func() error
+		// onAfterBuild runs the phases following a config bundle, buildCtx is canceled
+		// when a newer change supersedes the build
+		var onAfterBuild func(buildCtx context.Context) error
 
 		if codeServerFilePath != "" {
 			hooksBundler := bundler.NewBundler(bundler.Config{
@@ -121,6 +521,12 @@ var upCmd = &cobra.Command{
 				AbsWorkingDir: wunderGraphDir,
 				OutFile:       serverOutFile,
 				Logger:        log,
+				DisableCache:  disableCache,
+				Metafile:      upCmdMetafile,
+				Verbose:       upCmdVerboseBundler,
+				MaxSize:       maxBundleSize,
+				Target:        target,
+				Tracer:        tracer,
 				WatchPaths: []*watcher.WatchPath{
 					{Path: configJsonPath},
 				},
@@ -131,6 +537,10 @@ var upCmd = &cobra.Command{
 				if err != nil {
 					return err
 				}
+				webhookPaths, excludedWebhookPaths := webhooks.Exclude(webhookPaths, upCmdExcludeWebhooks)
+				for _, path := range excludedWebhookPaths {
+					log.Info("webhook excluded", zap.String("path", path))
+				}
 
 				webhooksBundler = bundler.NewBundler(bundler.Config{
 					Name:          "webhooks-bundler",
@@ -138,7 +548,13 @@ var upCmd = &cobra.Command{
 					AbsWorkingDir: wunderGraphDir,
 					OutDir:        generatedBundleOutDir,
 					Logger:        log,
-					OnAfterBundle: func() error {
+					DisableCache:  disableCache,
+					Metafile:      upCmdMetafile,
+					Verbose:       upCmdVerboseBundler,
+					MaxSize:       maxBundleSize,
+					Target:        target,
+					Tracer:        tracer,
+					OnAfterBundle: func(context.Context) error {
 						log.Debug("Webhooks bundled!", zap.String("bundlerName", "webhooks-bundler"))
 						return nil
 					},
@@ -148,19 +564,39 @@ var upCmd = &cobra.Command{
 			srvCfg := &helpers.ServerRunConfig{
 				WunderGraphDirAbs: wunderGraphDir,
 				ServerScriptFile:  serverOutFile,
-				Env:               helpers.CliEnv(rootFlags),
+				WorkingDir:        runnerDir,
+				Env:               append(helpers.CliEnv(rootFlags), nodeEnv...),
+				LogWriter:         devLogWriter,
+				Registry:          processRegistry,
+				Port:              hooksServerPort,
+				StructuredOutput:  !rootFlags.PrettyLogs,
 			}
 
 			hookServerRunner = helpers.NewServerRunner(log, srvCfg)
+			if upCmdPrintRunnerEnv {
+				helpers.LogRunnerEnv(log, "hooks-server-runner", srvCfg.ScriptEnv())
+			}
 
-			onAfterBuild = func() error {
+			onAfterBuild = func(buildCtx context.Context) error {
 				log.Debug("Config built!", zap.String("bundlerName", "config-bundler"))
 
 				if files.DirectoryExists(operationsDir) {
+					if err := operations.Validate(wunderGraphDir, upCmdExcludeOperations, graphqlExtensions); err != nil {
+						log.Error("operations invalid, the node keeps serving the last known good config", zap.Error(err))
+						return err
+					}
+					if err := depgraph.CheckImportCycles(wunderGraphDir, graphqlExtensions); err != nil {
+						log.Error("operations invalid, the node keeps serving the last known good config", zap.Error(err))
+						return err
+					}
 					operationsPaths, err := operations.GetPaths(wunderGraphDir)
 					if err != nil {
 						return err
 					}
+					operationsPaths, excludedOperationsPaths := operations.Exclude(operationsPaths, upCmdExcludeOperations)
+					for _, path := range excludedOperationsPaths {
+						log.Info("operation excluded", zap.String("path", path))
+					}
 					err = operations.Cleanup(wunderGraphDir, operationsPaths)
 					if err != nil {
 						return err
@@ -175,15 +611,24 @@ var upCmd = &cobra.Command{
 						AbsWorkingDir: wunderGraphDir,
 						OutDir:        generatedBundleOutDir,
 						Logger:        log,
+						DisableCache:  disableCache,
+						Metafile:      upCmdMetafile,
+						Verbose:       upCmdVerboseBundler,
+						MaxSize:       maxBundleSize,
+						Target:        target,
 					})
-					err = operationsBundler.Bundle()
+					endSpan := tracer.Start(tracing.SpanOperationsBundle, "operations-bundler")
+					err = operationsBundler.BundleContext(buildCtx)
+					endSpan()
 					if err != nil {
 						return err
 					}
 				}
 
 				// generate new config
-				<-configRunner.Run(ctx)
+				if err := generateConfig(buildCtx, configRunner, configJsonPath, configInputs, useConfigInputsHash, compileCacheDir, tracer); err != nil {
+					return err
+				}
 
 				var wg sync.WaitGroup
 
@@ -191,41 +636,65 @@ var upCmd = &cobra.Command{
 				go func() {
 					defer wg.Done()
 					// bundle hooks
-					_ = hooksBundler.Bundle()
+					_ = hooksBundler.BundleContext(buildCtx)
 				}()
 
 				if webhooksBundler != nil {
 					wg.Add(1)
 					go func() {
 						defer wg.Done()
-						_ = webhooksBundler.Bundle()
+						_ = webhooksBundler.BundleContext(buildCtx)
 					}()
 				}
 
 				wg.Wait()
+				if err := buildCtx.Err(); err != nil {
+					return err
+				}
 
 				go func() {
+					defer logging.Recover(log, "hooks-server-runner", crash)
 					// run or restart hook server
-					<-hookServerRunner.Run(ctx)
+					endSpan := tracer.Start(tracing.SpanHookRestart, "hooks-server-runner")
+					done := hookServerRunner.Run(ctx)
+					endSpan()
+					<-done
 				}()
 
-				go func() {
-					// run or restart the introspection poller
-					<-configIntrospectionRunner.Run(ctx)
-				}()
+				if upCmdFromSnapshot == "" {
+					go func() {
+						defer logging.Recover(log, "config-introspection-runner", crash)
+						// run or restart the introspection poller
+						<-configIntrospectionRunner.Run(ctx)
+					}()
+				}
 
 				return nil
 			}
 		} else {
 			log.Info("hooks EntryPoint not found, skipping", zap.String("file", serverEntryPointFilename))
-			onAfterBuild = func() error {
+			onAfterBuild = func(buildCtx context.Context) error {
+				if err := operations.Validate(wunderGraphDir, upCmdExcludeOperations, graphqlExtensions); err != nil {
+					log.Error("operations invalid, the node keeps serving the last known good config", zap.Error(err))
+					return err
+				}
+				if err := depgraph.CheckImportCycles(wunderGraphDir, graphqlExtensions); err != nil {
+					log.Error("operations invalid, the node keeps serving the last known good config", zap.Error(err))
+					return err
+				}
+
 				// generate new config
-				<-configRunner.Run(ctx)
+				if err := generateConfig(buildCtx, configRunner, configJsonPath, configInputs, useConfigInputsHash, compileCacheDir, tracer); err != nil {
+					return err
+				}
 
-				go func() {
-					// run or restart the introspection poller
-					<-configIntrospectionRunner.Run(ctx)
-				}()
+				if upCmdFromSnapshot == "" {
+					go func() {
+						defer logging.Recover(log, "config-introspection-runner", crash)
+						// run or restart the introspection poller
+						<-configIntrospectionRunner.Run(ctx)
+					}()
+				}
 
 				log.Debug("Config built!", zap.String("bundlerName", "config-bundler"))
 
@@ -233,30 +702,37 @@ var upCmd = &cobra.Command{
 			}
 		}
 
+		watchPause := watcher.NewPause()
 		configBundler := bundler.NewBundler(bundler.Config{
 			Name:          "config-bundler",
 			EntryPoints:   []string{configEntryPointFilename},
 			AbsWorkingDir: wunderGraphDir,
 			OutFile:       configOutFile,
 			Logger:        log,
-			WatchPaths: []*watcher.WatchPath{
-				{Path: filepath.Join(wunderGraphDir, "operations"), Optional: true},
-				{Path: filepath.Join(wunderGraphDir, "fragments"), Optional: true},
-				// all webhook filenames are stored in the config
-				// we are going to create HTTP routes on the node for all of them
-				{Path: webhooksDir, Optional: true},
-				{Path: operationsDir, Optional: true},
-				// a new cache entry is generated as soon as the introspection "poller" detects a change in the API dependencies
-				// in that case we want to rerun the script to build a new config
-				{Path: introspectionCacheDir},
-			},
+			DisableCache:  disableCache,
+			Metafile:      upCmdMetafile,
+			Verbose:       upCmdVerboseBundler,
+			MaxSize:       maxBundleSize,
+			Target:        target,
+			Tracer:        tracer,
+			WatchPaths:    configWatchPaths,
+			WatchPause:    watchPause,
 			IgnorePaths: []string{
 				"node_modules",
 			},
 			OnAfterBundle: onAfterBuild,
+			// the introspection poller records upstream changes no operation uses in the cache
+			// without rebuilding the config, the next rebuild picks them up
+			SkipChanges: func(changes []watcher.Change) bool {
+				paths := make([]string, len(changes))
+				for i, change := range changes {
+					paths[i] = change.Path
+				}
+				return introspectioncache.NoImpact(introspectionCacheDir, paths)
+			},
 		})
 
-		err = configBundler.Bundle()
+		err = bundleInitialConfig(ctx, configBundler, upCmdRetryInitialBuild)
 		if err != nil {
 			log.Error("could not bundle",
 				zap.String("bundlerName", "config-bundler"),
@@ -265,18 +741,77 @@ var upCmd = &cobra.Command{
 			)
 		}
 
+		if upCmdStrictEnv && files.FileExists(configJsonPath) {
+			if err := node.CheckEnvironmentVariables(configJsonPath); err != nil {
+				return err
+			}
+		}
+
 		// only start watching in the builder once the initial config was built and written to the filesystem
 		go configBundler.Watch(ctx)
+		if isTerminal(os.Stdin) {
+			log.Info("press p and enter to pause or resume watching")
+			go toggleWatchPause(os.Stdin, watchPause)
+		}
+
+		if upCmdRebuildOnSwitch {
+			gitHead, ok := files.FindGitHead(wunderGraphDir)
+			if !ok {
+				log.Warn("not a git repository, --rebuild-on-branch-switch has no effect", zap.String("dir", wunderGraphDir))
+			} else {
+				go watchBranchSwitch(ctx, gitHead, func(from, to string) {
+					log.Info("git branch switched, rebuilding from scratch", zap.String("from", from), zap.String("to", to))
+					if err := os.RemoveAll(filepath.Join(wunderGraphDir, generatedBundleOutDir)); err != nil {
+						log.Error("could not remove generated bundles", zap.Error(err))
+					}
+					if err := configBundler.Bundle(); err != nil {
+						log.Error("could not bundle",
+							zap.String("bundlerName", "config-bundler"),
+							zap.String("watcher", "git-head"),
+							zap.Error(err),
+						)
+					}
+				})
+			}
+		}
 
 		configFileChangeChan := make(chan struct{})
+		nodeConfigWatchPaths := []*watcher.WatchPath{
+			{Path: configJsonPath},
+		}
+		var overridesPath string
+		if upCmdOverrides != "" {
+			if overridesPath, err = filepath.Abs(upCmdOverrides); err != nil {
+				return err
+			}
+			if _, err := node.LoadDevOverrides(overridesPath); err != nil {
+				return err
+			}
+			// editing the overrides reloads the node like a new config
+			nodeConfigWatchPaths = append(nodeConfigWatchPaths, &watcher.WatchPath{Path: overridesPath})
+		}
+		var operationOverridesPath string
+		if upCmdOperationOverride != "" {
+			if strings.ContainsAny(upCmdOperationOverride, `/\`) {
+				return fmt.Errorf("invalid --operation-overrides %q, expected an env name like staging", upCmdOperationOverride)
+			}
+			operationOverridesPath = filepath.Join(wunderGraphDir, node.OperationOverridesFileName(upCmdOperationOverride))
+			if _, err := node.LoadOperationOverrides(operationOverridesPath); err != nil {
+				return err
+			}
+			nodeConfigWatchPaths = append(nodeConfigWatchPaths, &watcher.WatchPath{Path: operationOverridesPath})
+		}
 		configWatcher := watcher.NewWatcher("config", &watcher.Config{
-			WatchPaths: []*watcher.WatchPath{
-				{Path: configJsonPath},
-			},
+			WatchPaths: nodeConfigWatchPaths,
 		}, log)
 
 		go func() {
+			defer logging.Recover(log, "config-watcher", crash)
 			err := configWatcher.Watch(ctx, func(paths []string) error {
+				if upCmdAttach != "" {
+					pushConfigToNode(ctx, upCmdAttach, configJsonPath)
+					return nil
+				}
 				configFileChangeChan <- struct{}{}
 				return nil
 			})
@@ -288,23 +823,345 @@ var upCmd = &cobra.Command{
 			}
 		}()
 
+		if upCmdAttach != "" {
+			// the node runs elsewhere, e.g. in a container, and receives every config instead.
+			// Like below, the initial config is already built and fires no fs event
+			log.Info("attached to node, pushing configs", zap.String("nodeUrl", upCmdAttach))
+			pushConfigToNode(ctx, upCmdAttach, configJsonPath)
+
+			<-ctx.Done()
+
+			log.Info("Context was canceled. Detaching from WunderNode ....")
+			return crashError(crashed)
+		}
+
+		configFile := filepath.Join(wunderGraphDir, "generated", "wundergraph.config.json")
+		nodeOpts := []node.Option{
+			node.WithConfigFileChange(configFileChangeChan),
+			node.WithFileSystemConfig(configFile),
+			node.WithDebugMode(rootFlags.DebugMode),
+			node.WithInsecureCookies(),
+			node.WithIntrospection(true),
+			node.WithGitHubAuthDemo(GitHubAuthDemo),
+			node.WithPrettyLogging(rootFlags.PrettyLogs),
+			node.WithDevMode(),
+		}
+
+		if devLogWriter != nil {
+			nodeOpts = append(nodeOpts, node.WithLogWriter(devLogWriter))
+		}
+
+		if tracer != nil {
+			nodeOpts = append(nodeOpts, node.WithTracer(tracer))
+		}
+
+		if eventLog != nil {
+			// the node replaces its logger once the config is loaded
+			nodeOpts = append(nodeOpts, node.WithEventLog(eventLog))
+		}
+
+		if upCmdDevUI {
+			watchPaths := make([]string, 0, len(configWatchPaths))
+			for _, watchPath := range configWatchPaths {
+				watchPaths = append(watchPaths, watchPath.Path)
+			}
+			nodeOpts = append(nodeOpts, node.WithDevUI(node.DevUI{
+				Events:     eventLog,
+				WatchPaths: watchPaths,
+				Rebuild: func() {
+					if err := configBundler.Bundle(); err != nil {
+						log.Error("could not bundle",
+							zap.String("bundlerName", "config-bundler"),
+							zap.String("watcher", "dev-ui"),
+							zap.Error(err),
+						)
+					}
+				},
+				AllowRemote: upCmdDevUIAllowRemote,
+			}))
+			log.Info("dev UI enabled", zap.String("path", node.DevUIEndpoint), zap.Bool("allowRemote", upCmdDevUIAllowRemote))
+		}
+
+		if upCmdStrictEnv {
+			nodeOpts = append(nodeOpts, node.WithStrictEnv())
+		}
+
+		if upCmdServerTimeouts != (node.ServerTimeouts{}) {
+			nodeOpts = append(nodeOpts, node.WithServerTimeouts(
+				upCmdServerTimeouts.Read,
+				upCmdServerTimeouts.Write,
+				upCmdServerTimeouts.Idle,
+				upCmdServerTimeouts.ReadHeader,
+			))
+		}
+
+		if upCmdNoPlayground || upCmdPlaygroundPath != apihandler.DefaultPlaygroundPath {
+			if !strings.HasPrefix(upCmdPlaygroundPath, "/") {
+				return fmt.Errorf("invalid --playground-path %q, must start with /", upCmdPlaygroundPath)
+			}
+			nodeOpts = append(nodeOpts, node.WithPlayground(!upCmdNoPlayground, upCmdPlaygroundPath))
+		}
+
+		if len(upCmdPlaygroundHeaders) != 0 || upCmdPlaygroundQuery != "" {
+			headers := make(map[string]string, len(upCmdPlaygroundHeaders))
+			for _, header := range upCmdPlaygroundHeaders {
+				name, value, ok := strings.Cut(header, "=")
+				if !ok || name == "" {
+					return fmt.Errorf("invalid playground header %q, expected <name>=<value>", header)
+				}
+				headers[name] = value
+			}
+			var query string
+			if upCmdPlaygroundQuery != "" {
+				data, err := os.ReadFile(upCmdPlaygroundQuery)
+				if err != nil {
+					return fmt.Errorf("could not read --playground-query: %w", err)
+				}
+				query = string(data)
+			}
+			nodeOpts = append(nodeOpts, node.WithPlaygroundDefaults(headers, query))
+		}
+
+		if upCmdWarmPlans {
+			nodeOpts = append(nodeOpts, node.WithWarmPlans())
+		}
+
+		for _, limit := range upCmdRateLimits {
+			operationName, perSecond, burst, err := parseRateLimit(limit)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithRateLimit(operationName, perSecond, burst))
+		}
+
+		for _, value := range upCmdOperationTimeouts {
+			operationName, timeout, err := parseOperationTimeout(value)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithOperationTimeout(operationName, timeout))
+		}
+
+		if upCmdLazySources {
+			nodeOpts = append(nodeOpts, node.WithLazyDataSources())
+		}
+
+		if upCmdReadOnly {
+			log.Info("read-only mode, mutations are rejected")
+			nodeOpts = append(nodeOpts, node.WithReadOnly())
+		}
+
+		if len(upCmdFeatureFlags) != 0 {
+			nodeOpts = append(nodeOpts, node.WithFeatureFlags(upCmdFeatureFlags...))
+		}
+
+		if upCmdSSE {
+			nodeOpts = append(nodeOpts, node.WithSSESubscriptions())
+		}
+
+		if upCmdPersistSessions {
+			nodeOpts = append(nodeOpts, node.WithPersistentDevSessions(filepath.Join(wunderGraphDir, node.DevSessionsFileName)))
+		}
+
+		if overridesPath != "" {
+			nodeOpts = append(nodeOpts, node.WithDevOverrides(overridesPath))
+		}
+
+		if operationOverridesPath != "" {
+			nodeOpts = append(nodeOpts, node.WithOperationOverrides(operationOverridesPath))
+		}
+
+		if (upCmdTLSCert == "") != (upCmdTLSKey == "") {
+			return fmt.Errorf("--tls-cert and --tls-key must be set together")
+		}
+		if upCmdTLSCert != "" {
+			nodeOpts = append(nodeOpts, node.WithTLS(upCmdTLSCert, upCmdTLSKey))
+		}
+		if upCmdHTTP2 {
+			nodeOpts = append(nodeOpts, node.WithHTTP2())
+		}
+
+		if upCmdReloadCooldown < 0 {
+			return fmt.Errorf("--reload-cooldown must not be negative")
+		}
+		if upCmdReloadCooldown > 0 {
+			nodeOpts = append(nodeOpts, node.WithReloadCooldown(upCmdReloadCooldown))
+		}
+
+		if upCmdLogRequests != "" {
+			valid := false
+			for _, format := range node.AccessLogFormats {
+				valid = valid || format == upCmdLogRequests
+			}
+			if !valid {
+				return fmt.Errorf("invalid --log-requests format %q, expected one of %v", upCmdLogRequests, node.AccessLogFormats)
+			}
+			nodeOpts = append(nodeOpts, node.WithAccessLog(upCmdLogRequests))
+		}
+
+		if upCmdAuthAs != "" {
+			var claims map[string]interface{}
+			if err := json.Unmarshal([]byte(upCmdAuthAs), &claims); err != nil {
+				return fmt.Errorf("invalid --auth-as claims, expected a JSON object: %w", err)
+			}
+			nodeOpts = append(nodeOpts, node.WithDevAuthBypass(claims))
+		}
+
+		for _, mount := range upCmdMounts {
+			prefix, configPath, ok := strings.Cut(mount, "=")
+			if !ok || prefix == "" || configPath == "" {
+				return fmt.Errorf("invalid --mount %q, expected <prefix>=<config file>", mount)
+			}
+			absConfigPath, err := filepath.Abs(configPath)
+			if err != nil {
+				return fmt.Errorf("unable to get absolute path of mounted config %s: %w", configPath, err)
+			}
+			nodeOpts = append(nodeOpts, node.WithMountedConfig(prefix, absConfigPath))
+		}
+
+		if upCmdPersistedQueries {
+			nodeOpts = append(nodeOpts, node.WithPersistedQueries())
+		}
+
+		for _, proxy := range upCmdUpstreamProxies {
+			// proxy urls contain "=" only in their query, ids never contain "://"
+			sourceName, proxyURL, ok := strings.Cut(proxy, "=")
+			if !ok || strings.Contains(sourceName, "://") {
+				nodeOpts = append(nodeOpts, node.WithUpstreamProxy(proxy))
+				continue
+			}
+			if sourceName == "" {
+				return fmt.Errorf("invalid --upstream-proxy %q, expected <url> or <datasource id>=<url>", proxy)
+			}
+			nodeOpts = append(nodeOpts, node.WithDataSourceProxy(sourceName, proxyURL))
+		}
+
+		if upCmdRecord != "" && upCmdReplay != "" {
+			return fmt.Errorf("--record and --replay can't be used together")
+		}
+
+		if upCmdRecord != "" {
+			cassettePath, err := filepath.Abs(upCmdRecord)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithRecord(cassettePath))
+		}
+
+		if upCmdReplay != "" {
+			cassettePath, err := filepath.Abs(upCmdReplay)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithReplay(cassettePath))
+		}
+
+		// like the OpenTelemetry SDKs, traces are exported as soon as a collector is configured
+		if upCmdOTelEndpoint != "" || os.Getenv(otlp.EndpointEnvKey) != "" || os.Getenv(otlp.TracesEndpointEnvKey) != "" {
+			nodeOpts = append(nodeOpts, node.WithOTel(upCmdOTelEndpoint, ""))
+		}
+
+		for _, injection := range upCmdInjectLatency {
+			sourceName, delay, jitter, err := parseLatencyInjection(injection)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithLatencyInjection(sourceName, delay, jitter))
+		}
+
+		if len(upCmdHeaderRoutes) != 0 {
+			routings, err := parseHeaderRoutes(upCmdHeaderRoutes)
+			if err != nil {
+				return err
+			}
+			for sourceName, routing := range routings {
+				nodeOpts = append(nodeOpts, node.WithHeaderRouting(sourceName, routing.header, routing.routes))
+			}
+		}
+
+		if nodePort != 0 {
+			nodeOpts = append(nodeOpts, node.WithListenPort(nodePort), node.WithPortFile(portFile))
+		}
+		if nodeListener != nil {
+			nodeOpts = append(nodeOpts, node.WithListener(nodeListener))
+		}
+
+		if upCmdUpstreamBase != "" {
+			base, err := node.ParseUpstreamBase(upCmdUpstreamBase)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithUpstreamBase(base))
+		}
+
+		if len(upCmdListenAddrs) != 0 {
+			for _, addr := range upCmdListenAddrs {
+				if _, err := node.ParseListenAddr(addr, 0); err != nil {
+					return fmt.Errorf("invalid --listen: %w", err)
+				}
+			}
+			nodeOpts = append(nodeOpts, node.WithListenAddrs(upCmdListenAddrs...))
+		}
+
+		for _, transform := range upCmdTransforms {
+			sourceName, expression, ok := strings.Cut(transform, "=")
+			if !ok || sourceName == "" {
+				return fmt.Errorf("invalid --transform %q, expected id=expression", transform)
+			}
+			if _, err := engineconfigloader.ParseResponseTransform(expression); err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithResponseTransform(sourceName, expression))
+		}
+
+		for _, schedule := range upCmdSchedules {
+			operationName, interval, err := parseSchedule(schedule)
+			if err != nil {
+				return err
+			}
+			nodeOpts = append(nodeOpts, node.WithScheduledOperation(operationName, interval))
+		}
+
+		for _, header := range upCmdUpstreamHeaders {
+			name, value, ok := strings.Cut(header, "=")
+			if !ok || name == "" {
+				// don't include the value, it's likely a credential
+				return fmt.Errorf("invalid upstream header %q, expected <name>=<value>", name)
+			}
+			nodeOpts = append(nodeOpts, node.WithUpstreamHeader(name, value))
+		}
+
+		for _, header := range upCmdResponseHeaders {
+			name, value, ok := strings.Cut(header, "=")
+			if !ok || name == "" {
+				return fmt.Errorf("invalid response header %q, expected <name>=<value>", header)
+			}
+			nodeOpts = append(nodeOpts, node.WithResponseHeader(name, value))
+		}
+
+		if upCmdExplain != "" {
+			nodeOpts = append(nodeOpts, node.WithExplainOperation(upCmdExplain))
+		}
+
+		// queries annotated with @cache are always cached, --cache-responses caches all others too
+		nodeOpts = append(nodeOpts, node.WithResponseCache(responsecache.Options{
+			TTL:           upCmdCacheTTL,
+			AnnotatedOnly: !upCmdCacheResponses,
+		}))
+
 		n := node.New(ctx, BuildInfo, wunderGraphDir, log)
 		go func() {
-			configFile := filepath.Join(wunderGraphDir, "generated", "wundergraph.config.json")
-			err := n.StartBlocking(
-				node.WithConfigFileChange(configFileChangeChan),
-				node.WithFileSystemConfig(configFile),
-				node.WithDebugMode(rootFlags.DebugMode),
-				node.WithInsecureCookies(),
-				node.WithIntrospection(true),
-				node.WithGitHubAuthDemo(GitHubAuthDemo),
-				node.WithPrettyLogging(rootFlags.PrettyLogs),
-				node.WithDevMode(),
-			)
+			defer logging.Recover(log, "node", crash)
+			err := n.StartBlocking(nodeOpts...)
 			if err != nil {
-				log.Error("node exited", zap.Error(err))
+				var startupErr *node.StartupError
+				if errors.As(err, &startupErr) {
+					log.Error(startupErrorMessage(startupErr), zap.Error(startupErr.Err))
+				} else {
+					log.Error("node exited", zap.Error(err))
+				}
 				// exit context because we can't recover from a server start error
-				cancel()
+				crash(err)
 			}
 		}()
 
@@ -322,12 +1179,415 @@ var upCmd = &cobra.Command{
 
 		log.Info("server shutdown complete")
 
-		return nil
+		return crashError(crashed)
 	},
 }
 
 func init() {
-	upCmd.PersistentFlags().BoolVar(&upCmdPrettyLogging, "pretty-logging", true, "switches the logging to human readable format")
+	upCmd.PersistentFlags().BoolVar(&upCmdPrettyLogging, "pretty-logging", true, "switches the logging to human readable format, without it the node, the runners and the hooks server all log JSON")
+	upCmd.Flags().StringVar(&upCmdAuthAs, "auth-as", "", `injects the given JSON claims as the authenticated user into all requests, e.g. '{"sub":"user1"}'. Never use this in production`)
+	upCmd.Flags().BoolVar(&upCmdPersistedQueries, "persisted-queries", false, "registers the hashes of all operations as persisted queries and accepts GraphQL requests by hash")
+	upCmd.Flags().StringArrayVar(&upCmdTransforms, "transform", nil, "rewrites responses of a data source by id with a jq-style expression, e.g. billing='.data.price |= . * 1.1', can be repeated")
+	upCmd.Flags().StringVar(&upCmdOTelEndpoint, "otel-endpoint", "", fmt.Sprintf("exports traces of the node to the OpenTelemetry collector at the url with OTLP over HTTP, e.g. %s. Defaults to %s, the other OTEL_* variables are honored too", otlp.DefaultEndpoint, otlp.EndpointEnvKey))
+	upCmd.Flags().StringVar(&upCmdRecord, "record", "", "records every upstream request and its response to the cassette file at the given path, sensitive headers are redacted")
+	upCmd.Flags().StringVar(&upCmdReplay, "replay", "", "answers upstream requests from the cassette file written by --record instead of sending them, unrecorded requests fail")
+	upCmd.Flags().StringVar(&upCmdUpstreamBase, "upstream-base", "", "resolves relative urls of REST and GraphQL data sources against the url, e.g. http://localhost:4000. Absolute urls are kept")
+	upCmd.Flags().StringArrayVar(&upCmdUpstreamProxies, "upstream-proxy", nil, "sends upstream requests through the proxy at the url instead of the one of HTTP_PROXY/HTTPS_PROXY, or only those of a data source by id, e.g. billing=http://proxy:3128. Hosts in NO_PROXY are excluded, can be repeated")
+	upCmd.Flags().IntVar(&upCmdPort, "port", 0, "binds the node to the port instead of the one of the config, 0 selects a free port. The port is written to generated/port")
+	upCmd.Flags().StringArrayVar(&upCmdListenAddrs, "listen", nil, "binds the node to the address instead of the listener of the config, e.g. localhost or 192.168.1.10:9991. Without a port the one of the config is used, addresses that can't be bound are skipped. Can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdHeaderRoutes, "header-route", nil, "sends upstream requests of a data source by id to another upstream if a header of the client request has the value, e.g. billing:X-Backend:staging=https://billing.staging.example.com, can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdInjectLatency, "inject-latency", nil, "delays upstream requests of a data source by id, e.g. billing=200ms±50ms, can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdSchedules, "schedule", nil, "invokes a query or mutation on a timer, e.g. \"Users:@every 30s\", can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdUpstreamHeaders, "upstream-header", nil, "sets a header on every upstream request, e.g. X-Dev-Key=abc, can be repeated")
+	upCmd.Flags().StringVar(&upCmdWatchBackend, "watch-backend", string(watcher.BackendFsnotify), fmt.Sprintf("backend to watch files, one of %s", strings.Join(watcher.BackendNames(), ", ")))
+	upCmd.Flags().StringVar(&upCmdExplain, "explain", "", "prints the execution plan of the operation with the given name on every config load, all plans are served at /explain/<operation>")
+	upCmd.Flags().BoolVar(&upCmdDumpEventsOnExit, "dump-events-on-exit", false, fmt.Sprintf("keeps the last %d events in memory and writes them to generated/%s on exit", logging.DefaultEventLogSize, logging.LastRunLogFilename))
+	upCmd.Flags().StringVar(&upCmdTraceFile, "trace-file", "", "writes the timings of bundling, config runs, hook server restarts and node reloads to the given file in the Chrome Trace Event Format on exit")
+	upCmd.Flags().StringArrayVar(&upCmdExcludeOperations, "exclude-operation", nil, "omits the operation with the given name or path, e.g. users/get, from bundling and the config. Can be repeated")
+	upCmd.Flags().StringVar(&upCmdSourceRegistry, "datasource-registry", "", `adds the data sources of a JSON file, e.g. generated by another tool, to the ones of the config, e.g. {"dataSources":[{"kind":"graphql","id":"billing","apiNamespace":"billing","url":"https://billing.example.com/graphql"}]}. Kinds are the names of the introspect functions, the file is watched`)
+	upCmd.Flags().StringArrayVar(&upCmdPinSources, "pin-source", nil, "always reuses the cached introspection of the source with the given id or api namespace and never polls it, even with --no-cache. Can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdExcludeWebhooks, "exclude-webhook", nil, "omits the webhook with the given name from bundling and the config. Can be repeated")
+	upCmd.Flags().BoolVar(&upCmdStrictEnv, "strict-env", false, "fails on startup and rejects config changes if the config references environment variables that are unset and have no default value")
+	upCmd.Flags().BoolVar(&upCmdRebuildOnSwitch, "rebuild-on-branch-switch", false, "removes the generated bundles and rebuilds everything when the checked out git branch or commit changes")
+	upCmd.Flags().DurationVar(&upCmdServerTimeouts.Read, "server-read-timeout", 0, "maximum duration for reading a request including the body, 0 disables the timeout")
+	upCmd.Flags().DurationVar(&upCmdServerTimeouts.Write, "server-write-timeout", 0, "maximum duration for writing a response, subscriptions, live queries and WebSockets are exempt. 0 disables the timeout")
+	upCmd.Flags().DurationVar(&upCmdServerTimeouts.Idle, "server-idle-timeout", 0, "maximum duration a keep-alive connection waits for the next request, 0 disables the timeout")
+	upCmd.Flags().DurationVar(&upCmdServerTimeouts.ReadHeader, "server-read-header-timeout", 0, "maximum duration for reading the headers of a request, 0 disables the timeout")
+	upCmd.Flags().BoolVar(&upCmdNoPlayground, "no-playground", false, "disables the GraphQL playground, the GraphQL endpoint and introspection keep working")
+	upCmd.Flags().StringVar(&upCmdPlaygroundPath, "playground-path", apihandler.DefaultPlaygroundPath, "path of the GraphQL playground, e.g. /__playground")
+	upCmd.Flags().StringArrayVar(&upCmdPlaygroundHeaders, "playground-header", nil, "prefills the headers of the first playground tab, e.g. Authorization='Bearer <token>'. Visible to everyone opening the playground, use placeholders instead of secrets. Can be repeated")
+	upCmd.Flags().StringVar(&upCmdPlaygroundQuery, "playground-query", "", "file with the query prefilled in the first playground tab, e.g. .wundergraph/playground.graphql")
+	upCmd.Flags().BoolVar(&upCmdWarmPlans, "warm-plans", false, "prepares the plans of the GraphQL endpoint for all operations after each config load, so the first request doesn't pay for planning")
+	upCmd.Flags().BoolVar(&upCmdLazySources, "lazy-sources", false, "connects to datasources on their first request, so unreachable or misconfigured datasources only fail their own operations")
+	upCmd.Flags().StringArrayVar(&upCmdOperationTimeouts, "operation-timeout", nil, "cancels requests of the operation with the given name or path after the duration with 504, e.g. slowReport=60s. Can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdRateLimits, "rate-limit", nil, "rejects requests of the operation with the given name or path exceeding the rate with 429, e.g. getUser=5/s or getUser=5/s:10 for bursts of 10. Can be repeated")
+	upCmd.Flags().StringVar(&upCmdAttach, "attach", "", "pushes every generated config to the node at the given URL instead of starting one, the node must run with 'wunderctl node start --accept-config-push'")
+	upCmd.Flags().StringVar(&upCmdFromSnapshot, "from-snapshot", "", "seeds the introspection cache from a file written by 'wunderctl snapshot save' and builds the config without introspecting any upstream")
+	upCmd.Flags().BoolVar(&upCmdSSE, "sse", false, "serves the GraphQL endpoint over the GraphQL over SSE protocol at /graphql/stream and to clients accepting text/event-stream")
+	upCmd.Flags().StringArrayVar(&upCmdResponseHeaders, "response-header", nil, "sets a header on every response unless the node sets it, e.g. X-Frame-Options=DENY, can be repeated")
+	upCmd.Flags().BoolVar(&upCmdPersistSessions, "persist-sessions", false, "keeps login sessions across reloads and restarts by storing insecure dev cookie keys in "+node.DevSessionsFileName)
+	upCmd.Flags().BoolVar(&upCmdDebugConfig, "debug-config", false, fmt.Sprintf("enables debug logs of the config generation only by setting %s=true for the config runners, regenerates the config", configDebugEnvKey))
+	upCmd.Flags().BoolVar(&upCmdPrintRunnerEnv, "print-runner-env", false, "logs the sorted environment passed to the config and hooks server runners, values of variables that look like secrets are redacted")
+	upCmd.Flags().StringSliceVar(&upCmdOperationExts, "operation-extensions", operations.DefaultGraphQLExtensions, "the extensions of GraphQL operation and fragment files, the operation name is the file name without it. TypeScript operations are always loaded")
+	upCmd.Flags().IntVar(&upCmdRetryInitialBuild, "retry-initial-build", 0, "retries the initial config bundle and config run up to the given number of times with backoff, e.g. on a slow upstream during introspection. Rebuilds on changes are never retried")
+	upCmd.Flags().BoolVar(&upCmdDevUI, "dev-ui", false, fmt.Sprintf("serves a page at %s with the build status, recent errors, watched paths and datasources, live events and a rebuild button", node.DevUIEndpoint))
+	upCmd.Flags().BoolVar(&upCmdDevUIAllowRemote, "dev-ui-allow-remote", false, "serves the dev UI to clients other than loopback ones, anyone reaching the node can trigger rebuilds")
+	upCmd.Flags().BoolVar(&upCmdForceConfig, "force-config", false, "always runs the config runner, even if none of its inputs changed since the last generated config")
+	upCmd.Flags().BoolVar(&upCmdSkipNodeCheck, "skip-node-check", false, fmt.Sprintf("starts even if the installed Node.js is older than %s, the oldest supported version", helpers.MinNodeVersion))
+	upCmd.Flags().StringArrayVar(&upCmdPreStart, "pre-start", nil, "runs a shell command before the initial build, e.g. \"npm run migrate\", can be repeated to run several in order, fails if one fails")
+	upCmd.Flags().StringVar(&upCmdOperationOverride, "operation-overrides", "", "layers the settings of "+node.OperationOverridesFileName("<env>")+" in the .wundergraph directory onto the operations, e.g. their caching or auth, selected by the env name")
+	upCmd.Flags().StringVar(&upCmdOverrides, "overrides", "", "remaps schema fields to other data sources or static values with a dev only overrides file, e.g. "+node.DevOverridesFileName)
+	upCmd.Flags().StringVar(&upCmdTLSCert, "tls-cert", "", "serves HTTPS with the PEM certificate at the given path, e.g. created by mkcert, it's reloaded when the file changes")
+	upCmd.Flags().StringVar(&upCmdTLSKey, "tls-key", "", "PEM key of the certificate set by --tls-cert")
+	upCmd.Flags().StringArrayVar(&upCmdFeatureFlags, "flag", nil, "enables the operations below operations/flags/<name>/ of the feature flag with the given name, next to the ones of "+node.FeatureFlagsEnvKey+". Can be repeated")
+	upCmd.Flags().StringVar(&upCmdRunnerCwd, "runner-cwd", "", "working directory of the config and the hooks server instead of the WunderGraph directory, e.g. the root of a monorepo. Generated files stay in the WunderGraph directory")
+	upCmd.Flags().BoolVar(&upCmdReadOnly, "read-only", false, "rejects all mutations with 403, queries and subscriptions are still served")
+	upCmd.Flags().BoolVar(&upCmdHTTP2, "http2", false, "serves HTTP/2 next to HTTP/1.1, negotiated via ALPN with --tls-cert and as h2c over cleartext without it")
+	upCmd.Flags().DurationVar(&upCmdReloadCooldown, "reload-cooldown", 0, "after applying a config, hold back further reloads for this duration and apply the latest changes once it has passed, 0 disables the cooldown")
+	upCmd.Flags().StringVar(&upCmdClientOut, "client-out", "", "also writes the generated TypeScript client to this directory on every build, relative to the WunderGraph dir")
+	upCmd.Flags().StringVar(&upCmdLogRequests, "log-requests", "", fmt.Sprintf("writes an access log line per request in one of %v, health checks and the playground are excluded", node.AccessLogFormats))
+	upCmd.Flags().Lookup("log-requests").NoOptDefVal = node.AccessLogFormatCombined
+	upCmd.Flags().StringVar(&upCmdMaxBundleSize, "max-bundle-size", "", "warns if a bundle is larger than this size, e.g. 2MB, and lists its largest inputs. 'wunderctl generate --max-bundle-size' fails instead")
+	upCmd.Flags().StringVar(&upCmdTargetEnv, "target-env", "", "bundles the config, hooks and operations for one of dev, staging or prod: staging minifies them, prod drops the source maps too. Defines process.env.NODE_ENV and WG_TARGET_ENV in the bundles and sets them for the runners")
+	upCmd.Flags().StringVar(&upCmdPackageManager, "package-manager", "", fmt.Sprintf("resolves the packages imported by the config, hooks and operations like one of %v, detected from the lockfiles by default", bundler.PackageManagers))
+	upCmd.Flags().BoolVar(&upCmdCacheResponses, "cache-responses", false, "caches the responses of all query operations in memory, not only those annotated with @cache(ttl: <seconds>). Purge them with POST /cache/purge")
+	upCmd.Flags().DurationVar(&upCmdCacheTTL, "cache-ttl", responsecache.DefaultTTL, "duration responses of operations without @cache are cached when --cache-responses is set")
+	upCmd.Flags().BoolVar(&upCmdVerboseBundler, "verbose-bundler", false, "logs the warnings of each bundler build and what every import resolved to")
+	upCmd.Flags().BoolVar(&upCmdMetafile, "metafile", false, "writes the esbuild metafile of each bundle to generated/bundle/<name>.meta.json, see 'wunderctl bundle analyze'")
+	upCmd.Flags().StringArrayVar(&upCmdMounts, "mount", nil, "serves the API of an additional generated config under a path prefix, e.g. /v2=./v2.config.json. Can be repeated")
+	upCmd.Flags().StringArrayVar(&upCmdWatchPaths, "watch", nil, "additional file or directory to watch, a change triggers a full rebuild of the config. Relative to the WunderGraph directory, can be repeated")
 
 	rootCmd.AddCommand(upCmd)
 }
+
+// parseLatencyInjection parses values of --inject-latency in the form id=delay[±jitter]
+func parseLatencyInjection(value string) (sourceName string, delay, jitter time.Duration, err error) {
+	sourceName, durations, ok := strings.Cut(value, "=")
+	if !ok || sourceName == "" {
+		return "", 0, 0, fmt.Errorf("invalid latency injection %q, expected <datasource id>=<delay>[±<jitter>]", value)
+	}
+	delayValue, jitterValue, hasJitter := strings.Cut(durations, "±")
+	delay, err = time.ParseDuration(delayValue)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("invalid delay of latency injection %q: %w", value, err)
+	}
+	if hasJitter {
+		jitter, err = time.ParseDuration(jitterValue)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid jitter of latency injection %q: %w", value, err)
+		}
+	}
+	return sourceName, delay, jitter, nil
+}
+
+type headerRoutes struct {
+	header string
+	routes map[string]string
+}
+
+// parseHeaderRoutes parses values of --header-route in the form id:header:value=url and groups
+// them by data source, all routes of a data source must use the same header
+func parseHeaderRoutes(values []string) (map[string]headerRoutes, error) {
+	routings := map[string]headerRoutes{}
+	for _, value := range values {
+		route, routeURL, ok := strings.Cut(value, "=")
+		parts := strings.SplitN(route, ":", 3)
+		if !ok || len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" || routeURL == "" {
+			return nil, fmt.Errorf("invalid header route %q, expected <datasource id>:<header>:<value>=<url>", value)
+		}
+		sourceName, header, headerValue := parts[0], http.CanonicalHeaderKey(parts[1]), parts[2]
+		routing, ok := routings[sourceName]
+		if !ok {
+			routing = headerRoutes{header: header, routes: map[string]string{}}
+		}
+		if routing.header != header {
+			return nil, fmt.Errorf("invalid header route %q, data source %s is routed by %s already", value, sourceName, routing.header)
+		}
+		routing.routes[headerValue] = routeURL
+		routings[sourceName] = routing
+	}
+	return routings, nil
+}
+
+// parseRateLimit parses values of --rate-limit in the form operation=rate[:burst], the burst
+// defaults to the number of requests of the rate
+func parseRateLimit(value string) (operationName string, limit rate.Limit, burst int, err error) {
+	operationName, spec, ok := strings.Cut(value, "=")
+	if !ok || operationName == "" {
+		return "", 0, 0, fmt.Errorf("invalid rate limit %q, expected <operation>=<requests>/<unit>[:<burst>]", value)
+	}
+	rateValue, burstValue, hasBurst := strings.Cut(spec, ":")
+	limit, err = ratelimit.ParseRate(rateValue)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("invalid rate limit %q: %w", value, err)
+	}
+	if hasBurst {
+		burst, err = strconv.Atoi(burstValue)
+		if err != nil || burst < 1 {
+			return "", 0, 0, fmt.Errorf("invalid burst of rate limit %q, must be a positive integer", value)
+		}
+	} else {
+		requests, _, _ := strings.Cut(rateValue, "/")
+		count, _ := strconv.ParseFloat(requests, 64)
+		burst = int(math.Max(1, math.Ceil(count)))
+	}
+	return operationName, limit, burst, nil
+}
+
+// parseOperationTimeout parses values of --operation-timeout in the form operation=duration
+func parseOperationTimeout(value string) (operationName string, timeout time.Duration, err error) {
+	operationName, durationValue, ok := strings.Cut(value, "=")
+	if !ok || operationName == "" {
+		return "", 0, fmt.Errorf("invalid operation timeout %q, expected <operation>=<duration>", value)
+	}
+	timeout, err = time.ParseDuration(durationValue)
+	if err != nil || timeout <= 0 {
+		return "", 0, fmt.Errorf("invalid operation timeout %q, duration must be positive, e.g. 60s", value)
+	}
+	return operationName, timeout, nil
+}
+
+// parseSchedule parses values of --schedule in the form operation:@every <interval>
+func parseSchedule(value string) (operationName string, interval time.Duration, err error) {
+	operationName, spec, ok := strings.Cut(value, ":")
+	if !ok || operationName == "" {
+		return "", 0, fmt.Errorf("invalid schedule %q, expected <operation>:@every <interval>", value)
+	}
+	spec = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(spec), "@every"))
+	interval, err = time.ParseDuration(spec)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid interval of schedule %q: %w", value, err)
+	}
+	if interval <= 0 {
+		return "", 0, fmt.Errorf("invalid interval of schedule %q: must be positive", value)
+	}
+	return operationName, interval, nil
+}
+
+// crashError returns the error that stopped the dev loop, if any
+func crashError(crashed <-chan error) error {
+	select {
+	case err := <-crashed:
+		return err
+	default:
+		return nil
+	}
+}
+
+// startupErrorMessage explains why the node couldn't start and how to fix it
+func startupErrorMessage(err *node.StartupError) string {
+	switch err.Phase {
+	case node.StartupPhaseListener:
+		return fmt.Sprintf("node could not listen on %s. Is another process using the port? Pick a different one with the listen options of the node or WG_NODE_PORT", err.Addr)
+	case node.StartupPhaseHooks:
+		return fmt.Sprintf("node could not use the hooks server at %q. Check the serverUrl of the server options or WG_SERVER_URL", err.HooksServerURL)
+	case node.StartupPhaseTLS:
+		return fmt.Sprintf("node could not load the TLS certificate %s. Check the paths and PEM encoding of --tls-cert and --tls-key", err.File)
+	case node.StartupPhaseConfig:
+		return "node could not apply the generated config, see the errors above"
+	case node.StartupPhaseOptions:
+		return "node could not start with the given flags"
+	default:
+		return "node exited"
+	}
+}
+
+// configBuildError returns the error of the last run of the config runner. Runs
+// stopped by a restart are not an error, they are replaced by a newer run.
+func configBuildError(configRunner *scriptrunner.ScriptRunner) error {
+	if configRunner.ExitCode() == -1 {
+		return nil
+	}
+	return configRunner.Error()
+}
+
+// generateConfig runs the config runner unless useInputsHash is set and the config at
+// configJsonPath was generated from the same inputs
+func generateConfig(ctx context.Context, configRunner *scriptrunner.ScriptRunner, configJsonPath string, inputs helpers.ConfigInputs, useInputsHash bool, compileCacheDir string, tracer *tracing.Recorder) error {
+	if useInputsHash {
+		hash, err := inputs.Hash()
+		if err != nil {
+			log.Debug("could not hash config inputs", zap.Error(err))
+		} else if helpers.ConfigUpToDate(configJsonPath, hash) {
+			log.Info("config inputs unchanged, reusing the generated config, use --force-config to regenerate it",
+				zap.String("inputsHash", hash),
+			)
+			return nil
+		}
+	}
+	// a failed or canceled run must never leave a hash matching a stale config
+	if err := helpers.RemoveConfigInputsHash(configJsonPath); err != nil {
+		log.Debug("could not remove config inputs hash", zap.Error(err))
+	}
+
+	runConfig(ctx, configRunner, compileCacheDir, tracer)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := configBuildError(configRunner); err != nil {
+		log.Error("config build failed, the node keeps serving the last known good config", zap.Error(err))
+		return err
+	}
+	if useInputsHash {
+		// hashed after the run, it adds entries to the introspection cache
+		hash, err := inputs.Hash()
+		if err == nil {
+			err = helpers.WriteConfigInputsHash(configJsonPath, hash)
+		}
+		if err != nil {
+			log.Debug("could not write config inputs hash", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// runPreStartCommand runs command in a shell in the current working directory and waits for it
+func runPreStartCommand(ctx context.Context, command string, env []string) error {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	executable, args := "sh", []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		executable, args = "cmd", []string{"/C", command}
+	}
+	runner := scriptrunner.NewScriptRunner(&scriptrunner.Config{
+		Name:             "pre-start",
+		Executable:       executable,
+		ScriptArgs:       args,
+		ScriptEnv:        env,
+		AbsWorkingDir:    workingDir,
+		Logger:           log,
+		StructuredOutput: !rootFlags.PrettyLogs,
+	})
+	log.Info("running pre-start command", zap.String("command", command))
+	start := time.Now()
+	<-runner.Run(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !runner.Successful() {
+		return fmt.Errorf("pre-start command %q failed with exit code %d", command, runner.ExitCode())
+	}
+	log.Info("pre-start command finished",
+		zap.String("command", command),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
+// runConfig runs the config runner and logs its duration, which depends on
+// the state of the node compile cache
+func runConfig(ctx context.Context, configRunner *scriptrunner.ScriptRunner, compileCacheDir string, tracer *tracing.Recorder) {
+	warm := !disableCache && helpers.IsCacheWarm(compileCacheDir)
+	start := time.Now()
+	endSpan := tracer.Start(tracing.SpanConfigRun, "config-runner")
+	<-configRunner.Run(ctx)
+	endSpan()
+	if ctx.Err() != nil {
+		log.Info("config run canceled",
+			zap.Duration("duration", time.Since(start)),
+		)
+		return
+	}
+	log.Info("config runner finished",
+		zap.Duration("duration", time.Since(start)),
+		zap.Bool("compileCacheWarm", warm),
+	)
+}
+
+// maxInitialBuildBackoff caps the wait between the attempts of bundleInitialConfig
+const maxInitialBuildBackoff = 30 * time.Second
+
+// bundleInitialConfig bundles the config and runs the phases after it, retrying failed
+// attempts up to retries times. The wait between attempts doubles from a second on.
+func bundleInitialConfig(ctx context.Context, configBundler *bundler.Bundler, retries int) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := configBundler.BundleContext(ctx)
+		if err == nil || attempt > retries || ctx.Err() != nil {
+			return err
+		}
+		log.Warn("initial build failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("maxAttempts", retries+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxInitialBuildBackoff {
+			backoff = maxInitialBuildBackoff
+		}
+	}
+}
+
+// watchBranchSwitch calls onSwitch with the previous and the new ref whenever the HEAD file
+// at gitHead changes, e.g. on checkout. The file is polled, git replaces it instead of writing it.
+func watchBranchSwitch(ctx context.Context, gitHead string, onSwitch func(from, to string)) {
+	ref, err := files.ReadGitRef(gitHead)
+	if err != nil {
+		log.Warn("could not read git HEAD", zap.String("path", gitHead), zap.Error(err))
+	}
+	headWatcher := watcher.NewWatcher("git-head", &watcher.Config{
+		WatchPaths: []*watcher.WatchPath{
+			{Path: gitHead},
+		},
+		Backend: watcher.BackendPolling,
+	}, log)
+	err = headWatcher.Watch(ctx, func(paths []string) error {
+		current, err := files.ReadGitRef(gitHead)
+		// a commit on the checked out branch doesn't change the ref
+		if err != nil || current == ref {
+			return nil
+		}
+		previous := ref
+		ref = current
+		onSwitch(previous, current)
+		return nil
+	})
+	if err != nil {
+		log.Error("watcher",
+			zap.String("watcher", "git-head"),
+			zap.Error(err),
+		)
+	}
+}
+
+// toggleWatchPause pauses or resumes watching whenever a line with p is read from r, the reader
+// of the terminal is never closed so this runs until the process exits
+func toggleWatchPause(r io.Reader, pause *watcher.Pause) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "p" {
+			continue
+		}
+		if pause.Toggle() {
+			log.Info("watching paused, changes are rebuilt once on resume")
+		} else {
+			log.Info("watching resumed")
+		}
+	}
+}
+
+// maxEventLogSummaryErrors limits the errors printed on exit, all of them are in the dumped file
+const maxEventLogSummaryErrors = 10
+
+// dumpEventLog writes the events of the run to path and prints a summary of the last errors
+func dumpEventLog(eventLog *logging.EventLog, path string) {
+	if err := eventLog.Dump(path); err != nil {
+		fmt.Fprintf(os.Stderr, "could not write events of the last run to %s: %s\n", path, err)
+		return
+	}
+	errs := eventLog.Errors()
+	fmt.Fprintf(os.Stderr, "%d events of the last run written to %s, %d errors\n", len(eventLog.Entries()), path, len(errs))
+	if len(errs) > maxEventLogSummaryErrors {
+		errs = errs[len(errs)-maxEventLogSummaryErrors:]
+	}
+	for _, entry := range errs {
+		fmt.Fprintf(os.Stderr, "  %s\n", entry)
+	}
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/wundergraph/cli/helpers"
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/webhooks"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+var (
+	webhookTestPayload  string
+	webhookTestSecret   string
+	webhookTestProvider string
+	webhookTestNodeURL  string
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Subcommand to work with webhooks",
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Sends a signed test payload to a webhook of the running node",
+	Long: `Signs the payload and sends it to /webhooks/<name> of the node started by 'wunderctl up',
+then prints the response. Without --provider, the payload is signed like the verifier configured
+for the webhook expects it, with the secret of the verifier unless --secret is given. Use
+--provider to sign like GitHub or Stripe when the webhook verifies the signature itself.`,
+	Example: `wunderctl webhook test github --payload push.json --secret s3cr3t`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		wunderGraphDir, err := files.FindWunderGraphDir(_wunderGraphDirConfig)
+		if err != nil {
+			return err
+		}
+		paths, err := webhooks.GetWebhooks(wunderGraphDir)
+		if err != nil {
+			return fmt.Errorf("could not find webhooks: %w", err)
+		}
+		var names []string
+		found := false
+		for _, path := range paths {
+			names = append(names, webhooks.Name(path))
+			found = found || webhooks.Name(path) == name
+		}
+		if !found {
+			return fmt.Errorf("unknown webhook %q, available webhooks are %v", name, names)
+		}
+		payload, err := os.ReadFile(webhookTestPayload)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(cmd.Context(), http.MethodPost, strings.TrimSuffix(webhookTestNodeURL, "/")+"/webhooks/"+name, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		headerName, headerValue, err := webhookSignatureHeader(filepath.Join(wunderGraphDir, "generated", configJsonFilename), name, payload)
+		if err != nil {
+			return err
+		}
+		if headerName != "" {
+			req.Header.Set(headerName, headerValue)
+			fmt.Printf("%s: %s\n", headerName, headerValue)
+		} else {
+			fmt.Println("sending unsigned payload, the webhook has no verifier and no --provider was given")
+		}
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("could not reach the node, is 'wunderctl up' running? %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		fmt.Println(resp.Status)
+		if len(body) != 0 {
+			fmt.Println(string(body))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("the webhook rejected the signature")
+		}
+		return nil
+	},
+}
+
+// webhookSignatureHeader returns the signature header for payload, it is empty if
+// the payload is sent unsigned
+func webhookSignatureHeader(configJsonPath, name string, payload []byte) (string, string, error) {
+	var verifier *wgpb.WebhookVerifier
+	if config, err := helpers.LoadConfig(configJsonPath); err == nil {
+		for _, webhook := range config.GetApi().GetWebhooks() {
+			if webhook.GetName() == name {
+				verifier = webhook.GetVerifier()
+			}
+		}
+	}
+	secret := webhookTestSecret
+	if secret == "" && verifier != nil {
+		secret = loadvariable.String(verifier.GetSecret())
+	}
+	if webhookTestProvider == "" && verifier == nil && secret == "" {
+		return "", "", nil
+	}
+	if secret == "" {
+		return "", "", fmt.Errorf("no secret to sign the payload with, pass --secret")
+	}
+	if webhookTestProvider == "" && verifier != nil {
+		if verifier.GetKind() != wgpb.WebhookVerifierKind_HMAC_SHA256 {
+			return "", "", fmt.Errorf("unsupported verifier %s of webhook %q", verifier.GetKind(), name)
+		}
+		return verifier.GetSignatureHeader(), verifier.GetSignatureHeaderPrefix() + webhooks.HMACSHA256([]byte(secret), payload), nil
+	}
+	provider := webhooks.Provider(webhookTestProvider)
+	if provider == "" {
+		provider = webhooks.ProviderGitHub
+	}
+	return webhooks.SignatureHeader(provider, []byte(secret), payload, time.Now())
+}
+
+func init() {
+	webhookTestCmd.Flags().StringVar(&webhookTestPayload, "payload", "", "file with the payload to send")
+	webhookTestCmd.Flags().StringVar(&webhookTestSecret, "secret", "", "secret to sign the payload with, defaults to the secret of the webhook verifier")
+	webhookTestCmd.Flags().StringVar(&webhookTestProvider, "provider", "", fmt.Sprintf("sign like one of %v instead of like the webhook verifier", webhooks.Providers))
+	webhookTestCmd.Flags().StringVar(&webhookTestNodeURL, "node-url", "http://localhost:9991", "URL of the node started by 'wunderctl up'")
+	_ = webhookTestCmd.MarkFlagRequired("payload")
+	webhookCmd.AddCommand(webhookTestCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
@@ -14,3 +14,28 @@ func GlobalWunderGraphCacheDir() (string, error) {
 	}
 	return filepath.Join(cacheDir, "wundergraph"), nil
 }
+
+// ProcessesDir returns the directory of the PID files of all processes started by wunderctl
+func ProcessesDir() (string, error) {
+	cacheDir, err := GlobalWunderGraphCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "processes"), nil
+}
+
+// NodeCompileCacheEnvKey configures the directory of the V8 compile cache of node >= 22.1,
+// older versions ignore it
+const NodeCompileCacheEnvKey = "NODE_COMPILE_CACHE"
+
+// NodeCompileCacheDir returns the directory of the compile cache shared by
+// all node processes started for the WunderGraph directory
+func NodeCompileCacheDir(wunderGraphDir string) string {
+	return filepath.Join(wunderGraphDir, "cache", "node-compile")
+}
+
+// IsCacheWarm returns true if the cache directory exists and contains entries
+func IsCacheWarm(cacheDir string) bool {
+	entries, err := os.ReadDir(cacheDir)
+	return err == nil && len(entries) > 0
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// LoadConfig reads and decodes the generated wundergraph.config.json
+func LoadConfig(configJsonPath string) (*wgpb.WunderGraphConfiguration, error) {
+	data, err := os.ReadFile(configJsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read configuration file %s: %w", configJsonPath, err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("config file is empty")
+	}
+	var graphConfig wgpb.WunderGraphConfiguration
+	if err := json.Unmarshal(data, &graphConfig); err != nil {
+		return nil, fmt.Errorf("could not decode configuration file %s: %w", configJsonPath, err)
+	}
+	return &graphConfig, nil
+}
@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/wundergraph/pkg/watcher"
+)
+
+// ConfigInputsHashFilename is stored next to wundergraph.config.json and holds the hash
+// of the inputs of the config runner that generated it
+const ConfigInputsHashFilename = "wundergraph.config.inputs.sha256"
+
+// ConfigInputs is everything the generated config depends on
+type ConfigInputs struct {
+	// Paths are files or directories, missing ones are part of the hash as well
+	Paths []string
+	// Env are the environment variables of the config runner
+	Env []string
+}
+
+// Hash returns the hash of the content of all paths and the environment
+func (c ConfigInputs) Hash() (string, error) {
+	h := sha256.New()
+	env := append([]string(nil), c.Env...)
+	sort.Strings(env)
+	for _, value := range env {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+	for _, root := range c.Paths {
+		h.Write([]byte(root))
+		h.Write([]byte{0})
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			h.Write([]byte(filepath.ToSlash(rel)))
+			h.Write([]byte{0})
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(h, f)
+			return err
+		})
+		if errors.Is(err, os.ErrNotExist) {
+			h.Write([]byte("missing"))
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WatchedPaths returns the paths of watchPaths, e.g. to make everything a watcher rebuilds for
+// an input of the config
+func WatchedPaths(watchPaths []*watcher.WatchPath) []string {
+	paths := make([]string, len(watchPaths))
+	for i, watchPath := range watchPaths {
+		paths[i] = watchPath.Path
+	}
+	return paths
+}
+
+func configInputsHashPath(configJsonPath string) string {
+	return filepath.Join(filepath.Dir(configJsonPath), ConfigInputsHashFilename)
+}
+
+// ConfigUpToDate returns true if the config at configJsonPath exists and was generated from inputs with the given hash
+func ConfigUpToDate(configJsonPath, hash string) bool {
+	if info, err := os.Stat(configJsonPath); err != nil || info.Size() == 0 {
+		return false
+	}
+	data, err := os.ReadFile(configInputsHashPath(configJsonPath))
+	return err == nil && strings.TrimSpace(string(data)) == hash
+}
+
+// WriteConfigInputsHash records the hash of the inputs the config at configJsonPath was generated from
+func WriteConfigInputsHash(configJsonPath, hash string) error {
+	return os.WriteFile(configInputsHashPath(configJsonPath), []byte(hash+"\n"), 0644)
+}
+
+// RemoveConfigInputsHash invalidates the recorded hash, e.g. before the config is regenerated
+func RemoveConfigInputsHash(configJsonPath string) error {
+	err := os.Remove(configInputsHashPath(configJsonPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/watcher"
+)
+
+func TestConfigInputs(t *testing.T) {
+	dir := t.TempDir()
+	operationsDir := filepath.Join(dir, "operations")
+	require.NoError(t, os.MkdirAll(operationsDir, os.ModePerm))
+	operationPath := filepath.Join(operationsDir, "Users.graphql")
+	require.NoError(t, os.WriteFile(operationPath, []byte("query Users { users { id } }"), 0644))
+
+	inputs := ConfigInputs{
+		Paths: []string{operationsDir, filepath.Join(dir, "fragments")},
+		Env:   []string{"B=2", "A=1"},
+	}
+	hash, err := inputs.Hash()
+	require.NoError(t, err)
+
+	reordered := ConfigInputs{Paths: inputs.Paths, Env: []string{"A=1", "B=2"}}
+	same, err := reordered.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	require.NoError(t, os.WriteFile(operationPath, []byte("query Users { users { id name } }"), 0644))
+	changed, err := inputs.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	configJsonPath := filepath.Join(dir, "generated", "wundergraph.config.json")
+	assert.False(t, ConfigUpToDate(configJsonPath, changed))
+	require.NoError(t, os.MkdirAll(filepath.Dir(configJsonPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(configJsonPath, []byte(`{}`), 0644))
+	require.NoError(t, WriteConfigInputsHash(configJsonPath, changed))
+	assert.True(t, ConfigUpToDate(configJsonPath, changed))
+	assert.False(t, ConfigUpToDate(configJsonPath, hash))
+
+	require.NoError(t, RemoveConfigInputsHash(configJsonPath))
+	require.NoError(t, RemoveConfigInputsHash(configJsonPath))
+	assert.False(t, ConfigUpToDate(configJsonPath, changed))
+}
+
+func TestConfigInputsWatchedPaths(t *testing.T) {
+	dir := t.TempDir()
+	dataSourcesPath := filepath.Join(dir, "datasources.yaml")
+	registryPath := filepath.Join(dir, "registry.json")
+	require.NoError(t, os.WriteFile(dataSourcesPath, []byte("countries: https://countries.trevorblades.com/"), 0644))
+	require.NoError(t, os.WriteFile(registryPath, []byte(`{"sources":[]}`), 0644))
+
+	// files which aren't imported by the config, like --watch paths and the data source registry
+	inputs := ConfigInputs{Paths: WatchedPaths([]*watcher.WatchPath{
+		{Path: filepath.Join(dir, "operations"), Optional: true},
+		{Path: registryPath},
+		{Path: dataSourcesPath},
+	})}
+	hash, err := inputs.Hash()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(dataSourcesPath, []byte("countries: http://localhost:4000/"), 0644))
+	changed, err := inputs.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	require.NoError(t, os.WriteFile(registryPath, []byte(`{"sources":[{"name":"countries"}]}`), 0644))
+	registryChanged, err := inputs.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, changed, registryChanged)
+}
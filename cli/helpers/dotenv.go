@@ -0,0 +1,248 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadEnvFiles sets the variables of the given .env files in the process environment.
+// Variables that are already set are kept, the first file defining a variable wins.
+// Values can reference other variables as ${VAR} or $VAR, resolved against the files and
+// the process environment, and provide a default as ${VAR:-default} that's used if the
+// variable is unset or empty. Single quoted values are not interpolated, \$ is a literal $.
+// Missing files are reported as *fs.PathError.
+func LoadEnvFiles(filenames ...string) error {
+	vars, err := readEnvFiles(filenames...)
+	if err != nil {
+		return err
+	}
+	for _, v := range vars.order {
+		if _, ok := os.LookupEnv(v); ok {
+			continue
+		}
+		value, err := vars.resolve(v, nil)
+		if err != nil {
+			return err
+		}
+		if err := os.Setenv(v, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type envValue struct {
+	raw         string
+	interpolate bool
+	file        string
+}
+
+type envVars struct {
+	values   map[string]envValue
+	resolved map[string]string
+	order    []string
+}
+
+func readEnvFiles(filenames ...string) (*envVars, error) {
+	vars := &envVars{
+		values:   map[string]envValue{},
+		resolved: map[string]string{},
+	}
+	for _, filename := range filenames {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		lineNumber := 0
+		for scanner.Scan() {
+			lineNumber++
+			key, value, ok, err := parseEnvLine(scanner.Text())
+			if err != nil {
+				_ = f.Close()
+				return nil, fmt.Errorf("%s:%d: %w", filename, lineNumber, err)
+			}
+			if !ok {
+				continue
+			}
+			if _, exists := vars.values[key]; exists {
+				continue
+			}
+			value.file = filename
+			vars.values[key] = value
+			vars.order = append(vars.order, key)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// parseEnvLine parses a KEY=VALUE line, it returns false for empty lines and comments
+func parseEnvLine(line string) (key string, value envValue, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", envValue{}, false, nil
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+	key, rawValue, found := strings.Cut(line, "=")
+	if !found {
+		return "", envValue{}, false, fmt.Errorf("invalid line %q, expected KEY=VALUE", line)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", envValue{}, false, fmt.Errorf("invalid line %q, missing variable name", line)
+	}
+	rawValue = strings.TrimSpace(rawValue)
+	switch {
+	case len(rawValue) > 1 && rawValue[0] == '\'' && rawValue[len(rawValue)-1] == '\'':
+		return key, envValue{raw: rawValue[1 : len(rawValue)-1]}, true, nil
+	case len(rawValue) > 1 && rawValue[0] == '"' && rawValue[len(rawValue)-1] == '"':
+		return key, envValue{raw: unescapeDoubleQuoted(rawValue[1 : len(rawValue)-1]), interpolate: true}, true, nil
+	default:
+		// unquoted values end at an inline comment
+		if i := strings.Index(rawValue, " #"); i >= 0 {
+			rawValue = strings.TrimSpace(rawValue[:i])
+		}
+		return key, envValue{raw: rawValue, interpolate: true}, true, nil
+	}
+}
+
+func unescapeDoubleQuoted(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '$':
+			// kept escaped, interpolation turns it into a literal $
+			b.WriteString(`\$`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// resolve returns the interpolated value of the variable key, stack holds the variables
+// being resolved to detect circular references
+func (e *envVars) resolve(key string, stack []string) (string, error) {
+	if value, ok := e.resolved[key]; ok {
+		return value, nil
+	}
+	for i, name := range stack {
+		if name == key {
+			return "", fmt.Errorf("circular reference in %s: %s", e.values[key].file, strings.Join(append(stack[i:], key), " -> "))
+		}
+	}
+	value := e.values[key]
+	if !value.interpolate {
+		e.resolved[key] = value.raw
+		return value.raw, nil
+	}
+	interpolated, err := e.interpolate(value.raw, append(stack, key))
+	if err != nil {
+		return "", err
+	}
+	e.resolved[key] = interpolated
+	return interpolated, nil
+}
+
+// lookup returns the value of a referenced variable, the process environment takes
+// precedence like it does when setting the variables
+func (e *envVars) lookup(name string, stack []string) (string, bool, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true, nil
+	}
+	if _, ok := e.values[name]; !ok {
+		return "", false, nil
+	}
+	value, err := e.resolve(name, stack)
+	return value, true, err
+}
+
+func (e *envVars) interpolate(s string, stack []string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		if s[i+1] == '{' {
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated reference %q", s[i:])
+			}
+			name, defaultValue, hasDefault := strings.Cut(s[i+2:end], ":-")
+			value, ok, err := e.lookup(name, stack)
+			if err != nil {
+				return "", err
+			}
+			if hasDefault && (!ok || value == "") {
+				if value, err = e.interpolate(defaultValue, stack); err != nil {
+					return "", err
+				}
+			}
+			b.WriteString(value)
+			i = end
+			continue
+		}
+		end := i + 1
+		for end < len(s) && isEnvNameChar(s[end]) {
+			end++
+		}
+		if end == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+		value, _, err := e.lookup(s[i+1:end], stack)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		i = end - 1
+	}
+	return b.String(), nil
+}
+
+// closingBrace returns the index of the brace closing a reference starting at start,
+// defaults may contain references themselves
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isEnvNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -3,8 +3,18 @@ package helpers
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
 )
 
+// secretEnvPattern matches the names of variables whose values are masked by RedactEnv
+var secretEnvPattern = regexp.MustCompile(`(?i)(SECRET|TOKEN|PASS|KEY|PRIVATE|CREDENTIAL|AUTH|COOKIE|SESSION|DSN|DATABASE_URL)`)
+
+const redactedEnvValue = "[REDACTED]"
+
 // CliEnv expands env with cli specific env vars - to been able to resend it back to cli
 // from js SDK
 func CliEnv(flags RootFlags) []string {
@@ -14,3 +24,31 @@ func CliEnv(flags RootFlags) []string {
 		fmt.Sprintf("%s=%t", "WG_CLI_LOG_PRETTY", flags.PrettyLogs),
 		fmt.Sprintf("%s=%t", "WG_DEBUG_MODE", flags.DebugMode))
 }
+
+// RedactEnv returns env as the child process sees it: sorted by name, with the last
+// definition of a variable winning and the values of secrets masked
+func RedactEnv(env []string) []string {
+	values := make(map[string]string, len(env))
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if secretEnvPattern.MatchString(name) && value != "" {
+			value = redactedEnvValue
+		}
+		values[name] = value
+	}
+	redacted := make([]string, 0, len(values))
+	for name, value := range values {
+		redacted = append(redacted, name+"="+value)
+	}
+	sort.Strings(redacted)
+	return redacted
+}
+
+// LogRunnerEnv logs the redacted environment passed to the runner name, one variable per line
+func LogRunnerEnv(log *zap.Logger, name string, env []string) {
+	redacted := RedactEnv(env)
+	log.Info("runner environment", zap.String("runner", name), zap.Int("variables", len(redacted)))
+	for _, entry := range redacted {
+		log.Info(entry, zap.String("runner", name))
+	}
+}
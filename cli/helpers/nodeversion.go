@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinNodeVersion is the oldest Node.js version the SDK and the runners support, keep it in
+// sync with the engines of package.json
+var MinNodeVersion = NodeVersion{Major: 16}
+
+// nodeVersionTimeout bounds `node --version`, it answers without loading any script
+const nodeVersionTimeout = 10 * time.Second
+
+// NodeVersion is a version of Node.js as printed by `node --version`
+type NodeVersion struct {
+	Major, Minor, Patch int
+}
+
+func (v NodeVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less returns true if v is older than other
+func (v NodeVersion) Less(other NodeVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// ParseNodeVersion parses versions like v18.17.0, pre-release suffixes are ignored
+func ParseNodeVersion(s string) (NodeVersion, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	trimmed, _, _ = strings.Cut(trimmed, "-")
+	parts := strings.Split(trimmed, ".")
+	if len(parts) != 3 {
+		return NodeVersion{}, fmt.Errorf("invalid Node.js version %q", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return NodeVersion{}, fmt.Errorf("invalid Node.js version %q", s)
+		}
+		numbers[i] = n
+	}
+	return NodeVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// CheckNodeVersion runs `node --version` and returns an error if node can't be run or is
+// older than MinNodeVersion
+func CheckNodeVersion(ctx context.Context) (NodeVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, nodeVersionTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "node", "--version").Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return NodeVersion{}, fmt.Errorf("node not found, install Node.js %s or newer", MinNodeVersion)
+		}
+		return NodeVersion{}, fmt.Errorf("could not determine the Node.js version: %w", err)
+	}
+	version, err := ParseNodeVersion(string(output))
+	if err != nil {
+		return NodeVersion{}, err
+	}
+	if version.Less(MinNodeVersion) {
+		return version, fmt.Errorf("Node.js %s is not supported, WunderGraph requires %s or newer", version, MinNodeVersion)
+	}
+	return version, nil
+}
@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNodeVersion(t *testing.T) {
+	version, err := ParseNodeVersion("v18.17.1\n")
+	require.NoError(t, err)
+	assert.Equal(t, NodeVersion{Major: 18, Minor: 17, Patch: 1}, version)
+
+	version, err = ParseNodeVersion("v21.0.0-nightly20230801")
+	require.NoError(t, err)
+	assert.Equal(t, NodeVersion{Major: 21}, version)
+
+	_, err = ParseNodeVersion("v18")
+	assert.Error(t, err)
+	_, err = ParseNodeVersion("node: command not found")
+	assert.Error(t, err)
+}
+
+func TestNodeVersionLess(t *testing.T) {
+	assert.True(t, NodeVersion{Major: 14, Minor: 21, Patch: 3}.Less(MinNodeVersion))
+	assert.False(t, NodeVersion{Major: 16}.Less(MinNodeVersion))
+	assert.False(t, NodeVersion{Major: 18, Minor: 2}.Less(MinNodeVersion))
+	assert.True(t, NodeVersion{Major: 18, Minor: 2}.Less(NodeVersion{Major: 18, Minor: 2, Patch: 1}))
+}
@@ -37,6 +37,28 @@ func ServerPortFromConfig(configJsonPath string) (int, error) {
 	return newPort, nil
 }
 
+// NodePortFromConfig returns the port the node listens on according to the config
+func NodePortFromConfig(configJsonPath string) (int, error) {
+	data, err := os.ReadFile(configJsonPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var graphConfig struct {
+		Api *struct {
+			NodeOptions *wgpb.NodeOptions `json:"nodeOptions,omitempty"`
+		} `json:"api,omitempty"`
+	}
+	if err := json.Unmarshal(data, &graphConfig); err != nil {
+		return 0, err
+	}
+	if graphConfig.Api == nil || graphConfig.Api.NodeOptions.GetListen() == nil {
+		return 0, fmt.Errorf("config has no node listener")
+	}
+
+	return loadvariable.Int(graphConfig.Api.NodeOptions.Listen.Port), nil
+}
+
 // KillExistingHooksProcess kills the existing hooks process before we start the new one
 // some IDEs, like Goland, don't send a SIGINT to the process group
 // this leads to the middleware hooks server (sub-process) not being killed
@@ -0,0 +1,163 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+)
+
+// DefaultScaffoldGraphQLURL is the data source of scaffolded projects unless another one is given
+const DefaultScaffoldGraphQLURL = "https://countries.trevorblades.com/"
+
+// ScaffoldDataSourceKinds lists the data sources Scaffold can configure, an openapi
+// source is the path of a spec file, databases take a connection URL
+var ScaffoldDataSourceKinds = []string{"graphql", "openapi", "postgresql", "mysql", "sqlite", "sqlserver", "mongodb", "planetscale"}
+
+// ScaffoldOptions configures the project created by Scaffold
+type ScaffoldOptions struct {
+	// DataSourceKind is one of ScaffoldDataSourceKinds, defaults to graphql
+	DataSourceKind string
+	// DataSourceURL defaults to DefaultScaffoldGraphQLURL for graphql data sources
+	DataSourceURL string
+	// Namespace is the API namespace of the data source, defaults to the kind
+	Namespace string
+	// Server adds a server entry point for hooks
+	Server bool
+}
+
+// Scaffold creates a minimal WunderGraph project in dir that 'wunderctl up' can run, it
+// returns the created files. It fails if dir already contains a config entry point.
+func Scaffold(dir string, opts ScaffoldOptions) ([]string, error) {
+	if files.FileExists(filepath.Join(dir, files.WunderGraphConfigFilename)) {
+		return nil, fmt.Errorf("%s already exists in %s", files.WunderGraphConfigFilename, dir)
+	}
+	if opts.DataSourceKind == "" {
+		opts.DataSourceKind = "graphql"
+	}
+	if opts.DataSourceURL == "" {
+		if opts.DataSourceKind != "graphql" {
+			return nil, fmt.Errorf("the %s data source requires a URL", opts.DataSourceKind)
+		}
+		opts.DataSourceURL = DefaultScaffoldGraphQLURL
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = opts.DataSourceKind
+		if opts.DataSourceURL == DefaultScaffoldGraphQLURL {
+			opts.Namespace = "countries"
+		}
+	}
+	introspection, err := scaffoldIntrospection(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var config strings.Builder
+	config.WriteString("import { configureWunderGraphApplication, cors, introspect, templates } from '@wundergraph/sdk';\n")
+	if opts.Server {
+		config.WriteString("import server from './wundergraph.server';\n")
+	}
+	fmt.Fprintf(&config, `
+const %s = %s;
+
+configureWunderGraphApplication({
+	apis: [%s],
+`, opts.Namespace, introspection, opts.Namespace)
+	if opts.Server {
+		config.WriteString("\tserver,\n")
+	}
+	config.WriteString(`	codeGenerators: [
+		{
+			templates: [...templates.typescript.all],
+		},
+	],
+	cors: {
+		...cors.allowAll,
+		allowedOrigins: ['http://localhost:3000'],
+	},
+});
+`)
+
+	scaffolded := map[string]string{
+		files.WunderGraphConfigFilename: config.String(),
+		".gitignore":                    "generated\ncache\n",
+	}
+	if opts.Server {
+		scaffolded["wundergraph.server.ts"] = scaffoldServer
+	}
+	if opts.DataSourceURL == DefaultScaffoldGraphQLURL {
+		scaffolded[filepath.Join("operations", "Continents.graphql")] = fmt.Sprintf(scaffoldContinentsOperation, opts.Namespace)
+	} else {
+		// operations depend on the schema of the data source
+		scaffolded[filepath.Join("operations", ".gitkeep")] = ""
+	}
+
+	created := make([]string, 0, len(scaffolded))
+	for name, content := range scaffolded {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return created, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	return created, nil
+}
+
+func scaffoldIntrospection(opts ScaffoldOptions) (string, error) {
+	if !validIdentifier(opts.Namespace) {
+		return "", fmt.Errorf("invalid namespace %q, expected letters, digits and underscores", opts.Namespace)
+	}
+	namespace := jsString(opts.Namespace)
+	url := jsString(opts.DataSourceURL)
+	switch opts.DataSourceKind {
+	case "graphql":
+		return fmt.Sprintf("introspect.graphql({\n\tapiNamespace: %s,\n\turl: %s,\n})", namespace, url), nil
+	case "openapi":
+		return fmt.Sprintf("introspect.openApi({\n\tapiNamespace: %s,\n\tsource: {\n\t\tkind: 'file',\n\t\tfilePath: %s,\n\t},\n})", namespace, url), nil
+	case "postgresql", "mysql", "sqlite", "sqlserver", "mongodb", "planetscale":
+		return fmt.Sprintf("introspect.%s({\n\tapiNamespace: %s,\n\tdatabaseURL: %s,\n})", opts.DataSourceKind, namespace, url), nil
+	}
+	return "", fmt.Errorf("unknown data source %q, supported data sources are %v", opts.DataSourceKind, ScaffoldDataSourceKinds)
+}
+
+// jsString returns s as a single quoted JavaScript string
+func jsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+func validIdentifier(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+const scaffoldServer = `import { configureWunderGraphServer } from '@wundergraph/sdk/server';
+import type { HooksConfig } from './generated/wundergraph.hooks';
+import type { InternalClient } from './generated/wundergraph.internal.client';
+
+export default configureWunderGraphServer<HooksConfig, InternalClient>(() => ({
+	hooks: {
+		queries: {},
+		mutations: {},
+	},
+}));
+`
+
+const scaffoldContinentsOperation = `query Continents {
+	%s_continents {
+		code
+		name
+	}
+}
+`
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+)
+
+func TestScaffold(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".wundergraph")
+	created, err := Scaffold(dir, ScaffoldOptions{Server: true})
+	require.NoError(t, err)
+	assert.Len(t, created, 4)
+
+	configPath, err := files.CodeFilePath(dir, files.WunderGraphConfigFilename)
+	require.NoError(t, err)
+	config, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "url: '"+DefaultScaffoldGraphQLURL+"'")
+	assert.Contains(t, string(config), "apis: [countries]")
+	assert.Contains(t, string(config), "import server from './wundergraph.server';")
+	_, err = files.CodeFilePath(dir, "wundergraph.server.ts")
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "operations", "Continents.graphql"))
+
+	_, err = Scaffold(dir, ScaffoldOptions{})
+	assert.Error(t, err, "existing project")
+}
+
+func TestScaffoldDatabase(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Scaffold(dir, ScaffoldOptions{DataSourceKind: "postgresql"})
+	assert.Error(t, err, "missing URL")
+
+	_, err = Scaffold(dir, ScaffoldOptions{DataSourceKind: "postgresql", DataSourceURL: "postgresql://localhost:5432/db", Namespace: "db"})
+	require.NoError(t, err)
+	config, err := os.ReadFile(filepath.Join(dir, files.WunderGraphConfigFilename))
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "introspect.postgresql({\n\tapiNamespace: 'db',\n\tdatabaseURL: 'postgresql://localhost:5432/db',\n})")
+	assert.NotContains(t, string(config), "server")
+	assert.FileExists(t, filepath.Join(dir, "operations", ".gitkeep"))
+
+	_, err = Scaffold(t.TempDir(), ScaffoldOptions{DataSourceKind: "redis", DataSourceURL: "redis://localhost"})
+	assert.Error(t, err)
+	_, err = Scaffold(t.TempDir(), ScaffoldOptions{Namespace: "my-api"})
+	assert.Error(t, err)
+}
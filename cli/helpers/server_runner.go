@@ -2,9 +2,13 @@ package helpers
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
 
 	"go.uber.org/zap"
 
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/processes"
 	"github.com/wundergraph/wundergraph/pkg/scriptrunner"
 )
 
@@ -13,9 +17,21 @@ type ServerRunConfig struct {
 	ServerScriptFile  string
 	Production        bool
 	Env               []string
+	// WorkingDir is the absolute working directory of the server, defaults to WunderGraphDirAbs.
+	// A relative ServerScriptFile stays relative to WunderGraphDirAbs.
+	WorkingDir string
+	// LogWriter additionally receives the output of the server, if set
+	LogWriter io.Writer
+	// Registry tracks the server process, if set
+	Registry *processes.Registry
+	// Port the server listens on, 0 if unknown
+	Port int
+	// StructuredOutput logs output of the server that isn't JSON, see scriptrunner.Config
+	StructuredOutput bool
 }
 
-func NewServerRunner(log *zap.Logger, cfg *ServerRunConfig) *scriptrunner.ScriptRunner {
+// ScriptEnv returns the environment the server runs with
+func (cfg *ServerRunConfig) ScriptEnv() []string {
 	hooksEnv := []string{
 		"START_HOOKS_SERVER=true",
 		fmt.Sprintf("WG_DIR_ABS=%s", cfg.WunderGraphDirAbs),
@@ -24,15 +40,54 @@ func NewServerRunner(log *zap.Logger, cfg *ServerRunConfig) *scriptrunner.Script
 	if cfg.Production {
 		hooksEnv = append(hooksEnv, "NODE_ENV=production")
 	}
+	return append(append([]string(nil), cfg.Env...), hooksEnv...)
+}
+
+// workingDirAndScript returns the working directory of the server and the script to run in it
+func (cfg *ServerRunConfig) workingDirAndScript() (string, string) {
+	if cfg.WorkingDir == "" {
+		return cfg.WunderGraphDirAbs, cfg.ServerScriptFile
+	}
+	scriptFile := cfg.ServerScriptFile
+	if !filepath.IsAbs(scriptFile) {
+		scriptFile = filepath.Join(cfg.WunderGraphDirAbs, scriptFile)
+	}
+	return cfg.WorkingDir, scriptFile
+}
+
+func NewServerRunner(log *zap.Logger, cfg *ServerRunConfig) *scriptrunner.ScriptRunner {
+	var ports []int
+	if cfg.Port != 0 {
+		ports = append(ports, cfg.Port)
+	}
+
+	workingDir, scriptFile := cfg.workingDirAndScript()
 
 	hookServerRunner := scriptrunner.NewScriptRunner(&scriptrunner.Config{
-		Name:          "hooks-server-runner",
-		Executable:    "node",
-		AbsWorkingDir: cfg.WunderGraphDirAbs,
-		ScriptArgs:    []string{cfg.ServerScriptFile},
-		Logger:        log,
-		ScriptEnv:     append(cfg.Env, hooksEnv...),
+		Name:             "hooks-server-runner",
+		Executable:       "node",
+		AbsWorkingDir:    workingDir,
+		ScriptArgs:       []string{scriptFile},
+		Logger:           log,
+		ScriptEnv:        cfg.ScriptEnv(),
+		LogWriter:        cfg.LogWriter,
+		Registry:         cfg.Registry,
+		Ports:            ports,
+		StructuredOutput: cfg.StructuredOutput,
 	})
 
 	return hookServerRunner
 }
+
+// RunnerWorkingDir returns the absolute path of dir, the working directory of the config runners
+// and the server set by up --runner-cwd. It must exist.
+func RunnerWorkingDir(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if !files.DirectoryExists(absDir) {
+		return "", fmt.Errorf("invalid --runner-cwd %s: no such directory", dir)
+	}
+	return absDir, nil
+}
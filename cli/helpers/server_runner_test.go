@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerWorkingDir(t *testing.T) {
+	root := t.TempDir()
+	dir, err := RunnerWorkingDir(root)
+	require.NoError(t, err)
+	assert.Equal(t, root, dir)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir, err = RunnerWorkingDir(".")
+	require.NoError(t, err)
+	assert.Equal(t, wd, dir)
+
+	_, err = RunnerWorkingDir(filepath.Join(root, "missing"))
+	assert.ErrorContains(t, err, "no such directory")
+
+	file := filepath.Join(root, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	_, err = RunnerWorkingDir(file)
+	assert.Error(t, err)
+}
+
+func TestServerRunConfigWorkingDir(t *testing.T) {
+	cfg := &ServerRunConfig{WunderGraphDirAbs: "/project/.wundergraph", ServerScriptFile: "generated/bundle/server.js"}
+	dir, script := cfg.workingDirAndScript()
+	assert.Equal(t, "/project/.wundergraph", dir)
+	assert.Equal(t, "generated/bundle/server.js", script)
+
+	// the script stays in the WunderGraph directory
+	cfg.WorkingDir = "/project"
+	dir, script = cfg.workingDirAndScript()
+	assert.Equal(t, "/project", dir)
+	assert.Equal(t, "/project/.wundergraph/generated/bundle/server.js", script)
+}
@@ -0,0 +1,16 @@
+package helpers
+
+import "path/filepath"
+
+// WatchPaths returns the additional paths set by up --watch, relative ones are resolved against
+// the WunderGraph directory like the other path flags
+func WatchPaths(wunderGraphDir string, paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(wunderGraphDir, path)
+		}
+		resolved = append(resolved, filepath.Clean(path))
+	}
+	return resolved
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchPaths(t *testing.T) {
+	wunderGraphDir := filepath.Join(t.TempDir(), ".wundergraph")
+	absPath := filepath.Join(t.TempDir(), "schema.graphql")
+	assert.Equal(t, []string{
+		filepath.Join(wunderGraphDir, "datasources.yaml"),
+		filepath.Join(filepath.Dir(wunderGraphDir), "shared"),
+		absPath,
+	}, WatchPaths(wunderGraphDir, []string{"datasources.yaml", "../shared", absPath}))
+	assert.Empty(t, WatchPaths(wunderGraphDir, nil))
+}
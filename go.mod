@@ -28,7 +28,6 @@ require (
 	github.com/hashicorp/go-uuid v1.0.2
 	github.com/hetiansu5/urlquery v1.2.7
 	github.com/jensneuse/goprisma v0.2.0
-	github.com/joho/godotenv v1.4.0
 	github.com/klauspost/compress v1.15.15
 	github.com/mattbaird/jsonpatch v0.0.0-20200820163806-098863c1fc24
 	github.com/minio/minio-go/v7 v7.0.14
@@ -837,6 +837,8 @@ export interface Operation {
   postResolveTransformations: PostResolveTransformation[];
   engine: OperationExecutionEngine;
   path: string;
+  /** set by the @cache(ttl) directive, the dev response cache keeps responses for this many seconds */
+  responseCacheTtlSeconds: number;
 }
 
 export interface PostResolveTransformation {
@@ -1732,6 +1734,7 @@ function createBaseOperation(): Operation {
     postResolveTransformations: [],
     engine: 0,
     path: "",
+    responseCacheTtlSeconds: 0,
   };
 }
 
@@ -1768,6 +1771,7 @@ export const Operation = {
         : [],
       engine: isSet(object.engine) ? operationExecutionEngineFromJSON(object.engine) : 0,
       path: isSet(object.path) ? String(object.path) : "",
+      responseCacheTtlSeconds: isSet(object.responseCacheTtlSeconds) ? Number(object.responseCacheTtlSeconds) : 0,
     };
   },
 
@@ -1807,6 +1811,8 @@ export const Operation = {
     }
     message.engine !== undefined && (obj.engine = operationExecutionEngineToJSON(message.engine));
     message.path !== undefined && (obj.path = message.path);
+    message.responseCacheTtlSeconds !== undefined &&
+      (obj.responseCacheTtlSeconds = Math.round(message.responseCacheTtlSeconds));
     return obj;
   },
 
@@ -1843,6 +1849,7 @@ export const Operation = {
     ) || [];
     message.engine = object.engine ?? 0;
     message.path = object.path ?? "";
+    message.responseCacheTtlSeconds = object.responseCacheTtlSeconds ?? 0;
     return message;
   },
 };
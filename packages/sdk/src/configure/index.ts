@@ -25,6 +25,7 @@ import {
 	WG_DATA_SOURCE_POLLING_MODE,
 } from '../definition';
 import { mergeApis } from '../definition/merge';
+import { loadDataSourceRegistry, WG_DATASOURCE_REGISTRY } from '../definition/datasource-registry';
 import {
 	GraphQLOperation,
 	loadOperations,
@@ -34,6 +35,7 @@ import {
 	removeHookVariables,
 } from '../graphql/operations';
 import { GenerateCode, Template } from '../codegen';
+import templates from '../codegen/templates';
 import {
 	ArgumentRenderConfiguration,
 	ArgumentSource,
@@ -60,7 +62,7 @@ import { FieldInfo, LinkConfiguration, LinkDefinition, queryTypeFields } from '.
 import { PostmanBuilder } from '../postman/builder';
 import { CustomizeMutation, CustomizeQuery, CustomizeSubscription, OperationsConfiguration } from './operations';
 import { HooksConfiguration, ResolvedServerOptions, WunderGraphHooksAndServerConfig } from '../server/types';
-import { getWebhooks } from '../webhooks';
+import { getWebhooks, WG_EXCLUDE_WEBHOOKS } from '../webhooks';
 import { NodeOptions, ResolvedNodeOptions, resolveNodeOptions } from './options';
 import { EnvironmentVariable, InputVariable, mapInputVariable, resolveConfigurationVariable } from './variables';
 import logger, { Logger } from '../logger';
@@ -68,6 +70,7 @@ import { resolveServerOptions, serverOptionsWithDefaults } from '../server/serve
 import { loadNodeJsOperationDefaultModule, NodeJSOperation } from '../operations/operations';
 import zodToJsonSchema from 'zod-to-json-schema';
 import { cleanOpenApiSpecs } from '../openapi/introspection';
+import { wunderGraphPath } from '../utils/wundergraph-dir';
 
 export interface WunderGraphCorsConfiguration {
 	allowedOrigins: InputVariable[];
@@ -335,6 +338,10 @@ const resolveConfig = async (config: WunderGraphConfigApplicationConfig): Promis
 		config.apis.push(...graphqlApis);
 	}
 
+	if (WG_DATASOURCE_REGISTRY) {
+		config.apis.push(...loadDataSourceRegistry(WG_DATASOURCE_REGISTRY));
+	}
+
 	const roles = config.authorization?.roles || ['admin', 'user'];
 	const customClaims = Object.keys(config.authentication?.customClaims ?? {});
 
@@ -613,8 +620,13 @@ const resolveApplication = async (
 ): Promise<ResolvedApplication> => {
 	await cleanOpenApiSpecs();
 	const apiPromises = apis.map((api) => api());
+	const resolveStart = Date.now();
 	const resolvedApis = await Promise.all(apiPromises);
 	const merged = mergeApis(roles, customClaims, ...resolvedApis);
+	const resolveDuration = Date.now() - resolveStart;
+	Logger.debug(
+		`Resolved ${resolvedApis.length} apis with ${merged.DataSources.length} data sources in ${resolveDuration}ms`
+	);
 	const s3Configurations = s3?.map((config) => resolveUploadConfiguration(config, hooks)) || [];
 	return {
 		EngineConfiguration: merged,
@@ -628,6 +640,25 @@ const resolveApplication = async (
 
 // configureWunderGraphApplication generates the file "generated/wundergraph.config.json" and runs the configured code generators
 // the wundergraph.config.json file will be picked up by "wunderctl up" to configure your development environment
+/**
+ * writeConfigJson writes the config to generated/wundergraph.config.json of the WunderGraph
+ * directory, if it changed. The node watches this file to reload.
+ */
+export const writeConfigJson = (configJSON: string) => {
+	const configJsonPath = wunderGraphPath('generated', 'wundergraph.config.json');
+	// config json exists
+	if (fs.existsSync(configJsonPath)) {
+		const existing = fs.readFileSync(configJsonPath, 'utf8');
+		if (configJSON !== existing) {
+			fs.writeFileSync(configJsonPath, configJSON, { encoding: 'utf8' });
+			Logger.info(`wundergraph.config.json updated`);
+		}
+	} else {
+		fs.writeFileSync(configJsonPath, configJSON, { encoding: 'utf8' });
+		Logger.info(`wundergraph.config.json created`);
+	}
+};
+
 export const configureWunderGraphApplication = (config: WunderGraphConfigApplicationConfig) => {
 	if (WG_DATA_SOURCE_POLLING_MODE) {
 		// if the DataSourcePolling environment variable is set to 'true',
@@ -648,7 +679,7 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 
 			const schemaContent = '# Code generated by "wunderctl"; DO NOT EDIT.\n\n' + app.EngineConfiguration.Schema;
 
-			fs.writeFileSync(path.join('generated', schemaFileName), schemaContent, { encoding: 'utf8' });
+			fs.writeFileSync(wunderGraphPath('generated', schemaFileName), schemaContent, { encoding: 'utf8' });
 			done();
 			Logger.info(`${schemaFileName} updated`);
 
@@ -657,8 +688,14 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 			 */
 
 			const webhooksDir = path.join('webhooks');
-			if (fs.existsSync(webhooksDir)) {
-				const webhooks = await getWebhooks(path.join('webhooks'));
+			if (fs.existsSync(wunderGraphPath(webhooksDir))) {
+				const webhooks = (await getWebhooks(webhooksDir)).filter((webhook) => {
+					if (WG_EXCLUDE_WEBHOOKS.includes(webhook.name)) {
+						Logger.info(`excluding webhook: ${webhook.name}`);
+						return false;
+					}
+					return true;
+				});
 				resolved.webhooks = webhooks.map((webhook) => {
 					let webhookConfig: WebhookConfiguration = {
 						name: webhook.name,
@@ -697,6 +734,7 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 			);
 			app.Operations = operations.operations;
 			app.InvalidOperationNames = loadedOperations.invalid || [];
+			Logger.debug(`Loaded ${app.Operations.length} operations, ${app.InvalidOperationNames.length} invalid`);
 			if (app.Operations && config.operations !== undefined) {
 				const ops = app.Operations.map(async (op) => {
 					const cfg = config.operations!;
@@ -723,7 +761,8 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 							}
 							return loadAndApplyNodeJsOperationOverrides({
 								...op,
-								CacheConfig: {
+								// @cache directives take precedence over the caching configuration
+								CacheConfig: op.CacheConfig ?? {
 									enable: queryConfig.caching.enable,
 									maxAge: queryConfig.caching.maxAge,
 									public: queryConfig.caching.public,
@@ -875,27 +914,25 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 					await GenerateCode({
 						wunderGraphConfig: resolved,
 						templates: gen.templates,
-						basePath: gen.path || 'generated',
+						basePath: wunderGraphPath(gen.path || 'generated'),
 					});
 				}
 				done();
 				Logger.info(`Code generation completed.`);
 			}
 
-			const configJsonPath = path.join('generated', 'wundergraph.config.json');
-			const configJSON = ResolvedWunderGraphConfigToJSON(resolved);
-			// config json exists
-			if (fs.existsSync(configJsonPath)) {
-				const existing = fs.readFileSync(configJsonPath, 'utf8');
-				if (configJSON !== existing) {
-					fs.writeFileSync(configJsonPath, configJSON, { encoding: 'utf8' });
-					Logger.info(`wundergraph.config.json updated`);
-				}
-			} else {
-				fs.writeFileSync(configJsonPath, configJSON, { encoding: 'utf8' });
-				Logger.info(`wundergraph.config.json created`);
+			// set by wunderctl up --client-out, the client and its models are written there as well
+			const clientOutDir = process.env.WG_CLIENT_OUT_DIR;
+			if (clientOutDir) {
+				await GenerateCode({
+					wunderGraphConfig: resolved,
+					templates: [templates.typescript.client],
+					basePath: clientOutDir,
+				});
 			}
 
+			writeConfigJson(ResolvedWunderGraphConfigToJSON(resolved));
+
 			done();
 
 			let publicNodeUrl = trimTrailingSlash(resolveConfigurationVariable(resolved.nodeOptions.publicNodeUrl));
@@ -904,7 +941,7 @@ export const configureWunderGraphApplication = (config: WunderGraphConfigApplica
 				baseURL: publicNodeUrl,
 			});
 			fs.writeFileSync(
-				path.join('generated', 'wundergraph.postman.json'),
+				wunderGraphPath('generated', 'wundergraph.postman.json'),
 				JSON.stringify(postman.toJSON(), null, '  '),
 				{
 					encoding: 'utf8',
@@ -949,6 +986,7 @@ const done = () => {
 const ResolvedWunderGraphConfigToJSON = (config: ResolvedWunderGraphConfig): string => {
 	const operations: Operation[] = config.application.Operations.map((op) => ({
 		content: removeHookVariables(op.Content),
+		responseCacheTtlSeconds: op.ResponseCacheTtlSeconds ?? 0,
 		name: op.Name,
 		path: op.PathName,
 		responseSchema: JSON.stringify(op.ResponseSchema),
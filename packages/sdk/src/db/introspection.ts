@@ -11,6 +11,7 @@ import path from 'path';
 import { resolveVariable } from '../configure/variables';
 import { Logger } from '../logger';
 import { DatabaseSchema, prisma } from './types';
+import { wunderGraphPath } from '../utils/wundergraph-dir';
 
 export interface PrismaDatabaseIntrospectionResult {
 	success: boolean;
@@ -42,11 +43,11 @@ const introspectPrismaDatabase = async (
 	}
 	await ensurePrisma;
 	const id = hash({ databaseURL, databaseSchema });
-	const introspectionDir = path.join('generated', 'introspection', 'database');
+	const introspectionDir = wunderGraphPath('generated', 'introspection', 'database');
 	if (!fs.existsSync(introspectionDir)) {
 		fs.mkdirSync(introspectionDir, { recursive: true });
 	}
-	const introspectionFilePath = path.join('generated', 'introspection', 'database', `${id}.json`);
+	const introspectionFilePath = path.join(introspectionDir, `${id}.json`);
 	const cmd = ['introspect', databaseSchema, databaseURL, `--outfile=${introspectionFilePath}`, '--debug'];
 	const result = await wunderctlExecAsync({ cmd });
 	if (result === undefined) {
@@ -0,0 +1,31 @@
+import { parseDataSourceRegistry } from './datasource-registry';
+
+describe('Data source registry', () => {
+	test('returns an introspection per data source', () => {
+		const introspections = parseDataSourceRegistry(
+			JSON.stringify({
+				dataSources: [
+					{
+						kind: 'graphql',
+						id: 'billing',
+						apiNamespace: 'billing',
+						url: 'https://billing.example.com/graphql',
+						headers: { 'X-Api-Key': { env: 'BILLING_API_KEY' } },
+					},
+					{ kind: 'openApiV2', id: 'users', apiNamespace: 'users', source: { kind: 'file', filePath: 'users.json' } },
+				],
+			}),
+			'registry.json'
+		);
+		expect(introspections).toHaveLength(2);
+		introspections.forEach((introspection) => expect(typeof introspection).toBe('function'));
+	});
+
+	test('rejects invalid registries', () => {
+		expect(() => parseDataSourceRegistry('{', 'registry.json')).toThrow('could not parse data source registry');
+		expect(() => parseDataSourceRegistry('{}', 'registry.json')).toThrow('expected a dataSources array');
+		expect(() => parseDataSourceRegistry('{"dataSources":[{"kind":"soap"}]}', 'registry.json')).toThrow(
+			'invalid data source 0 in registry registry.json: unknown kind "soap"'
+		);
+	});
+});
@@ -0,0 +1,74 @@
+import fs from 'fs';
+import { EnvironmentVariable, InputVariable } from '../configure/variables';
+import { Api, ILazyIntrospection, introspect } from './index';
+
+// path of a JSON file with data sources to add to the ones of the config, set by
+// wunderctl up --datasource-registry
+export const WG_DATASOURCE_REGISTRY = process.env['WG_DATASOURCE_REGISTRY'] || '';
+
+export type DataSourceRegistryKind = keyof typeof introspect;
+
+// a header value, either static or read from an environment variable
+type DataSourceRegistryHeaderValue = string | { env: string; default?: string };
+
+export interface DataSourceRegistryEntry {
+	// the name of the introspect function to use, e.g. graphql or openApiV2
+	kind: DataSourceRegistryKind;
+	// static headers sent to the upstream
+	headers?: Record<string, DataSourceRegistryHeaderValue>;
+	// the remaining options are passed to the introspect function as they are
+	[option: string]: unknown;
+}
+
+export interface DataSourceRegistry {
+	dataSources: DataSourceRegistryEntry[];
+}
+
+const headerValue = (value: DataSourceRegistryHeaderValue): InputVariable => {
+	if (typeof value === 'string') {
+		return value;
+	}
+	return new EnvironmentVariable(value.env, value.default);
+};
+
+/**
+ * Parses a data source registry and returns the introspections of its data sources, errors
+ * name the file and the index of the invalid entry.
+ */
+export const parseDataSourceRegistry = (data: string, filePath: string): ILazyIntrospection<Api<any>>[] => {
+	let registry: DataSourceRegistry;
+	try {
+		registry = JSON.parse(data);
+	} catch (e: any) {
+		throw new Error(`could not parse data source registry ${filePath}: ${e.message}`);
+	}
+	if (!Array.isArray(registry?.dataSources)) {
+		throw new Error(`invalid data source registry ${filePath}: expected a dataSources array`);
+	}
+	return registry.dataSources.map((entry, index) => {
+		const { kind, headers, ...options } = entry ?? ({} as DataSourceRegistryEntry);
+		if (typeof kind !== 'string' || !Object.prototype.hasOwnProperty.call(introspect, kind)) {
+			throw new Error(
+				`invalid data source ${index} in registry ${filePath}: unknown kind ${JSON.stringify(kind)}, ` +
+					`expected one of ${Object.keys(introspect).join(', ')}`
+			);
+		}
+		const introspection: Record<string, unknown> = { ...options };
+		if (headers !== undefined) {
+			introspection.headers = (builder: { addStaticHeader: (key: string, value: InputVariable) => any }) => {
+				for (const [key, value] of Object.entries(headers)) {
+					builder.addStaticHeader(key, headerValue(value));
+				}
+				return builder;
+			};
+		}
+		return (introspect[kind] as (introspection: any) => ILazyIntrospection<Api<any>>)(introspection);
+	});
+};
+
+/**
+ * Reads the data source registry at filePath, see parseDataSourceRegistry.
+ */
+export const loadDataSourceRegistry = (filePath: string): ILazyIntrospection<Api<any>>[] => {
+	return parseDataSourceRegistry(fs.readFileSync(filePath, 'utf-8'), filePath);
+};
@@ -7,7 +7,7 @@ import { mergeApis } from './merge';
 import { introspectWithCache } from './introspection-cache';
 import { introspectGraphql, resolveGraphqlIntrospectionHeaders } from './graphql-introspection';
 import { HeadersBuilder, mapHeaders } from './headers-builder';
-import { Fetcher } from './introspection-fetcher';
+import { fetchError, Fetcher, TransientIntrospectionError } from './introspection-fetcher';
 import { Logger } from '../logger';
 
 export const fetchFederationServiceSDL = async (
@@ -42,15 +42,14 @@ export const fetchFederationServiceSDL = async (
 	try {
 		res = await Fetcher().post(url, data, opts);
 	} catch (e: any) {
-		throw new Error(`failed to fetch federation service sdl (url: ${url}), error: ${e.message}`);
+		throw fetchError(`failed to fetch federation service sdl (url: ${url}), error: ${e.message}`, e);
 	}
 	if (res === undefined) {
 		throw new Error(`failed to fetch federation service sdl (url: ${url}), no response`);
 	}
 	if (res.status !== 200) {
-		throw new Error(
-			`failed to fetch federation service sdl (url: ${url}), response code: ${res.status}, message: ${res.statusText}`
-		);
+		const message = `failed to fetch federation service sdl (url: ${url}), response code: ${res.status}, message: ${res.statusText}`;
+		throw res.status >= 500 ? new TransientIntrospectionError(message) : new Error(message);
 	}
 
 	return res.data.data._service.sdl;
@@ -150,6 +150,32 @@ test('introspection via string', async () => {
 	expect(federatedApiFromString).toMatchSnapshot();
 });
 
+test('introspection of a subgraph in federation mode', async () => {
+	const data = await fs.readFile(path.join(__dirname, 'testdata', 'introspection.json'), { encoding: 'utf8' });
+	const { accountsSDL } = JSON.parse(data);
+
+	// only the service SDL is served, the introspection query must not be used
+	nock('http://accounts.federation-mode.service')
+		.post('/graphql', (body: any) => body.query.includes('_service{sdl}'))
+		.reply(200, accountsSDL);
+
+	const api: GraphQLApi = await introspect.graphql({
+		apiNamespace: 'accounts',
+		url: 'http://accounts.federation-mode.service/graphql',
+		introspection: {
+			mode: 'federation',
+			disableCache: true,
+		},
+	})();
+
+	assert.equal(api.DataSources.length, 1);
+	const { Federation } = api.DataSources[0].Custom;
+	assert.isTrue(Federation.Enabled);
+	assert.equal(Federation.ServiceSDL, accountsSDL.data._service.sdl);
+	assert.include(Federation.ServiceSDL, '@key');
+	assert.isTrue(nock.isDone());
+});
+
 test('build subgraph schema', async () => {
 	const data = await fs.readFile(path.join(__dirname, 'testdata', 'introspection.json'), { encoding: 'utf8' });
 	const { accountsSDL, productsSDL, reviewsSDL, inventorySDL } = JSON.parse(data);
@@ -30,7 +30,7 @@ import { introspectWithCache } from './introspection-cache';
 import { mapInputVariable, resolveVariable } from '../configure/variables';
 import { buildMTLSConfiguration, buildUpstreamAuthentication, GraphQLApi, GraphQLIntrospection } from './index';
 import { HeadersBuilder, mapHeaders } from './headers-builder';
-import { Fetcher } from './introspection-fetcher';
+import { fetchError, Fetcher, TransientIntrospectionError } from './introspection-fetcher';
 import { Logger } from '../logger';
 import { mergeSchemas } from '@graphql-tools/schema';
 import transformSchema from '../transformations/schema';
@@ -85,11 +85,8 @@ export const introspectGraphql = async (introspection: GraphQLIntrospection): Pr
 		const headers = mapHeaders(headersBuilder);
 		const introspectionHeaders = resolveGraphqlIntrospectionHeaders(mapHeaders(introspectionHeadersBuilder));
 
-		let schema = await introspectGraphQLSchema(introspection, introspectionHeaders);
-		schema = lexicographicSortSchema(schema);
-		const federationEnabled = introspection.isFederation || false;
-		const upstreamSchema = cleanupSchema(schema, introspection);
-		const { schemaSDL, customScalarTypeFields } = transformSchema.replaceCustomScalars(upstreamSchema, introspection);
+		const federationMode = introspection.introspection?.mode === 'federation';
+		const federationEnabled = introspection.isFederation || federationMode;
 		let serviceSDL: string | undefined;
 		if (federationEnabled) {
 			if (introspection.loadSchemaFromString) {
@@ -103,6 +100,14 @@ export const introspectGraphql = async (introspection: GraphQLIntrospection): Pr
 				});
 			}
 		}
+		// the introspection query strips federation directives, the subgraph schema is built from the service SDL instead
+		let schema =
+			federationMode && serviceSDL !== undefined
+				? buildSubgraphSchemaFromSDL(serviceSDL, introspection)
+				: await introspectGraphQLSchema(introspection, introspectionHeaders);
+		schema = lexicographicSortSchema(schema);
+		const upstreamSchema = cleanupSchema(schema, introspection);
+		const { schemaSDL, customScalarTypeFields } = transformSchema.replaceCustomScalars(upstreamSchema, introspection);
 		const serviceDocumentNode = serviceSDL !== undefined ? parse(serviceSDL) : undefined;
 		const schemaDocumentNode = parse(schemaSDL);
 		const graphQLSchema = buildSchema(schemaSDL);
@@ -187,6 +192,16 @@ export const introspectGraphql = async (introspection: GraphQLIntrospection): Pr
 	});
 };
 
+const buildSubgraphSchemaFromSDL = (serviceSDL: string, introspection: GraphQLIntrospection) => {
+	try {
+		return buildSubgraphSchema(parse(serviceSDL));
+	} catch (e: any) {
+		throw new Error(
+			`Building the subgraph schema from the federation service SDL failed for apiNamespace '${introspection.apiNamespace}': ${e}`
+		);
+	}
+};
+
 const introspectGraphQLSchema = async (introspection: GraphQLIntrospection, headers?: Record<string, string>) => {
 	if (introspection.loadSchemaFromString) {
 		try {
@@ -253,10 +268,11 @@ const introspectGraphQLAPI = async (
 		}
 		res = await Fetcher().post(resolveVariable(introspection.url), data, opts);
 	} catch (e: any) {
-		throw new Error(
+		throw fetchError(
 			`introspection failed (url: ${introspection.url}, namespace: ${introspection.apiNamespace || ''}), error: ${
 				e.message
-			}`
+			}`,
+			e
 		);
 	}
 	if (res === undefined) {
@@ -265,11 +281,10 @@ const introspectGraphQLAPI = async (
 		);
 	}
 	if (res.status !== 200) {
-		throw new Error(
-			`introspection failed (url: ${introspection.url}, namespace: ${
-				introspection.apiNamespace || ''
-			}), response code: ${res.status}, message: ${res.statusText}`
-		);
+		const message = `introspection failed (url: ${introspection.url}, namespace: ${
+			introspection.apiNamespace || ''
+		}), response code: ${res.status}, message: ${res.statusText}`;
+		throw res.status >= 500 ? new TransientIntrospectionError(message) : new Error(message);
 	}
 	return buildClientSchema(res.data.data);
 };
@@ -42,6 +42,19 @@ export const WG_ENABLE_INTROSPECTION_OFFLINE = process.env['WG_ENABLE_INTROSPECT
 export const WG_THROW_ON_OPERATION_LOADING_ERROR = process.env['WG_THROW_ON_OPERATION_LOADING_ERROR'] === 'true';
 
 export const WG_PRETTY_GRAPHQL_VALIDATION_ERRORS = process.env['WG_PRETTY_GRAPHQL_VALIDATION_ERRORS'] === 'true';
+// comma separated ids or api namespaces of sources whose cached introspection is always reused,
+// set by wunderctl up --pin-source
+export const WG_PINNED_INTROSPECTIONS = (process.env['WG_PINNED_INTROSPECTIONS'] || '')
+	.split(',')
+	.map((name) => name.trim())
+	.filter((name) => name !== '');
+// Number of times a failed introspection request is retried before the introspection fails
+export const WG_INTROSPECTION_RETRIES = parseInt(process.env['WG_INTROSPECTION_RETRIES'] ?? '', 10);
+// Upper bound of the polling interval while an upstream is unreachable
+export const WG_INTROSPECTION_POLLING_MAX_BACKOFF_SECONDS = parseInt(
+	process.env['WG_INTROSPECTION_POLLING_MAX_BACKOFF_SECONDS'] ?? '',
+	10
+);
 
 export interface RenameType {
 	from: string;
@@ -335,13 +348,19 @@ export interface GraphqlIntrospectionHeaders {
 	headers?: (builder: IGraphqlIntrospectionHeadersBuilder) => IGraphqlIntrospectionHeadersBuilder;
 }
 
+export interface GraphqlIntrospectionMode {
+	// mode 'federation' introspects an Apollo Federation subgraph through its _service { sdl } field
+	// instead of the introspection query, preserving federation directives like @key and @external
+	mode?: 'introspection' | 'federation';
+}
+
 export interface GraphQLUpstream extends HTTPUpstream {
 	url: InputVariable;
 	baseUrl?: InputVariable;
 	path?: InputVariable;
 	subscriptionsURL?: InputVariable;
 	subscriptionsUseSSE?: boolean;
-	introspection?: HTTPUpstream['introspection'] & GraphqlIntrospectionHeaders;
+	introspection?: HTTPUpstream['introspection'] & GraphqlIntrospectionHeaders & GraphqlIntrospectionMode;
 }
 
 export interface OpenAPIIntrospectionFile {
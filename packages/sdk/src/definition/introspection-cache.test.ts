@@ -0,0 +1,40 @@
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { Api } from './index';
+import { readIntrospectionCacheFile, updateIntrospectionCache } from './introspection-cache';
+
+describe('updateIntrospectionCache', () => {
+	const wgDirAbs = process.env.WG_DIR_ABS;
+	let wunderGraphDir: string;
+
+	beforeEach(() => {
+		wunderGraphDir = fs.mkdtempSync(path.join(os.tmpdir(), 'wundergraph-'));
+		process.env.WG_DIR_ABS = wunderGraphDir;
+	});
+
+	afterEach(() => {
+		if (wgDirAbs === undefined) {
+			delete process.env.WG_DIR_ABS;
+		} else {
+			process.env.WG_DIR_ABS = wgDirAbs;
+		}
+		fs.rmSync(wunderGraphDir, { recursive: true, force: true });
+	});
+
+	const api = (schema: string) => new Api(schema, [], [], [], [], undefined);
+
+	test('marks entries without impact and keeps them', async () => {
+		expect(await updateIntrospectionCache(api('type Query { a: String }'), 'key')).toBe(true);
+		expect(JSON.parse(await readIntrospectionCacheFile('key')).noImpact).toBeUndefined();
+
+		expect(await updateIntrospectionCache(api('type Query { a: String b: String }'), 'key', true)).toBe(true);
+		const entry = JSON.parse(await readIntrospectionCacheFile('key'));
+		expect(entry.schema).toBe('type Query { a: String b: String }');
+		expect(entry.noImpact).toBe(true);
+
+		// the next polls see the new schema as unchanged
+		expect(await updateIntrospectionCache(api('type Query { a: String b: String }'), 'key')).toBe(false);
+		expect(JSON.parse(await readIntrospectionCacheFile('key')).noImpact).toBe(true);
+	});
+});
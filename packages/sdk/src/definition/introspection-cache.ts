@@ -6,6 +6,8 @@ import {
 	WG_DATA_SOURCE_POLLING_MODE,
 	WG_ENABLE_INTROSPECTION_CACHE,
 	WG_ENABLE_INTROSPECTION_OFFLINE,
+	WG_INTROSPECTION_POLLING_MAX_BACKOFF_SECONDS,
+	WG_PINNED_INTROSPECTIONS,
 } from './index';
 import path from 'path';
 import fsP from 'fs/promises';
@@ -13,6 +15,11 @@ import { FieldConfiguration, TypeConfiguration } from '@wundergraph/protobuf';
 import objectHash from 'object-hash';
 import { Logger } from '../logger';
 import { onParentProcessExit } from '../utils/process';
+import { wunderGraphPath } from '../utils/wundergraph-dir';
+import { TransientIntrospectionError } from './introspection-fetcher';
+import { readOperationDocuments, relevantSchemaChanges } from './introspection-diff';
+
+const defaultPollingMaxBackoffSeconds = 60;
 
 export interface IntrospectionCacheFile<A extends ApiType> {
 	version: '1.0.0';
@@ -22,6 +29,9 @@ export interface IntrospectionCacheFile<A extends ApiType> {
 	types: TypeConfiguration[];
 	interpolateVariableDefinitionAsJSON: string[];
 	customJsonScalars: string[] | undefined;
+	// set by the poller if the schema changed only in types and fields no operation uses,
+	// wunderctl doesn't rebuild the config for the entry
+	noImpact?: boolean;
 }
 
 export function toCacheEntry<T extends ApiType>(api: Api<T>): IntrospectionCacheFile<T> {
@@ -47,8 +57,11 @@ export function fromCacheEntry<A extends ApiType>(cache: IntrospectionCacheFile<
 	);
 }
 
+const introspectionCacheFilePath = (cacheKey: string): string =>
+	wunderGraphPath('cache', 'introspection', `${cacheKey}.json`);
+
 export const readIntrospectionCacheFile = async (cacheKey: string): Promise<string> => {
-	const cacheFile = path.join('cache', 'introspection', `${cacheKey}.json`);
+	const cacheFile = introspectionCacheFilePath(cacheKey);
 	try {
 		return await fsP.readFile(cacheFile, 'utf8');
 	} catch (e) {
@@ -60,10 +73,55 @@ export const readIntrospectionCacheFile = async (cacheKey: string): Promise<stri
 	}
 };
 
+/**
+ * parseIntrospectionCacheEntry returns the entry stored as content, or undefined if it's corrupted,
+ * e.g. truncated by an interrupted write or a full disk.
+ */
+export const parseIntrospectionCacheEntry = <A extends ApiType>(
+	content: string
+): IntrospectionCacheFile<A> | undefined => {
+	let entry: IntrospectionCacheFile<A>;
+	try {
+		entry = JSON.parse(content);
+	} catch {
+		return undefined;
+	}
+	if (
+		typeof entry !== 'object' ||
+		entry === null ||
+		entry.version !== '1.0.0' ||
+		typeof entry.schema !== 'string' ||
+		!Array.isArray(entry.dataSources)
+	) {
+		return undefined;
+	}
+	return entry;
+};
+
+const removeIntrospectionCacheFile = async (cacheKey: string): Promise<void> => {
+	try {
+		await fsP.rm(introspectionCacheFilePath(cacheKey), { force: true });
+	} catch (e) {
+		Logger.error(`Error removing corrupted introspection cache entry ${cacheKey}: ${e}`);
+	}
+};
+
+// writeFileAtomically writes to a temporary file first, so readers never see partial entries
+const writeFileAtomically = async (file: string, content: string): Promise<void> => {
+	const tmpFile = `${file}.${process.pid}.tmp`;
+	try {
+		await fsP.writeFile(tmpFile, content, { encoding: 'utf8' });
+		await fsP.rename(tmpFile, file);
+	} catch (e) {
+		await fsP.rm(tmpFile, { force: true }).catch(() => {});
+		throw e;
+	}
+};
+
 export const writeIntrospectionCacheFile = async (cacheKey: string, content: string): Promise<void> => {
-	const cacheFile = path.join('cache', 'introspection', `${cacheKey}.json`);
+	const cacheFile = introspectionCacheFilePath(cacheKey);
 	try {
-		return await fsP.writeFile(cacheFile, content, { encoding: 'utf8' });
+		return await writeFileAtomically(cacheFile, content);
 	} catch (e) {
 		if (e instanceof Error && e.message.startsWith('ENOENT')) {
 			const dir = path.dirname(cacheFile);
@@ -75,63 +133,158 @@ export const writeIntrospectionCacheFile = async (cacheKey: string, content: str
 			// Now try again. Avoid calling writeIntrospectionCacheFile(), otherwise
 			// a bug could end up causing infinite recursion instead of a a non-working
 			// cache
-			return await fsP.writeFile(cacheFile, content, { encoding: 'utf8' });
+			return await writeFileAtomically(cacheFile, content);
 		}
 		// Could not write cache file, rethrow original error
 		throw e;
 	}
 };
 
+// withoutNoImpact returns the cached entry without the noImpact mark, as written by toCacheEntry
+const withoutNoImpact = (cachedIntrospectionString: string): string => {
+	const cached = parseIntrospectionCacheEntry(cachedIntrospectionString);
+	if (!cached?.noImpact) {
+		return cachedIntrospectionString;
+	}
+	delete cached.noImpact;
+	return JSON.stringify(cached);
+};
+
+/**
+ * updateIntrospectionCache writes the entry of api if the introspection result has changed. Entries
+ * written with noImpact are marked, so wunderctl keeps the config instead of rebuilding it.
+ */
 export const updateIntrospectionCache = async <Introspection extends IntrospectionConfiguration, A extends ApiType>(
 	api: Api<A>,
-	introspectionCacheKey: string
+	introspectionCacheKey: string,
+	noImpact = false
 ): Promise<boolean> => {
 	const cachedIntrospectionString = await readIntrospectionCacheFile(introspectionCacheKey);
 	const actualApiCacheEntry = toCacheEntry(api);
 	const actualApiCacheEntryString = JSON.stringify(actualApiCacheEntry);
 
-	if (actualApiCacheEntryString === cachedIntrospectionString) {
+	if (actualApiCacheEntryString === withoutNoImpact(cachedIntrospectionString)) {
 		return false;
 	}
 
 	// we only write to the file system if the introspection result has changed.
-	// A file change will trigger a rebuild of the entire WunderGraph config.
-	await writeIntrospectionCacheFile(introspectionCacheKey, actualApiCacheEntryString);
+	// A file change will trigger a rebuild of the entire WunderGraph config, unless it's marked.
+	await writeIntrospectionCacheFile(
+		introspectionCacheKey,
+		noImpact ? JSON.stringify({ ...actualApiCacheEntry, noImpact }) : actualApiCacheEntryString
+	);
 
 	return true;
 };
 
+/**
+ * hasNoImpact returns true if the schema of api differs from the cached one only in types and fields
+ * the operations don't use, so regenerating the config can be skipped. If the operations can't be read
+ * or parsed, their uses are unknown and the config is regenerated.
+ */
+const hasNoImpact = async <A extends ApiType>(api: Api<A>, introspectionCacheKey: string): Promise<boolean> => {
+	const cached = parseIntrospectionCacheEntry<A>(await readIntrospectionCacheFile(introspectionCacheKey));
+	const wunderGraphDir = process.env.WG_DIR_ABS;
+	// changes besides the schema are never skipped
+	if (!cached || cached.schema === api.Schema || !wunderGraphDir) {
+		return false;
+	}
+	try {
+		const documents = await readOperationDocuments(wunderGraphDir);
+		return relevantSchemaChanges(cached.schema, api.Schema, documents).length === 0;
+	} catch (e) {
+		Logger.debug(`Could not diff the introspection ${introspectionCacheKey}, regenerating config: ${e}`);
+		return false;
+	}
+};
+
 export const introspectInInterval = async <Introspection extends IntrospectionConfiguration, A extends ApiType>(
 	intervalInSeconds: number,
 	introspectionCacheKey: string,
 	introspection: Introspection,
 	generator: (introspection: Introspection) => Promise<Api<A>>
 ) => {
+	const maxBackoffSeconds = Math.max(
+		intervalInSeconds,
+		WG_INTROSPECTION_POLLING_MAX_BACKOFF_SECONDS > 0
+			? WG_INTROSPECTION_POLLING_MAX_BACKOFF_SECONDS
+			: defaultPollingMaxBackoffSeconds
+	);
+	// consecutive polls that failed because the upstream was unreachable
+	let transientFailures = 0;
+	let exiting = false;
+	let timeout: NodeJS.Timeout;
+
 	const pollingRunner = async () => {
 		try {
 			const api = await generator(introspection);
-			const updated = await updateIntrospectionCache(api, introspectionCacheKey);
-			if (updated) {
-				Logger.info(`Introspection cache updated. Trigger rebuild of WunderGraph config.`);
+			if (transientFailures > 0) {
+				Logger.info(`Upstream reachable again after ${transientFailures} failed introspections.`);
+				transientFailures = 0;
+			}
+			// the cache entry is watched by wunderctl, writing it rebuilds the config unless it has no impact.
+			// It's always written, so the next polls diff against the latest schema.
+			const noImpact = await hasNoImpact(api, introspectionCacheKey);
+			const updated = await updateIntrospectionCache(api, introspectionCacheKey, noImpact);
+			if (updated && noImpact) {
+				Logger.info(`upstream ${sourceName(introspection)} changed but no impact, skipping rebuild`);
+			} else if (updated) {
+				Logger.info(`upstream schema changed for ${sourceName(introspection)}, regenerating config`);
 			}
 		} catch (e) {
-			Logger.error('Error during introspection cache update', e);
+			if (e instanceof TransientIntrospectionError) {
+				// the upstream is probably restarting, keep the last good introspection instead of
+				// dropping the data source from the config
+				if (transientFailures === 0) {
+					Logger.warn(`Upstream unreachable, keeping the last introspection until it's back: ${e.message}`);
+				} else {
+					Logger.debug(`Upstream still unreachable: ${e.message}`);
+				}
+				transientFailures++;
+			} else {
+				transientFailures = 0;
+				Logger.error('Error during introspection cache update', e);
+			}
+		}
+		schedule();
+	};
+
+	// schedule runs the next poll, backing off exponentially while the upstream is unreachable
+	const schedule = () => {
+		const delaySeconds = Math.min(intervalInSeconds * 2 ** transientFailures, maxBackoffSeconds);
+		timeout = setTimeout(pollingRunner, delaySeconds * 1000);
+		if (exiting) {
+			timeout.unref();
 		}
 	};
 
-	const pollingInterval = setInterval(pollingRunner, intervalInSeconds * 1000);
+	schedule();
 
 	// Exit the long-running introspection poller when wunderctl exited without the chance to kill the child processes
 	onParentProcessExit(() => {
-		pollingInterval.unref();
+		exiting = true;
+		timeout.unref();
 	});
 };
 
+// sourceName identifies a source in logs by its id, api namespace or url, whichever is set first
+const sourceName = (introspection: IntrospectionConfiguration): string => {
+	const { apiNamespace, url } = introspection as { apiNamespace?: string; url?: string };
+	return introspection.id || apiNamespace || url || objectHash(introspection);
+};
+
+// isPinned returns true if the introspection of the source must be taken from the cache
+const isPinned = (introspection: IntrospectionConfiguration): boolean => {
+	const apiNamespace = (introspection as { apiNamespace?: string }).apiNamespace;
+	return WG_PINNED_INTROSPECTIONS.some((name) => name === introspection.id || name === apiNamespace);
+};
+
 export const introspectWithCache = async <Introspection extends IntrospectionConfiguration, A extends ApiType>(
 	introspection: Introspection,
 	generator: (introspection: Introspection) => Promise<Api<A>>
 ): Promise<Api<A>> => {
 	const cacheKey = objectHash(introspection);
+	const pinned = isPinned(introspection);
 
 	/**
 	 * This section is only executed when WG_DATA_SOURCE_POLLING_MODE is set to 'true'
@@ -139,6 +292,7 @@ export const introspectWithCache = async <Introspection extends IntrospectionCon
 	 */
 	if (WG_DATA_SOURCE_POLLING_MODE) {
 		if (
+			!pinned &&
 			introspection.introspection?.pollingIntervalSeconds !== undefined &&
 			introspection.introspection?.pollingIntervalSeconds > 0
 		) {
@@ -154,7 +308,8 @@ export const introspectWithCache = async <Introspection extends IntrospectionCon
 
 	const isIntrospectionDisabledBySource = introspection.introspection?.disableCache === true;
 	const isIntrospectionEnabledByEnv = WG_ENABLE_INTROSPECTION_CACHE;
-	const isIntrospectionCacheEnabled = !isIntrospectionDisabledBySource && isIntrospectionEnabledByEnv;
+	// pinned sources use the cache even if it's disabled otherwise
+	const isIntrospectionCacheEnabled = pinned || (!isIntrospectionDisabledBySource && isIntrospectionEnabledByEnv);
 
 	/**
 	 * As long as the cache is enabled, always try to hit it first
@@ -163,8 +318,19 @@ export const introspectWithCache = async <Introspection extends IntrospectionCon
 	if (isIntrospectionCacheEnabled) {
 		const cacheEntryString = await readIntrospectionCacheFile(cacheKey);
 		if (cacheEntryString) {
-			const cacheEntry = JSON.parse(cacheEntryString) as IntrospectionCacheFile<A>;
-			return fromCacheEntry<A>(cacheEntry);
+			const cacheEntry = parseIntrospectionCacheEntry<A>(cacheEntryString);
+			if (cacheEntry) {
+				Logger.debug(`Using cached introspection ${cacheKey}`);
+				return fromCacheEntry<A>(cacheEntry);
+			}
+			// the entry is introspected again and rewritten below
+			Logger.warn(
+				`Introspection cache entry ${cacheKey} is corrupted, removing it and introspecting the source again.`
+			);
+			await removeIntrospectionCacheFile(cacheKey);
+		}
+		if (pinned) {
+			Logger.info(`No cached introspection for pinned source, introspecting it once.`);
 		}
 	}
 
@@ -181,7 +347,10 @@ export const introspectWithCache = async <Introspection extends IntrospectionCon
 		);
 	}
 
+	Logger.debug(`Introspecting ${cacheKey}, no cached introspection is used`);
+	const introspectionStart = Date.now();
 	const api = await generator(introspection);
+	Logger.debug(`Introspected ${cacheKey} in ${Date.now() - introspectionStart}ms`);
 
 	/*
 	 * We got a result. If the cache is enabled, populate it
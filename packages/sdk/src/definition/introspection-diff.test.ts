@@ -0,0 +1,136 @@
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { graphQLExtensions, readOperationDocuments, relevantSchemaChanges, schemaChanges } from './introspection-diff';
+
+const schema = `
+type Query {
+	users(filter: UserFilter): [User]
+	posts: [Post]
+}
+
+input UserFilter {
+	name: String
+}
+
+type User {
+	id: ID!
+	name: String
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	USER
+}
+
+type Post {
+	id: ID!
+	title: String
+}
+`;
+
+const usersOperation = `
+query Users($filter: UserFilter) {
+	users(filter: $filter) {
+		id
+		name
+	}
+}
+`;
+
+test('ignores descriptions and order', () => {
+	const next = `
+type Post {
+	title: String
+	id: ID!
+}
+
+"the users"
+type User {
+	role: Role
+	name: String
+	id: ID!
+}
+
+enum Role {
+	USER
+	ADMIN
+}
+
+input UserFilter {
+	name: String
+}
+
+type Query {
+	posts: [Post]
+	"all users"
+	users(filter: UserFilter): [User]
+}
+`;
+	expect(schemaChanges(schema, next)).toEqual([]);
+});
+
+test('reports changed types and fields', () => {
+	const next = schema
+		.replace('title: String', 'title: String!')
+		.replace('USER', 'USER\n\tGUEST')
+		.replace('posts: [Post]', 'posts: [Post]\n\tcomments: [Comment]\n}\n\ntype Comment {\n\tid: ID!');
+	expect(schemaChanges(schema, next)).toEqual(['Comment', 'Post.title', 'Query.comments', 'Role']);
+});
+
+test('ignores changes not used by the operations', () => {
+	const next = schema.replace('title: String', 'title: String!').replace('role: Role', 'role: Role\n\temail: String');
+	expect(relevantSchemaChanges(schema, next, [usersOperation])).toEqual([]);
+});
+
+test('reports changes used by the operations', () => {
+	expect(
+		relevantSchemaChanges(schema, schema.replace('name: String\n\trole', 'name: String!\n\trole'), [usersOperation])
+	).toEqual(['User.name']);
+	expect(
+		relevantSchemaChanges(schema, schema.replace('name: String\n}', 'name: String\n\tid: ID!\n}'), [usersOperation])
+	).toEqual(['UserFilter']);
+	// operations written against the next schema
+	const next = schema.replace('title: String', 'title: String\n\tbody: String');
+	expect(relevantSchemaChanges(schema, next, ['{ posts { body } }'])).toEqual(['Post.body']);
+});
+
+test('throws on operations that can not be parsed', () => {
+	const next = schema.replace('title: String', 'title: String!');
+	expect(() => relevantSchemaChanges(schema, next, [usersOperation, '{ posts {'])).toThrow();
+});
+
+describe('readOperationDocuments', () => {
+	const extensions = process.env.WG_OPERATION_EXTENSIONS;
+	let wunderGraphDir: string;
+
+	beforeEach(() => {
+		wunderGraphDir = fs.mkdtempSync(path.join(os.tmpdir(), 'wundergraph-'));
+		fs.mkdirSync(path.join(wunderGraphDir, 'operations', 'users'), { recursive: true });
+		fs.writeFileSync(path.join(wunderGraphDir, 'operations', 'users', 'Users.graphql'), usersOperation);
+		fs.writeFileSync(path.join(wunderGraphDir, 'operations', 'Posts.op'), '{ posts { id } }');
+		fs.writeFileSync(path.join(wunderGraphDir, 'operations', 'Posts.ts'), 'export default {};');
+	});
+
+	afterEach(() => {
+		if (extensions === undefined) {
+			delete process.env.WG_OPERATION_EXTENSIONS;
+		} else {
+			process.env.WG_OPERATION_EXTENSIONS = extensions;
+		}
+		fs.rmSync(wunderGraphDir, { recursive: true, force: true });
+	});
+
+	test('reads .graphql and .gql files by default', async () => {
+		delete process.env.WG_OPERATION_EXTENSIONS;
+		expect(graphQLExtensions()).toEqual(['.graphql', '.gql']);
+		expect(await readOperationDocuments(wunderGraphDir)).toEqual([usersOperation]);
+	});
+
+	test('reads the extensions set by wunderctl', async () => {
+		process.env.WG_OPERATION_EXTENSIONS = '.graphql, op';
+		expect(graphQLExtensions()).toEqual(['.graphql', '.op']);
+		expect((await readOperationDocuments(wunderGraphDir)).sort()).toEqual(['{ posts { id } }', usersOperation].sort());
+	});
+});
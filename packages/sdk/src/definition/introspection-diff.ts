@@ -0,0 +1,208 @@
+import {
+	buildSchema,
+	getNamedType,
+	GraphQLInputType,
+	GraphQLNamedType,
+	GraphQLSchema,
+	isEnumType,
+	isInputObjectType,
+	isInterfaceType,
+	isObjectType,
+	isUnionType,
+	parse,
+	TypeInfo,
+	visit,
+	visitWithTypeInfo,
+} from 'graphql';
+import path from 'path';
+import fsP from 'fs/promises';
+
+/**
+ * schemaChanges returns the coordinates of the types ("User") and fields ("User.email") that differ
+ * between two schemas. Descriptions and the order of types, fields and values are ignored.
+ * Changes to the fields of input types, the values of enums, the members of unions and the
+ * implementations of interfaces are reported on the type, since they affect every use of it.
+ */
+export const schemaChanges = (previous: string, next: string): string[] => {
+	const previousSchema = buildSchema(previous, { assumeValidSDL: true });
+	const nextSchema = buildSchema(next, { assumeValidSDL: true });
+	const typeNames = new Set([...Object.keys(previousSchema.getTypeMap()), ...Object.keys(nextSchema.getTypeMap())]);
+	const changes: string[] = [];
+	typeNames.forEach((typeName) => {
+		if (typeName.startsWith('__')) {
+			return;
+		}
+		const previousType = previousSchema.getType(typeName);
+		const nextType = nextSchema.getType(typeName);
+		if (!previousType || !nextType) {
+			changes.push(typeName);
+			return;
+		}
+		if (typeSignature(previousSchema, previousType) !== typeSignature(nextSchema, nextType)) {
+			changes.push(typeName);
+		}
+		const previousFields = fieldSignatures(previousType);
+		const nextFields = fieldSignatures(nextType);
+		new Set([...previousFields.keys(), ...nextFields.keys()]).forEach((fieldName) => {
+			if (previousFields.get(fieldName) !== nextFields.get(fieldName)) {
+				changes.push(`${typeName}.${fieldName}`);
+			}
+		});
+	});
+	return changes.sort();
+};
+
+// typeSignature prints everything of a type but the fields of object and interface types
+const typeSignature = (schema: GraphQLSchema, type: GraphQLNamedType): string => {
+	if (isObjectType(type)) {
+		return `type:${names(type.getInterfaces())}`;
+	}
+	if (isInterfaceType(type)) {
+		return `interface:${names(type.getInterfaces())}:${names(schema.getPossibleTypes(type))}`;
+	}
+	if (isUnionType(type)) {
+		return `union:${names(type.getTypes())}`;
+	}
+	if (isEnumType(type)) {
+		return `enum:${type
+			.getValues()
+			.map((value) => `${value.name}@${value.deprecationReason ?? ''}`)
+			.sort()
+			.join(',')}`;
+	}
+	if (isInputObjectType(type)) {
+		return `input:${Object.values(type.getFields())
+			.map((field) => `${field.name}:${field.type}=${JSON.stringify(field.defaultValue)}`)
+			.sort()
+			.join(',')}`;
+	}
+	return 'scalar';
+};
+
+const fieldSignatures = (type: GraphQLNamedType): Map<string, string> => {
+	const signatures = new Map<string, string>();
+	if (!isObjectType(type) && !isInterfaceType(type)) {
+		return signatures;
+	}
+	Object.values(type.getFields()).forEach((field) => {
+		const args = field.args
+			.map((arg) => `${arg.name}:${arg.type}=${JSON.stringify(arg.defaultValue)}`)
+			.sort()
+			.join(',');
+		signatures.set(field.name, `(${args}):${field.type}@${field.deprecationReason ?? ''}`);
+	});
+	return signatures;
+};
+
+const names = (types: readonly GraphQLNamedType[]): string =>
+	types
+		.map((type) => type.name)
+		.sort()
+		.join(',');
+
+/**
+ * usedCoordinates returns the coordinates of the types and fields the documents select, pass as
+ * arguments or declare as variables in schema. Fields of other schemas are ignored.
+ */
+export const usedCoordinates = (schema: GraphQLSchema, documents: string[]): Set<string> => {
+	const used = new Set<string>();
+	const useInputType = (inputType: GraphQLInputType | null | undefined) => {
+		const type = inputType && getNamedType(inputType);
+		if (!type || used.has(type.name)) {
+			return;
+		}
+		used.add(type.name);
+		if (isInputObjectType(type)) {
+			Object.values(type.getFields()).forEach((field) => useInputType(field.type));
+		}
+	};
+	documents.forEach((document) => {
+		const typeInfo = new TypeInfo(schema);
+		visit(
+			parse(document),
+			visitWithTypeInfo(typeInfo, {
+				Field: (node) => {
+					const parentType = typeInfo.getParentType();
+					const type = typeInfo.getType();
+					if (parentType) {
+						used.add(parentType.name);
+						used.add(`${parentType.name}.${node.name.value}`);
+					}
+					if (type) {
+						used.add(getNamedType(type).name);
+					}
+				},
+				Argument: () => useInputType(typeInfo.getInputType()),
+				VariableDefinition: () => useInputType(typeInfo.getInputType()),
+				InlineFragment: (node) => {
+					if (node.typeCondition) {
+						used.add(node.typeCondition.name.value);
+					}
+				},
+				FragmentDefinition: (node) => {
+					used.add(node.typeCondition.name.value);
+				},
+			})
+		);
+	});
+	return used;
+};
+
+/**
+ * relevantSchemaChanges returns the changes between two schemas of an api that touch a type or
+ * field used by the documents. Uses are looked up in both schemas, so documents written against
+ * the next schema are taken into account too. Documents that can't be parsed throw, their uses
+ * are unknown.
+ */
+export const relevantSchemaChanges = (previous: string, next: string, documents: string[]): string[] => {
+	const used = usedCoordinates(buildSchema(previous, { assumeValidSDL: true }), documents);
+	usedCoordinates(buildSchema(next, { assumeValidSDL: true }), documents).forEach((coordinate) => used.add(coordinate));
+	return schemaChanges(previous, next).filter((coordinate) => used.has(coordinate));
+};
+
+const defaultGraphQLExtensions = ['.graphql', '.gql'];
+
+/**
+ * graphQLExtensions returns the extensions of GraphQL operation files set by wunderctl up
+ * --operation-extensions in WG_OPERATION_EXTENSIONS, .graphql and .gql if unset.
+ */
+export const graphQLExtensions = (): string[] => {
+	const extensions = (process.env['WG_OPERATION_EXTENSIONS'] || '')
+		.split(',')
+		.map((extension) => extension.trim())
+		.filter((extension) => extension !== '' && extension !== '.')
+		.map((extension) => (extension.startsWith('.') ? extension : `.${extension}`));
+	return extensions.length > 0 ? extensions : defaultGraphQLExtensions;
+};
+
+/**
+ * readOperationDocuments returns the content of the GraphQL operations and fragments of the
+ * .wundergraph directory. TypeScript operations use the GraphQL operations and are skipped.
+ */
+export const readOperationDocuments = async (wunderGraphDir: string): Promise<string[]> => {
+	const extensions = graphQLExtensions();
+	const isGraphQLFile = (file: string): boolean => extensions.some((extension) => file.endsWith(extension));
+	const documents: string[] = [];
+	const walk = async (dir: string) => {
+		let entries;
+		try {
+			entries = await fsP.readdir(dir, { withFileTypes: true });
+		} catch (e) {
+			if (e instanceof Error && e.message.startsWith('ENOENT')) {
+				return;
+			}
+			throw e;
+		}
+		for (const entry of entries) {
+			const entryPath = path.join(dir, entry.name);
+			if (entry.isDirectory()) {
+				await walk(entryPath);
+			} else if (isGraphQLFile(entry.name)) {
+				documents.push(await fsP.readFile(entryPath, 'utf8'));
+			}
+		}
+	};
+	await walk(path.join(wunderGraphDir, 'operations'));
+	await walk(path.join(wunderGraphDir, 'fragments'));
+	return documents;
+};
@@ -1,5 +1,8 @@
 import axiosRetry from 'axios-retry';
 import axios, { AxiosError, AxiosInstance, AxiosRequestConfig } from 'axios';
+import { WG_INTROSPECTION_RETRIES } from './index';
+
+const defaultRetries = 5;
 
 let axiosInstance: AxiosInstance | undefined;
 
@@ -16,7 +19,7 @@ const initAxios = (): AxiosInstance => {
 	const instance = axios.create();
 
 	axiosRetry(instance, {
-		retries: 5,
+		retries: WG_INTROSPECTION_RETRIES >= 0 ? WG_INTROSPECTION_RETRIES : defaultRetries,
 		retryDelay: axiosRetry.exponentialDelay,
 		retryCondition: (error: AxiosError) => {
 			if (error.response) {
@@ -28,3 +31,33 @@ const initAxios = (): AxiosInstance => {
 
 	return instance;
 };
+
+/**
+ * TransientIntrospectionError is thrown if an upstream couldn't be introspected because it's
+ * unreachable, timed out or responded with a server error. Retrying later might succeed.
+ */
+export class TransientIntrospectionError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = 'TransientIntrospectionError';
+	}
+}
+
+export const isTransientFetchError = (e: any): boolean => {
+	if (!axios.isAxiosError(e)) {
+		return false;
+	}
+	if (e.response) {
+		return e.response.status >= 500;
+	}
+	// no response, the upstream is unreachable or the request timed out
+	return true;
+};
+
+// fetchError wraps a failed request of the Fetcher, keeping whether it's transient
+export const fetchError = (message: string, e: any): Error => {
+	if (isTransientFetchError(e)) {
+		return new TransientIntrospectionError(message);
+	}
+	return new Error(message);
+};
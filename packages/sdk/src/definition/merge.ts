@@ -429,6 +429,14 @@ It can only be accessed by internal services, like hooks.
 directive @internalOperation on QUERY | MUTATION | SUBSCRIPTION 
 `;
 
+const cacheSchema = `
+"""
+The @cache directive enables caching for the responses of a query.
+The ttl argument is the number of seconds a response is cached, it's used as max age.
+"""
+directive @cache(ttl: Int!) on QUERY
+`;
+
 const exportSchema = `
 
 """
@@ -530,6 +538,7 @@ const mergeApiSchemas = <T extends {} = {}>(
 	graphQLSchemas.push(buildSchema(dateTimeSchema, { assumeValidSDL: true }));
 	graphQLSchemas.push(buildSchema(uuidSchema, { assumeValidSDL: true }));
 	graphQLSchemas.push(buildSchema(internalSchema, { assumeValidSDL: true }));
+	graphQLSchemas.push(buildSchema(cacheSchema, { assumeValidSDL: true }));
 	graphQLSchemas.push(buildSchema(injectEnvironmentVariableSchema, { assumeValidSDL: true }));
 	graphQLSchemas.push(buildSchema(exportSchema, { assumeValidSDL: true }));
 	graphQLSchemas.push(buildSchema(transformSchema, { assumeValidSDL: true }));
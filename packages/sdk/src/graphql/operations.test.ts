@@ -1453,3 +1453,41 @@ input CreateEnvironment {
   kind: EnvironmentKind!
   edges: [ID!]
 }`;
+
+test('parse operations with cache directive', () => {
+	const file = (name: string, content: string) => ({
+		operation_name: name,
+		api_mount_path: name,
+		file_path: `${name}.graphql`,
+		content,
+	});
+	const actual = parseGraphQLOperations(cacheDirectiveSchema, {
+		graphql_operation_files: [
+			file('Cached', 'query Cached @cache(ttl: 60) { me { name } }'),
+			file('Uncached', 'query Uncached { me { name } }'),
+			file('CachedMutation', 'mutation CachedMutation @cache(ttl: 60) { setName(name: "a") { name } }'),
+			file('VariableTtl', 'query VariableTtl($ttl: Int!) @cache(ttl: $ttl) { me { name } }'),
+		],
+	});
+	const ttl = (name: string) => actual.operations.find((op) => op.Name === name)?.ResponseCacheTtlSeconds;
+	expect(ttl('Cached')).toEqual(60);
+	expect(ttl('Uncached')).toBeUndefined();
+	expect(ttl('CachedMutation')).toBeUndefined();
+	expect(ttl('VariableTtl')).toBeUndefined();
+	expect(actual.operations.find((op) => op.Name === 'Cached')?.CacheConfig?.maxAge).toEqual(60);
+});
+
+const cacheDirectiveSchema = `
+directive @cache(ttl: Int!) on QUERY | MUTATION
+
+type Query {
+  me: User
+}
+
+type Mutation {
+  setName(name: String!): User
+}
+
+type User {
+  name: String
+}`;
@@ -59,6 +59,8 @@ export interface GraphQLOperation {
 		maxAge: number;
 		staleWhileRevalidate: number;
 	};
+	// set by @cache(ttl), only these operations are cached by the dev response cache by default
+	ResponseCacheTtlSeconds?: number;
 	LiveQuery?: {
 		enable: boolean;
 		pollingIntervalSeconds: number;
@@ -247,6 +249,7 @@ export const parseGraphQLOperations = (
 							handleInjectEnvironmentVariableDirective(variable, operation);
 						});
 						operation.Internal = node.directives?.find((d) => d.name.value === 'internalOperation') !== undefined;
+						handleCacheDirective(node, operation);
 						if (wgRoleEnum && wgRoleEnum.kind === 'EnumTypeDefinition') {
 							const rbac = node.directives?.find((d) => d.name.value === 'rbac');
 							rbac?.arguments?.forEach((arg) => {
@@ -305,13 +308,35 @@ export const parseGraphQLOperations = (
 			Logger.error(e);
 			Logger.error(`Operations document: ${operationFile.content}`);
 			Logger.error('No Operations found! Please create at least one Operation in the directory ./operations');
-			Logger.error("Operation files must have the file extension '.graphql', otherwise they are ignored.");
+			Logger.error("Operation files must have the file extension '.graphql' or '.gql', otherwise they are ignored.");
 			Logger.error("Operations don't need to be named, the file name is responsible for the operation name.");
 		}
 	});
 	return parsed;
 };
 
+const handleCacheDirective = (node: OperationDefinitionNode, operation: GraphQLOperation) => {
+	if (node.operation !== 'query') {
+		return;
+	}
+	const directive = node.directives?.find((directive) => directive.name.value === 'cache');
+	if (directive === undefined) {
+		return;
+	}
+	const arg = directive.arguments?.find((arg) => arg.name.value === 'ttl');
+	if (arg === undefined || arg.value.kind !== Kind.INT) {
+		return;
+	}
+	const ttl = parseInt(arg.value.value, 10);
+	operation.CacheConfig = {
+		enable: true,
+		public: false,
+		maxAge: ttl,
+		staleWhileRevalidate: 0,
+	};
+	operation.ResponseCacheTtlSeconds = ttl;
+};
+
 const handleJsonSchemaDirective = (variable: VariableDefinitionNode, operation: GraphQLOperation) => {
 	const variableName = variable.variable.name.value;
 	const directive = variable.directives?.find((directive) => directive.name.value === 'jsonSchema');
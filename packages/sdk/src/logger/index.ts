@@ -42,8 +42,9 @@ const resolvePinoLogLevel = (level: string): PinoLogLevel => {
 
 const initLogger = (): pino.Logger => {
 	const enablePretty = process.env.WG_CLI_LOG_PRETTY === 'true';
+	// WG_CONFIG_DEBUG is only passed to the config runners, see wunderctl up --debug-config
 	const logLevel =
-		process.env.WG_DEBUG_MODE === 'true'
+		process.env.WG_DEBUG_MODE === 'true' || process.env.WG_CONFIG_DEBUG === 'true'
 			? PinoLogLevel.Debug
 			: process.env.WG_CLI_LOG_LEVEL
 			? resolvePinoLogLevel(process.env.WG_CLI_LOG_LEVEL)
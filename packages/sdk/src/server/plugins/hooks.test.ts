@@ -28,6 +28,7 @@ export const getFastify = async (serverConfig: WunderGraphHooksAndServerConfig)
 						interpolationVariablesSchema: '',
 						postResolveTransformations: [],
 						content: '',
+						responseCacheTtlSeconds: 0,
 					},
 				],
 				invalidOperationNames: [],
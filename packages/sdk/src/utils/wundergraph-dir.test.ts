@@ -0,0 +1,50 @@
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { wunderGraphPath } from './wundergraph-dir';
+import { writeConfigJson } from '../configure';
+
+describe('wunderGraphPath', () => {
+	const cwd = process.cwd();
+	const wgDirAbs = process.env.WG_DIR_ABS;
+	let wunderGraphDir: string;
+	let runnerDir: string;
+
+	beforeEach(() => {
+		wunderGraphDir = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'wundergraph-')));
+		runnerDir = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'runner-')));
+		process.env.WG_DIR_ABS = wunderGraphDir;
+		// wunderctl up --runner-cwd
+		process.chdir(runnerDir);
+	});
+
+	afterEach(() => {
+		process.chdir(cwd);
+		if (wgDirAbs === undefined) {
+			delete process.env.WG_DIR_ABS;
+		} else {
+			process.env.WG_DIR_ABS = wgDirAbs;
+		}
+		fs.rmSync(wunderGraphDir, { recursive: true, force: true });
+		fs.rmSync(runnerDir, { recursive: true, force: true });
+	});
+
+	test('resolves against the WunderGraph directory', () => {
+		expect(wunderGraphPath('generated', 'wundergraph.config.json')).toBe(
+			path.join(wunderGraphDir, 'generated', 'wundergraph.config.json')
+		);
+		expect(wunderGraphPath('/tmp/client')).toBe('/tmp/client');
+	});
+
+	test('falls back to the working directory', () => {
+		delete process.env.WG_DIR_ABS;
+		expect(wunderGraphPath('generated')).toBe(path.join(runnerDir, 'generated'));
+	});
+
+	test('writes the config to the WunderGraph directory', () => {
+		fs.mkdirSync(path.join(wunderGraphDir, 'generated'));
+		writeConfigJson('{}');
+		expect(fs.readFileSync(path.join(wunderGraphDir, 'generated', 'wundergraph.config.json'), 'utf8')).toBe('{}');
+		expect(fs.existsSync(path.join(runnerDir, 'generated'))).toBe(false);
+	});
+});
@@ -0,0 +1,9 @@
+import path from 'path';
+
+/**
+ * wunderGraphPath resolves a path relative to the WunderGraph directory. The config runner can run
+ * in another working directory (wunderctl up --runner-cwd), so files of the WunderGraph directory
+ * must never be resolved against process.cwd(). Absolute paths are returned as they are.
+ */
+export const wunderGraphPath = (...segments: string[]): string =>
+	path.resolve(process.env.WG_DIR_ABS || process.cwd(), ...segments);
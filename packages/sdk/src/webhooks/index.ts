@@ -1,11 +1,18 @@
 import { Dirent, promises } from 'fs';
 import path from 'path';
+import { wunderGraphPath } from '../utils/wundergraph-dir';
+
+// comma separated names of webhooks to omit from the config, set by wunderctl up --exclude-webhook
+export const WG_EXCLUDE_WEBHOOKS = (process.env['WG_EXCLUDE_WEBHOOKS'] || '')
+	.split(',')
+	.map((name) => name.trim())
+	.filter((name) => name !== '');
 
 /**
- * Returns the list webhook files in the directory.
+ * Returns the list webhook files in the directory, relative to the WunderGraph directory.
  */
 export const getWebhooks = async (dir: string): Promise<{ filePath: string; name: string }[]> => {
-	const list = await promises.readdir(dir, { withFileTypes: true });
+	const list = await promises.readdir(wunderGraphPath(dir), { withFileTypes: true });
 	return list
 		.filter((file: Dirent) => {
 			return file.isFile() && !file.name.endsWith('.d.ts') && file.name.endsWith('.ts');
@@ -47,12 +47,18 @@ import (
 	"github.com/wundergraph/wundergraph/pkg/engineconfigloader"
 	"github.com/wundergraph/wundergraph/pkg/graphiql"
 	"github.com/wundergraph/wundergraph/pkg/hooks"
+	"github.com/wundergraph/wundergraph/pkg/httpwritetimeout"
 	"github.com/wundergraph/wundergraph/pkg/inputvariables"
 	"github.com/wundergraph/wundergraph/pkg/interpolate"
 	"github.com/wundergraph/wundergraph/pkg/loadvariable"
 	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/otlp"
+	"github.com/wundergraph/wundergraph/pkg/persistedqueries"
 	"github.com/wundergraph/wundergraph/pkg/pool"
 	"github.com/wundergraph/wundergraph/pkg/postresolvetransform"
+	"github.com/wundergraph/wundergraph/pkg/queryplan"
+	"github.com/wundergraph/wundergraph/pkg/ratelimit"
+	"github.com/wundergraph/wundergraph/pkg/responsecache"
 	"github.com/wundergraph/wundergraph/pkg/s3uploadclient"
 	"github.com/wundergraph/wundergraph/pkg/webhookhandler"
 	"github.com/wundergraph/wundergraph/pkg/wgpb"
@@ -107,6 +113,23 @@ type Builder struct {
 	enableDebugMode     bool
 	enableIntrospection bool
 	devMode             bool
+	devAuthBypassUser   *authentication.User
+	persistedQueries    *persistedqueries.Store
+	responseCache       *responsecache.Cache
+	rateLimiter         *ratelimit.Limiter
+	operationTimeouts   map[string]time.Duration
+	sseSubscriptions    bool
+	featureFlags        map[string]bool
+	readOnly            bool
+	// explanations of the plans of all operations, only collected in dev mode
+	explanations map[string]*queryplan.Explanation
+
+	disablePlayground  bool
+	playgroundPath     string
+	playgroundDefaults PlaygroundDefaults
+
+	// graphqlHandler serves the GraphQL endpoint, nil if it's disabled
+	graphqlHandler *GraphQLHandler
 
 	renameTypeNames []resolve.RenameTypeName
 
@@ -122,7 +145,34 @@ type BuilderConfig struct {
 	GitHubAuthDemoClientID     string
 	GitHubAuthDemoClientSecret string
 	DevMode                    bool
-}
+	// DevAuthBypassUser is injected into all unauthenticated requests, only honored in DevMode
+	DevAuthBypassUser *authentication.User
+	// PersistedQueries enables Automatic Persisted Queries on the GraphQL endpoint
+	PersistedQueries *persistedqueries.Store
+	// ResponseCache caches the responses of query operations
+	ResponseCache *responsecache.Cache
+	// RateLimiter limits the requests of single operations, only honored in DevMode
+	RateLimiter *ratelimit.Limiter
+	// OperationTimeouts cancels requests of queries, mutations and functions by operation name
+	// or path after the given durations, only honored in DevMode
+	OperationTimeouts map[string]time.Duration
+	// SSESubscriptions serves the GraphQL endpoint over the GraphQL over SSE protocol as well,
+	// only honored in DevMode
+	SSESubscriptions bool
+	// DisablePlayground stops serving the GraphQL playground, the GraphQL endpoint still works
+	DisablePlayground bool
+	// PlaygroundPath is the path of the GraphQL playground, defaults to DefaultPlaygroundPath
+	PlaygroundPath string
+	// FeatureFlags are the flags enabling the operations below FeatureFlagsDir
+	FeatureFlags map[string]bool
+	// PlaygroundDefaults seed the initial tab of the GraphQL playground
+	PlaygroundDefaults PlaygroundDefaults
+	// ReadOnly rejects all mutations, of the operations and on the GraphQL endpoint
+	ReadOnly bool
+}
+
+// DefaultPlaygroundPath serves the playground on GET requests to the GraphQL endpoint
+const DefaultPlaygroundPath = "/graphql"
 
 func NewBuilder(pool *pool.Pool,
 	log *zap.Logger,
@@ -142,6 +192,18 @@ func NewBuilder(pool *pool.Pool,
 		githubAuthDemoClientID:     config.GitHubAuthDemoClientID,
 		githubAuthDemoClientSecret: config.GitHubAuthDemoClientSecret,
 		devMode:                    config.DevMode,
+		devAuthBypassUser:          config.DevAuthBypassUser,
+		persistedQueries:           config.PersistedQueries,
+		responseCache:              config.ResponseCache,
+		rateLimiter:                config.RateLimiter,
+		operationTimeouts:          config.OperationTimeouts,
+		sseSubscriptions:           config.SSESubscriptions,
+		explanations:               map[string]*queryplan.Explanation{},
+		disablePlayground:          config.DisablePlayground,
+		playgroundPath:             config.PlaygroundPath,
+		featureFlags:               config.FeatureFlags,
+		playgroundDefaults:         config.PlaygroundDefaults,
+		readOnly:                   config.ReadOnly,
 	}
 }
 
@@ -303,6 +365,7 @@ func (r *Builder) BuildAndMountApiHandler(ctx context.Context, router *mux.Route
 				Profiles:        profiles,
 				HooksClient:     r.middlewareClient,
 				Name:            s3Provider.Name,
+				ReportProgress:  r.devMode,
 			},
 		)
 		if err != nil {
@@ -336,6 +399,16 @@ func (r *Builder) BuildAndMountApiHandler(ctx context.Context, router *mux.Route
 		r.registerInvalidOperation(operationName)
 	}
 
+	if r.devMode {
+		r.router.Methods(http.MethodGet).Path(explainApiPath("{operation}")).Handler(&explainHandler{
+			explanations: r.explanations,
+		})
+		r.log.Debug("registered explainHandler",
+			zap.String("method", http.MethodGet),
+			zap.String("path", explainApiPath("{operation}")),
+		)
+	}
+
 	if api.EnableGraphqlEndpoint {
 		graphqlHandler := &GraphQLHandler{
 			planConfig:      r.planConfig,
@@ -347,25 +420,30 @@ func (r *Builder) BuildAndMountApiHandler(ctx context.Context, router *mux.Route
 			prepared:        map[uint64]planWithExtractedVariables{},
 			preparedMux:     &sync.RWMutex{},
 			renameTypeNames: r.renameTypeNames,
+			readOnly:        r.readOnly,
 		}
+		r.graphqlHandler = graphqlHandler
 		apiPath := "/graphql"
-		r.router.Methods(http.MethodPost, http.MethodOptions).Path(apiPath).Handler(graphqlHandler)
+		var handler http.Handler = graphqlHandler
+		if r.persistedQueries != nil {
+			handler = r.persistedQueries.Handler(graphqlHandler)
+		}
+		if r.sseSubscriptions && r.devMode {
+			sseHandler := &graphqlSSEHandler{handler: handler, heartbeat: sseHeartbeatInterval}
+			r.router.Methods(http.MethodGet, http.MethodPost).Path(graphqlStreamPath).Handler(sseHandler)
+			// clients negotiating SSE on the GraphQL endpoint are served the same way
+			r.router.Methods(http.MethodGet, http.MethodPost).Path(apiPath).MatcherFunc(acceptsEventStream).Handler(sseHandler)
+			r.log.Debug("registered GraphQL over SSE handler",
+				zap.String("path", graphqlStreamPath),
+			)
+		}
+		r.router.Methods(http.MethodPost, http.MethodOptions).Path(apiPath).Handler(handler)
 		r.log.Debug("registered GraphQLHandler",
 			zap.String("method", http.MethodPost),
 			zap.String("path", apiPath),
 		)
 
-		graphqlPlaygroundHandler := &GraphQLPlaygroundHandler{
-			log:     r.log,
-			html:    graphiql.GetGraphiqlPlaygroundHTML(),
-			nodeUrl: api.Options.PublicNodeUrl,
-		}
-		r.router.Methods(http.MethodGet, http.MethodOptions).Path(apiPath).Handler(graphqlPlaygroundHandler)
-		r.log.Debug("registered GraphQLPlaygroundHandler",
-			zap.String("method", http.MethodGet),
-			zap.String("path", apiPath),
-		)
-
+		r.registerPlayground(api, apiPath)
 	}
 
 	return streamClosers, err
@@ -443,6 +521,38 @@ func operationApiPath(name string) string {
 	return fmt.Sprintf("/operations/%s", name)
 }
 
+func explainApiPath(name string) string {
+	return fmt.Sprintf("/explain/%s", name)
+}
+
+// Explanation returns the plan of the operation with the given name, only available in dev mode
+func (r *Builder) Explanation(operationName string) (*queryplan.Explanation, bool) {
+	explanation, ok := r.explanations[operationName]
+	return explanation, ok
+}
+
+// WarmPlans prepares the plans of the GraphQL endpoint for the documents of all GraphQL
+// operations, so the first request sending one of them doesn't pay for planning. The plans
+// of the operation endpoints are prepared when they are registered. It returns the number
+// of warmed plans.
+func (r *Builder) WarmPlans(ctx context.Context) int {
+	if r.graphqlHandler == nil || r.api == nil {
+		return 0
+	}
+	warmed := 0
+	for _, operation := range r.api.Operations {
+		if operation.Engine != wgpb.OperationExecutionEngine_ENGINE_GRAPHQL || operation.Content == "" {
+			continue
+		}
+		if err := r.graphqlHandler.warm(ctx, operation.Name, operation.Content); err != nil {
+			r.log.Debug("could not warm plan", zap.String("operation", operation.Name), zap.Error(err))
+			continue
+		}
+		warmed++
+	}
+	return warmed
+}
+
 func (r *Builder) registerInvalidOperation(name string) {
 	apiPath := operationApiPath(name)
 	route := r.router.Methods(http.MethodGet, http.MethodPost, http.MethodOptions).Path(apiPath)
@@ -463,6 +573,14 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 		return nil
 	}
 
+	if r.registerFeatureDisabledOperation(operation) {
+		return nil
+	}
+
+	if r.registerReadOnlyOperation(operation) {
+		return nil
+	}
+
 	apiPath := operationApiPath(operation.Path)
 
 	if operation.Engine == wgpb.OperationExecutionEngine_ENGINE_NODEJS {
@@ -501,6 +619,10 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 	preparedPlan := shared.Planner.Plan(shared.Doc, r.definition, operation.Name, shared.Report)
 	shared.Postprocess.Process(preparedPlan)
 
+	if r.devMode {
+		r.explanations[operation.Name] = queryplan.Explain(operation.Name, preparedPlan)
+	}
+
 	variablesValidator, err := inputvariables.NewValidator(cleanupJsonSchema(operation.VariablesSchema), false)
 	if err != nil {
 		return err
@@ -576,11 +698,20 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 
 		copy(handler.extractedVariables, shared.Doc.Input.Variables)
 
+		queryHandler := r.timeLimited(operation, handler)
+		if r.responseCache != nil {
+			// only operations annotated with @cache(ttl) carry a TTL, enabling HTTP caching
+			// through the caching config doesn't cache their responses in memory
+			ttl := time.Duration(operation.ResponseCacheTtlSeconds) * time.Second
+			queryHandler = r.responseCache.HandlerWithTTL(operation.Name, ttl, queryHandler)
+		}
+		queryHandler = r.rateLimited(operation, queryHandler)
+
 		route := r.router.Methods(http.MethodGet, http.MethodOptions).Path(apiPath)
 		if operation.AuthenticationConfig != nil && operation.AuthenticationConfig.AuthRequired {
-			route.Handler(authentication.RequiresAuthentication(handler))
+			route.Handler(authentication.RequiresAuthentication(queryHandler))
 		} else {
-			route.Handler(handler)
+			route.Handler(queryHandler)
 		}
 
 		operationIsConfigured = true
@@ -625,9 +756,9 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 		route := r.router.Methods(http.MethodPost, http.MethodOptions).Path(apiPath)
 
 		if operation.AuthenticationConfig != nil && operation.AuthenticationConfig.AuthRequired {
-			route.Handler(authentication.RequiresAuthentication(handler))
+			route.Handler(authentication.RequiresAuthentication(r.rateLimited(operation, r.timeLimited(operation, handler))))
 		} else {
-			route.Handler(handler)
+			route.Handler(r.rateLimited(operation, r.timeLimited(operation, handler)))
 		}
 
 		operationIsConfigured = true
@@ -669,9 +800,9 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 		route := r.router.Methods(http.MethodGet, http.MethodOptions).Path(apiPath)
 
 		if operation.AuthenticationConfig != nil && operation.AuthenticationConfig.AuthRequired {
-			route.Handler(authentication.RequiresAuthentication(handler))
+			route.Handler(authentication.RequiresAuthentication(r.rateLimited(operation, handler)))
 		} else {
-			route.Handler(handler)
+			route.Handler(r.rateLimited(operation, handler))
 		}
 
 		operationIsConfigured = true
@@ -692,6 +823,14 @@ func (r *Builder) registerOperation(operation *wgpb.Operation) error {
 	return nil
 }
 
+// rateLimited applies the rate limit of the operation to handler, if any
+func (r *Builder) rateLimited(operation *wgpb.Operation, handler http.Handler) http.Handler {
+	if r.rateLimiter == nil || !r.devMode {
+		return handler
+	}
+	return r.rateLimiter.Handler(operation, handler)
+}
+
 func generateQueryArgumentsAllowList(schema string) []string {
 	var allowList []string
 	schema = cleanupJsonSchema(schema)
@@ -754,14 +893,83 @@ func (r *Builder) Close() error {
 	return nil
 }
 
+// registerPlayground serves the playground on the configured path. When it's disabled or
+// moved, GET requests to the GraphQL endpoint return 404 instead of 405.
+func (r *Builder) registerPlayground(api *Api, graphqlPath string) {
+	playgroundPath := r.playgroundPath
+	if playgroundPath == "" {
+		playgroundPath = DefaultPlaygroundPath
+	}
+	if r.disablePlayground || playgroundPath != graphqlPath {
+		r.router.Methods(http.MethodGet).Path(graphqlPath).Handler(http.NotFoundHandler())
+	}
+	if r.disablePlayground {
+		r.log.Debug("GraphQL playground disabled")
+		return
+	}
+	graphqlPlaygroundHandler := &GraphQLPlaygroundHandler{
+		log:      r.log,
+		html:     graphiql.GetGraphiqlPlaygroundHTML(),
+		nodeUrl:  api.Options.PublicNodeUrl,
+		defaults: r.playgroundDefaults,
+	}
+	r.router.Methods(http.MethodGet, http.MethodOptions).Path(playgroundPath).Handler(graphqlPlaygroundHandler)
+	r.log.Debug("registered GraphQLPlaygroundHandler",
+		zap.String("method", http.MethodGet),
+		zap.String("path", playgroundPath),
+	)
+}
+
+// PlaygroundDefaults are the headers and the query of the initial tab of the GraphQL playground.
+// The playground keeps its tabs in the browser, so they only apply when it's opened the first
+// time. They are visible to everyone opening it, use placeholders instead of secrets.
+type PlaygroundDefaults struct {
+	Headers map[string]string
+	Query   string
+}
+
+// script returns the defaults as a JavaScript object for the playground template
+func (d PlaygroundDefaults) script() (string, error) {
+	var script struct {
+		Headers string `json:"headers,omitempty"`
+		Query   string `json:"query,omitempty"`
+	}
+	script.Query = d.Query
+	if len(d.Headers) != 0 {
+		// the headers are escaped once the script is encoded
+		var headers strings.Builder
+		encoder := json.NewEncoder(&headers)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(d.Headers); err != nil {
+			return "", err
+		}
+		script.Headers = strings.TrimSuffix(headers.String(), "\n")
+	}
+	// json.Marshal escapes <, > and &, so the object can't close the script tag
+	data, err := json.Marshal(script)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 type GraphQLPlaygroundHandler struct {
-	log     *zap.Logger
-	html    string
-	nodeUrl string
+	log      *zap.Logger
+	html     string
+	nodeUrl  string
+	defaults PlaygroundDefaults
 }
 
 func (h *GraphQLPlaygroundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defaults, err := h.defaults.script()
+	if err != nil {
+		h.log.Error("could not encode playground defaults", zap.Error(err))
+		http.Error(w, "could not render playground", http.StatusInternalServerError)
+		return
+	}
 	tpl := strings.Replace(h.html, "{{apiURL}}", h.nodeUrl, 1)
+	tpl = strings.Replace(tpl, "{{playgroundDefaults}}", defaults, 1)
 	resp := []byte(tpl)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -769,6 +977,27 @@ func (h *GraphQLPlaygroundHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	_, _ = w.Write(resp)
 }
 
+// explainHandler serves the plan of an operation as text or, with ?format=json, as JSON
+type explainHandler struct {
+	explanations map[string]*queryplan.Explanation
+}
+
+func (h *explainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	explanation, ok := h.explanations[mux.Vars(r)["operation"]]
+	if !ok {
+		http.Error(w, "operation not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(explanation)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = explanation.WriteText(w)
+}
+
 type GraphQLHandler struct {
 	planConfig plan.Configuration
 	definition *ast.Document
@@ -781,6 +1010,9 @@ type GraphQLHandler struct {
 	preparedMux *sync.RWMutex
 
 	renameTypeNames []resolve.RenameTypeName
+
+	// readOnly rejects mutations
+	readOnly bool
 }
 
 type planWithExtractedVariables struct {
@@ -835,6 +1067,12 @@ func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.readOnly && selectsMutation(shared.Doc, requestOperationName) {
+		requestLogger.Warn("rejected mutation in read-only mode", zap.ByteString("operationName", requestOperationName))
+		writeReadOnlyError(w)
+		return
+	}
+
 	_, _ = shared.Hash.Write(requestOperationName)
 
 	err = shared.Printer.Print(shared.Doc, h.definition, shared.Hash)
@@ -857,7 +1095,11 @@ func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	prepared, exists := h.prepared[operationHash]
 	h.preparedMux.RUnlock()
 	if !exists {
+		_, planSpan := otlp.Start(r.Context(), "plan", otlp.SpanKindInternal)
+		planSpan.SetAttribute("graphql.operation.name", string(requestOperationName))
 		prepared, err = h.preparePlan(operationHash, requestOperationName, shared)
+		planSpan.SetError(err)
+		planSpan.End()
 		if err != nil {
 			if shared.Report.HasErrors() {
 				h.logInternalErrors(shared.Report, requestLogger)
@@ -965,6 +1207,36 @@ func (h *GraphQLHandler) writeRequestErrors(report *operationreport.Report, w ht
 	}
 }
 
+// warm prepares the plan of a request with the given query and operationName, the plan
+// is cached under the same hash ServeHTTP computes for such a request
+func (h *GraphQLHandler) warm(ctx context.Context, operationName, query string) error {
+	shared := h.pool.GetShared(ctx, h.planConfig, pool.Config{
+		RenameTypeNames: h.renameTypeNames,
+	})
+	defer h.pool.PutShared(shared)
+
+	shared.Doc.Input.ResetInputString(query)
+	shared.Parser.Parse(shared.Doc, shared.Report)
+	if shared.Report.HasErrors() {
+		return shared.Report
+	}
+
+	_, _ = shared.Hash.Write([]byte(operationName))
+	if err := shared.Printer.Print(shared.Doc, h.definition, shared.Hash); err != nil {
+		return err
+	}
+	operationHash := shared.Hash.Sum64()
+
+	h.preparedMux.RLock()
+	_, exists := h.prepared[operationHash]
+	h.preparedMux.RUnlock()
+	if exists {
+		return nil
+	}
+	_, err := h.preparePlan(operationHash, []byte(operationName), shared)
+	return err
+}
+
 func (h *GraphQLHandler) preparePlan(operationHash uint64, requestOperationName []byte, shared *pool.Shared) (planWithExtractedVariables, error) {
 	preparedPlan, err, _ := h.sf.Do(strconv.Itoa(int(operationHash)), func() (interface{}, error) {
 		if len(requestOperationName) == 0 {
@@ -1344,7 +1616,7 @@ func (h *QueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "requires flushing", http.StatusBadRequest)
 			return
 		}
-		setSubscriptionHeaders(w)
+		setSubscriptionHeaders(w, r)
 		flusher.Flush()
 	} else {
 		w.Header().Set("Content-Type", "application/json")
@@ -1965,6 +2237,9 @@ func (r *Builder) registerAuth(insecureCookies bool) error {
 	}
 
 	r.router.Use(authentication.NewLoadUserMw(loadUserConfig))
+	if r.devMode && r.devAuthBypassUser != nil {
+		r.router.Use(authentication.NewDevAuthBypassMw(r.log, r.devAuthBypassUser))
+	}
 	r.router.Use(authentication.NewCSRFMw(authentication.CSRFConfig{
 		InsecureCookies: insecureCookies,
 		Secret:          csrfSecret,
@@ -2188,9 +2463,9 @@ func (r *Builder) registerNodejsOperation(operation *wgpb.Operation, apiPath str
 	}
 
 	if operation.AuthenticationConfig != nil && operation.AuthenticationConfig.AuthRequired {
-		route.Handler(authentication.RequiresAuthentication(handler))
+		route.Handler(authentication.RequiresAuthentication(r.rateLimited(operation, r.timeLimited(operation, handler))))
 	} else {
-		route.Handler(handler)
+		route.Handler(r.rateLimited(operation, r.timeLimited(operation, handler)))
 	}
 
 	r.log.Debug("registered FunctionsHandler",
@@ -2369,7 +2644,7 @@ func (h *FunctionsHandler) handleRequest(ctx context.Context, w http.ResponseWri
 func (h *FunctionsHandler) handleSubscriptionRequest(ctx context.Context, w http.ResponseWriter, r *http.Request, input []byte, requestLogger *zap.Logger) {
 	wgParams := NewWgRequestParams(r.URL)
 
-	setSubscriptionHeaders(w)
+	setSubscriptionHeaders(w, r)
 	buf := pool.GetBytesBuffer()
 	defer pool.PutBytesBuffer(buf)
 	err := h.hooksClient.DoFunctionSubscriptionRequest(ctx, h.operation.Path, input, wgParams.SubsribeOnce, wgParams.UseSse, wgParams.UseJsonPatch, w, buf)
@@ -2431,6 +2706,7 @@ func (o *OperationMetaData) GetOperationTypeString() string {
 }
 
 func setOperationMetaData(r *http.Request, operation *wgpb.Operation) *http.Request {
+	logging.AccessLogEntryFromContext(r.Context()).SetOperationName(operation.Name)
 	metaData := &OperationMetaData{
 		OperationName: operation.Name,
 		OperationType: operation.OperationType,
@@ -2446,7 +2722,9 @@ func getOperationMetaData(r *http.Request) *OperationMetaData {
 	return maybeMetaData.(*OperationMetaData)
 }
 
-func setSubscriptionHeaders(w http.ResponseWriter) {
+// setSubscriptionHeaders prepares a long-lived streaming response, which is exempt from write timeouts
+func setSubscriptionHeaders(w http.ResponseWriter, r *http.Request) {
+	httpwritetimeout.Disable(r)
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
@@ -2479,7 +2757,7 @@ func getFlushWriter(ctx context.Context, variables []byte, r *http.Request, w ht
 	}
 
 	if !wgParams.SubsribeOnce {
-		setSubscriptionHeaders(w)
+		setSubscriptionHeaders(w, r)
 	}
 
 	flusher.Flush()
@@ -2518,7 +2796,7 @@ func handleOperationErr(log *zap.Logger, err error, w http.ResponseWriter, error
 			zap.String("operationName", operation.Name),
 			zap.String("operationType", operation.OperationType.String()),
 		)
-		w.WriteHeader(http.StatusGatewayTimeout)
+		writeOperationTimeout(w, operation)
 		return true
 	}
 	var validationError *inputvariables.ValidationError
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gavv/httpexpect/v2"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
@@ -1072,3 +1073,46 @@ func TestLogMiddleware_Debug(t *testing.T) {
 
 	})
 }
+
+func TestRegisterPlayground(t *testing.T) {
+	api := &Api{Options: &Options{PublicNodeUrl: "http://localhost:9991"}}
+	serve := func(b *Builder) *httpexpect.Expect {
+		b.router = mux.NewRouter()
+		b.log = zap.NewNop()
+		b.registerPlayground(api, "/graphql")
+		srv := httptest.NewServer(b.router)
+		t.Cleanup(srv.Close)
+		return httpexpect.New(t, srv.URL)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		e := serve(&Builder{})
+		e.GET("/graphql").Expect().Status(http.StatusOK).ContentType("text/html")
+	})
+
+	t.Run("custom path", func(t *testing.T) {
+		e := serve(&Builder{playgroundPath: "/__playground"})
+		e.GET("/__playground").Expect().Status(http.StatusOK).ContentType("text/html")
+		e.GET("/graphql").Expect().Status(http.StatusNotFound)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		e := serve(&Builder{})
+		e.GET("/graphql").Expect().Body().Contains("var playgroundDefaults = {};")
+
+		e = serve(&Builder{playgroundDefaults: PlaygroundDefaults{
+			Headers: map[string]string{"Authorization": "Bearer <token>"},
+			Query:   "query { me { id } }",
+		}})
+		body := e.GET("/graphql").Expect().Status(http.StatusOK).Body()
+		body.Contains(`"query":"query { me { id } }"`)
+		body.Contains(`\"Authorization\": \"Bearer \u003ctoken\u003e\"`)
+		body.NotContains("{{playgroundDefaults}}")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		e := serve(&Builder{disablePlayground: true, playgroundPath: "/__playground"})
+		e.GET("/graphql").Expect().Status(http.StatusNotFound)
+		e.GET("/__playground").Expect().Status(http.StatusNotFound)
+	})
+}
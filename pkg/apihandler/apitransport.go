@@ -116,6 +116,7 @@ type Claims struct {
 
 func (t *ApiTransport) RoundTrip(request *http.Request) (*http.Response, error) {
 	request.Header.Set(logging.RequestIDHeader, logging.RequestIDFromContext(request.Context()))
+	logging.AccessLogEntryFromContext(request.Context()).AddUpstreamRequest()
 
 	if request.Header.Get(WgInternalApiCallHeader) == "true" {
 		return t.internalGraphQLRoundTrip(request)
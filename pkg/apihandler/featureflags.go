@@ -0,0 +1,63 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// FeatureFlagsDir holds the operations which are only served with a feature flag, the directory
+// below it names the flag, e.g. operations/flags/newCheckout/Checkout.graphql requires newCheckout
+const FeatureFlagsDir = "flags"
+
+// RequiredFeatureFlag returns the feature flag the operation at path requires, if any
+func RequiredFeatureFlag(operationPath string) string {
+	if !strings.HasPrefix(operationPath, FeatureFlagsDir+"/") {
+		return ""
+	}
+	flag, _, ok := strings.Cut(strings.TrimPrefix(operationPath, FeatureFlagsDir+"/"), "/")
+	if !ok {
+		return ""
+	}
+	return flag
+}
+
+// featureDisabledHandler answers requests of an operation whose feature flag isn't set
+type featureDisabledHandler struct {
+	operation *wgpb.Operation
+	flag      string
+}
+
+func (h *featureDisabledHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{
+			"message": fmt.Sprintf("operation %s is not enabled, it requires the feature flag %s", h.operation.Name, h.flag),
+		}},
+	})
+}
+
+// registerFeatureDisabledOperation returns true if the operation requires a feature flag which
+// isn't set and registers a handler rejecting its requests instead of the operation
+func (r *Builder) registerFeatureDisabledOperation(operation *wgpb.Operation) bool {
+	flag := RequiredFeatureFlag(operation.Path)
+	if flag == "" || r.featureFlags[flag] {
+		return false
+	}
+	apiPath := operationApiPath(operation.Path)
+	r.router.Methods(http.MethodGet, http.MethodPost, http.MethodOptions).Path(apiPath).Handler(&featureDisabledHandler{
+		operation: operation,
+		flag:      flag,
+	})
+	r.log.Info("operation disabled by feature flag",
+		zap.String("operation", operation.Name),
+		zap.String("featureFlag", flag),
+	)
+	return true
+}
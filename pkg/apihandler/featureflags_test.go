@@ -0,0 +1,37 @@
+package apihandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestRequiredFeatureFlag(t *testing.T) {
+	assert.Equal(t, "newCheckout", RequiredFeatureFlag("flags/newCheckout/Checkout"))
+	assert.Equal(t, "newCheckout", RequiredFeatureFlag("flags/newCheckout/nested/Checkout"))
+	assert.Equal(t, "", RequiredFeatureFlag("flags/Checkout"))
+	assert.Equal(t, "", RequiredFeatureFlag("users/flags/Checkout"))
+	assert.Equal(t, "", RequiredFeatureFlag("Checkout"))
+}
+
+func TestRegisterFeatureDisabledOperation(t *testing.T) {
+	operation := &wgpb.Operation{Name: "FlagsNewCheckoutCheckout", Path: "flags/newCheckout/Checkout"}
+	r := &Builder{log: zap.NewNop(), router: mux.NewRouter(), featureFlags: map[string]bool{"other": true}}
+	require.True(t, r.registerFeatureDisabledOperation(operation))
+	assert.False(t, r.registerFeatureDisabledOperation(&wgpb.Operation{Name: "Checkout", Path: "Checkout"}))
+
+	rec := httptest.NewRecorder()
+	r.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/flags/newCheckout/Checkout", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"errors":[{"message":"operation FlagsNewCheckoutCheckout is not enabled, it requires the feature flag newCheckout"}]}`, rec.Body.String())
+
+	r = &Builder{log: zap.NewNop(), router: mux.NewRouter(), featureFlags: map[string]bool{"newCheckout": true}}
+	assert.False(t, r.registerFeatureDisabledOperation(operation))
+}
@@ -0,0 +1,198 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wundergraph/wundergraph/pkg/httpwritetimeout"
+)
+
+// graphqlStreamPath serves the GraphQL endpoint over the GraphQL over Server-Sent Events
+// protocol in distinct connections mode, see https://github.com/enisdenjo/graphql-sse/blob/master/PROTOCOL.md
+const graphqlStreamPath = "/graphql/stream"
+
+// sseHeartbeatInterval is short enough to keep common proxies from closing idle streams
+const sseHeartbeatInterval = 12 * time.Second
+
+// acceptsEventStream returns true if the client prefers text/event-stream responses
+func acceptsEventStream(r *http.Request, _ *mux.RouteMatch) bool {
+	for _, value := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err == nil && mediaType == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+// graphqlSSEHandler adapts the GraphQL handler to the GraphQL over SSE protocol. Every
+// response, including the results of queries and mutations, is sent as a next event
+// followed by a complete event. Errors before the first event keep their status code.
+type graphqlSSEHandler struct {
+	handler   http.Handler
+	heartbeat time.Duration
+}
+
+func (h *graphqlSSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Connection not flushable", http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodGet {
+		body, err := graphqlSSERequestBody(r)
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+	}
+	httpwritetimeout.Disable(r)
+
+	sw := &sseResponseWriter{writer: w, flusher: flusher}
+	done := make(chan struct{})
+	defer close(done)
+	go sw.heartbeats(h.heartbeat, done)
+
+	h.handler.ServeHTTP(sw, r)
+	sw.complete()
+}
+
+// graphqlSSERequestBody builds the JSON body of the GraphQL handler from the query parameters of a GET request
+func graphqlSSERequestBody(r *http.Request) ([]byte, error) {
+	query := r.URL.Query()
+	request := struct {
+		Query         string          `json:"query"`
+		OperationName string          `json:"operationName,omitempty"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+		Extensions    json.RawMessage `json:"extensions,omitempty"`
+	}{
+		Query:         query.Get("query"),
+		OperationName: query.Get("operationName"),
+	}
+	if variables := query.Get("variables"); variables != "" {
+		request.Variables = json.RawMessage(variables)
+	}
+	if extensions := query.Get("extensions"); extensions != "" {
+		request.Extensions = json.RawMessage(extensions)
+	}
+	// Marshal validates the raw messages
+	return json.Marshal(request)
+}
+
+// sseResponseWriter frames every flushed message as a next event. Writes are buffered
+// until the next flush, so a response failing before it can still change its status.
+type sseResponseWriter struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started bool
+	// status is set if the response failed before the stream started, it's sent as is
+	status int
+	// closed is set once the handler returned, the writer must not be used anymore
+	closed bool
+}
+
+func (w *sseResponseWriter) Header() http.Header {
+	return w.writer.Header()
+}
+
+func (w *sseResponseWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started || w.status != 0 || statusCode < http.StatusBadRequest {
+		return
+	}
+	w.status = statusCode
+	if w.buf.Len() != 0 {
+		// a GraphQL error response written before its status
+		w.writer.Header().Set("Content-Type", "application/json")
+	}
+	w.writer.WriteHeader(statusCode)
+	_, _ = w.buf.WriteTo(w.writer)
+}
+
+func (w *sseResponseWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status != 0 {
+		return w.writer.Write(p)
+	}
+	return w.buf.Write(p)
+}
+
+func (w *sseResponseWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+}
+
+func (w *sseResponseWriter) flushLocked() {
+	if w.status != 0 {
+		w.flusher.Flush()
+		return
+	}
+	if !w.started {
+		w.started = true
+		header := w.writer.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+		w.writer.WriteHeader(http.StatusOK)
+	}
+	if data := bytes.TrimSpace(w.buf.Bytes()); len(data) != 0 {
+		_, _ = w.writer.Write([]byte("event: next\n"))
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			_, _ = w.writer.Write([]byte("data: "))
+			_, _ = w.writer.Write(line)
+			_, _ = w.writer.Write([]byte("\n"))
+		}
+		_, _ = w.writer.Write([]byte("\n"))
+	}
+	w.buf.Reset()
+	w.flusher.Flush()
+}
+
+// complete sends the remaining response and ends the stream
+func (w *sseResponseWriter) complete() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.status != 0 {
+		return
+	}
+	w.flushLocked()
+	_, _ = w.writer.Write([]byte("event: complete\ndata:\n\n"))
+	w.flusher.Flush()
+}
+
+// heartbeats sends a comment every interval once the stream started, until done is closed
+func (w *sseResponseWriter) heartbeats(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.started && !w.closed {
+				_, _ = w.writer.Write([]byte(":\n\n"))
+				w.flusher.Flush()
+			}
+			w.mu.Unlock()
+		}
+	}
+}
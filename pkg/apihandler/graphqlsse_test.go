@@ -0,0 +1,85 @@
+package apihandler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLSSEHandler(t *testing.T) {
+	var body string
+	handler := &graphqlSSEHandler{
+		heartbeat: time.Hour,
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+			if strings.Contains(body, "subscription") {
+				for _, message := range []string{`{"data":{"count":1}}`, `{"data":{"count":2}}`} {
+					_, _ = w.Write([]byte(message + "\n\n"))
+					w.(http.Flusher).Flush()
+				}
+				return
+			}
+			if strings.Contains(body, "invalid") {
+				_, _ = w.Write([]byte(`{"errors":[{"message":"invalid"}]}`))
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"hello":"world"}}`))
+		}),
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, graphqlStreamPath, strings.NewReader(`{"query":"{hello}"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "event: next\ndata: {\"data\":{\"hello\":\"world\"}}\n\nevent: complete\ndata:\n\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, graphqlStreamPath, strings.NewReader(`{"query":"subscription {count}"}`)))
+	assert.Equal(t, "event: next\ndata: {\"data\":{\"count\":1}}\n\nevent: next\ndata: {\"data\":{\"count\":2}}\n\nevent: complete\ndata:\n\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, graphqlStreamPath, strings.NewReader(`{"query":"invalid"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `{"errors":[{"message":"invalid"}]}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, graphqlStreamPath+`?query=subscription+%7Bcount%7D&variables=%7B%22a%22%3A1%7D`, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"query":"subscription {count}","variables":{"a":1}}`, body)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, graphqlStreamPath+`?query=x&variables=%7Bbroken`, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSSEHeartbeats(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &sseResponseWriter{writer: rec, flusher: rec}
+	done := make(chan struct{})
+	go w.heartbeats(time.Millisecond, done)
+
+	w.Flush()
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return strings.Contains(rec.Body.String(), ":\n\n")
+	}, time.Second, time.Millisecond)
+	close(done)
+}
+
+func TestAcceptsEventStream(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	assert.False(t, acceptsEventStream(r, nil))
+	r.Header.Set("Accept", "application/json, text/event-stream;q=0.9")
+	assert.True(t, acceptsEventStream(r, nil))
+}
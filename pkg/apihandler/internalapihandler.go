@@ -40,14 +40,17 @@ type InternalBuilder struct {
 	router           *mux.Router
 	renameTypeNames  []resolve.RenameTypeName
 	middlewareClient *hooks.Client
+	// readOnly rejects mutations
+	readOnly bool
 }
 
-func NewInternalBuilder(pool *pool.Pool, log *zap.Logger, hooksClient *hooks.Client, loader *engineconfigloader.EngineConfigLoader) *InternalBuilder {
+func NewInternalBuilder(pool *pool.Pool, log *zap.Logger, hooksClient *hooks.Client, loader *engineconfigloader.EngineConfigLoader, readOnly bool) *InternalBuilder {
 	return &InternalBuilder{
 		pool:             pool,
 		log:              log,
 		loader:           loader,
 		middlewareClient: hooksClient,
+		readOnly:         readOnly,
 	}
 }
 
@@ -110,6 +113,10 @@ func (i *InternalBuilder) BuildAndMountInternalApiHandler(ctx context.Context, r
 
 func (i *InternalBuilder) registerOperation(operation *wgpb.Operation) error {
 
+	if i.registerReadOnlyOperation(operation) {
+		return nil
+	}
+
 	apiPath := operationApiPath(operation.Path)
 
 	if operation.Engine == wgpb.OperationExecutionEngine_ENGINE_NODEJS {
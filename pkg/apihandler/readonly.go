@@ -0,0 +1,86 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// errReadOnly is the error of requests rejected in read-only mode
+const errReadOnly = "mutations are rejected, the node is in read-only mode"
+
+// writeReadOnlyError responds with 403 and errReadOnly
+func writeReadOnlyError(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": errReadOnly}},
+	})
+}
+
+// readOnlyHandler answers requests of a mutation in read-only mode
+type readOnlyHandler struct {
+	log       *zap.Logger
+	operation *wgpb.Operation
+}
+
+func (h *readOnlyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodOptions {
+		h.log.Warn("rejected mutation in read-only mode",
+			logging.WithRequestIDFromContext(r.Context()),
+			zap.String("operationName", h.operation.Name),
+		)
+	}
+	writeReadOnlyError(w)
+}
+
+// registerReadOnlyOperation returns true if the operation is a mutation and the node is in
+// read-only mode, it registers a handler rejecting its requests instead of the operation
+func (r *Builder) registerReadOnlyOperation(operation *wgpb.Operation) bool {
+	if !r.readOnly || operation.OperationType != wgpb.OperationType_MUTATION {
+		return false
+	}
+	apiPath := operationApiPath(operation.Path)
+	r.router.Methods(http.MethodGet, http.MethodPost, http.MethodOptions).Path(apiPath).Handler(&readOnlyHandler{
+		log:       r.log,
+		operation: operation,
+	})
+	r.log.Debug("mutation disabled by read-only mode", zap.String("operation", operation.Name))
+	return true
+}
+
+// registerReadOnlyOperation is like Builder.registerReadOnlyOperation for the internal API,
+// so hooks can't run mutations in read-only mode either
+func (i *InternalBuilder) registerReadOnlyOperation(operation *wgpb.Operation) bool {
+	if !i.readOnly || operation.OperationType != wgpb.OperationType_MUTATION {
+		return false
+	}
+	apiPath := operationApiPath(operation.Path)
+	i.router.Methods(http.MethodPost).Path(apiPath).Handler(&readOnlyHandler{
+		log:       i.log,
+		operation: operation,
+	})
+	i.log.Debug("internal mutation disabled by read-only mode", zap.String("operation", operation.Name))
+	return true
+}
+
+// selectsMutation returns true if the operation of doc selected by operationName is a mutation,
+// without a name the first operation is selected
+func selectsMutation(doc *ast.Document, operationName []byte) bool {
+	for _, node := range doc.RootNodes {
+		if node.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if len(operationName) != 0 && !bytes.Equal(doc.OperationDefinitionNameBytes(node.Ref), operationName) {
+			continue
+		}
+		return doc.OperationDefinitions[node.Ref].OperationType == ast.OperationTypeMutation
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package apihandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestRegisterReadOnlyOperation(t *testing.T) {
+	r := &Builder{log: zap.NewNop(), router: mux.NewRouter(), readOnly: true}
+	require.True(t, r.registerReadOnlyOperation(&wgpb.Operation{Name: "CreateUser", Path: "CreateUser", OperationType: wgpb.OperationType_MUTATION}))
+	assert.False(t, r.registerReadOnlyOperation(&wgpb.Operation{Name: "Users", Path: "Users", OperationType: wgpb.OperationType_QUERY}))
+
+	rec := httptest.NewRecorder()
+	r.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/operations/CreateUser", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.JSONEq(t, `{"errors":[{"message":"mutations are rejected, the node is in read-only mode"}]}`, rec.Body.String())
+
+	r = &Builder{log: zap.NewNop(), router: mux.NewRouter()}
+	assert.False(t, r.registerReadOnlyOperation(&wgpb.Operation{Name: "CreateUser", Path: "CreateUser", OperationType: wgpb.OperationType_MUTATION}))
+}
+
+func TestInternalRegisterReadOnlyOperation(t *testing.T) {
+	i := &InternalBuilder{log: zap.NewNop(), router: mux.NewRouter(), readOnly: true}
+	require.True(t, i.registerReadOnlyOperation(&wgpb.Operation{Name: "CreateUser", Path: "CreateUser", OperationType: wgpb.OperationType_MUTATION}))
+	assert.False(t, i.registerReadOnlyOperation(&wgpb.Operation{Name: "Users", Path: "Users", OperationType: wgpb.OperationType_QUERY}))
+
+	rec := httptest.NewRecorder()
+	i.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/operations/CreateUser", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.JSONEq(t, `{"errors":[{"message":"mutations are rejected, the node is in read-only mode"}]}`, rec.Body.String())
+
+	// registerOperation stops at the guard, before the operation is parsed
+	i = &InternalBuilder{log: zap.NewNop(), router: mux.NewRouter(), readOnly: true}
+	require.NoError(t, i.registerOperation(&wgpb.Operation{Name: "CreateUser", Path: "CreateUser", OperationType: wgpb.OperationType_MUTATION}))
+	rec = httptest.NewRecorder()
+	i.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/operations/CreateUser", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	i = &InternalBuilder{log: zap.NewNop(), router: mux.NewRouter()}
+	assert.False(t, i.registerReadOnlyOperation(&wgpb.Operation{Name: "CreateUser", Path: "CreateUser", OperationType: wgpb.OperationType_MUTATION}))
+}
+
+func TestSelectsMutation(t *testing.T) {
+	doc, report := astparser.ParseGraphqlDocumentString(`query Users { users { id } } mutation CreateUser { createUser { id } }`)
+	require.False(t, report.HasErrors())
+	assert.False(t, selectsMutation(&doc, nil))
+	assert.False(t, selectsMutation(&doc, []byte("Users")))
+	assert.True(t, selectsMutation(&doc, []byte("CreateUser")))
+	assert.False(t, selectsMutation(&doc, []byte("Unknown")))
+
+	doc, report = astparser.ParseGraphqlDocumentString(`mutation { createUser { id } }`)
+	require.False(t, report.HasErrors())
+	assert.True(t, selectsMutation(&doc, nil))
+}
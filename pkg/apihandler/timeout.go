@@ -0,0 +1,92 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// operationTimeout cancels requests of the operation after timeout. Handlers surface the
+// cancellation through handleOperationErr, requests they leave unanswered get a 504 here.
+// Live queries are long-lived by design and never time out.
+type operationTimeout struct {
+	log       *zap.Logger
+	operation *wgpb.Operation
+	timeout   time.Duration
+	next      http.Handler
+}
+
+func (h *operationTimeout) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions || r.URL.Query().Has(WgLiveParam) {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+	tw := &timeoutResponseWriter{ResponseWriter: w}
+	h.next.ServeHTTP(tw, r.WithContext(ctx))
+	// the deadline of the client or a parent might have passed as well
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) || r.Context().Err() != nil {
+		return
+	}
+	h.log.Warn("operation cancelled by its timeout",
+		logging.WithRequestIDFromContext(r.Context()),
+		zap.String("operationName", h.operation.Name),
+		zap.Duration("timeout", h.timeout),
+	)
+	if !tw.wroteHeader {
+		writeOperationTimeout(w, h.operation)
+	}
+}
+
+// writeOperationTimeout responds with 504 and an error naming the operation
+func writeOperationTimeout(w http.ResponseWriter, operation *wgpb.Operation) {
+	http.Error(w, fmt.Sprintf("operation %s timed out", operation.Name), http.StatusGatewayTimeout)
+}
+
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *timeoutResponseWriter) WriteHeader(statusCode int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// timeLimited applies the timeout of the operation to handler, if any
+func (r *Builder) timeLimited(operation *wgpb.Operation, handler http.Handler) http.Handler {
+	if !r.devMode {
+		return handler
+	}
+	timeout, ok := r.operationTimeouts[operation.Name]
+	if !ok {
+		if timeout, ok = r.operationTimeouts[operation.Path]; !ok {
+			return handler
+		}
+	}
+	return &operationTimeout{
+		log:       r.log,
+		operation: operation,
+		timeout:   timeout,
+		next:      handler,
+	}
+}
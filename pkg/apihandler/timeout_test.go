@@ -0,0 +1,61 @@
+package apihandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestOperationTimeout(t *testing.T) {
+	operation := &wgpb.Operation{Name: "slowReport", Path: "reports/slow"}
+	builder := &Builder{
+		log:               zap.NewNop(),
+		devMode:           true,
+		operationTimeouts: map[string]time.Duration{"reports/slow": 10 * time.Millisecond},
+	}
+	waitForCancellation := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	rec := httptest.NewRecorder()
+	builder.timeLimited(operation, waitForCancellation).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/reports/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, "operation slowReport timed out\n", rec.Body.String())
+
+	// handlers answering the cancellation themselves keep their response
+	rec = httptest.NewRecorder()
+	builder.timeLimited(operation, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		handleOperationErr(zap.NewNop(), r.Context().Err(), w, "hooks pipeline failed", operation)
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/reports/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, "operation slowReport timed out\n", rec.Body.String())
+
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	rec = httptest.NewRecorder()
+	builder.timeLimited(operation, fast).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/reports/slow", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	// live queries and operations without a timeout are untouched
+	handler := builder.timeLimited(&wgpb.Operation{Name: "fastReport"}, fast)
+	assert.IsType(t, fast, handler)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		assert.NoError(t, r.Context().Err())
+	})
+	rec = httptest.NewRecorder()
+	builder.timeLimited(operation, slow).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/reports/slow?"+WgLiveParam, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	builder.devMode = false
+	assert.IsType(t, waitForCancellation, builder.timeLimited(operation, waitForCancellation))
+}
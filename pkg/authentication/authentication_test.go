@@ -33,3 +33,18 @@ func TestCustomClaims(t *testing.T) {
 	const testTag = "origin"
 	assert.Truef(t, isCustomClaim(testTag), "%s should be a custom tag", testTag)
 }
+
+func TestUserFromClaims(t *testing.T) {
+	user, err := UserFromClaims(map[string]interface{}{
+		"sub":   "user1",
+		"email": "user1@example.com",
+		"roles": []interface{}{"admin"},
+		"org":   "wundergraph",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", user.UserID)
+	assert.Equal(t, "user1@example.com", user.Email)
+	assert.Equal(t, []string{"admin"}, user.Roles)
+	assert.Equal(t, "wundergraph", user.CustomClaims["org"])
+	assert.Equal(t, devAuthBypassProviderName, user.ProviderName)
+}
@@ -0,0 +1,103 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+const devAuthBypassProviderName = "dev-auth-bypass"
+
+// UserFromClaims creates a synthetic user from the given token claims, using
+// the same mapping as claims loaded from a JWT or a userInfo endpoint.
+func UserFromClaims(claims map[string]interface{}) (*User, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	var loader UserLoader
+	parsed, err := loader.parseClaims(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	user := parsed.ToUser()
+	user.ProviderName = devAuthBypassProviderName
+	user.ProviderID = devAuthBypassProviderName
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				user.Roles = append(user.Roles, s)
+			}
+		}
+	}
+	return &user, nil
+}
+
+// NewDevAuthBypassMw returns a middleware that injects the given user into every request
+// without an authenticated user. It must never be used outside of development.
+func NewDevAuthBypassMw(log *zap.Logger, user *User) func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if UserFromContext(r.Context()) == nil {
+				log.Warn("DEV AUTH BYPASS: serving request with a synthetic identity, never use this in production",
+					zap.String("userId", user.UserID),
+					zap.String("path", r.URL.Path),
+					logging.WithRequestIDFromContext(r.Context()),
+				)
+				// copy to avoid sharing the user between requests, hooks are allowed to modify it
+				r = r.WithContext(context.WithValue(r.Context(), "user", user.clone()))
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clone returns a deep copy of the user, slices and maps included
+func (u *User) clone() *User {
+	clone := *u
+	clone.CustomClaims = cloneClaim(u.CustomClaims).(map[string]interface{})
+	if u.CustomAttributes != nil {
+		clone.CustomAttributes = append([]string(nil), u.CustomAttributes...)
+	}
+	if u.Roles != nil {
+		clone.Roles = append([]string(nil), u.Roles...)
+	}
+	if u.AccessToken != nil {
+		clone.AccessToken = append(json.RawMessage(nil), u.AccessToken...)
+	}
+	if u.IdToken != nil {
+		clone.IdToken = append(json.RawMessage(nil), u.IdToken...)
+	}
+	return &clone
+}
+
+// cloneClaim deep copies the JSON values of custom claims
+func cloneClaim(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneClaim(item)
+		}
+		return clone
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneClaim(item)
+		}
+		return clone
+	default:
+		return v
+	}
+}
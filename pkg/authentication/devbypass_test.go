@@ -0,0 +1,50 @@
+package authentication
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDevAuthBypassMw(t *testing.T) {
+	user, err := UserFromClaims(map[string]interface{}{
+		"sub":   "dev",
+		"roles": []interface{}{"admin"},
+		"org":   map[string]interface{}{"teams": []interface{}{"core"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin"}, user.Roles)
+
+	var seen []*User
+	mw := NewDevAuthBypassMw(zap.NewNop(), user)
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUser := UserFromContext(r.Context())
+		seen = append(seen, requestUser)
+		if requestUser.UserID != "dev" {
+			return
+		}
+		// hooks are allowed to modify the user of a request
+		requestUser.Roles = append(requestUser.Roles[:0], "guest")
+		requestUser.CustomClaims["org"].(map[string]interface{})["teams"].([]interface{})[0] = "other"
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Len(t, seen, 2)
+	assert.Equal(t, "dev", seen[1].UserID)
+	assert.NotSame(t, seen[0], seen[1])
+	assert.Equal(t, []string{"admin"}, user.Roles)
+	assert.Equal(t, []interface{}{"core"}, user.CustomClaims["org"].(map[string]interface{})["teams"])
+
+	authenticated := &User{UserID: "real"}
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request = request.WithContext(context.WithValue(request.Context(), "user", authenticated))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	require.Len(t, seen, 3)
+	assert.Same(t, authenticated, seen[2])
+}
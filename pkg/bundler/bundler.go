@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/evanw/esbuild/pkg/api"
 	"go.uber.org/zap"
 
+	"github.com/wundergraph/wundergraph/pkg/tracing"
 	"github.com/wundergraph/wundergraph/pkg/watcher"
 )
 
@@ -26,13 +28,29 @@ type Bundler struct {
 	absWorkingDir         string
 	watchPaths            []*watcher.WatchPath
 	ignorePaths           []string
+	watchPause            *watcher.Pause
 	log                   *zap.Logger
 	skipWatchOnEntryPoint bool
 	outFile               string
 	outDir                string
 	fileLoaders           []string
-	buildResult           *api.BuildResult
-	onAfterBundle         func() error
+	// buildMu serializes builds started by Bundle and by the watcher, including onAfterBundle
+	buildMu       sync.Mutex
+	buildResult   *api.BuildResult
+	onAfterBundle func(ctx context.Context) error
+	metafile      bool
+	tracer        *tracing.Recorder
+	cache         Cache
+	verbose       bool
+	maxSize       int64
+	failOnMaxSize bool
+	target        *Target
+	skipChanges   func(changes []watcher.Change) bool
+
+	// cancelBuild cancels the context of the latest build, buildID identifies it
+	cancelMu    sync.Mutex
+	cancelBuild context.CancelFunc
+	buildID     uint64
 
 	newWatchPath chan *watcher.WatchPath
 }
@@ -48,10 +66,41 @@ type Config struct {
 	IgnorePaths           []string
 	OutFile               string
 	OutDir                string
-	OnAfterBundle         func() error
+	// OnAfterBundle runs after each successful build. Its context is canceled as soon as
+	// a newer build supersedes the one it belongs to.
+	OnAfterBundle func(ctx context.Context) error
+	// Metafile enables writing the esbuild metafile to generated/bundle/<name>.meta.json after each build
+	Metafile bool
+	// Tracer records a span for each build, the OnAfterBundle callback is not part of it
+	Tracer *tracing.Recorder
+	// Cache restores the outputs of builds with the same options and inputs instead of building,
+	// defaults to a disk cache in DefaultCacheDir. Only bundlers without WatchPaths are cached.
+	Cache        Cache
+	DisableCache bool
+	// Verbose logs the warnings of each build and what every import resolved to
+	Verbose bool
+	// MaxSize is the maximum size in bytes of the outputs without source maps, builds
+	// exceeding it log their largest inputs. Zero disables the check.
+	MaxSize int64
+	// FailOnMaxSize makes builds exceeding MaxSize fail instead of only warning
+	FailOnMaxSize bool
+	// WatchPause holds back rebuilds while paused, the changes made in the meantime are
+	// rebuilt once on resume
+	WatchPause *watcher.Pause
+	// Target selects the minification, the source maps and the defines of the build instead of
+	// Production and marks the outputs with its name
+	Target *Target
+	// SkipChanges returns true if the watched changes don't need a rebuild, nil rebuilds on every change
+	SkipChanges func(changes []watcher.Change) bool
 }
 
 func NewBundler(config Config) *Bundler {
+	cache := config.Cache
+	if config.DisableCache {
+		cache = nil
+	} else if cache == nil {
+		cache = NewDiskCache(DefaultCacheDir(config.AbsWorkingDir))
+	}
 
 	return &Bundler{
 		name:                  config.Name,
@@ -62,8 +111,17 @@ func NewBundler(config Config) *Bundler {
 		entryPoints:           entryPoints(config),
 		watchPaths:            config.WatchPaths,
 		ignorePaths:           config.IgnorePaths,
+		watchPause:            config.WatchPause,
 		skipWatchOnEntryPoint: config.SkipWatchOnEntryPoint,
 		onAfterBundle:         config.OnAfterBundle,
+		metafile:              config.Metafile,
+		tracer:                config.Tracer,
+		cache:                 cache,
+		verbose:               config.Verbose,
+		maxSize:               config.MaxSize,
+		failOnMaxSize:         config.FailOnMaxSize,
+		target:                config.Target,
+		skipChanges:           config.SkipChanges,
 		log:                   config.Logger,
 		fileLoaders:           []string{".graphql", ".gql", ".graphqls", ".yml", ".yaml"},
 		newWatchPath:          make(chan *watcher.WatchPath),
@@ -95,7 +153,24 @@ func entryPoints(config Config) []api.EntryPoint {
 	return entries
 }
 
+// Bundle builds the entry points and runs OnAfterBundle, see BundleContext
 func (b *Bundler) Bundle() error {
+	return b.BundleContext(context.Background())
+}
+
+// BundleContext builds the entry points and runs OnAfterBundle with a context derived from
+// ctx. Starting a build cancels the one in flight, it returns the error of its context
+// instead of finishing with outdated inputs.
+func (b *Bundler) BundleContext(ctx context.Context) error {
+	ctx, done := b.supersede(ctx)
+	defer done()
+	b.buildMu.Lock()
+	defer b.buildMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	endSpan := b.tracer.Start(tracing.SpanBundle, b.name)
+	defer endSpan()
 	if b.buildResult != nil {
 		buildResult := b.buildResult.Rebuild()
 		b.buildResult = &buildResult
@@ -110,6 +185,16 @@ func (b *Bundler) Bundle() error {
 			return fmt.Errorf("build failed: %s, %s", b.buildResult.Errors[0].Location.LineText, b.buildResult.Errors[0].Text)
 		}
 		b.log.Debug("Build successful", zap.String("bundlerName", b.name))
+		b.writeMetafile(b.buildResult)
+		b.logVerbose(b.buildResult)
+		if err := b.checkSize(b.buildResult); err != nil {
+			return err
+		}
+	} else if restored := b.restoreFromCache(); restored != nil {
+		b.log.Debug("Build restored from cache", zap.String("bundlerName", b.name))
+		if err := b.checkSize(restored); err != nil {
+			return err
+		}
 	} else {
 		buildResult := b.initialBuild()
 		b.buildResult = &buildResult
@@ -121,14 +206,49 @@ func (b *Bundler) Bundle() error {
 			return fmt.Errorf("build failed: %s, %s", b.buildResult.Errors[0].Location.LineText, b.buildResult.Errors[0].Text)
 		}
 		b.log.Debug("Initial Build successful", zap.String("bundlerName", b.name))
+		b.writeMetafile(b.buildResult)
+		b.logVerbose(b.buildResult)
+		if b.cacheable() {
+			b.storeInCache(b.buildResult)
+		}
+		if err := b.checkSize(b.buildResult); err != nil {
+			return err
+		}
+	}
+	endSpan()
+	if err := ctx.Err(); err != nil {
+		b.log.Debug("Build superseded", zap.String("bundlerName", b.name))
+		return err
 	}
 	if b.onAfterBundle != nil {
-		return b.onAfterBundle()
+		return b.onAfterBundle(ctx)
 	}
 
 	return nil
 }
 
+// supersede cancels the build in flight, if any, and returns the context of a new build.
+// done must be called when the build is finished.
+func (b *Bundler) supersede(parent context.Context) (ctx context.Context, done func()) {
+	b.cancelMu.Lock()
+	defer b.cancelMu.Unlock()
+	if b.cancelBuild != nil {
+		b.cancelBuild()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	b.buildID++
+	id := b.buildID
+	b.cancelBuild = cancel
+	return ctx, func() {
+		cancel()
+		b.cancelMu.Lock()
+		defer b.cancelMu.Unlock()
+		if b.buildID == id {
+			b.cancelBuild = nil
+		}
+	}
+}
+
 func (b *Bundler) Watch(ctx context.Context) {
 	if len(b.watchPaths) == 0 {
 		return
@@ -182,9 +302,21 @@ func (b *Bundler) initialBuild() api.BuildResult {
 			{Name: api.EngineNode, Version: "18"}, // LTS
 		},
 		Write: true,
+		// the inputs of cached builds and the verbose resolution log are taken from the metafile
+		Metafile: b.metafile || b.cacheable() || b.verbose || b.maxSize > 0,
+	}
+
+	minify := b.production
+	if b.target != nil {
+		minify = b.target.Minify
+		if !b.target.Sourcemap {
+			options.Sourcemap = api.SourceMapNone
+		}
+		options.Define = b.target.Defines()
+		options.Banner = map[string]string{"js": b.target.banner()}
 	}
 
-	if b.production {
+	if minify {
 		options.MinifySyntax = true
 		options.TreeShaking = api.TreeShakingTrue
 		options.MinifyIdentifiers = true
@@ -284,14 +416,43 @@ func (b *Bundler) runWatcher(ctx context.Context, rebuild func() api.BuildResult
 	w := watcher.NewWatcher(b.name, &watcher.Config{
 		IgnorePaths: b.ignorePaths,
 		WatchPaths:  b.watchPaths,
+		Pause:       b.watchPause,
 	}, b.log)
 
 	go func() {
-		err := w.Watch(ctx, func(paths []string) error {
+		err := w.WatchChanges(ctx, func(changes []watcher.Change) error {
+			if b.skipChanges != nil && b.skipChanges(changes) {
+				b.log.Debug("rebuild skipped for "+watcher.DescribeChanges(changes, b.absWorkingDir), zap.String("bundlerName", b.name))
+				return nil
+			}
+			b.log.Info("rebuild triggered by "+watcher.DescribeChanges(changes, b.absWorkingDir),
+				zap.String("bundlerName", b.name),
+				zap.Int("changes", len(changes)),
+			)
+			// a change while the previous build is still running makes it outdated
+			buildCtx, done := b.supersede(ctx)
+			defer done()
+			b.buildMu.Lock()
+			defer b.buildMu.Unlock()
+			if buildCtx.Err() != nil {
+				return nil
+			}
+			endSpan := b.tracer.Start(tracing.SpanBundle, b.name)
 			result := rebuild()
+			endSpan()
 			if len(result.Errors) == 0 {
+				b.writeMetafile(&result)
+				b.logVerbose(&result)
+				if err := b.checkSize(&result); err != nil {
+					// onAfterBundle must not pick up the oversized outputs
+					return nil
+				}
+				if buildCtx.Err() != nil {
+					b.log.Debug("Build superseded", zap.String("bundlerName", b.name))
+					return nil
+				}
 				if b.onAfterBundle != nil {
-					_ = b.onAfterBundle()
+					_ = b.onAfterBundle(buildCtx)
 				}
 			} else {
 				for _, message := range result.Errors {
@@ -309,6 +470,9 @@ func (b *Bundler) runWatcher(ctx context.Context, rebuild func() api.BuildResult
 						zap.String("message", message.Text),
 					)
 				}
+				b.log.Warn("Bundler build failed, keeping the output of the last successful build",
+					zap.String("watcherName", b.name),
+				)
 			}
 			return nil
 		})
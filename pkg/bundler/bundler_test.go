@@ -0,0 +1,47 @@
+package bundler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBundleSupersedesBuildInFlight(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`export const value = 1;`), 0644))
+
+	started := make(chan struct{}, 2)
+	b := NewBundler(Config{
+		Name:          "test-bundler",
+		Logger:        zap.NewNop(),
+		AbsWorkingDir: dir,
+		EntryPoints:   []string{"entry.ts"},
+		OutFile:       filepath.Join("generated", "entry.js"),
+		DisableCache:  true,
+		OnAfterBundle: func(ctx context.Context) error {
+			started <- struct{}{}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+				return nil
+			}
+		},
+	})
+
+	first := make(chan error)
+	go func() {
+		first <- b.Bundle()
+	}()
+	<-started
+
+	assert.NoError(t, b.Bundle())
+	assert.ErrorIs(t, <-first, context.Canceled)
+	assert.Len(t, started, 1, "the second build ran OnAfterBundle")
+}
@@ -0,0 +1,243 @@
+package bundler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"go.uber.org/zap"
+)
+
+// cacheVersion is part of every cache key, bump it when the layout of the entries
+// or the build options change in a way the key doesn't capture
+const cacheVersion = 1
+
+// ErrCacheMiss is returned by Cache.Get if there is no entry for the key
+var ErrCacheMiss = errors.New("bundler cache miss")
+
+// Cache stores the outputs of builds. Keys are derived from the build options and
+// the content of all inputs, so entries can be shared between machines, e.g. in CI.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Has(key string) (bool, error)
+	// Get returns ErrCacheMiss if there is no entry for key
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+}
+
+// DefaultCacheDir returns the directory of the local disk cache
+func DefaultCacheDir(absWorkingDir string) string {
+	return filepath.Join(absWorkingDir, "cache", "bundler")
+}
+
+// DiskCache stores every entry as a file in a directory
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+func (c *DiskCache) Has(key string) (bool, error) {
+	_, err := os.Stat(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *DiskCache) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCacheMiss
+	}
+	return data, err
+}
+
+// Put writes the entry to a temporary file first, so concurrent readers never see partial entries
+func (c *DiskCache) Put(key string, data []byte) error {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// cacheManifest lists the inputs of the last build with the same options. The inputs
+// are only known after a build, their content makes up the key of the outputs.
+type cacheManifest struct {
+	Inputs []string `json:"inputs"`
+}
+
+// cacheEntry holds the outputs of a build, paths are relative to the working directory
+type cacheEntry struct {
+	Files    map[string][]byte `json:"files"`
+	Metafile string            `json:"metafile,omitempty"`
+}
+
+// cacheable returns true if builds of the bundler can be restored from the cache.
+// Watched bundlers need the incremental build result of esbuild to rebuild.
+func (b *Bundler) cacheable() bool {
+	return b.cache != nil && len(b.watchPaths) == 0
+}
+
+// optionsKey hashes everything but the inputs that influences the outputs of a build
+func (b *Bundler) optionsKey() string {
+	data, _ := json.Marshal(struct {
+		Version     int
+		Production  bool
+		OutFile     string
+		OutDir      string
+		EntryPoints []api.EntryPoint
+		FileLoaders []string
+		Target      *Target
+	}{cacheVersion, b.production, b.outFile, b.outDir, b.entryPoints, b.fileLoaders, b.target})
+	return hash(data)
+}
+
+// contentKey hashes the options and the content of the inputs, it returns false if
+// an input can't be read
+func (b *Bundler) contentKey(optionsKey string, inputs []string) (string, bool) {
+	h := sha256.New()
+	h.Write([]byte(optionsKey))
+	for _, input := range inputs {
+		data, err := os.ReadFile(filepath.Join(b.absWorkingDir, input))
+		if err != nil {
+			return "", false
+		}
+		h.Write([]byte(input))
+		h.Write([]byte{0})
+		h.Write([]byte(hash(data)))
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// restoreFromCache writes the outputs of a previous build with the same options and inputs,
+// it returns nil if the bundler isn't cacheable or there is no such build
+func (b *Bundler) restoreFromCache() *api.BuildResult {
+	if !b.cacheable() {
+		return nil
+	}
+	optionsKey := b.optionsKey()
+	data, err := b.cache.Get("manifest-" + optionsKey)
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			b.log.Warn("could not read bundler cache", zap.String("bundlerName", b.name), zap.Error(err))
+		}
+		return nil
+	}
+	var manifest cacheManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+	contentKey, ok := b.contentKey(optionsKey, manifest.Inputs)
+	if !ok {
+		return nil
+	}
+	data, err = b.cache.Get("outputs-" + contentKey)
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			b.log.Warn("could not read bundler cache", zap.String("bundlerName", b.name), zap.Error(err))
+		}
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	for path, content := range entry.Files {
+		absPath := filepath.Join(b.absWorkingDir, path)
+		if err := os.MkdirAll(filepath.Dir(absPath), os.ModePerm); err != nil {
+			b.log.Warn("could not restore build from cache", zap.String("bundlerName", b.name), zap.Error(err))
+			return nil
+		}
+		if err := os.WriteFile(absPath, content, 0644); err != nil {
+			b.log.Warn("could not restore build from cache", zap.String("bundlerName", b.name), zap.Error(err))
+			return nil
+		}
+	}
+	restored := &api.BuildResult{Metafile: entry.Metafile}
+	b.writeMetafile(restored)
+	b.logVerbose(restored)
+	return restored
+}
+
+// storeInCache stores the outputs of a successful build
+func (b *Bundler) storeInCache(result *api.BuildResult) {
+	var metafile Metafile
+	if err := json.Unmarshal([]byte(result.Metafile), &metafile); err != nil {
+		return
+	}
+	inputs := make([]string, 0, len(metafile.Inputs))
+	for input := range metafile.Inputs {
+		// inputs of plugin namespaces don't exist on disk
+		if strings.Contains(input, ":") {
+			return
+		}
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+
+	optionsKey := b.optionsKey()
+	contentKey, ok := b.contentKey(optionsKey, inputs)
+	if !ok {
+		return
+	}
+	entry := cacheEntry{
+		Files:    make(map[string][]byte, len(result.OutputFiles)),
+		Metafile: result.Metafile,
+	}
+	for _, file := range result.OutputFiles {
+		path, err := filepath.Rel(b.absWorkingDir, file.Path)
+		if err != nil || strings.HasPrefix(path, "..") {
+			return
+		}
+		entry.Files[path] = file.Contents
+	}
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	manifestData, err := json.Marshal(cacheManifest{Inputs: inputs})
+	if err != nil {
+		return
+	}
+	// the outputs are stored first, a manifest must never point to missing outputs
+	if exists, err := b.cache.Has("outputs-" + contentKey); err != nil || !exists {
+		if err := b.cache.Put("outputs-"+contentKey, entryData); err != nil {
+			b.log.Warn("could not write bundler cache", zap.String("bundlerName", b.name), zap.Error(err))
+			return
+		}
+	}
+	if err := b.cache.Put("manifest-"+optionsKey, manifestData); err != nil {
+		b.log.Warn("could not write bundler cache", zap.String("bundlerName", b.name), zap.Error(err))
+	}
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
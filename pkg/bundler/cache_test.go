@@ -0,0 +1,85 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type countingCache struct {
+	*DiskCache
+	mu   sync.Mutex
+	puts int
+	hits int
+}
+
+func (c *countingCache) Get(key string) ([]byte, error) {
+	data, err := c.DiskCache.Get(key)
+	if err == nil {
+		c.mu.Lock()
+		c.hits++
+		c.mu.Unlock()
+	}
+	return data, err
+}
+
+func (c *countingCache) Put(key string, data []byte) error {
+	c.mu.Lock()
+	c.puts++
+	c.mu.Unlock()
+	return c.DiskCache.Put(key, data)
+}
+
+func TestBundlerCache(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`import { value } from './dep';
+console.log(value);
+`), 0644))
+	depPath := filepath.Join(dir, "dep.ts")
+	require.NoError(t, os.WriteFile(depPath, []byte(`export const value = 1;`), 0644))
+
+	cache := &countingCache{DiskCache: NewDiskCache(filepath.Join(dir, "cache"))}
+	newBundler := func() *Bundler {
+		return NewBundler(Config{
+			Name:          "test-bundler",
+			Logger:        zap.NewNop(),
+			AbsWorkingDir: dir,
+			EntryPoints:   []string{"entry.ts"},
+			OutFile:       filepath.Join("generated", "entry.js"),
+			Cache:         cache,
+		})
+	}
+	outPath := filepath.Join(dir, "generated", "entry.js")
+
+	require.NoError(t, newBundler().Bundle())
+	assert.Equal(t, 2, cache.puts, "outputs and manifest")
+	assert.Equal(t, 0, cache.hits)
+	built, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(outPath))
+	require.NoError(t, newBundler().Bundle())
+	assert.Equal(t, 2, cache.hits, "manifest and outputs")
+	restored, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Equal(t, built, restored)
+
+	// changing an import invalidates the outputs
+	require.NoError(t, os.WriteFile(depPath, []byte(`export const value = 2;`), 0644))
+	require.NoError(t, newBundler().Bundle())
+	assert.Equal(t, 3, cache.hits, "only the manifest")
+	rebuilt, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(rebuilt), "2")
+	assert.NotEqual(t, built, rebuilt)
+}
+
+func TestBundlerCacheDisabled(t *testing.T) {
+	b := NewBundler(Config{AbsWorkingDir: t.TempDir(), DisableCache: true, Cache: NewDiskCache(t.TempDir())})
+	assert.False(t, b.cacheable())
+}
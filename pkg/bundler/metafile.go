@@ -0,0 +1,121 @@
+package bundler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"go.uber.org/zap"
+)
+
+const metafileSuffix = ".meta.json"
+
+// Metafile is the subset of the esbuild metafile we use for analysis,
+// see https://esbuild.github.io/api/#metafile
+type Metafile struct {
+	Inputs  map[string]MetafileInput  `json:"inputs"`
+	Outputs map[string]MetafileOutput `json:"outputs"`
+}
+
+type MetafileInput struct {
+	Bytes   int              `json:"bytes"`
+	Imports []MetafileImport `json:"imports"`
+}
+
+// MetafileImport is an import of an input, Path is the path it resolved to
+type MetafileImport struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	External bool   `json:"external"`
+}
+
+type MetafileOutput struct {
+	Bytes  int                            `json:"bytes"`
+	Inputs map[string]MetafileOutputInput `json:"inputs"`
+}
+
+type MetafileOutputInput struct {
+	BytesInOutput int `json:"bytesInOutput"`
+}
+
+// InputSize describes how much an input contributes to the bundle
+type InputSize struct {
+	Path          string
+	Bytes         int
+	BytesInOutput int
+}
+
+// MetafilePath returns the path of the metafile written by the bundler with the given name
+func MetafilePath(absWorkingDir, bundlerName string) string {
+	return filepath.Join(absWorkingDir, "generated", "bundle", bundlerName+metafileSuffix)
+}
+
+// FindMetafiles returns all metafiles in the bundle directory
+func FindMetafiles(absWorkingDir string) ([]string, error) {
+	return filepath.Glob(filepath.Join(absWorkingDir, "generated", "bundle", "*"+metafileSuffix))
+}
+
+// BundlerNameFromMetafile returns the bundler name of a metafile path
+func BundlerNameFromMetafile(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), metafileSuffix)
+}
+
+func ReadMetafile(path string) (*Metafile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var metafile Metafile
+	if err := json.Unmarshal(data, &metafile); err != nil {
+		return nil, err
+	}
+	return &metafile, nil
+}
+
+// LargestInputs returns up to limit inputs ordered by the bytes they contribute to all outputs.
+// A limit <= 0 returns all inputs.
+func (m *Metafile) LargestInputs(limit int) []InputSize {
+	contributions := make(map[string]int, len(m.Inputs))
+	for _, output := range m.Outputs {
+		for path, input := range output.Inputs {
+			contributions[path] += input.BytesInOutput
+		}
+	}
+	sizes := make([]InputSize, 0, len(m.Inputs))
+	for path, input := range m.Inputs {
+		sizes = append(sizes, InputSize{
+			Path:          path,
+			Bytes:         input.Bytes,
+			BytesInOutput: contributions[path],
+		})
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].BytesInOutput != sizes[j].BytesInOutput {
+			return sizes[i].BytesInOutput > sizes[j].BytesInOutput
+		}
+		return sizes[i].Path < sizes[j].Path
+	})
+	if limit > 0 && len(sizes) > limit {
+		sizes = sizes[:limit]
+	}
+	return sizes
+}
+
+func (b *Bundler) writeMetafile(result *api.BuildResult) {
+	if !b.metafile || result == nil || result.Metafile == "" {
+		return
+	}
+	path := MetafilePath(b.absWorkingDir, b.name)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		b.log.Error("could not create metafile directory", zap.String("bundlerName", b.name), zap.Error(err))
+		return
+	}
+	if err := os.WriteFile(path, []byte(result.Metafile), 0644); err != nil {
+		b.log.Error("could not write metafile", zap.String("bundlerName", b.name), zap.Error(err))
+		return
+	}
+	b.log.Debug("Metafile written", zap.String("bundlerName", b.name), zap.String("path", path))
+}
@@ -0,0 +1,71 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLargestInputs(t *testing.T) {
+	metafile := &Metafile{
+		Inputs: map[string]MetafileInput{
+			"a.ts":     {Bytes: 100},
+			"b.ts":     {Bytes: 50},
+			"c.ts":     {Bytes: 300},
+			"types.ts": {Bytes: 10},
+		},
+		Outputs: map[string]MetafileOutput{
+			"out/config.js": {Inputs: map[string]MetafileOutputInput{"a.ts": {BytesInOutput: 40}, "c.ts": {BytesInOutput: 200}}},
+			"out/server.js": {Inputs: map[string]MetafileOutputInput{"a.ts": {BytesInOutput: 40}, "b.ts": {BytesInOutput: 80}}},
+		},
+	}
+	// contributions to all outputs are summed up, ties are ordered by path
+	assert.Equal(t, []InputSize{
+		{Path: "c.ts", Bytes: 300, BytesInOutput: 200},
+		{Path: "a.ts", Bytes: 100, BytesInOutput: 80},
+		{Path: "b.ts", Bytes: 50, BytesInOutput: 80},
+		{Path: "types.ts", Bytes: 10, BytesInOutput: 0},
+	}, metafile.LargestInputs(0))
+	assert.Equal(t, []InputSize{{Path: "c.ts", Bytes: 300, BytesInOutput: 200}}, metafile.LargestInputs(1))
+	assert.Len(t, metafile.LargestInputs(10), 4)
+}
+
+func TestBundlerMetafile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`import { data } from './data';
+console.log(data);
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.ts"), []byte(`export const data = 1;`), 0644))
+	newBundler := func(metafile bool) *Bundler {
+		return NewBundler(Config{
+			Name:          "test-bundler",
+			Logger:        zap.NewNop(),
+			AbsWorkingDir: dir,
+			EntryPoints:   []string{"entry.ts"},
+			OutFile:       filepath.Join("generated", "entry.js"),
+			DisableCache:  true,
+			Metafile:      metafile,
+		})
+	}
+
+	require.NoError(t, newBundler(false).Bundle())
+	paths, err := FindMetafiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	require.NoError(t, newBundler(true).Bundle())
+	paths, err = FindMetafiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{MetafilePath(dir, "test-bundler")}, paths)
+	assert.Equal(t, "test-bundler", BundlerNameFromMetafile(paths[0]))
+
+	metafile, err := ReadMetafile(paths[0])
+	require.NoError(t, err)
+	assert.Contains(t, metafile.Inputs, "entry.ts")
+	assert.Contains(t, metafile.Inputs, "data.ts")
+	assert.Contains(t, metafile.Outputs, "generated/entry.js")
+}
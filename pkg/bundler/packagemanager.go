@@ -0,0 +1,103 @@
+package bundler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageManager selects how the packages imported by bundles are resolved. Bundles keep
+// all packages external, node resolves them when running the bundle:
+//
+//   - npm and yarn with the node-modules linker install a flat node_modules, node resolves
+//     packages from the directories above the bundle
+//   - pnpm links packages into node_modules with symlinks, node follows them
+//   - yarn-pnp installs no node_modules, node requires the .pnp.cjs of the project, whose
+//     resolver serves packages from the Yarn cache
+type PackageManager string
+
+const (
+	PackageManagerNPM     PackageManager = "npm"
+	PackageManagerPNPM    PackageManager = "pnpm"
+	PackageManagerYarn    PackageManager = "yarn"
+	PackageManagerYarnPnP PackageManager = "yarn-pnp"
+)
+
+// PackageManagers lists the supported package managers
+var PackageManagers = []PackageManager{PackageManagerNPM, PackageManagerPNPM, PackageManagerYarn, PackageManagerYarnPnP}
+
+// PnPManifestFilename is the resolver written by Yarn PnP to the root of the project
+const PnPManifestFilename = ".pnp.cjs"
+
+// lockfiles identify the package manager of a project, .pnp.cjs comes first because
+// yarn.lock is written by both linkers
+var lockfiles = []struct {
+	filename       string
+	packageManager PackageManager
+}{
+	{PnPManifestFilename, PackageManagerYarnPnP},
+	{"pnpm-lock.yaml", PackageManagerPNPM},
+	{"yarn.lock", PackageManagerYarn},
+	{"package-lock.json", PackageManagerNPM},
+}
+
+// PackageResolution is the package manager of a project and its root directory
+type PackageResolution struct {
+	PackageManager PackageManager
+	// Root is the directory of the lockfile, empty if there is none
+	Root string
+}
+
+// DetectPackageManager looks for lockfiles in dir and its parents, the first directory with
+// one of them is the root of the project. Without a lockfile it defaults to npm.
+func DetectPackageManager(dir string) (PackageResolution, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return PackageResolution{}, err
+	}
+	for {
+		for _, lockfile := range lockfiles {
+			if _, err := os.Stat(filepath.Join(dir, lockfile.filename)); err == nil {
+				return PackageResolution{PackageManager: lockfile.packageManager, Root: dir}, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return PackageResolution{PackageManager: PackageManagerNPM}, nil
+		}
+		dir = parent
+	}
+}
+
+// ResolvePackageManager returns the resolution for the given package manager, an empty
+// one is detected from the lockfiles of dir
+func ResolvePackageManager(dir string, packageManager PackageManager) (PackageResolution, error) {
+	detected, err := DetectPackageManager(dir)
+	if err != nil || packageManager == "" {
+		return detected, err
+	}
+	for _, known := range PackageManagers {
+		if known == packageManager {
+			resolution := PackageResolution{PackageManager: packageManager, Root: detected.Root}
+			if packageManager == PackageManagerYarnPnP && detected.PackageManager != PackageManagerYarnPnP {
+				return resolution, fmt.Errorf("no %s found in %s or its parents, run yarn install first", PnPManifestFilename, dir)
+			}
+			return resolution, nil
+		}
+	}
+	return PackageResolution{}, fmt.Errorf("unknown package manager %q, supported package managers are %v", packageManager, PackageManagers)
+}
+
+// NodeEnv returns the environment variables node needs to resolve the packages of bundles,
+// nodeOptions are the NODE_OPTIONS set by the user
+func (r PackageResolution) NodeEnv(nodeOptions string) []string {
+	if r.PackageManager != PackageManagerYarnPnP {
+		return nil
+	}
+	require := "--require " + filepath.Join(r.Root, PnPManifestFilename)
+	if strings.Contains(nodeOptions, require) {
+		return nil
+	}
+	return []string{"NODE_OPTIONS=" + strings.TrimSpace(nodeOptions+" "+require)}
+}
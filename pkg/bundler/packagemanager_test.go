@@ -0,0 +1,47 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "packages", "api", ".wundergraph")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+
+	resolution, err := DetectPackageManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, PackageManagerNPM, resolution.PackageManager)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "yarn.lock"), nil, 0644))
+	resolution, err = DetectPackageManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, PackageResolution{PackageManager: PackageManagerYarn, Root: root}, resolution)
+	assert.Nil(t, resolution.NodeEnv(""))
+
+	_, err = ResolvePackageManager(dir, PackageManagerYarnPnP)
+	assert.Error(t, err, "missing .pnp.cjs")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, PnPManifestFilename), nil, 0644))
+	resolution, err = DetectPackageManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, PackageManagerYarnPnP, resolution.PackageManager)
+	manifest := filepath.Join(root, PnPManifestFilename)
+	assert.Equal(t, []string{"NODE_OPTIONS=--require " + manifest}, resolution.NodeEnv(""))
+	assert.Equal(t, []string{"NODE_OPTIONS=--max-old-space-size=4096 --require " + manifest}, resolution.NodeEnv("--max-old-space-size=4096"))
+	assert.Nil(t, resolution.NodeEnv("--require "+manifest))
+
+	// a lockfile closer to dir wins
+	require.NoError(t, os.WriteFile(filepath.Join(root, "packages", "pnpm-lock.yaml"), nil, 0644))
+	resolution, err = ResolvePackageManager(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, PackageManagerPNPM, resolution.PackageManager)
+
+	_, err = ResolvePackageManager(dir, "bun")
+	assert.Error(t, err)
+}
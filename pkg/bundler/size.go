@@ -0,0 +1,98 @@
+package bundler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"go.uber.org/zap"
+)
+
+// sizeBreakdownInputs is the number of inputs listed when a bundle exceeds its maximum size
+const sizeBreakdownInputs = 10
+
+var byteSizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses sizes like 2MB, 512KB or 1048576, units are powers of 1024
+func ParseByteSize(value string) (int64, error) {
+	number := strings.TrimSpace(strings.ToUpper(value))
+	unit := int64(1)
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(number, u.suffix) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, u.suffix))
+			unit = u.bytes
+			break
+		}
+	}
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected e.g. 2MB, 512KB or a number of bytes", value)
+	}
+	return int64(size * float64(unit)), nil
+}
+
+// FormatByteSize formats bytes with the largest unit of ParseByteSize that fits
+func FormatByteSize(bytes int64) string {
+	for _, u := range byteSizeUnits {
+		if bytes >= u.bytes && u.bytes > 1 {
+			return strconv.FormatFloat(float64(bytes)/float64(u.bytes), 'f', 1, 64) + u.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10) + "B"
+}
+
+// outputSize returns the size of the outputs of a build without source maps
+func outputSize(metafile *Metafile) int64 {
+	var size int64
+	for path, output := range metafile.Outputs {
+		if strings.HasSuffix(path, ".map") {
+			continue
+		}
+		size += int64(output.Bytes)
+	}
+	return size
+}
+
+// checkSize compares the size of the outputs with the maximum size of the bundler. When it's
+// exceeded, the largest inputs are logged and an error is returned if the bundler fails on it.
+func (b *Bundler) checkSize(result *api.BuildResult) error {
+	if b.maxSize <= 0 || result == nil {
+		return nil
+	}
+	var metafile Metafile
+	if err := json.Unmarshal([]byte(result.Metafile), &metafile); err != nil {
+		return nil
+	}
+	size := outputSize(&metafile)
+	if size <= b.maxSize {
+		return nil
+	}
+	largest := metafile.LargestInputs(sizeBreakdownInputs)
+	breakdown := make([]string, 0, len(largest))
+	for _, input := range largest {
+		breakdown = append(breakdown, fmt.Sprintf("%s %s", FormatByteSize(int64(input.BytesInOutput)), input.Path))
+	}
+	fields := []zap.Field{
+		zap.String("bundlerName", b.name),
+		zap.String("size", FormatByteSize(size)),
+		zap.String("maxSize", FormatByteSize(b.maxSize)),
+		zap.Strings("largestInputs", breakdown),
+	}
+	if !b.failOnMaxSize {
+		b.log.Warn("Bundle exceeds the maximum size", fields...)
+		return nil
+	}
+	b.log.Error("Bundle exceeds the maximum size", fields...)
+	return fmt.Errorf("bundle %s is %s and exceeds the maximum size of %s, largest inputs:\n  %s",
+		b.name, FormatByteSize(size), FormatByteSize(b.maxSize), strings.Join(breakdown, "\n  "))
+}
@@ -0,0 +1,58 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"2MB":     2 << 20,
+		"512kb":   512 << 10,
+		"1.5 GB":  3 << 29,
+		"100B":    100,
+		"1048576": 1 << 20,
+	} {
+		size, err := ParseByteSize(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, size, value)
+	}
+	for _, value := range []string{"", "MB", "-1MB", "2 TB"} {
+		_, err := ParseByteSize(value)
+		assert.Error(t, err, value)
+	}
+	assert.Equal(t, "2.0MB", FormatByteSize(2<<20))
+	assert.Equal(t, "1.5KB", FormatByteSize(1536))
+	assert.Equal(t, "12B", FormatByteSize(12))
+}
+
+func TestBundlerMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`import { data } from './data';
+console.log(data);
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.ts"), []byte(`export const data = "`+strings.Repeat("x", 4096)+`";`), 0644))
+	newBundler := func(failOnMaxSize bool) *Bundler {
+		return NewBundler(Config{
+			Name:          "test-bundler",
+			Logger:        zap.NewNop(),
+			AbsWorkingDir: dir,
+			EntryPoints:   []string{"entry.ts"},
+			OutFile:       filepath.Join("generated", "entry.js"),
+			DisableCache:  true,
+			MaxSize:       1 << 10,
+			FailOnMaxSize: failOnMaxSize,
+		})
+	}
+	assert.NoError(t, newBundler(false).Bundle(), "only a warning")
+	err := newBundler(true).Bundle()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum size of 1.0KB")
+	assert.Contains(t, err.Error(), "data.ts")
+}
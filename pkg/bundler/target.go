@@ -0,0 +1,61 @@
+package bundler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TargetEnvKey tells the bundles and the runners which target environment they were built for
+const TargetEnvKey = "WG_TARGET_ENV"
+
+// Target is a profile of bundler settings for an environment, see Targets
+type Target struct {
+	Name      string
+	Minify    bool
+	Sourcemap bool
+	// NodeEnv is defined as process.env.NODE_ENV in the bundles and set for the runners
+	NodeEnv string
+}
+
+// Targets are the target environments, dev keeps the bundles readable, staging minifies them
+// but keeps the source maps for debugging and prod drops them too
+var Targets = []Target{
+	{Name: "dev", Sourcemap: true, NodeEnv: "development"},
+	{Name: "staging", Minify: true, Sourcemap: true, NodeEnv: "production"},
+	{Name: "prod", Minify: true, NodeEnv: "production"},
+}
+
+// ParseTarget returns the target with the given name
+func ParseTarget(name string) (*Target, error) {
+	names := make([]string, len(Targets))
+	for i := range Targets {
+		if Targets[i].Name == name {
+			target := Targets[i]
+			return &target, nil
+		}
+		names[i] = Targets[i].Name
+	}
+	return nil, fmt.Errorf("invalid target env %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// Defines returns the esbuild defines of the target
+func (t *Target) Defines() map[string]string {
+	return map[string]string{
+		"process.env.NODE_ENV":        strconv.Quote(t.NodeEnv),
+		"process.env." + TargetEnvKey: strconv.Quote(t.Name),
+	}
+}
+
+// Env returns the environment of the runners executing the bundles of the target
+func (t *Target) Env() []string {
+	return []string{
+		"NODE_ENV=" + t.NodeEnv,
+		TargetEnvKey + "=" + t.Name,
+	}
+}
+
+// banner marks the outputs with the target they were built for
+func (t *Target) banner() string {
+	return fmt.Sprintf("/* wundergraph target env: %s */", t.Name)
+}
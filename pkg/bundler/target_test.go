@@ -0,0 +1,47 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("staging")
+	require.NoError(t, err)
+	assert.True(t, target.Minify)
+	assert.Equal(t, []string{"NODE_ENV=production", "WG_TARGET_ENV=staging"}, target.Env())
+
+	_, err = ParseTarget("production")
+	assert.EqualError(t, err, `invalid target env "production", expected one of dev, staging, prod`)
+}
+
+func TestBundleTarget(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`if (process.env.WG_TARGET_ENV === 'prod') {
+	console.log('running in', process.env.NODE_ENV);
+}
+`), 0644))
+	target, err := ParseTarget("prod")
+	require.NoError(t, err)
+	require.NoError(t, NewBundler(Config{
+		Name:          "test-bundler",
+		Logger:        zap.NewNop(),
+		AbsWorkingDir: dir,
+		EntryPoints:   []string{"entry.ts"},
+		OutFile:       filepath.Join("generated", "entry.js"),
+		DisableCache:  true,
+		Target:        target,
+	}).Bundle())
+
+	out, err := os.ReadFile(filepath.Join(dir, "generated", "entry.js"))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "/* wundergraph target env: prod */")
+	assert.Contains(t, string(out), `console.log("running in","production")`)
+	assert.NotContains(t, string(out), "process.env")
+	assert.NoFileExists(t, filepath.Join(dir, "generated", "entry.js.map"))
+}
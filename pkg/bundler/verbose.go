@@ -0,0 +1,59 @@
+package bundler
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"go.uber.org/zap"
+)
+
+// logVerbose logs the warnings of a build and what every import resolved to, taken from
+// the metafile. Builds restored from the cache have no warnings.
+func (b *Bundler) logVerbose(result *api.BuildResult) {
+	if !b.verbose || result == nil {
+		return
+	}
+	for _, message := range result.Warnings {
+		fields := []zap.Field{
+			zap.String("bundlerName", b.name),
+			zap.String("message", message.Text),
+		}
+		if message.Location != nil {
+			fields = append(fields,
+				zap.String("file", message.Location.File),
+				zap.Int("line", message.Location.Line),
+				zap.Int("column", message.Location.Column),
+			)
+		}
+		if len(message.Notes) > 0 {
+			notes := make([]string, len(message.Notes))
+			for i, note := range message.Notes {
+				notes[i] = note.Text
+			}
+			fields = append(fields, zap.Strings("notes", notes))
+		}
+		b.log.Warn("Bundler build warning", fields...)
+	}
+
+	var metafile Metafile
+	if err := json.Unmarshal([]byte(result.Metafile), &metafile); err != nil {
+		return
+	}
+	importers := make([]string, 0, len(metafile.Inputs))
+	for importer := range metafile.Inputs {
+		importers = append(importers, importer)
+	}
+	sort.Strings(importers)
+	for _, importer := range importers {
+		for _, imported := range metafile.Inputs[importer].Imports {
+			b.log.Info("Bundler resolved import",
+				zap.String("bundlerName", b.name),
+				zap.String("importer", importer),
+				zap.String("path", imported.Path),
+				zap.String("kind", imported.Kind),
+				zap.Bool("external", imported.External),
+			)
+		}
+	}
+}
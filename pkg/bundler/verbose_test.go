@@ -0,0 +1,40 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBundlerVerbose(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entry.ts"), []byte(`import { value } from './dep';
+console.log(value);
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dep.ts"), []byte(`export const value = 1;`), 0644))
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	b := NewBundler(Config{
+		Name:          "test-bundler",
+		Logger:        zap.New(core),
+		AbsWorkingDir: dir,
+		EntryPoints:   []string{"entry.ts"},
+		OutFile:       filepath.Join("generated", "entry.js"),
+		DisableCache:  true,
+		Verbose:       true,
+	})
+	require.NoError(t, b.Bundle())
+
+	resolved := logs.FilterMessage("Bundler resolved import").All()
+	require.Len(t, resolved, 1)
+	fields := resolved[0].ContextMap()
+	assert.Equal(t, "entry.ts", fields["importer"])
+	assert.Equal(t, "dep.ts", fields["path"])
+	assert.Equal(t, "import-statement", fields["kind"])
+}
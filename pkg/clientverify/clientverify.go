@@ -0,0 +1,279 @@
+// Package clientverify compares a generated TypeScript client with the schema served by a
+// node and with the operations of the config it was generated from, so stale or incorrectly
+// generated clients are noticed before requests fail at runtime.
+package clientverify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/pkg/introspection"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+const (
+	SectionSchema     = "schema"
+	SectionOperations = "operations"
+)
+
+// Mismatch is a difference between the generated client and the node. Expected is what the
+// client was generated with, Actual what the node serves, either is empty if missing.
+type Mismatch struct {
+	Section  string
+	Name     string
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	switch {
+	case m.Actual == "":
+		return fmt.Sprintf("%s: %s: %s in the client, missing on the node", m.Section, m.Name, m.Expected)
+	case m.Expected == "":
+		return fmt.Sprintf("%s: %s: %s on the node, missing in the client", m.Section, m.Name, m.Actual)
+	default:
+		return fmt.Sprintf("%s: %s: %s in the client, %s on the node", m.Section, m.Name, m.Expected, m.Actual)
+	}
+}
+
+// IntrospectionQuery fetches the types of the schema with type references deep enough for
+// lists of non-null lists
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name
+  fields(includeDeprecated: true) { name args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } }
+}`
+
+// FetchSchema introspects the GraphQL endpoint of the node at nodeURL
+func FetchSchema(ctx context.Context, client *http.Client, nodeURL string) (*ast.Document, error) {
+	body, err := json.Marshal(map[string]string{"query": IntrospectionQuery})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(nodeURL, "/")+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection failed: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return SchemaFromIntrospection(data)
+}
+
+// SchemaFromIntrospection converts the response to IntrospectionQuery into a schema
+func SchemaFromIntrospection(data []byte) (*ast.Document, error) {
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if len(response.Errors) != 0 {
+		return nil, fmt.Errorf("introspection failed: %s, is introspection enabled on the node?", response.Errors[0].Message)
+	}
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		return nil, fmt.Errorf("introspection response without data")
+	}
+	var converter introspection.JsonConverter
+	return converter.GraphQLDocument(bytes.NewReader(response.Data))
+}
+
+// LoadSchema reads the schema the client was generated with, e.g. generated/wundergraph.schema.graphql
+func LoadSchema(path string) (*ast.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, report := astparser.ParseGraphqlDocumentBytes(data)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("could not parse %s: %w", path, report)
+	}
+	return &doc, nil
+}
+
+// operationMetadataReg matches the entries of the operationMetadata of a generated client.ts
+var operationMetadataReg = regexp.MustCompile(`"([^"]+)":\s*{\s*requiresAuthentication:\s*(true|false)`)
+
+// LoadClientOperations returns the operations of the generated client at path, e.g.
+// generated/client.ts, by path with whether they require authentication
+func LoadClientOperations(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	start := bytes.Index(data, []byte("export const operationMetadata"))
+	if start == -1 {
+		return nil, fmt.Errorf("%s is not a generated client, operationMetadata not found", path)
+	}
+	data = data[start:]
+	if end := bytes.Index(data, []byte("\n}")); end != -1 {
+		data = data[:end]
+	}
+	operations := map[string]bool{}
+	for _, match := range operationMetadataReg.FindAllSubmatch(data, -1) {
+		operations[string(match[1])] = string(match[2]) == "true"
+	}
+	return operations, nil
+}
+
+// DiffOperations compares the operations of the client with the ones of config
+func DiffOperations(client map[string]bool, config *wgpb.WunderGraphConfiguration) []Mismatch {
+	served := map[string]bool{}
+	for _, operation := range config.GetApi().GetOperations() {
+		served[operation.Path] = operation.GetAuthenticationConfig().GetAuthRequired()
+	}
+	describe := func(requiresAuthentication bool) string {
+		if requiresAuthentication {
+			return "requires authentication"
+		}
+		return "public"
+	}
+	var mismatches []Mismatch
+	for path, auth := range client {
+		servedAuth, ok := served[path]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, Mismatch{Section: SectionOperations, Name: path, Expected: describe(auth)})
+		case servedAuth != auth:
+			mismatches = append(mismatches, Mismatch{Section: SectionOperations, Name: path, Expected: describe(auth), Actual: describe(servedAuth)})
+		}
+	}
+	for path, auth := range served {
+		if _, ok := client[path]; !ok {
+			mismatches = append(mismatches, Mismatch{Section: SectionOperations, Name: path, Actual: describe(auth)})
+		}
+	}
+	sortMismatches(mismatches)
+	return mismatches
+}
+
+// DiffSchemas compares the types, fields, enum values and union members of the schema the client
+// was generated with and the one served by the node. Descriptions, directives and built-in types
+// are ignored.
+func DiffSchemas(client, node *ast.Document) []Mismatch {
+	expected, actual := schemaEntries(client), schemaEntries(node)
+	var mismatches []Mismatch
+	for name, signature := range expected {
+		served, ok := actual[name]
+		if !ok || served != signature {
+			mismatches = append(mismatches, Mismatch{Section: SectionSchema, Name: name, Expected: signature, Actual: served})
+		}
+	}
+	for name, signature := range actual {
+		if _, ok := expected[name]; !ok {
+			mismatches = append(mismatches, Mismatch{Section: SectionSchema, Name: name, Actual: signature})
+		}
+	}
+	sortMismatches(mismatches)
+	return mismatches
+}
+
+var builtInScalars = map[string]struct{}{"String": {}, "Int": {}, "Float": {}, "Boolean": {}, "ID": {}}
+
+// schemaEntries maps types to their kind and fields, enum values and union members to their type
+func schemaEntries(doc *ast.Document) map[string]string {
+	entries := map[string]string{}
+	add := func(name, signature string) {
+		if strings.HasPrefix(name, "__") {
+			return
+		}
+		entries[name] = signature
+	}
+	typeString := func(ref int) string {
+		out, err := doc.PrintTypeBytes(ref, nil)
+		if err != nil {
+			return ""
+		}
+		return string(out)
+	}
+	addFields := func(typeName string, refs []int) {
+		for _, ref := range refs {
+			add(typeName+"."+doc.FieldDefinitionNameString(ref), typeString(doc.FieldDefinitions[ref].Type))
+		}
+	}
+	for i, def := range doc.ObjectTypeDefinitions {
+		name := doc.ObjectTypeDefinitionNameString(i)
+		add(name, "type")
+		addFields(name, def.FieldsDefinition.Refs)
+	}
+	for i, def := range doc.InterfaceTypeDefinitions {
+		name := doc.InterfaceTypeDefinitionNameString(i)
+		add(name, "interface")
+		addFields(name, def.FieldsDefinition.Refs)
+	}
+	for i, def := range doc.InputObjectTypeDefinitions {
+		name := doc.InputObjectTypeDefinitionNameString(i)
+		add(name, "input")
+		for _, ref := range def.InputFieldsDefinition.Refs {
+			add(name+"."+doc.InputValueDefinitionNameString(ref), typeString(doc.InputValueDefinitions[ref].Type))
+		}
+	}
+	for i, def := range doc.EnumTypeDefinitions {
+		name := doc.EnumTypeDefinitionNameString(i)
+		add(name, "enum")
+		for _, ref := range def.EnumValuesDefinition.Refs {
+			add(name+"."+doc.EnumValueDefinitionNameString(ref), "enum value")
+		}
+	}
+	for i, def := range doc.UnionTypeDefinitions {
+		name := doc.UnionTypeDefinitionNameString(i)
+		add(name, "union")
+		for _, ref := range def.UnionMemberTypes.Refs {
+			add(name+"|"+doc.TypeNameString(ref), "union member")
+		}
+	}
+	for i := range doc.ScalarTypeDefinitions {
+		name := doc.ScalarTypeDefinitionNameString(i)
+		if _, ok := builtInScalars[name]; !ok {
+			add(name, "scalar")
+		}
+	}
+	return entries
+}
+
+func sortMismatches(mismatches []Mismatch) {
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Name < mismatches[j].Name
+	})
+}
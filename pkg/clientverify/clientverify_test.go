@@ -0,0 +1,117 @@
+package clientverify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/pkg/introspection"
+	"github.com/wundergraph/graphql-go-tools/pkg/operationreport"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+const clientSchema = `
+type Query { users(first: Int): [User!]! me: User }
+type User { id: ID! name: String role: Role }
+enum Role { ADMIN USER }
+scalar JSON
+`
+
+// introspectionServer serves the introspection of schema like the GraphQL endpoint of a node
+func introspectionServer(t *testing.T, schema string) *httptest.Server {
+	doc, report := astparser.ParseGraphqlDocumentString(schema)
+	require.False(t, report.HasErrors(), report.Error())
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&doc))
+	var data introspection.Data
+	var generateReport operationreport.Report
+	introspection.NewGenerator().Generate(&doc, &generateReport, &data)
+	require.False(t, generateReport.HasErrors(), generateReport.Error())
+	response, err := json.Marshal(map[string]interface{}{"data": data})
+	require.NoError(t, err)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		_, _ = w.Write(response)
+	}))
+}
+
+func TestDiffSchemas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wundergraph.schema.graphql")
+	require.NoError(t, os.WriteFile(path, []byte(clientSchema), 0644))
+	client, err := LoadSchema(path)
+	require.NoError(t, err)
+
+	same := introspectionServer(t, clientSchema)
+	defer same.Close()
+	node, err := FetchSchema(context.Background(), http.DefaultClient, same.URL)
+	require.NoError(t, err)
+	assert.Empty(t, DiffSchemas(client, node))
+
+	changed := introspectionServer(t, `
+type Query { users(first: Int): [User] me: User }
+type User { id: ID! name: String email: String }
+scalar JSON
+`)
+	defer changed.Close()
+	node, err = FetchSchema(context.Background(), http.DefaultClient, changed.URL)
+	require.NoError(t, err)
+	mismatches := DiffSchemas(client, node)
+	var lines []string
+	for _, mismatch := range mismatches {
+		lines = append(lines, mismatch.String())
+	}
+	assert.Equal(t, []string{
+		"schema: Query.users: [User!]! in the client, [User] on the node",
+		"schema: Role: enum in the client, missing on the node",
+		"schema: Role.ADMIN: enum value in the client, missing on the node",
+		"schema: Role.USER: enum value in the client, missing on the node",
+		"schema: User.email: String on the node, missing in the client",
+		"schema: User.role: Role in the client, missing on the node",
+	}, lines)
+}
+
+func TestDiffOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.ts")
+	require.NoError(t, os.WriteFile(path, []byte(`
+export const operationMetadata: OperationMetadata = {
+    "users/get": {
+        requiresAuthentication: false
+		}
+    ,
+    "Me": {
+        requiresAuthentication: true
+		}
+}
+
+export type Queries = {
+    "users/get": {
+        requiresAuthentication: true
+    }
+}
+`), 0644))
+	client, err := LoadClientOperations(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"users/get": false, "Me": true}, client)
+
+	config := &wgpb.WunderGraphConfiguration{Api: &wgpb.UserDefinedApi{Operations: []*wgpb.Operation{
+		{Path: "users/get", AuthenticationConfig: &wgpb.OperationAuthenticationConfig{AuthRequired: true}},
+		{Path: "Weather"},
+	}}}
+	var lines []string
+	for _, mismatch := range DiffOperations(client, config) {
+		lines = append(lines, mismatch.String())
+	}
+	assert.Equal(t, []string{
+		"operations: Me: requires authentication in the client, missing on the node",
+		"operations: Weather: public on the node, missing in the client",
+		"operations: users/get: public in the client, requires authentication on the node",
+	}, lines)
+}
@@ -0,0 +1,236 @@
+// Package configdiff compares two generated configs by what they mean to the node,
+// e.g. which datasources, operations and routes were added, instead of their JSON.
+package configdiff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+const (
+	SectionDataSources = "datasources"
+	SectionOperations  = "operations"
+	SectionRoutes      = "routes"
+	SectionAuth        = "auth"
+	SectionApi         = "api"
+)
+
+// Sections lists the sections of a diff in the order they are written
+var Sections = []string{SectionDataSources, SectionOperations, SectionRoutes, SectionAuth, SectionApi}
+
+type Kind string
+
+const (
+	Added   Kind = "+"
+	Removed Kind = "-"
+	Changed Kind = "~"
+)
+
+// Change is a single difference between two configs. Fields lists the JSON names of the
+// changed fields of Changed entries.
+type Change struct {
+	Section string
+	Kind    Kind
+	Name    string
+	Fields  []string
+}
+
+func (c Change) String() string {
+	if len(c.Fields) == 0 {
+		return fmt.Sprintf("%s %s", c.Kind, c.Name)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.Name, strings.Join(c.Fields, ", "))
+}
+
+// Diff returns the changes from old to new, grouped by section in the order of Sections
+// and sorted by name within a section
+func Diff(old, new *wgpb.WunderGraphConfiguration) []Change {
+	oldApi, newApi := old.GetApi(), new.GetApi()
+	var changes []Change
+	changes = append(changes, diffKeyed(SectionDataSources, dataSources(oldApi), dataSources(newApi))...)
+	changes = append(changes, diffKeyed(SectionOperations, operations(oldApi), operations(newApi))...)
+	changes = append(changes, diffKeyed(SectionRoutes, routes(old), routes(new))...)
+	changes = append(changes, diffAuth(oldApi.GetAuthenticationConfig(), newApi.GetAuthenticationConfig())...)
+	changes = append(changes, diffApi(old, new)...)
+	return changes
+}
+
+// Write prints changes grouped by section, it writes nothing if there are none
+func Write(w io.Writer, changes []Change) error {
+	for _, section := range Sections {
+		var lines []string
+		for _, change := range changes {
+			if change.Section == section {
+				lines = append(lines, "  "+change.String())
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s\n%s\n", section, strings.Join(lines, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dataSources(api *wgpb.UserDefinedApi) map[string]proto.Message {
+	entries := map[string]proto.Message{}
+	for i, dataSource := range api.GetEngineConfiguration().GetDatasourceConfigurations() {
+		// configs of older SDKs don't carry ids
+		key := dataSource.Id
+		if key == "" {
+			key = fmt.Sprintf("%s#%d", dataSource.Kind, i)
+		}
+		entries[key] = dataSource
+	}
+	return entries
+}
+
+func operations(api *wgpb.UserDefinedApi) map[string]proto.Message {
+	entries := map[string]proto.Message{}
+	for _, operation := range api.GetOperations() {
+		entries[operation.Name] = operation
+	}
+	return entries
+}
+
+// routes returns the routes the config makes the node serve, mirroring the apihandler
+func routes(config *wgpb.WunderGraphConfiguration) map[string]proto.Message {
+	api := config.GetApi()
+	entries := map[string]proto.Message{}
+	add := func(route string) {
+		// routes are only ever added or removed
+		entries[route] = &emptypb.Empty{}
+	}
+	if config.GetDangerouslyEnableGraphQLEndpoint() || api.GetEnableGraphqlEndpoint() {
+		add("POST /graphql")
+	}
+	for _, operation := range api.GetOperations() {
+		if operation.Internal {
+			continue
+		}
+		method := "GET"
+		if operation.OperationType == wgpb.OperationType_MUTATION {
+			method = "POST"
+		}
+		add(fmt.Sprintf("%s /operations/%s", method, operation.Path))
+	}
+	for _, name := range api.GetInvalidOperationNames() {
+		add(fmt.Sprintf("* /operations/%s (unavailable)", name))
+	}
+	for _, webhook := range api.GetWebhooks() {
+		add(fmt.Sprintf("POST /webhooks/%s", webhook.Name))
+	}
+	for _, provider := range api.GetS3UploadConfiguration() {
+		add(fmt.Sprintf("POST /s3/%s/upload", provider.Name))
+	}
+	return entries
+}
+
+func diffAuth(old, new *wgpb.ApiAuthenticationConfig) []Change {
+	oldProviders, newProviders := map[string]proto.Message{}, map[string]proto.Message{}
+	for _, provider := range old.GetCookieBased().GetProviders() {
+		oldProviders["cookie provider "+provider.Id] = provider
+	}
+	for _, provider := range new.GetCookieBased().GetProviders() {
+		newProviders["cookie provider "+provider.Id] = provider
+	}
+	changes := diffKeyed(SectionAuth, oldProviders, newProviders)
+	for _, field := range changedFields(old.GetCookieBased(), new.GetCookieBased(), &wgpb.CookieBasedAuthentication{}) {
+		if field != "providers" {
+			changes = append(changes, Change{Section: SectionAuth, Kind: Changed, Name: "cookieBased." + field})
+		}
+	}
+	for _, field := range changedFields(old, new, &wgpb.ApiAuthenticationConfig{}) {
+		if field != "cookieBased" {
+			changes = append(changes, Change{Section: SectionAuth, Kind: Changed, Name: field})
+		}
+	}
+	return changes
+}
+
+// diffApi reports the remaining settings of the api, sections above cover the others
+func diffApi(old, new *wgpb.WunderGraphConfiguration) []Change {
+	covered := map[string]bool{
+		"engineConfiguration":   true,
+		"operations":            true,
+		"invalidOperationNames": true,
+		"authenticationConfig":  true,
+		"webhooks":              true,
+	}
+	var changes []Change
+	for _, field := range changedFields(old.GetApi(), new.GetApi(), &wgpb.UserDefinedApi{}) {
+		if !covered[field] {
+			changes = append(changes, Change{Section: SectionApi, Kind: Changed, Name: field})
+		}
+	}
+	for _, field := range changedFields(old, new, &wgpb.WunderGraphConfiguration{}) {
+		if field != "api" {
+			changes = append(changes, Change{Section: SectionApi, Kind: Changed, Name: field})
+		}
+	}
+	return changes
+}
+
+func diffKeyed(section string, old, new map[string]proto.Message) []Change {
+	var changes []Change
+	for key, oldEntry := range old {
+		newEntry, ok := new[key]
+		if !ok {
+			changes = append(changes, Change{Section: section, Kind: Removed, Name: key})
+			continue
+		}
+		if fields := changedFields(oldEntry, newEntry, oldEntry); len(fields) != 0 {
+			changes = append(changes, Change{Section: section, Kind: Changed, Name: key, Fields: fields})
+		}
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			changes = append(changes, Change{Section: section, Kind: Added, Name: key})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// changedFields returns the JSON names of the fields differing between a and b, which
+// are of the same type as zero. Nil messages compare like empty ones.
+func changedFields(a, b, zero proto.Message) []string {
+	ma, mb := orZero(a, zero), orZero(b, zero)
+	fields := ma.Descriptor().Fields()
+	var changed []string
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !proto.Equal(only(ma, field), only(mb, field)) {
+			changed = append(changed, field.JSONName())
+		}
+	}
+	return changed
+}
+
+func orZero(msg, zero proto.Message) protoreflect.Message {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return zero.ProtoReflect()
+	}
+	return msg.ProtoReflect()
+}
+
+// only returns a copy of msg with nothing but field set
+func only(msg protoreflect.Message, field protoreflect.FieldDescriptor) proto.Message {
+	copied := msg.New()
+	if msg.Has(field) {
+		copied.Set(field, msg.Get(field))
+	}
+	return copied.Interface()
+}
@@ -0,0 +1,67 @@
+package configdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestDiff(t *testing.T) {
+	old := &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			EngineConfiguration: &wgpb.EngineConfiguration{
+				DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+					{Id: "countries", Kind: wgpb.DataSourceKind_GRAPHQL},
+					{Id: "weather", Kind: wgpb.DataSourceKind_REST},
+				},
+			},
+			Operations: []*wgpb.Operation{
+				{Name: "Countries", Path: "Countries", Content: "{countries{code}}", OperationType: wgpb.OperationType_QUERY},
+				{Name: "Weather", Path: "Weather", OperationType: wgpb.OperationType_QUERY},
+			},
+			AuthenticationConfig: &wgpb.ApiAuthenticationConfig{
+				CookieBased: &wgpb.CookieBasedAuthentication{
+					Providers: []*wgpb.AuthProvider{{Id: "github", Kind: wgpb.AuthProviderKind_AuthProviderGithub}},
+				},
+			},
+		},
+	}
+	require.Empty(t, Diff(old, proto.Clone(old).(*wgpb.WunderGraphConfiguration)))
+
+	updated := proto.Clone(old).(*wgpb.WunderGraphConfiguration)
+	updated.Api.EngineConfiguration.DatasourceConfigurations = []*wgpb.DataSourceConfiguration{
+		{Id: "countries", Kind: wgpb.DataSourceKind_GRAPHQL, RequestTimeoutSeconds: 10},
+		{Id: "users", Kind: wgpb.DataSourceKind_POSTGRESQL},
+	}
+	updated.Api.Operations[0].Content = "{countries{code name}}"
+	updated.Api.Operations = append(updated.Api.Operations, &wgpb.Operation{Name: "CreateUser", Path: "users/create", OperationType: wgpb.OperationType_MUTATION})
+	updated.Api.AuthenticationConfig.CookieBased.Providers = nil
+	updated.Api.AuthenticationConfig.JwksBased = &wgpb.JwksBasedAuthentication{}
+	updated.Api.EnableGraphqlEndpoint = true
+
+	changes := Diff(old, updated)
+	assert.Equal(t, []Change{
+		{Section: SectionDataSources, Kind: Changed, Name: "countries", Fields: []string{"requestTimeoutSeconds"}},
+		{Section: SectionDataSources, Kind: Added, Name: "users"},
+		{Section: SectionDataSources, Kind: Removed, Name: "weather"},
+		{Section: SectionOperations, Kind: Changed, Name: "Countries", Fields: []string{"content"}},
+		{Section: SectionOperations, Kind: Added, Name: "CreateUser"},
+		{Section: SectionRoutes, Kind: Added, Name: "POST /graphql"},
+		{Section: SectionRoutes, Kind: Added, Name: "POST /operations/users/create"},
+		{Section: SectionAuth, Kind: Removed, Name: "cookie provider github"},
+		{Section: SectionAuth, Kind: Changed, Name: "jwksBased"},
+		{Section: SectionApi, Kind: Changed, Name: "enableGraphqlEndpoint"},
+	}, changes)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, changes[3:5]))
+	assert.Equal(t, "operations\n  ~ Countries: content\n  + CreateUser\n", out.String())
+
+	// nil configs compare like empty ones
+	assert.Len(t, Diff(nil, old), 7)
+}
@@ -0,0 +1,158 @@
+// Package configschema derives a JSON Schema of the generated WunderGraph config from
+// the protobuf generated Go types, so editors can validate and autocomplete it.
+package configschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+const draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is the subset of JSON Schema draft 7 used to describe the config
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// WunderGraphConfiguration returns the schema of the generated wundergraph.config.json
+func WunderGraphConfiguration() *Schema {
+	return ForType(reflect.TypeOf(&wgpb.WunderGraphConfiguration{}))
+}
+
+// ForType returns the schema of the JSON encoding of t as used by encoding/json, every
+// message type is added once to the definitions. Enums are encoded as numbers.
+func ForType(t reflect.Type) *Schema {
+	g := &generator{definitions: map[string]*Schema{}}
+	root := g.schema(t)
+	root.Schema = draft
+	root.Definitions = g.definitions
+	return root
+}
+
+// JSON returns the indented JSON encoding of the schema
+func (s *Schema) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+type generator struct {
+	definitions map[string]*Schema
+}
+
+var (
+	enumType  = reflect.TypeOf((*protoreflect.Enum)(nil)).Elem()
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t.Implements(enumType) {
+		return enumSchema(reflect.Zero(t).Interface().(protoreflect.Enum).Descriptor())
+	}
+	if t == bytesType {
+		return &Schema{Type: "string", Description: "base64 encoded bytes"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		// e.g. interfaces, any value is accepted
+		return &Schema{}
+	}
+}
+
+// ref adds the definition of the struct t, unless it exists, and returns a reference to it
+func (g *generator) ref(t reflect.Type) *Schema {
+	name := t.Name()
+	ref := &Schema{Ref: "#/definitions/" + name}
+	if _, ok := g.definitions[name]; ok {
+		return ref
+	}
+	definition := &Schema{
+		Title:                name,
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	// registered before the fields, messages can be recursive
+	g.definitions[name] = definition
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		definition.Properties[name] = g.schema(field.Type)
+	}
+	return ref
+}
+
+func jsonName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func enumSchema(descriptor protoreflect.EnumDescriptor) *Schema {
+	values := descriptor.Values()
+	numbers := make([]int, values.Len())
+	names := make(map[int]string, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		number := int(values.Get(i).Number())
+		numbers[i] = number
+		names[number] = string(values.Get(i).Name())
+	}
+	sort.Ints(numbers)
+	schema := &Schema{
+		Title: string(descriptor.Name()),
+		Type:  "integer",
+	}
+	descriptions := make([]string, len(numbers))
+	for i, number := range numbers {
+		schema.Enum = append(schema.Enum, number)
+		descriptions[i] = fmt.Sprintf("%d: %s", number, names[number])
+	}
+	schema.Description = strings.Join(descriptions, ", ")
+	return schema
+}
@@ -0,0 +1,36 @@
+package configschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWunderGraphConfiguration(t *testing.T) {
+	schema := WunderGraphConfiguration()
+	assert.Equal(t, draft, schema.Schema)
+	assert.Equal(t, "#/definitions/WunderGraphConfiguration", schema.Ref)
+
+	root := schema.Definitions["WunderGraphConfiguration"]
+	require.NotNil(t, root)
+	assert.Equal(t, "#/definitions/UserDefinedApi", root.Properties["api"].Ref)
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, root.Properties["environmentIds"])
+	assert.Equal(t, false, root.AdditionalProperties)
+
+	variable := schema.Definitions["ConfigurationVariable"]
+	require.NotNil(t, variable)
+	kind := variable.Properties["kind"]
+	assert.Equal(t, "integer", kind.Type)
+	assert.Equal(t, []interface{}{0, 1, 2}, kind.Enum)
+	assert.Contains(t, kind.Description, "1: ENV_CONFIGURATION_VARIABLE")
+
+	fetch := schema.Definitions["FetchConfiguration"]
+	require.NotNil(t, fetch)
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Ref: "#/definitions/HTTPHeader"}}, fetch.Properties["header"])
+
+	data, err := schema.JSON()
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
@@ -0,0 +1,289 @@
+// Package depgraph builds a graph of the relationships between operations,
+// fragments, datasources and webhooks of a WunderGraph application.
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/operations"
+	"github.com/wundergraph/wundergraph/pkg/webhooks"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+const FragmentsDirectoryName = files.FragmentsDirectoryName
+
+type NodeKind string
+
+const (
+	NodeKindOperation  NodeKind = "operation"
+	NodeKindFragment   NodeKind = "fragment"
+	NodeKindDataSource NodeKind = "datasource"
+	NodeKindWebhook    NodeKind = "webhook"
+)
+
+type EdgeKind string
+
+const (
+	// EdgeKindUses connects an operation with a datasource serving one of its root fields
+	EdgeKindUses EdgeKind = "uses"
+	// EdgeKindSpreads connects an operation with a fragment it spreads
+	EdgeKindSpreads EdgeKind = "spreads"
+)
+
+type Node struct {
+	ID    string   `json:"id"`
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+	// File is the path of the source file relative to the WunderGraph directory, if known
+	File string `json:"file,omitempty"`
+}
+
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+var fragmentDefinitionReg = regexp.MustCompile(`fragment\s+([_A-Za-z][_0-9A-Za-z]*)\s+on\s`)
+
+// Build creates the graph from the generated config and the operation, fragment and webhook
+// files found in wunderGraphDir. Operations are linked to the datasources that serve their
+// root fields.
+func Build(wunderGraphDir string, config *wgpb.WunderGraphConfiguration) (*Graph, error) {
+	b := &builder{
+		graph: &Graph{},
+		nodes: map[string]*Node{},
+		edges: map[string]struct{}{},
+	}
+
+	rootFields := map[string][]string{}
+	if config.Api != nil && config.Api.EngineConfiguration != nil {
+		for i, ds := range config.Api.EngineConfiguration.DatasourceConfigurations {
+			id := dataSourceID(i, ds)
+			b.addNode(&Node{ID: id, Kind: NodeKindDataSource, Label: dataSourceLabel(ds)})
+			for _, rootNode := range ds.RootNodes {
+				for _, fieldName := range rootNode.FieldNames {
+					key := rootNode.TypeName + "." + fieldName
+					rootFields[key] = append(rootFields[key], id)
+				}
+			}
+		}
+	}
+
+	fragmentFiles, err := findFragmentFiles(wunderGraphDir)
+	if err != nil {
+		return nil, err
+	}
+	operationFiles := map[string]string{}
+	if files.DirectoryExists(filepath.Join(wunderGraphDir, operations.DirectoryName)) {
+		paths, err := operations.GetPaths(wunderGraphDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			operationFiles[operationPathFromFile(path)] = path
+		}
+	}
+
+	if config.Api != nil {
+		for _, operation := range config.Api.Operations {
+			id := "operation:" + operation.Name
+			b.addNode(&Node{ID: id, Kind: NodeKindOperation, Label: operation.Name, File: operationFiles[operation.Path]})
+			if operation.Engine == wgpb.OperationExecutionEngine_ENGINE_NODEJS {
+				continue
+			}
+			doc, report := astparser.ParseGraphqlDocumentString(operation.Content)
+			if report.HasErrors() {
+				return nil, fmt.Errorf("parsing operation %s: %w", operation.Name, report)
+			}
+			for _, key := range operationRootFields(&doc) {
+				for _, dsID := range rootFields[key] {
+					b.addEdge(id, dsID, EdgeKindUses)
+				}
+			}
+			for _, name := range fragmentSpreads(&doc) {
+				fragmentID := "fragment:" + name
+				b.addNode(&Node{ID: fragmentID, Kind: NodeKindFragment, Label: name, File: fragmentFiles[name]})
+				b.addEdge(id, fragmentID, EdgeKindSpreads)
+			}
+		}
+	}
+
+	if files.DirectoryExists(filepath.Join(wunderGraphDir, webhooks.WebhookDirectoryName)) {
+		paths, err := webhooks.GetWebhooks(wunderGraphDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			b.addNode(&Node{ID: "webhook:" + name, Kind: NodeKindWebhook, Label: name, File: path})
+		}
+	}
+
+	return b.graph, nil
+}
+
+// WriteDOT renders the graph in the Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph wundergraph {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%q [label=%q, shape=%s];\n", node.ID, node.Label, dotShape(node.Kind))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&sb, "\t%q -> %q [label=%q];\n", edge.From, edge.To, edge.Kind)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type builder struct {
+	graph *Graph
+	nodes map[string]*Node
+	edges map[string]struct{}
+}
+
+func (b *builder) addNode(node *Node) {
+	if _, ok := b.nodes[node.ID]; ok {
+		return
+	}
+	b.nodes[node.ID] = node
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+func (b *builder) addEdge(from, to string, kind EdgeKind) {
+	key := from + "->" + to
+	if _, ok := b.edges[key]; ok {
+		return
+	}
+	b.edges[key] = struct{}{}
+	b.graph.Edges = append(b.graph.Edges, &Edge{From: from, To: to, Kind: kind})
+}
+
+func dataSourceID(index int, ds *wgpb.DataSourceConfiguration) string {
+	if ds.Id != "" {
+		return "datasource:" + ds.Id
+	}
+	return fmt.Sprintf("datasource:%d", index)
+}
+
+func dataSourceLabel(ds *wgpb.DataSourceConfiguration) string {
+	kind := strings.ToLower(ds.Kind.String())
+	if ds.Id != "" {
+		return kind + ":" + ds.Id
+	}
+	return kind
+}
+
+func dotShape(kind NodeKind) string {
+	switch kind {
+	case NodeKindDataSource:
+		return "cylinder"
+	case NodeKindFragment:
+		return "note"
+	case NodeKindWebhook:
+		return "hexagon"
+	default:
+		return "box"
+	}
+}
+
+// operationRootFields returns the root fields of all operations in doc as "Type.field"
+func operationRootFields(doc *ast.Document) []string {
+	var fields []string
+	for _, operation := range doc.OperationDefinitions {
+		if !operation.HasSelections {
+			continue
+		}
+		var typeName string
+		switch operation.OperationType {
+		case ast.OperationTypeMutation:
+			typeName = "Mutation"
+		case ast.OperationTypeSubscription:
+			typeName = "Subscription"
+		default:
+			typeName = "Query"
+		}
+		for _, selectionRef := range doc.SelectionSets[operation.SelectionSet].SelectionRefs {
+			selection := doc.Selections[selectionRef]
+			if selection.Kind != ast.SelectionKindField {
+				continue
+			}
+			fields = append(fields, typeName+"."+doc.FieldNameString(selection.Ref))
+		}
+	}
+	return fields
+}
+
+func fragmentSpreads(doc *ast.Document) []string {
+	seen := map[string]struct{}{}
+	var names []string
+	for i := range doc.FragmentSpreads {
+		name := doc.FragmentSpreadNameString(i)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findFragmentFiles maps fragment names to the file defining them, fragment files have one
+// of the GraphQL operation extensions set via operations.ExtensionsEnvKey
+func findFragmentFiles(wunderGraphDir string) (map[string]string, error) {
+	fragments := map[string]string{}
+	extensions := operations.GraphQLExtensionsFromEnv()
+	fragmentsDir := filepath.Join(wunderGraphDir, FragmentsDirectoryName)
+	if !files.DirectoryExists(fragmentsDir) {
+		return fragments, nil
+	}
+	err := filepath.Walk(fragmentsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if _, ok := operations.GraphQLExtension(path, extensions); !ok {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(wunderGraphDir, path)
+		if err != nil {
+			return err
+		}
+		for _, match := range fragmentDefinitionReg.FindAllSubmatch(data, -1) {
+			fragments[string(match[1])] = rel
+		}
+		return nil
+	})
+	return fragments, err
+}
+
+// operationPathFromFile converts operations/users/Get.ts into users/Get
+func operationPathFromFile(path string) string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), operations.DirectoryName+"/")
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
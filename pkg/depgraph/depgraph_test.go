@@ -0,0 +1,76 @@
+package depgraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/operations"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, FragmentsDirectoryName), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FragmentsDirectoryName, "user.graphql"), []byte("fragment UserFields on User { id }"), os.ModePerm))
+
+	config := &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			EngineConfiguration: &wgpb.EngineConfiguration{
+				DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+					{
+						Id:        "users",
+						Kind:      wgpb.DataSourceKind_GRAPHQL,
+						RootNodes: []*wgpb.TypeField{{TypeName: "Query", FieldNames: []string{"me"}}},
+					},
+					{
+						Id:        "products",
+						Kind:      wgpb.DataSourceKind_REST,
+						RootNodes: []*wgpb.TypeField{{TypeName: "Query", FieldNames: []string{"topProducts"}}},
+					},
+				},
+			},
+			Operations: []*wgpb.Operation{
+				{
+					Name:    "Me",
+					Path:    "Me",
+					Content: "query Me { me { ...UserFields } } fragment UserFields on User { id }",
+				},
+			},
+		},
+	}
+
+	graph, err := Build(dir, config)
+	require.NoError(t, err)
+
+	assert.Len(t, graph.Nodes, 4)
+	assert.Equal(t, []*Edge{
+		{From: "operation:Me", To: "datasource:users", Kind: EdgeKindUses},
+		{From: "operation:Me", To: "fragment:UserFields", Kind: EdgeKindSpreads},
+	}, graph.Edges)
+	assert.Equal(t, filepath.Join(FragmentsDirectoryName, "user.graphql"), graph.Nodes[3].File)
+
+	var buf bytes.Buffer
+	require.NoError(t, graph.WriteDOT(&buf))
+	assert.Contains(t, buf.String(), `"operation:Me" -> "datasource:users" [label="uses"];`)
+}
+
+func TestFindFragmentFilesExtensions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, FragmentsDirectoryName), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FragmentsDirectoryName, "user.graphql"), []byte("fragment UserFields on User { id }"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FragmentsDirectoryName, "post.gqls"), []byte("fragment PostFields on Post { id }"), os.ModePerm))
+
+	fragments, err := findFragmentFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"UserFields": filepath.Join(FragmentsDirectoryName, "user.graphql")}, fragments)
+
+	t.Setenv(operations.ExtensionsEnvKey, "gqls")
+	fragments, err = findFragmentFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"PostFields": filepath.Join(FragmentsDirectoryName, "post.gqls")}, fragments)
+}
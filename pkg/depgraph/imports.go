@@ -0,0 +1,189 @@
+package depgraph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/pkg/astparser"
+
+	"github.com/wundergraph/wundergraph/pkg/files"
+	"github.com/wundergraph/wundergraph/pkg/operations"
+)
+
+// EdgeKindImports connects an operation or fragment file with a file it imports, either via a
+// TypeScript import or by spreading a fragment defined in the other file
+const EdgeKindImports EdgeKind = "imports"
+
+// importReg matches the relative module specifiers of TypeScript import and export statements,
+// imports from packages can't point back into the operations and are ignored
+var importReg = regexp.MustCompile(`(?m)^\s*(?:import|export)\s(?:[^'";]*?\sfrom\s*)?['"](\.\.?/[^'"]+)['"]`)
+
+// BuildImports creates the graph of the files in the operations and fragments directories, nodes
+// are identified by their path relative to wunderGraphDir. GraphQL files that can't be parsed are
+// added without edges, the config runner reports their errors. GraphQL files are recognized by
+// graphqlExtensions, operations.DefaultGraphQLExtensions if nil.
+func BuildImports(wunderGraphDir string, graphqlExtensions []string) (*Graph, error) {
+	b := &builder{
+		graph: &Graph{},
+		nodes: map[string]*Node{},
+		edges: map[string]struct{}{},
+	}
+	var paths []string
+	for _, dirName := range []string{operations.DirectoryName, FragmentsDirectoryName} {
+		dir := filepath.Join(wunderGraphDir, dirName)
+		if !files.DirectoryExists(dir) {
+			continue
+		}
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || strings.HasSuffix(info.Name(), ".d.ts") {
+				return nil
+			}
+			if _, ok := operations.GraphQLExtension(path, graphqlExtensions); !ok && filepath.Ext(path) != ".ts" {
+				return nil
+			}
+			rel, err := filepath.Rel(wunderGraphDir, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(paths)
+
+	known := map[string]struct{}{}
+	for _, path := range paths {
+		known[path] = struct{}{}
+		kind := NodeKindOperation
+		if strings.HasPrefix(filepath.ToSlash(path), FragmentsDirectoryName+"/") {
+			kind = NodeKindFragment
+		}
+		b.addNode(&Node{ID: path, Kind: kind, Label: filepath.ToSlash(path), File: path})
+	}
+
+	fragmentFiles := map[string]string{}
+	spreadsByFile := map[string][]string{}
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(wunderGraphDir, path))
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Ext(path) == ".ts" {
+			for _, match := range importReg.FindAllSubmatch(data, -1) {
+				if target, ok := resolveImport(path, string(match[1]), known); ok {
+					b.addEdge(path, target, EdgeKindImports)
+				}
+			}
+			continue
+		}
+		doc, report := astparser.ParseGraphqlDocumentBytes(data)
+		if report.HasErrors() {
+			continue
+		}
+		for i := range doc.FragmentDefinitions {
+			fragmentFiles[doc.FragmentDefinitionNameString(i)] = path
+		}
+		spreadsByFile[path] = fragmentSpreads(&doc)
+	}
+	for _, path := range paths {
+		for _, name := range spreadsByFile[path] {
+			// fragments defined in the same file don't import anything
+			if target, ok := fragmentFiles[name]; ok && target != path {
+				b.addEdge(path, target, EdgeKindImports)
+			}
+		}
+	}
+	return b.graph, nil
+}
+
+// resolveImport returns the file imported by specifier from the file at path, like esbuild it
+// tries the path itself, with a .ts extension and as a directory with an index.ts
+func resolveImport(path, specifier string, known map[string]struct{}) (string, bool) {
+	base := filepath.Join(filepath.Dir(path), filepath.FromSlash(specifier))
+	candidates := []string{base, base + ".ts", filepath.Join(base, "index.ts")}
+	if ext := filepath.Ext(base); ext == ".js" {
+		// TypeScript allows importing ./user.ts as ./user.js
+		candidates = append(candidates, strings.TrimSuffix(base, ext)+".ts")
+	}
+	for _, candidate := range candidates {
+		if _, ok := known[candidate]; ok {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Cycles returns the cycles of the graph, each as the IDs of its nodes starting and ending with
+// the same node. Every cycle is reported once, starting at the first of its nodes that is visited.
+func (g *Graph) Cycles() [][]string {
+	edges := map[string][]string{}
+	for _, edge := range g.Edges {
+		edges[edge.From] = append(edges[edge.From], edge.To)
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var stack []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, to := range edges[id] {
+			switch state[to] {
+			case unvisited:
+				visit(to)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == to {
+						cycle := append(append([]string{}, stack[i:]...), to)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+	}
+	for _, node := range g.Nodes {
+		if state[node.ID] == unvisited {
+			visit(node.ID)
+		}
+	}
+	return cycles
+}
+
+// CheckImportCycles returns an error listing the chain of files of every circular import between
+// operations and fragments, esbuild and the config runner fail with confusing errors on them
+func CheckImportCycles(wunderGraphDir string, graphqlExtensions []string) error {
+	graph, err := BuildImports(wunderGraphDir, graphqlExtensions)
+	if err != nil {
+		return err
+	}
+	cycles := graph.Cycles()
+	if len(cycles) == 0 {
+		return nil
+	}
+	chains := make([]string, len(cycles))
+	for i, cycle := range cycles {
+		for j := range cycle {
+			cycle[j] = filepath.ToSlash(cycle[j])
+		}
+		chains[i] = strings.Join(cycle, " -> ")
+	}
+	return fmt.Errorf("circular imports between operations and fragments, remove one import of each chain: %s", strings.Join(chains, "; "))
+}
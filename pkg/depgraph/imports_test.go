@@ -0,0 +1,47 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, contents map[string]string) {
+	for path, content := range contents {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(content), os.ModePerm))
+	}
+}
+
+func TestCheckImportCycles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"operations/Me.graphql":      "query Me { me { ...UserFields } }",
+		"operations/Users.ts":        "import { schema } from './users/schema';\nexport default schema;",
+		"operations/users/schema.ts": "import '../Users';\nexport const schema = {};",
+		"fragments/user.graphql":     "fragment UserFields on User { id friends { ...FriendFields } }",
+		"fragments/friend.graphql":   "fragment FriendFields on User { id best { ...UserFields } }",
+		"fragments/post.graphql":     "fragment PostFields on Post { id ...PostAuthor } fragment PostAuthor on Post { author { id } }",
+	})
+
+	graph, err := BuildImports(dir, nil)
+	require.NoError(t, err)
+	assert.Len(t, graph.Nodes, 6)
+	assert.Equal(t, [][]string{
+		{filepath.Join("fragments", "friend.graphql"), filepath.Join("fragments", "user.graphql"), filepath.Join("fragments", "friend.graphql")},
+		{filepath.Join("operations", "Users.ts"), filepath.Join("operations", "users", "schema.ts"), filepath.Join("operations", "Users.ts")},
+	}, graph.Cycles())
+
+	err = CheckImportCycles(dir, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fragments/friend.graphql -> fragments/user.graphql -> fragments/friend.graphql")
+	assert.Contains(t, err.Error(), "operations/Users.ts -> operations/users/schema.ts -> operations/Users.ts")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fragments", "friend.graphql"), []byte("fragment FriendFields on User { id }"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "operations", "users", "schema.ts"), []byte("export const schema = {};"), os.ModePerm))
+	assert.NoError(t, CheckImportCycles(dir, nil))
+}
@@ -0,0 +1,282 @@
+package engineconfigloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CassetteVersion is the version of the cassette file format
+const CassetteVersion = 1
+
+const redactedHeaderValue = "[REDACTED]"
+
+// sensitiveHeaderPattern matches the names of headers whose values are redacted in cassettes
+var sensitiveHeaderPattern = regexp.MustCompile(`(?i)(authorization|cookie|token|secret|api-?key|password|session)`)
+
+// Cassette holds recorded upstream interactions in the order they happened
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request    CassetteRequest  `json:"request"`
+	Response   CassetteResponse `json:"response"`
+	RecordedAt time.Time        `json:"recordedAt"`
+}
+
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	CassetteBody
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	CassetteBody
+}
+
+// CassetteBody stores text bodies as they are and binary ones base64 encoded
+type CassetteBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+func newCassetteBody(data []byte) CassetteBody {
+	if utf8.Valid(data) {
+		return CassetteBody{Body: string(data)}
+	}
+	return CassetteBody{BodyBase64: base64.StdEncoding.EncodeToString(data)}
+}
+
+func (b CassetteBody) bytes() []byte {
+	if b.BodyBase64 != "" {
+		data, _ := base64.StdEncoding.DecodeString(b.BodyBase64)
+		return data
+	}
+	return []byte(b.Body)
+}
+
+// signature identifies requests by method, url and body, headers differ between runs
+// e.g. by tracing ids and are ignored
+func signature(method, url string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return method + " " + url + " " + hex.EncodeToString(sum[:])
+}
+
+func (r CassetteRequest) signature() string {
+	return signature(r.Method, r.URL, r.bytes())
+}
+
+// LoadCassette reads the cassette at path
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if cassette.Version != CassetteVersion {
+		return nil, fmt.Errorf("cassette %s has version %d, expected %d", path, cassette.Version, CassetteVersion)
+	}
+	return &cassette, nil
+}
+
+// Recorder writes every upstream interaction to a cassette file, the file is rewritten
+// after each interaction so it's complete even if the node is killed
+type Recorder struct {
+	path     string
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func NewRecorder(path string) *Recorder {
+	return &Recorder{
+		path:     path,
+		cassette: Cassette{Version: CassetteVersion},
+	}
+}
+
+func (r *Recorder) record(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// Replayer answers upstream requests from a cassette. Requests with the same signature get
+// the recorded responses in order, the last one is repeated once all are used.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]CassetteResponse
+}
+
+func NewReplayer(cassette *Cassette) *Replayer {
+	responses := map[string][]CassetteResponse{}
+	for _, interaction := range cassette.Interactions {
+		key := interaction.Request.signature()
+		responses[key] = append(responses[key], interaction.Response)
+	}
+	return &Replayer{responses: responses}
+}
+
+func (r *Replayer) next(key string) (CassetteResponse, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		return CassetteResponse{}, false
+	}
+	if len(responses) > 1 {
+		r.responses[key] = responses[1:]
+	}
+	return responses[0], true
+}
+
+// NewCassetteTransportFactory wraps the transports created by factory to record their
+// interactions with recorder or to answer them from replayer, exactly one must be set.
+// Streaming requests, e.g. of subscriptions, are neither recorded nor replayed.
+func NewCassetteTransportFactory(factory ApiTransportFactory, recorder *Recorder, replayer *Replayer) ApiTransportFactory {
+	return &cassetteTransportFactory{
+		factory:  factory,
+		recorder: recorder,
+		replayer: replayer,
+	}
+}
+
+type cassetteTransportFactory struct {
+	factory  ApiTransportFactory
+	recorder *Recorder
+	replayer *Replayer
+}
+
+func (f *cassetteTransportFactory) RoundTripper(tripper http.RoundTripper, enableStreamingMode bool) http.RoundTripper {
+	if enableStreamingMode {
+		return f.factory.RoundTripper(tripper, enableStreamingMode)
+	}
+	return f.factory.RoundTripper(&cassetteRoundTripper{
+		roundTripper: tripper,
+		recorder:     f.recorder,
+		replayer:     f.replayer,
+	}, enableStreamingMode)
+}
+
+func (f *cassetteTransportFactory) DefaultTransportTimeout() time.Duration {
+	return f.factory.DefaultTransportTimeout()
+}
+
+type cassetteRoundTripper struct {
+	roundTripper http.RoundTripper
+	recorder     *Recorder
+	replayer     *Replayer
+}
+
+func (t *cassetteRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		_ = request.Body.Close()
+		request = request.Clone(request.Context())
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	if t.replayer != nil {
+		return t.replay(request, requestBody)
+	}
+	response, err := t.roundTripper.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	err = t.recorder.record(Interaction{
+		Request: CassetteRequest{
+			Method:       request.Method,
+			URL:          request.URL.String(),
+			Header:       redactHeader(request.Header),
+			CassetteBody: newCassetteBody(requestBody),
+		},
+		Response: CassetteResponse{
+			StatusCode:   response.StatusCode,
+			Header:       redactHeader(response.Header),
+			CassetteBody: newCassetteBody(responseBody),
+		},
+		RecordedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not record upstream interaction: %w", err)
+	}
+	return response, nil
+}
+
+func (t *cassetteRoundTripper) replay(request *http.Request, requestBody []byte) (*http.Response, error) {
+	url := request.URL.String()
+	recorded, ok := t.replayer.next(signature(request.Method, url, requestBody))
+	if !ok {
+		return nil, fmt.Errorf("no recorded interaction for %s %s in cassette", request.Method, url)
+	}
+	body := recorded.bytes()
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if sensitiveHeaderPattern.MatchString(name) {
+			redacted[name] = []string{redactedHeaderValue}
+			continue
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
@@ -0,0 +1,68 @@
+package engineconfigloader
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Upstream", "yes")
+		_, _ = w.Write([]byte(strings.ToUpper(string(body))))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	send := func(tripper http.RoundTripper, body string) (*http.Response, error) {
+		r, _ := http.NewRequest(http.MethodPost, upstream.URL+"/graphql", strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer secret")
+		return tripper.RoundTrip(r)
+	}
+
+	recording := NewCassetteTransportFactory(passthroughTransportFactory{}, NewRecorder(path), nil).RoundTripper(http.DefaultTransport, false)
+	resp, err := send(recording, "hello")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "HELLO", string(body))
+	_, err = send(recording, "world")
+	require.NoError(t, err)
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 2)
+	assert.Equal(t, redactedHeaderValue, cassette.Interactions[0].Request.Header.Get("Authorization"))
+	assert.Equal(t, redactedHeaderValue, cassette.Interactions[0].Response.Header.Get("Set-Cookie"))
+	assert.Equal(t, "yes", cassette.Interactions[0].Response.Header.Get("X-Upstream"))
+
+	replaying := NewCassetteTransportFactory(passthroughTransportFactory{}, nil, NewReplayer(cassette)).RoundTripper(http.DefaultTransport, false)
+	for _, body := range []string{"world", "hello", "hello"} {
+		resp, err := send(replaying, body)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, strings.ToUpper(body), string(data))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, 2, calls, "replayed requests don't reach the upstream")
+
+	_, err = send(replaying, "unrecorded")
+	assert.ErrorContains(t, err, "no recorded interaction")
+}
+
+func TestNewCassetteBody(t *testing.T) {
+	text := newCassetteBody([]byte(`{"data":null}`))
+	assert.Equal(t, `{"data":null}`, text.Body)
+	binary := newCassetteBody([]byte{0xff, 0xfe})
+	assert.Empty(t, binary.Body)
+	assert.Equal(t, []byte{0xff, 0xfe}, binary.bytes())
+}
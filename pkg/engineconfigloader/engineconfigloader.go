@@ -53,6 +53,12 @@ type DefaultFactoryResolver struct {
 	static           *staticdatasource.Factory
 	database         *database.Factory
 	hooksClient      *hooks.Client
+	log              *zap.Logger
+	latencies        map[string]LatencyInjection
+	transforms       map[string][]ResponseTransform
+	lazy             bool
+	proxies          map[string]ProxyFunc
+	headerRoutings   map[string]HeaderRouting
 }
 
 func NewDefaultFactoryResolver(transportFactory ApiTransportFactory, baseTransport http.RoundTripper,
@@ -84,9 +90,35 @@ func NewDefaultFactoryResolver(transportFactory ApiTransportFactory, baseTranspo
 			Log:    log,
 		},
 		hooksClient: hooksClient,
+		log:         log,
 	}
 }
 
+// InjectLatency delays all upstream requests of the data source with the given id,
+// it must be called before the engine config is loaded
+func (d *DefaultFactoryResolver) InjectLatency(dataSourceID string, latency LatencyInjection) {
+	if d.latencies == nil {
+		d.latencies = map[string]LatencyInjection{}
+	}
+	d.latencies[dataSourceID] = latency
+}
+
+// TransformResponses applies the transforms to all upstream responses of the data source
+// with the given id, it must be called before the engine config is loaded
+func (d *DefaultFactoryResolver) TransformResponses(dataSourceID string, transforms ...ResponseTransform) {
+	if d.transforms == nil {
+		d.transforms = map[string][]ResponseTransform{}
+	}
+	d.transforms[dataSourceID] = append(d.transforms[dataSourceID], transforms...)
+}
+
+// ConnectLazily defers setting up the connections of data sources to their first request,
+// so invalid connection settings fail requests instead of the config load. It must be
+// called before the engine config is loaded.
+func (d *DefaultFactoryResolver) ConnectLazily() {
+	d.lazy = true
+}
+
 // requiresCustomHTTPClient returns true iff the given FetchConfiguration requires a dedicated HTTP client
 func (d *DefaultFactoryResolver) requiresCustomHTTPClient(ds *wgpb.DataSourceConfiguration, cfg *wgpb.FetchConfiguration) bool {
 	// when a custom timeout is specified, we can't use the shared http.Client
@@ -97,6 +129,22 @@ func (d *DefaultFactoryResolver) requiresCustomHTTPClient(ds *wgpb.DataSourceCon
 	if cfg != nil && cfg.MTLS != nil {
 		return true
 	}
+	// latency is injected by wrapping the transport
+	if _, ok := d.latencies[ds.GetId()]; ok {
+		return true
+	}
+	// as are response transforms
+	if _, ok := d.transforms[ds.GetId()]; ok {
+		return true
+	}
+	// the proxy is a setting of the transport
+	if _, ok := d.proxies[ds.GetId()]; ok {
+		return true
+	}
+	// header routing wraps the transport as well
+	if _, ok := d.headerRoutings[ds.GetId()]; ok {
+		return true
+	}
 	return false
 }
 
@@ -129,6 +177,7 @@ func (d *DefaultFactoryResolver) customTLSRoundTripper(mTLS *wgpb.MTLSConfigurat
 		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 			return dialer.DialContext(ctx, network, addr)
 		},
+		Proxy:               http.ProxyFromEnvironment,
 		MaxIdleConns:        1024,
 		IdleConnTimeout:     90 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
@@ -148,17 +197,60 @@ func (d *DefaultFactoryResolver) newHTTPClient(ds *wgpb.DataSourceConfiguration,
 	if ds != nil && ds.RequestTimeoutSeconds > 0 {
 		timeout = time.Duration(ds.RequestTimeoutSeconds) * time.Second
 	}
+	proxy, hasProxy := d.proxies[ds.GetId()]
 	// TLS
 	var transport http.RoundTripper
 	var err error
-	if cfg != nil && cfg.MTLS != nil {
+	if cfg != nil && cfg.MTLS != nil && d.lazy {
+		mTLS := cfg.MTLS
+		transport = &lazyRoundTripper{
+			dataSourceID: ds.GetId(),
+			build: func() (http.RoundTripper, error) {
+				transport, err := d.customTLSRoundTripper(mTLS)
+				if err == nil && hasProxy {
+					transport = d.withProxy(ds.GetId(), transport, proxy)
+				}
+				return transport, err
+			},
+			log: d.log,
+		}
+	} else if cfg != nil && cfg.MTLS != nil {
 		transport, err = d.customTLSRoundTripper(cfg.MTLS)
 		if err != nil {
 			return nil, err
 		}
+		if hasProxy {
+			transport = d.withProxy(ds.GetId(), transport, proxy)
+		}
+	} else if hasProxy {
+		transport = d.withProxy(ds.GetId(), d.baseTransport, proxy)
 	} else {
 		transport = d.baseTransport
 	}
+	if routing, ok := d.headerRoutings[ds.GetId()]; ok {
+		transport = &headerRoutingRoundTripper{
+			roundTripper: transport,
+			dataSourceID: ds.Id,
+			routing:      routing,
+			log:          d.log,
+		}
+	}
+	if transforms, ok := d.transforms[ds.GetId()]; ok {
+		transport = &transformRoundTripper{
+			roundTripper: transport,
+			dataSourceID: ds.Id,
+			transforms:   transforms,
+			log:          d.log,
+		}
+	}
+	if latency, ok := d.latencies[ds.GetId()]; ok {
+		transport = &latencyRoundTripper{
+			roundTripper: transport,
+			dataSourceID: ds.Id,
+			latency:      latency,
+			log:          d.log,
+		}
+	}
 	return &http.Client{
 		Timeout:   timeout,
 		Transport: d.transportFactory.RoundTripper(transport, false),
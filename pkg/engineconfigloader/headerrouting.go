@@ -0,0 +1,105 @@
+package engineconfigloader
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/pool"
+)
+
+// HeaderRouting sends the upstream requests of a data source to another upstream depending on
+// the value of a header of the client request. Routes map header values to the base URL whose
+// scheme and host replace the ones of the upstream request, the path is kept. Requests without
+// the header or with a value without a route go to the configured upstream.
+type HeaderRouting struct {
+	Header string
+	Routes map[string]*url.URL
+}
+
+// ParseHeaderRouting validates the routes of a HeaderRouting, route URLs must be absolute
+// http(s) URLs without a path
+func ParseHeaderRouting(header string, routes map[string]string) (HeaderRouting, error) {
+	if header == "" {
+		return HeaderRouting{}, fmt.Errorf("missing header of header routing")
+	}
+	routing := HeaderRouting{
+		Header: http.CanonicalHeaderKey(header),
+		Routes: make(map[string]*url.URL, len(routes)),
+	}
+	for value, route := range routes {
+		u, err := url.Parse(route)
+		if err != nil {
+			return HeaderRouting{}, fmt.Errorf("invalid route %q for %s: %s: %w", route, header, value, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return HeaderRouting{}, fmt.Errorf("invalid route %q for %s: %s, expected an absolute http(s) url", route, header, value)
+		}
+		if u.Path != "" && u.Path != "/" || u.RawQuery != "" {
+			return HeaderRouting{}, fmt.Errorf("invalid route %q for %s: %s, only scheme and host are replaced, remove the path", route, header, value)
+		}
+		routing.Routes[value] = u
+	}
+	return routing, nil
+}
+
+// Values returns the header values with a route, sorted
+func (r HeaderRouting) Values() []string {
+	values := make([]string, 0, len(r.Routes))
+	for value := range r.Routes {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// RouteByHeader applies routing to all upstream requests of the data source with the given id
+// but subscriptions, it must be called before the engine config is loaded
+func (d *DefaultFactoryResolver) RouteByHeader(dataSourceID string, routing HeaderRouting) {
+	if d.headerRoutings == nil {
+		d.headerRoutings = map[string]HeaderRouting{}
+	}
+	d.headerRoutings[dataSourceID] = routing
+}
+
+type headerRoutingRoundTripper struct {
+	roundTripper http.RoundTripper
+	dataSourceID string
+	routing      HeaderRouting
+	log          *zap.Logger
+}
+
+func (t *headerRoutingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	clientRequest, ok := request.Context().Value(pool.ClientRequestKey).(*http.Request)
+	if !ok {
+		return t.roundTripper.RoundTrip(request)
+	}
+	value := clientRequest.Header.Get(t.routing.Header)
+	if value == "" {
+		return t.roundTripper.RoundTrip(request)
+	}
+	route, ok := t.routing.Routes[value]
+	if !ok {
+		t.log.Warn("no route for header value, using the configured upstream",
+			zap.String("dataSourceId", t.dataSourceID),
+			zap.String("header", t.routing.Header),
+			zap.String("value", value),
+		)
+		return t.roundTripper.RoundTrip(request)
+	}
+	routed := request.Clone(request.Context())
+	routed.URL.Scheme = route.Scheme
+	routed.URL.Host = route.Host
+	// the Host header follows the URL
+	routed.Host = ""
+	t.log.Info("routing upstream request by header",
+		zap.String("dataSourceId", t.dataSourceID),
+		zap.String("header", t.routing.Header),
+		zap.String("value", value),
+		zap.String("upstream", route.Host),
+	)
+	return t.roundTripper.RoundTrip(routed)
+}
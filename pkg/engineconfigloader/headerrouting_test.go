@@ -0,0 +1,69 @@
+package engineconfigloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/pool"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestParseHeaderRouting(t *testing.T) {
+	routing, err := ParseHeaderRouting("x-backend", map[string]string{"staging": "https://staging.example.com", "local": "http://localhost:4000/"})
+	require.NoError(t, err)
+	assert.Equal(t, "X-Backend", routing.Header)
+	assert.Equal(t, []string{"local", "staging"}, routing.Values())
+
+	_, err = ParseHeaderRouting("", map[string]string{"staging": "https://staging.example.com"})
+	assert.Error(t, err)
+	_, err = ParseHeaderRouting("X-Backend", map[string]string{"staging": "staging.example.com"})
+	assert.Error(t, err)
+	_, err = ParseHeaderRouting("X-Backend", map[string]string{"staging": "https://staging.example.com/graphql"})
+	assert.Error(t, err)
+}
+
+func TestRouteByHeader(t *testing.T) {
+	var configured, staging []string
+	configuredServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		configured = append(configured, r.URL.Path)
+	}))
+	defer configuredServer.Close()
+	stagingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		staging = append(staging, r.URL.Path)
+	}))
+	defer stagingServer.Close()
+
+	ds := &wgpb.DataSourceConfiguration{Id: "billing", Kind: wgpb.DataSourceKind_REST}
+	resolver := NewDefaultFactoryResolver(passthroughTransportFactory{}, &http.Transport{}, false, zap.NewNop(), nil)
+	routing, err := ParseHeaderRouting("X-Backend", map[string]string{"staging": stagingServer.URL})
+	require.NoError(t, err)
+	resolver.RouteByHeader("billing", routing)
+	require.True(t, resolver.requiresCustomHTTPClient(ds, nil))
+	client, err := resolver.newHTTPClient(ds, nil)
+	require.NoError(t, err)
+
+	get := func(backend string) {
+		clientRequest := httptest.NewRequest(http.MethodGet, "/operations/Invoices", nil)
+		if backend != "" {
+			clientRequest.Header.Set("X-Backend", backend)
+		}
+		ctx := context.WithValue(context.Background(), pool.ClientRequestKey, clientRequest)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, configuredServer.URL+"/invoices", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	get("staging")
+	get("")
+	get("unknown")
+	assert.Equal(t, []string{"/invoices"}, staging)
+	assert.Equal(t, []string{"/invoices", "/invoices"}, configured)
+}
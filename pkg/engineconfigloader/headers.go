@@ -0,0 +1,46 @@
+package engineconfigloader
+
+import (
+	"net/http"
+	"time"
+)
+
+type headerInjectingTransportFactory struct {
+	factory ApiTransportFactory
+	header  http.Header
+}
+
+// NewHeaderInjectingTransportFactory wraps the transports created by factory to set header
+// on every upstream request. The header is set below the transport of the factory, it's
+// neither visible to hooks nor included in debug logs.
+func NewHeaderInjectingTransportFactory(factory ApiTransportFactory, header http.Header) ApiTransportFactory {
+	return &headerInjectingTransportFactory{
+		factory: factory,
+		header:  header,
+	}
+}
+
+func (f *headerInjectingTransportFactory) RoundTripper(tripper http.RoundTripper, enableStreamingMode bool) http.RoundTripper {
+	return f.factory.RoundTripper(&headerRoundTripper{
+		roundTripper: tripper,
+		header:       f.header,
+	}, enableStreamingMode)
+}
+
+func (f *headerInjectingTransportFactory) DefaultTransportTimeout() time.Duration {
+	return f.factory.DefaultTransportTimeout()
+}
+
+type headerRoundTripper struct {
+	roundTripper http.RoundTripper
+	header       http.Header
+}
+
+func (t *headerRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	request = request.Clone(request.Context())
+	for name, values := range t.header {
+		request.Header[name] = append([]string(nil), values...)
+	}
+	return t.roundTripper.RoundTrip(request)
+}
@@ -0,0 +1,52 @@
+package engineconfigloader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestHeaderInjectingTransportFactory(t *testing.T) {
+	received := make(chan http.Header, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	header := http.Header{}
+	header.Add("X-Dev-Key", "abc")
+	factory := NewHeaderInjectingTransportFactory(passthroughTransportFactory{}, header)
+	resolver := NewDefaultFactoryResolver(factory, http.DefaultTransport, false, zap.NewNop(), nil)
+
+	// the shared client and the dedicated ones of data sources with their own settings
+	dedicated, err := resolver.newHTTPClient(&wgpb.DataSourceConfiguration{Id: "billing", RequestTimeoutSeconds: 5}, nil)
+	require.NoError(t, err)
+	for name, client := range map[string]*http.Client{"shared": resolver.graphql.HTTPClient, "dedicated": dedicated} {
+		req, err := http.NewRequest(http.MethodGet, upstream.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Dev-Key", "from-client")
+		req.Header.Set("Accept", "application/json")
+		resp, err := client.Do(req)
+		require.NoError(t, err, name)
+		resp.Body.Close()
+
+		upstreamHeader := <-received
+		assert.Equal(t, []string{"abc"}, upstreamHeader.Values("X-Dev-Key"), name)
+		assert.Equal(t, "application/json", upstreamHeader.Get("Accept"), name)
+		// the request of the caller is left as it was
+		assert.Equal(t, "from-client", req.Header.Get("X-Dev-Key"), name)
+	}
+
+	// requests without the factory don't carry the header
+	plain := NewDefaultFactoryResolver(passthroughTransportFactory{}, http.DefaultTransport, false, zap.NewNop(), nil)
+	resp, err := plain.graphql.HTTPClient.Get(upstream.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, (<-received).Get("X-Dev-Key"))
+}
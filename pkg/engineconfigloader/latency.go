@@ -0,0 +1,50 @@
+package engineconfigloader
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LatencyInjection adds an artificial delay of Delay ± Jitter to upstream requests of a data source
+type LatencyInjection struct {
+	Delay  time.Duration
+	Jitter time.Duration
+}
+
+func (l LatencyInjection) duration() time.Duration {
+	delay := l.Delay
+	if l.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(2*l.Jitter)+1)) - l.Jitter
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+type latencyRoundTripper struct {
+	roundTripper http.RoundTripper
+	dataSourceID string
+	latency      LatencyInjection
+	log          *zap.Logger
+}
+
+func (t *latencyRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	delay := t.latency.duration()
+	t.log.Debug("injecting latency",
+		zap.String("dataSourceId", t.dataSourceID),
+		zap.String("url", request.URL.String()),
+		zap.Duration("delay", delay),
+	)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	case <-timer.C:
+	}
+	return t.roundTripper.RoundTrip(request)
+}
@@ -0,0 +1,58 @@
+package engineconfigloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLatencyInjectionDuration(t *testing.T) {
+	assert.Equal(t, 100*time.Millisecond, LatencyInjection{Delay: 100 * time.Millisecond}.duration())
+	jittered := LatencyInjection{Delay: 100 * time.Millisecond, Jitter: 20 * time.Millisecond}
+	for i := 0; i < 1000; i++ {
+		delay := jittered.duration()
+		assert.GreaterOrEqual(t, delay, 80*time.Millisecond)
+		assert.LessOrEqual(t, delay, 120*time.Millisecond)
+	}
+	// jitter larger than the delay never results in a negative delay
+	clamped := LatencyInjection{Delay: 10 * time.Millisecond, Jitter: 50 * time.Millisecond}
+	for i := 0; i < 1000; i++ {
+		assert.GreaterOrEqual(t, clamped.duration(), time.Duration(0))
+	}
+	assert.Equal(t, time.Duration(0), LatencyInjection{}.duration())
+}
+
+func TestLatencyRoundTripper(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+	newClient := func(delay time.Duration) *http.Client {
+		return &http.Client{Transport: &latencyRoundTripper{
+			roundTripper: http.DefaultTransport,
+			dataSourceID: "billing",
+			latency:      LatencyInjection{Delay: delay},
+			log:          zap.NewNop(),
+		}}
+	}
+
+	start := time.Now()
+	resp, err := newClient(50 * time.Millisecond).Get(upstream.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// canceled requests return without waiting for the delay
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	require.NoError(t, err)
+	start = time.Now()
+	_, err = newClient(time.Minute).Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
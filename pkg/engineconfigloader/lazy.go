@@ -0,0 +1,37 @@
+package engineconfigloader
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// lazyRoundTripper builds the transport of a data source on its first request. If that
+// fails, every request of the data source fails with the error instead of the config load.
+type lazyRoundTripper struct {
+	dataSourceID string
+	build        func() (http.RoundTripper, error)
+	log          *zap.Logger
+
+	once         sync.Once
+	roundTripper http.RoundTripper
+	err          error
+}
+
+func (t *lazyRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		t.roundTripper, t.err = t.build()
+		if t.err != nil {
+			t.log.Error("could not connect to data source",
+				zap.String("dataSourceId", t.dataSourceID),
+				zap.Error(t.err),
+			)
+		}
+	})
+	if t.err != nil {
+		return nil, fmt.Errorf("data source %s is unavailable: %w", t.dataSourceID, t.err)
+	}
+	return t.roundTripper.RoundTrip(request)
+}
@@ -0,0 +1,50 @@
+package engineconfigloader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+type passthroughTransportFactory struct{}
+
+func (passthroughTransportFactory) RoundTripper(tripper http.RoundTripper, enableStreamingMode bool) http.RoundTripper {
+	return tripper
+}
+
+func (passthroughTransportFactory) DefaultTransportTimeout() time.Duration {
+	return 0
+}
+
+func TestConnectLazily(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	invalidMTLS := &wgpb.FetchConfiguration{MTLS: &wgpb.MTLSConfiguration{}}
+	ds := &wgpb.DataSourceConfiguration{
+		Id:         "billing",
+		Kind:       wgpb.DataSourceKind_REST,
+		CustomRest: &wgpb.DataSourceCustom_REST{Fetch: invalidMTLS},
+	}
+
+	resolver := NewDefaultFactoryResolver(passthroughTransportFactory{}, http.DefaultTransport, false, zap.NewNop(), nil)
+	_, err := resolver.Resolve(ds)
+	assert.Error(t, err)
+
+	resolver.ConnectLazily()
+	factory, err := resolver.Resolve(ds)
+	require.NoError(t, err)
+	assert.NotNil(t, factory)
+
+	client, err := resolver.newHTTPClient(ds, invalidMTLS)
+	require.NoError(t, err)
+	_, err = client.Get(upstream.URL)
+	assert.ErrorContains(t, err, "data source billing is unavailable: invalid key/cert in mTLS configuration")
+}
@@ -0,0 +1,65 @@
+package engineconfigloader
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyFunc is the proxy selection of an http.Transport
+type ProxyFunc func(*http.Request) (*url.URL, error)
+
+// NewProxyFunc returns a ProxyFunc sending http and https requests through proxyURL, hosts
+// excluded by NO_PROXY are connected directly like with http.ProxyFromEnvironment
+func NewProxyFunc(proxyURL string) (ProxyFunc, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url %q: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy url %q, expected an http(s):// or socks5:// url", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url %q, missing host", proxyURL)
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    noProxy,
+	}).ProxyFunc()
+	return func(r *http.Request) (*url.URL, error) {
+		return proxy(r.URL)
+	}, nil
+}
+
+// UseProxy sends all upstream requests of the data source with the given id through
+// proxy, it must be called before the engine config is loaded
+func (d *DefaultFactoryResolver) UseProxy(dataSourceID string, proxy ProxyFunc) {
+	if d.proxies == nil {
+		d.proxies = map[string]ProxyFunc{}
+	}
+	d.proxies[dataSourceID] = proxy
+}
+
+// withProxy returns a copy of transport using proxy, transports other than *http.Transport
+// can't be configured and are returned unchanged
+func (d *DefaultFactoryResolver) withProxy(dataSourceID string, transport http.RoundTripper, proxy ProxyFunc) http.RoundTripper {
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		d.log.Warn("can't apply proxy to the transport of data source, ignoring", zap.String("dataSourceId", dataSourceID))
+		return transport
+	}
+	t = t.Clone()
+	t.Proxy = proxy
+	return t
+}
@@ -0,0 +1,58 @@
+package engineconfigloader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestNewProxyFunc(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.example.com")
+
+	proxy, err := NewProxyFunc("http://proxy.example.com:3128")
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "https://api.example.com/graphql", nil)
+	u, err := proxy(r)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", u.String())
+
+	r = httptest.NewRequest(http.MethodGet, "https://internal.example.com/graphql", nil)
+	u, err = proxy(r)
+	require.NoError(t, err)
+	assert.Nil(t, u, "hosts in NO_PROXY are connected directly")
+
+	_, err = NewProxyFunc("proxy.example.com:3128")
+	assert.Error(t, err)
+	_, err = NewProxyFunc("http://")
+	assert.Error(t, err)
+}
+
+func TestUseProxy(t *testing.T) {
+	// the proxy answers itself, requests reaching the upstream fail the test
+	var proxied string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxyServer.Close()
+
+	ds := &wgpb.DataSourceConfiguration{Id: "billing", Kind: wgpb.DataSourceKind_REST}
+	resolver := NewDefaultFactoryResolver(passthroughTransportFactory{}, &http.Transport{}, false, zap.NewNop(), nil)
+	proxy, err := NewProxyFunc(proxyServer.URL)
+	require.NoError(t, err)
+	resolver.UseProxy("billing", proxy)
+	require.True(t, resolver.requiresCustomHTTPClient(ds, nil))
+
+	client, err := resolver.newHTTPClient(ds, nil)
+	require.NoError(t, err)
+	resp, err := client.Get("http://billing.invalid/invoices")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "http://billing.invalid/invoices", proxied)
+}
@@ -0,0 +1,34 @@
+package engineconfigloader
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/wundergraph/wundergraph/pkg/otlp"
+)
+
+// NewTracingTransportFactory wraps the transports created by factory to record a span for
+// every upstream request of a traced request and to pass the trace on to the upstream.
+// Streaming requests, e.g. of subscriptions, are not traced, they last as long as the stream.
+func NewTracingTransportFactory(factory ApiTransportFactory) ApiTransportFactory {
+	return &tracingTransportFactory{factory: factory}
+}
+
+type tracingTransportFactory struct {
+	factory ApiTransportFactory
+}
+
+func (f *tracingTransportFactory) RoundTripper(tripper http.RoundTripper, enableStreamingMode bool) http.RoundTripper {
+	if enableStreamingMode {
+		return f.factory.RoundTripper(tripper, enableStreamingMode)
+	}
+	return f.factory.RoundTripper(otlp.NewTransport(tripper, upstreamSpanName), enableStreamingMode)
+}
+
+func (f *tracingTransportFactory) DefaultTransportTimeout() time.Duration {
+	return f.factory.DefaultTransportTimeout()
+}
+
+func upstreamSpanName(r *http.Request) string {
+	return "upstream " + r.Method + " " + r.URL.Host
+}
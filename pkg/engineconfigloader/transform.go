@@ -0,0 +1,168 @@
+package engineconfigloader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"go.uber.org/zap"
+)
+
+// ResponseTransform rewrites JSON responses of a data source with a jq-style expression.
+// A subset of jq is supported:
+//
+//	.data.price |= . * 1.1     updates a number with one of + - * /
+//	.data.status = "archived"  assigns a JSON value
+//	del(.data.items[0])        deletes a value
+type ResponseTransform struct {
+	Expression string
+	path       string
+	apply      func(data []byte, path string) ([]byte, error)
+}
+
+var (
+	transformDeletion   = regexp.MustCompile(`^del\((.+)\)$`)
+	transformUpdate     = regexp.MustCompile(`^(\S+)\s*\|=\s*\.\s*([-+*/])\s*(\S+)$`)
+	transformAssignment = regexp.MustCompile(`^(\S+)\s*=\s*(.+)$`)
+	transformPathToken  = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)|\[(\d+)\]`)
+)
+
+// ParseResponseTransform parses a jq-style expression, see ResponseTransform
+func ParseResponseTransform(expression string) (ResponseTransform, error) {
+	expression = strings.TrimSpace(expression)
+	t := ResponseTransform{Expression: expression}
+	var (
+		path string
+		err  error
+	)
+	if match := transformDeletion.FindStringSubmatch(expression); match != nil {
+		path, err = transformPath(match[1])
+		t.apply = func(data []byte, path string) ([]byte, error) {
+			return sjson.DeleteBytes(data, path)
+		}
+	} else if match := transformUpdate.FindStringSubmatch(expression); match != nil {
+		path, err = transformPath(match[1])
+		op := match[2]
+		operand, parseErr := strconv.ParseFloat(match[3], 64)
+		if parseErr != nil {
+			return t, fmt.Errorf("invalid transform %q: %s is not a number", expression, match[3])
+		}
+		if op == "/" && operand == 0 {
+			return t, fmt.Errorf("invalid transform %q: division by zero", expression)
+		}
+		t.apply = func(data []byte, path string) ([]byte, error) {
+			value := gjson.GetBytes(data, path)
+			if value.Type != gjson.Number {
+				return nil, fmt.Errorf("%s is not a number", path)
+			}
+			return sjson.SetBytes(data, path, calculate(value.Num, op, operand))
+		}
+	} else if match := transformAssignment.FindStringSubmatch(expression); match != nil {
+		path, err = transformPath(match[1])
+		value := []byte(strings.TrimSpace(match[2]))
+		if !gjson.ValidBytes(value) {
+			return t, fmt.Errorf("invalid transform %q: %s is not a JSON value", expression, value)
+		}
+		t.apply = func(data []byte, path string) ([]byte, error) {
+			return sjson.SetRawBytes(data, path, value)
+		}
+	} else {
+		return t, fmt.Errorf("invalid transform %q, expected PATH |= . OP NUMBER, PATH = JSON or del(PATH)", expression)
+	}
+	if err != nil {
+		return t, fmt.Errorf("invalid transform %q: %w", expression, err)
+	}
+	t.path = path
+	return t, nil
+}
+
+// transformPath converts a jq path like .data.items[0] to a gjson path
+func transformPath(jqPath string) (string, error) {
+	tokens := transformPathToken.FindAllStringSubmatchIndex(jqPath, -1)
+	end := 0
+	segments := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token[0] != end {
+			break
+		}
+		end = token[1]
+		if token[2] != -1 {
+			segments = append(segments, jqPath[token[2]:token[3]])
+		} else {
+			segments = append(segments, jqPath[token[4]:token[5]])
+		}
+	}
+	if len(segments) == 0 || end != len(jqPath) {
+		return "", fmt.Errorf("invalid path %s, expected fields and indexes like .data.items[0]", jqPath)
+	}
+	return strings.Join(segments, "."), nil
+}
+
+func calculate(value float64, op string, operand float64) float64 {
+	switch op {
+	case "+":
+		return value + operand
+	case "-":
+		return value - operand
+	case "*":
+		return value * operand
+	default:
+		return value / operand
+	}
+}
+
+// Apply returns the transformed data, it fails if data isn't JSON or the path doesn't fit
+func (t ResponseTransform) Apply(data []byte) ([]byte, error) {
+	if !gjson.ValidBytes(data) {
+		return nil, errors.New("response is not JSON")
+	}
+	return t.apply(data, t.path)
+}
+
+type transformRoundTripper struct {
+	roundTripper http.RoundTripper
+	dataSourceID string
+	transforms   []ResponseTransform
+	log          *zap.Logger
+}
+
+// RoundTrip applies the transforms to the response body, on errors the response is passed through unchanged
+func (t *transformRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.roundTripper.RoundTrip(request)
+	if err != nil || response.Body == nil {
+		return response, err
+	}
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, transform := range t.transforms {
+		transformed, err := transform.Apply(body)
+		if err != nil {
+			t.log.Warn("could not transform response, passing it through",
+				zap.String("dataSourceId", t.dataSourceID),
+				zap.String("transform", transform.Expression),
+				zap.Error(err),
+			)
+			continue
+		}
+		t.log.Info("transforming response",
+			zap.String("dataSourceId", t.dataSourceID),
+			zap.String("url", request.URL.String()),
+			zap.String("transform", transform.Expression),
+		)
+		body = transformed
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	response.ContentLength = int64(len(body))
+	response.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return response, nil
+}
@@ -0,0 +1,70 @@
+package engineconfigloader
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResponseTransform(t *testing.T) {
+	data := []byte(`{"data":{"price":10,"status":"active","items":[{"id":1},{"id":2}]}}`)
+	for _, tc := range []struct {
+		expression string
+		expected   string
+	}{
+		{`.data.price |= .*1.5`, `{"data":{"price":15,"status":"active","items":[{"id":1},{"id":2}]}}`},
+		{`.data.price |= . - 2`, `{"data":{"price":8,"status":"active","items":[{"id":1},{"id":2}]}}`},
+		{`.data.status = "archived"`, `{"data":{"price":10,"status":"archived","items":[{"id":1},{"id":2}]}}`},
+		{`.data.items[1].id = null`, `{"data":{"price":10,"status":"active","items":[{"id":1},{"id":null}]}}`},
+		{`del(.data.items[0])`, `{"data":{"price":10,"status":"active","items":[{"id":2}]}}`},
+	} {
+		transform, err := ParseResponseTransform(tc.expression)
+		require.NoError(t, err, tc.expression)
+		transformed, err := transform.Apply(data)
+		require.NoError(t, err, tc.expression)
+		assert.JSONEq(t, tc.expected, string(transformed), tc.expression)
+	}
+
+	transform, err := ParseResponseTransform(`.data.status |= . + 1`)
+	require.NoError(t, err)
+	_, err = transform.Apply(data)
+	assert.Error(t, err, "not a number")
+	_, err = transform.Apply([]byte(`<html>`))
+	assert.Error(t, err, "not JSON")
+
+	for _, expression := range []string{`.data.price |= . * x`, `.data.price |= . / 0`, `.data.status = active`, `del(data)`, `.data..price = 1`, `map(.id)`} {
+		_, err := ParseResponseTransform(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestTransformRoundTripper(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"data":{"price":10}}`)
+	}))
+	defer upstream.Close()
+
+	double, err := ParseResponseTransform(`.data.price |= . * 2`)
+	require.NoError(t, err)
+	broken, err := ParseResponseTransform(`.data.missing |= . * 2`)
+	require.NoError(t, err)
+	client := &http.Client{Transport: &transformRoundTripper{
+		roundTripper: http.DefaultTransport,
+		dataSourceID: "billing",
+		transforms:   []ResponseTransform{broken, double},
+		log:          zap.NewNop(),
+	}}
+	resp, err := client.Get(upstream.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":{"price":20}}`, strings.TrimSpace(string(body)))
+	assert.Equal(t, int64(len(body)), resp.ContentLength)
+}
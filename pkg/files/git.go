@@ -0,0 +1,61 @@
+package files
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindGitHead returns the path of the HEAD file of the git repository containing dir,
+// it returns false if dir is not part of a repository. Worktrees and submodules, where
+// .git is a file pointing to the actual git directory, are supported.
+func FindGitHead(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return filepath.Join(gitPath, "HEAD"), true
+			}
+			if gitDir, ok := readGitDirFile(gitPath); ok {
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(dir, gitDir)
+				}
+				return filepath.Join(gitDir, "HEAD"), true
+			}
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func readGitDirFile(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	content := string(bytes.TrimSpace(data))
+	if !strings.HasPrefix(content, "gitdir: ") {
+		return "", false
+	}
+	gitDir := strings.TrimPrefix(content, "gitdir: ")
+	return gitDir, gitDir != ""
+}
+
+// ReadGitRef returns the content of a HEAD file, either the ref of the checked out
+// branch or the commit of a detached HEAD
+func ReadGitRef(headPath string) (string, error) {
+	data, err := os.ReadFile(headPath)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(data)), nil
+}
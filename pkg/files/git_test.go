@@ -0,0 +1,36 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindGitHead(t *testing.T) {
+	t.Run("repository", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+		nested := filepath.Join(dir, "app", ".wundergraph")
+		require.NoError(t, os.MkdirAll(nested, os.ModePerm))
+
+		head, ok := FindGitHead(nested)
+		require.True(t, ok)
+		assert.Equal(t, filepath.Join(dir, ".git", "HEAD"), head)
+		ref, err := ReadGitRef(head)
+		require.NoError(t, err)
+		assert.Equal(t, "ref: refs/heads/main", ref)
+	})
+
+	t.Run("worktree", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: ../repo/.git/worktrees/feature\n"), 0644))
+
+		head, ok := FindGitHead(dir)
+		require.True(t, ok)
+		assert.Equal(t, filepath.Join(filepath.Dir(dir), "repo", ".git", "worktrees", "feature", "HEAD"), head)
+	})
+}
@@ -0,0 +1,67 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleType is the module system node uses to load a JavaScript file
+type ModuleType string
+
+const (
+	ModuleTypeModule   ModuleType = "module"
+	ModuleTypeCommonJS ModuleType = "commonjs"
+)
+
+// DetectModuleType returns the module type of the .js files in dir, which is set by the
+// type field of the nearest package.json in dir or its parents. Like in node, it's
+// ModuleTypeCommonJS if there is no package.json or it has no type.
+func DetectModuleType(dir string) (ModuleType, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		packageJSONPath := filepath.Join(dir, "package.json")
+		data, err := os.ReadFile(packageJSONPath)
+		if err == nil {
+			var packageJSON struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(data, &packageJSON); err != nil {
+				return "", fmt.Errorf("could not decode %s: %w", packageJSONPath, err)
+			}
+			switch ModuleType(packageJSON.Type) {
+			case ModuleTypeModule:
+				return ModuleTypeModule, nil
+			case ModuleTypeCommonJS, "":
+				return ModuleTypeCommonJS, nil
+			default:
+				return "", fmt.Errorf("invalid type %q in %s, expected module or commonjs", packageJSON.Type, packageJSONPath)
+			}
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ModuleTypeCommonJS, nil
+		}
+		dir = parent
+	}
+}
+
+// DetectFileModuleType returns the module type of the file at path, the .mjs and .cjs
+// extensions (.mts and .cts for TypeScript) take precedence over the package.json
+func DetectFileModuleType(path string) (ModuleType, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mjs", ".mts":
+		return ModuleTypeModule, nil
+	case ".cjs", ".cts":
+		return ModuleTypeCommonJS, nil
+	}
+	return DetectModuleType(filepath.Dir(path))
+}
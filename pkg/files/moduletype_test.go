@@ -0,0 +1,45 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectModuleType(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "app", ".wundergraph")
+	require.NoError(t, os.MkdirAll(nested, os.ModePerm))
+
+	moduleType, err := DetectModuleType(nested)
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeCommonJS, moduleType)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"type":"module"}`), 0644))
+	moduleType, err = DetectModuleType(nested)
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeModule, moduleType)
+
+	// the nearest package.json wins, even without a type
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "package.json"), []byte(`{"name":"app"}`), 0644))
+	moduleType, err = DetectModuleType(nested)
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeCommonJS, moduleType)
+
+	moduleType, err = DetectFileModuleType(filepath.Join(nested, "wundergraph.server.mts"))
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeModule, moduleType)
+	moduleType, err = DetectFileModuleType(filepath.Join(dir, "hooks.cjs"))
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeCommonJS, moduleType)
+	moduleType, err = DetectFileModuleType(filepath.Join(dir, "hooks.js"))
+	require.NoError(t, err)
+	assert.Equal(t, ModuleTypeModule, moduleType)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "package.json"), []byte(`{"type":"esm"}`), 0644))
+	_, err = DetectModuleType(nested)
+	assert.Error(t, err)
+}
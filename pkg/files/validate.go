@@ -0,0 +1,166 @@
+package files
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wundergraph/wundergraph/pkg/operations"
+	"github.com/wundergraph/wundergraph/pkg/webhooks"
+)
+
+const (
+	WunderGraphServerFilename = "wundergraph.server.ts"
+	FragmentsDirectoryName    = "fragments"
+
+	// maxSearchDepth limits how deep misplaced files are searched for below the WunderGraph directory
+	maxSearchDepth = 3
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is a problem with the layout of a WunderGraph directory
+type Finding struct {
+	Severity Severity
+	Message  string
+	// Suggestion describes how to fix the problem
+	Suggestion string
+}
+
+func (f Finding) String() string {
+	if f.Suggestion == "" {
+		return f.Message
+	}
+	return f.Message + ", " + f.Suggestion
+}
+
+// HasErrors returns true if any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWunderGraphDir checks dir for the expected layout of a WunderGraph directory:
+// the wundergraph.config.ts entrypoint, the optional wundergraph.server.ts entrypoint and the
+// optional operations, webhooks and fragments directories. Misplaced files and directories are
+// reported with the location they were expected at.
+func ValidateWunderGraphDir(dir string) []Finding {
+	var findings []Finding
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return []Finding{{Severity: SeverityError, Message: fmt.Sprintf("unable to get absolute path of %s: %s", dir, err)}}
+	}
+	if !DirectoryExists(absDir) {
+		return []Finding{{
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("WunderGraph directory %s does not exist", absDir),
+			Suggestion: "set --wundergraph-dir to the directory containing " + WunderGraphConfigFilename,
+		}}
+	}
+
+	configPath := filepath.Join(absDir, WunderGraphConfigFilename)
+	if !FileExists(configPath) {
+		finding := Finding{
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("%s not found in %s", WunderGraphConfigFilename, absDir),
+			Suggestion: "create it or set --wundergraph-dir to the directory containing it",
+		}
+		if found := findMisplaced(absDir, WunderGraphConfigFilename, false); found != "" {
+			finding.Message = fmt.Sprintf("found %s at %s but expected at %s", WunderGraphConfigFilename, found, configPath)
+			finding.Suggestion = fmt.Sprintf("set --wundergraph-dir to %s", filepath.Dir(found))
+		}
+		// the remaining checks are relative to a WunderGraph directory we couldn't find
+		return append(findings, finding)
+	}
+
+	serverPath := filepath.Join(absDir, WunderGraphServerFilename)
+	hasServer := FileExists(serverPath)
+	if !hasServer {
+		if found := findMisplaced(absDir, WunderGraphServerFilename, false); found != "" {
+			findings = append(findings, Finding{
+				Severity:   SeverityWarning,
+				Message:    fmt.Sprintf("found %s at %s but expected at %s", WunderGraphServerFilename, found, serverPath),
+				Suggestion: fmt.Sprintf("move it next to %s", WunderGraphConfigFilename),
+			})
+		}
+	}
+
+	for _, name := range []string{operations.DirectoryName, webhooks.WebhookDirectoryName, FragmentsDirectoryName} {
+		expected := filepath.Join(absDir, name)
+		if DirectoryExists(expected) {
+			if nested := filepath.Join(expected, name); DirectoryExists(nested) {
+				findings = append(findings, Finding{
+					Severity:   SeverityWarning,
+					Message:    fmt.Sprintf("found nested %s directory at %s", name, nested),
+					Suggestion: fmt.Sprintf("move its contents to %s", expected),
+				})
+			}
+			continue
+		}
+		if found := findMisplaced(absDir, name, true); found != "" {
+			findings = append(findings, Finding{
+				Severity:   SeverityWarning,
+				Message:    fmt.Sprintf("found %s at %s but expected at %s", name, found, expected),
+				Suggestion: fmt.Sprintf("move %s to %s", found, expected),
+			})
+		}
+	}
+
+	if DirectoryExists(filepath.Join(absDir, webhooks.WebhookDirectoryName)) && !hasServer {
+		findings = append(findings, Finding{
+			Severity:   SeverityWarning,
+			Message:    fmt.Sprintf("webhooks found but %s is missing, webhooks are served by the hooks server", WunderGraphServerFilename),
+			Suggestion: fmt.Sprintf("create %s", serverPath),
+		})
+	}
+
+	return findings
+}
+
+// findMisplaced looks for name in the parent of dir and below dir, skipping node_modules
+// and the directory where name is expected. It returns the first match or an empty string.
+func findMisplaced(dir, name string, isDir bool) string {
+	matches := func(path string) bool {
+		if isDir {
+			return DirectoryExists(path)
+		}
+		info, err := os.Stat(path)
+		return err == nil && !info.IsDir()
+	}
+
+	if parent := filepath.Join(filepath.Dir(dir), name); matches(parent) {
+		return parent
+	}
+
+	expected := filepath.Join(dir, name)
+	found := ""
+	_ = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			// unreadable directories are skipped
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		depth := len(strings.Split(rel, string(filepath.Separator)))
+		if entry.IsDir() && (entry.Name() == "node_modules" || entry.Name() == "generated" || (path != dir && depth > maxSearchDepth)) {
+			return filepath.SkipDir
+		}
+		if path != expected && entry.Name() == name && entry.IsDir() == isDir {
+			found = path
+			return io.EOF
+		}
+		return nil
+	})
+	return found
+}
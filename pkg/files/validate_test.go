@@ -0,0 +1,62 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWunderGraphDir(t *testing.T) {
+	mkdir := func(t *testing.T, path string) {
+		require.NoError(t, os.MkdirAll(path, os.ModePerm))
+	}
+	touch := func(t *testing.T, path string) {
+		mkdir(t, filepath.Dir(path))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, filepath.Join(dir, WunderGraphConfigFilename))
+		touch(t, filepath.Join(dir, WunderGraphServerFilename))
+		mkdir(t, filepath.Join(dir, "operations"))
+		mkdir(t, filepath.Join(dir, "webhooks"))
+		assert.Empty(t, ValidateWunderGraphDir(dir))
+	})
+
+	t.Run("misplaced config", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, filepath.Join(dir, "src", WunderGraphConfigFilename))
+		findings := ValidateWunderGraphDir(dir)
+		require.Len(t, findings, 1)
+		assert.True(t, HasErrors(findings))
+		assert.Contains(t, findings[0].Suggestion, filepath.Join(dir, "src"))
+	})
+
+	t.Run("misplaced directories", func(t *testing.T) {
+		root := t.TempDir()
+		dir := filepath.Join(root, ".wundergraph")
+		touch(t, filepath.Join(dir, WunderGraphConfigFilename))
+		mkdir(t, filepath.Join(root, "operations"))
+		mkdir(t, filepath.Join(dir, "src", "fragments"))
+		mkdir(t, filepath.Join(dir, "node_modules", "webhooks"))
+		findings := ValidateWunderGraphDir(dir)
+		require.Len(t, findings, 2)
+		assert.False(t, HasErrors(findings))
+		assert.Equal(t, "found operations at "+filepath.Join(root, "operations")+" but expected at "+filepath.Join(dir, "operations"), findings[0].Message)
+		assert.Contains(t, findings[1].Message, filepath.Join(dir, "src", "fragments"))
+	})
+
+	t.Run("webhooks without server", func(t *testing.T) {
+		dir := t.TempDir()
+		touch(t, filepath.Join(dir, WunderGraphConfigFilename))
+		mkdir(t, filepath.Join(dir, "webhooks", "webhooks"))
+		findings := ValidateWunderGraphDir(dir)
+		require.Len(t, findings, 2)
+		assert.Contains(t, findings[0].Message, "nested webhooks")
+		assert.Contains(t, findings[1].Message, WunderGraphServerFilename)
+	})
+}
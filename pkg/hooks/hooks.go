@@ -21,6 +21,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/otlp"
 	"github.com/wundergraph/wundergraph/pkg/pool"
 )
 
@@ -178,6 +179,10 @@ func buildClient(requestTimeout time.Duration) *retryablehttp.Client {
 	httpClient.RetryWaitMax = 50 * time.Millisecond
 	httpClient.RetryWaitMin = 50 * time.Millisecond
 	httpClient.HTTPClient.Timeout = requestTimeout
+	// hook calls of traced requests are recorded as spans, every retry as its own
+	httpClient.HTTPClient.Transport = otlp.NewTransport(httpClient.HTTPClient.Transport, func(r *http.Request) string {
+		return "hook " + r.URL.Path
+	})
 	httpClient.Logger = log.New(ioutil.Discard, "", log.LstdFlags)
 	httpClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
 		if resp != nil && resp.StatusCode == http.StatusInternalServerError {
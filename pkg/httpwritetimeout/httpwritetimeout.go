@@ -0,0 +1,61 @@
+// Package httpwritetimeout implements an HTTP middleware limiting the time to write
+// a response, like http.Server.WriteTimeout. Unlike the server option, handlers of
+// long-lived responses, e.g. subscriptions, can exempt their request via Disable.
+package httpwritetimeout
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type contextKey int
+
+const (
+	connKey contextKey = iota
+)
+
+// ConnContext stores the connection in ctx, it must be used as or called from
+// http.Server.ConnContext for the Middleware to work
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connKey, c)
+}
+
+// Middleware sets the write deadline of the connection when a request starts.
+// Only HTTP/1 requests are limited, HTTP/2 multiplexes requests on a single connection.
+type Middleware struct {
+	timeout time.Duration
+}
+
+// New returns a Middleware limiting writing the response to timeout, counting from
+// the start of the request
+func New(timeout time.Duration) *Middleware {
+	return &Middleware{
+		timeout: timeout,
+	}
+}
+
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, ok := r.Context().Value(connKey).(net.Conn); ok && r.ProtoMajor == 1 {
+			if websocket.IsWebSocketUpgrade(r) {
+				_ = conn.SetWriteDeadline(time.Time{})
+			} else {
+				_ = conn.SetWriteDeadline(time.Now().Add(m.timeout))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Disable removes the write deadline of the request, use it for responses that are
+// kept open to push updates. The read deadline set by http.Server.ReadTimeout is removed
+// as well, once it expires the server cancels the context of the request.
+func Disable(r *http.Request) {
+	if conn, ok := r.Context().Value(connKey).(net.Conn); ok && r.ProtoMajor == 1 {
+		_ = conn.SetDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,48 @@
+package httpwritetimeout
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * timeout)
+		_, _ = w.Write([]byte(strings.Repeat("a", 1<<20)))
+	})
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		Disable(r)
+		time.Sleep(2 * timeout)
+		_, _ = w.Write([]byte("done"))
+	})
+	srv := httptest.NewUnstartedServer(New(timeout).Handler(mux))
+	srv.Config.ConnContext = ConnContext
+	srv.Start()
+	defer srv.Close()
+
+	get := func(path string) (string, error) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	_, err := get("/slow")
+	assert.Error(t, err)
+
+	body, err := get("/stream")
+	require.NoError(t, err)
+	assert.Equal(t, "done", body)
+}
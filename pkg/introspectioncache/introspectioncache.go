@@ -0,0 +1,221 @@
+// Package introspectioncache reads and writes the introspection cache of the config runner.
+// Every entry holds the introspected schema and data sources of one upstream, keyed by the
+// hash of its introspection config.
+package introspectioncache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot file format
+const SnapshotVersion = 1
+
+const entryExt = ".json"
+
+// PinEnvKey holds the comma separated ids or api namespaces of the sources the config runner
+// always takes from the cache, they are only introspected if they have no entry yet
+const PinEnvKey = "WG_PINNED_INTROSPECTIONS"
+
+// Dir returns the directory of the introspection cache
+func Dir(wunderGraphDir string) string {
+	return filepath.Join(wunderGraphDir, "cache", "introspection")
+}
+
+// Snapshot holds all entries of an introspection cache in a single, portable file
+type Snapshot struct {
+	Version   int                        `json:"version"`
+	CreatedAt time.Time                  `json:"createdAt"`
+	Entries   map[string]json.RawMessage `json:"entries"`
+}
+
+// Keys returns the sorted keys of the entries
+func (s *Snapshot) Keys() []string {
+	keys := make([]string, 0, len(s.Entries))
+	for key := range s.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadSnapshot reads all entries of the cache at dir, a missing directory is an error
+func ReadSnapshot(dir string) (*Snapshot, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("introspection cache %s doesn't exist, run 'wunderctl up' or 'wunderctl generate' first", dir)
+		}
+		return nil, err
+	}
+	snapshot := &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Entries:   map[string]json.RawMessage{},
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != entryExt {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := validateEntry(data); err != nil {
+			return nil, fmt.Errorf("introspection cache entry %s is corrupted, run 'wunderctl cache verify --fix': %w", file.Name(), err)
+		}
+		snapshot.Entries[strings.TrimSuffix(file.Name(), entryExt)] = data
+	}
+	return snapshot, nil
+}
+
+// entryVersion is the version of the entries written by the config runner
+const entryVersion = "1.0.0"
+
+// validateEntry checks that data is an entry as written by the config runner, the same
+// checks make the config runner drop an entry and introspect its source again
+func validateEntry(data []byte) error {
+	if !json.Valid(data) {
+		return errors.New("not valid JSON, the file might be truncated")
+	}
+	var entry struct {
+		Version     string            `json:"version"`
+		Schema      *string           `json:"schema"`
+		DataSources []json.RawMessage `json:"dataSources"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	if entry.Version != entryVersion {
+		return fmt.Errorf("unsupported version %q, expected %q", entry.Version, entryVersion)
+	}
+	if entry.Schema == nil {
+		return errors.New("missing schema")
+	}
+	if entry.DataSources == nil {
+		return errors.New("missing dataSources")
+	}
+	return nil
+}
+
+// NoImpact returns true if the changed paths of the cache at dir don't require a new config: at
+// least one entry changed and every changed entry was written by the introspection poller for
+// schema changes no operation uses. Other files of the cache, like the temporary files of
+// atomic writes, are ignored.
+func NoImpact(dir string, paths []string) bool {
+	entries := 0
+	for _, path := range paths {
+		if filepath.Dir(path) != dir {
+			return false
+		}
+		if filepath.Ext(path) != entryExt {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		var entry struct {
+			NoImpact bool `json:"noImpact"`
+		}
+		if err := json.Unmarshal(data, &entry); err != nil || !entry.NoImpact {
+			return false
+		}
+		entries++
+	}
+	return entries > 0
+}
+
+// CorruptedEntry is an entry of the cache the config runner can't use
+type CorruptedEntry struct {
+	Key string
+	Err error
+}
+
+// Verify checks all entries of the cache at dir and returns the corrupted ones, sorted by key.
+// A missing directory is an empty cache.
+func Verify(dir string) (entries int, corrupted []CorruptedEntry, err error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != entryExt {
+			continue
+		}
+		entries++
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return entries, corrupted, err
+		}
+		if err := validateEntry(data); err != nil {
+			corrupted = append(corrupted, CorruptedEntry{Key: strings.TrimSuffix(file.Name(), entryExt), Err: err})
+		}
+	}
+	return entries, corrupted, nil
+}
+
+// Remove deletes the entry with the given key from the cache at dir, the config runner
+// introspects its source again on the next build
+func Remove(dir, key string) error {
+	err := os.Remove(filepath.Join(dir, key+entryExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// WriteFile writes the snapshot to path, replacing it. It isn't indented, entries are
+// written by the config runner without whitespace and must be restored as they were.
+func (s *Snapshot) WriteFile(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSnapshot reads the snapshot file at path
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d of %s, expected %d", snapshot.Version, path, SnapshotVersion)
+	}
+	return &snapshot, nil
+}
+
+// Restore writes all entries of the snapshot to the cache at dir. Existing entries with the
+// same keys are replaced, other entries are kept.
+func (s *Snapshot) Restore(dir string) error {
+	for key := range s.Entries {
+		// keys are object hashes, anything else could escape the cache directory
+		if key == "" || strings.ContainsAny(key, `/\.`) {
+			return fmt.Errorf("invalid snapshot entry key %q", key)
+		}
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for key, entry := range s.Entries {
+		if err := os.WriteFile(filepath.Join(dir, key+entryExt), entry, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
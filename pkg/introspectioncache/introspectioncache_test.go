@@ -0,0 +1,104 @@
+package introspectioncache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ReadSnapshot(Dir(dir))
+	assert.Error(t, err)
+
+	cacheDir := Dir(dir)
+	require.NoError(t, os.MkdirAll(cacheDir, os.ModePerm))
+	entries := map[string]string{
+		"3f2a": `{"version":"1.0.0","schema":"type Query { a: String }","dataSources":[]}`,
+		"9c8b": `{"version":"1.0.0","schema":"type Query { b: String }","dataSources":[]}`,
+	}
+	for key, entry := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, key+".json"), []byte(entry), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "README"), []byte("ignored"), 0644))
+
+	snapshot, err := ReadSnapshot(cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3f2a", "9c8b"}, snapshot.Keys())
+
+	snapshotPath := filepath.Join(dir, "snapshot.json")
+	require.NoError(t, snapshot.WriteFile(snapshotPath))
+	loaded, err := LoadSnapshot(snapshotPath)
+	require.NoError(t, err)
+
+	restoredDir := filepath.Join(t.TempDir(), "cache", "introspection")
+	require.NoError(t, loaded.Restore(restoredDir))
+	for key, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(restoredDir, key+".json"))
+		require.NoError(t, err)
+		assert.Equal(t, entry, string(data))
+	}
+
+	loaded.Entries["../escape"] = []byte(`{}`)
+	assert.Error(t, loaded.Restore(restoredDir))
+}
+
+func TestVerify(t *testing.T) {
+	cacheDir := Dir(t.TempDir())
+	entries, corrupted, err := Verify(cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, entries)
+	assert.Empty(t, corrupted)
+
+	require.NoError(t, os.MkdirAll(cacheDir, os.ModePerm))
+	for key, entry := range map[string]string{
+		"3f2a": `{"version":"1.0.0","schema":"type Query { a: String }","dataSources":[]}`,
+		"5d1e": `{"version":"1.0.0","schema":"type Query { a: Str`,
+		"9c8b": `{"version":"1.0.0","dataSources":[]}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, key+".json"), []byte(entry), 0644))
+	}
+
+	entries, corrupted, err = Verify(cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, entries)
+	require.Len(t, corrupted, 2)
+	assert.Equal(t, "5d1e", corrupted[0].Key)
+	assert.Equal(t, "9c8b", corrupted[1].Key)
+
+	_, err = ReadSnapshot(cacheDir)
+	assert.Error(t, err, "snapshots never contain corrupted entries")
+
+	for _, entry := range corrupted {
+		require.NoError(t, Remove(cacheDir, entry.Key))
+	}
+	entries, corrupted, err = Verify(cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, entries)
+	assert.Empty(t, corrupted)
+}
+
+func TestNoImpact(t *testing.T) {
+	cacheDir := Dir(t.TempDir())
+	require.NoError(t, os.MkdirAll(cacheDir, os.ModePerm))
+	entries := map[string]string{
+		"3f2a": `{"version":"1.0.0","schema":"type Query { a: String }","dataSources":[],"noImpact":true}`,
+		"9c8b": `{"version":"1.0.0","schema":"type Query { b: String }","dataSources":[]}`,
+	}
+	for key, entry := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, key+".json"), []byte(entry), 0644))
+	}
+	noImpact := filepath.Join(cacheDir, "3f2a.json")
+
+	assert.True(t, NoImpact(cacheDir, []string{noImpact}))
+	assert.True(t, NoImpact(cacheDir, []string{noImpact, noImpact + ".123.tmp"}))
+	assert.False(t, NoImpact(cacheDir, nil))
+	assert.False(t, NoImpact(cacheDir, []string{noImpact + ".123.tmp"}))
+	assert.False(t, NoImpact(cacheDir, []string{noImpact, filepath.Join(cacheDir, "9c8b.json")}))
+	// removed entries and changes outside of the cache rebuild the config
+	assert.False(t, NoImpact(cacheDir, []string{noImpact, filepath.Join(cacheDir, "5d1e.json")}))
+	assert.False(t, NoImpact(cacheDir, []string{noImpact, filepath.Join(filepath.Dir(cacheDir), "operations", "Users.graphql")}))
+}
@@ -20,12 +20,16 @@ import (
 	"github.com/wundergraph/graphql-go-tools/pkg/astprinter"
 	"github.com/wundergraph/graphql-go-tools/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/pkg/astvisitor"
+
+	"github.com/wundergraph/wundergraph/pkg/operations"
 )
 
 type Loader struct {
 	operationsRootPath string
 	fragmentsRootPath  string
 	schemaFilePath     string
+	excluded           []string
+	graphqlExtensions  []string
 	out                *Output
 }
 
@@ -34,10 +38,30 @@ func NewLoader(operationsRootPath string, fragmentsRootPath string, schemaFilePa
 		operationsRootPath: operationsRootPath,
 		fragmentsRootPath:  fragmentsRootPath,
 		schemaFilePath:     schemaFilePath,
+		graphqlExtensions:  operations.DefaultGraphQLExtensions,
 		out:                &Output{},
 	}
 }
 
+// UseGraphQLExtensions replaces the extensions of the GraphQL operation and fragment files
+// loaded, operations.DefaultGraphQLExtensions by default
+func (l *Loader) UseGraphQLExtensions(extensions ...string) {
+	l.graphqlExtensions = extensions
+}
+
+// Exclude skips the operations with the given mount paths or names, see operations.IsExcluded
+func (l *Loader) Exclude(operationNames ...string) {
+	l.excluded = append(l.excluded, operationNames...)
+}
+
+func (l *Loader) isExcluded(mountPath string) bool {
+	if !operations.IsExcluded(mountPath, l.excluded) {
+		return false
+	}
+	l.out.Info = append(l.out.Info, fmt.Sprintf("excluding operation: %s", mountPath))
+	return true
+}
+
 type GraphQLOperationFile struct {
 	OperationName string `json:"operation_name"`
 	ApiMountPath  string `json:"api_mount_path"`
@@ -115,13 +139,12 @@ func (l *Loader) readOperations() error {
 			return err
 		}
 
-		switch {
-		case strings.HasSuffix(filePath, ".ts"):
+		if strings.HasSuffix(filePath, ".ts") {
 			l.readTypescriptOperation(filePath)
-		case strings.HasSuffix(filePath, ".graphql"):
-			l.readGraphQLOperation(filePath)
-		default:
-			l.out.Info = append(l.out.Info, fmt.Sprintf("skipping non .graphql file: %s", filePath))
+		} else if ext, ok := operations.GraphQLExtension(filePath, l.graphqlExtensions); ok {
+			l.readGraphQLOperation(filePath, ext)
+		} else {
+			l.out.Info = append(l.out.Info, fmt.Sprintf("skipping file without an operation extension (%s): %s", strings.Join(l.graphqlExtensions, ", "), filePath))
 		}
 
 		return nil
@@ -132,6 +155,9 @@ func (l *Loader) readTypescriptOperation(relativeFilePath string) {
 	ext := filepath.Ext(relativeFilePath)
 	relativeFilePathNonExt := relativeFilePath[:len(relativeFilePath)-len(ext)]
 	unixLikeRelativeFilePathNonExt := filepath.ToSlash(relativeFilePathNonExt)
+	if l.isExcluded(unixLikeRelativeFilePathNonExt) {
+		return
+	}
 	operationName := normalizeOperationName(unixLikeRelativeFilePathNonExt)
 
 	for _, file := range l.out.TypeScriptOperationFiles {
@@ -151,14 +177,17 @@ func (l *Loader) readTypescriptOperation(relativeFilePath string) {
 	l.out.TypeScriptOperationFiles = append(l.out.TypeScriptOperationFiles, typeScriptFile)
 }
 
-func (l *Loader) readGraphQLOperation(filePath string) {
-	fileName := strings.TrimSuffix(strings.TrimPrefix(filePath, l.operationsRootPath+"/"), ".graphql")
+func (l *Loader) readGraphQLOperation(filePath string, ext string) {
+	fileName := strings.TrimSuffix(strings.TrimPrefix(filePath, l.operationsRootPath+"/"), ext)
 
 	if !isValidOperationName(fileName) {
 		l.out.Info = append(l.out.Info, fmt.Sprintf("file names must be alpanumeric only, skipping file: %s", fileName))
 		return
 	}
 
+	if l.isExcluded(fileName) {
+		return
+	}
 	operationName := normalizeOperationName(fileName)
 
 	for _, file := range l.out.GraphQLOperationFiles {
@@ -276,7 +305,7 @@ func (l *Loader) loadFragments(schemaDocument *ast.Document) (string, error) {
 		if info.IsDir() {
 			return nil
 		}
-		if !strings.HasSuffix(path, ".graphql") {
+		if _, ok := operations.GraphQLExtension(path, l.graphqlExtensions); !ok {
 			return nil
 		}
 		content, err := ioutil.ReadFile(path)
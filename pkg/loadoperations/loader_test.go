@@ -1,6 +1,8 @@
 package loadoperations
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,3 +27,43 @@ func TestLoader_Load(t *testing.T) {
 
 	goldie.New(t).Assert(t, "loadoperations", []byte(out))
 }
+
+func TestLoader_Exclude(t *testing.T) {
+	loader := NewLoader("testdata/operations", "testdata/fragments", "testdata/schema.graphql")
+	loader.Exclude("level/message", "PREFIXChat")
+	_, err := loader.Load(false)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, file := range loader.out.GraphQLOperationFiles {
+		names = append(names, file.OperationName)
+	}
+	assert.NotContains(t, names, "LevelMessage")
+	assert.NotContains(t, names, "PREFIXChat")
+	assert.Contains(t, names, "LevelChat")
+	assert.Contains(t, loader.out.Info, "excluding operation: level/message")
+}
+
+func TestLoader_GraphQLExtensions(t *testing.T) {
+	content, err := os.ReadFile("testdata/operations/LevelChat.graphql")
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Chat.gql"), content, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Messages.graphql"), content, 0644))
+
+	names := func(loader *Loader) []string {
+		_, err := loader.Load(false)
+		assert.NoError(t, err)
+		var names []string
+		for _, file := range loader.out.GraphQLOperationFiles {
+			names = append(names, file.OperationName)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"Chat", "Messages"}, names(NewLoader(dir, "testdata/fragments", "testdata/schema.graphql")))
+
+	loader := NewLoader(dir, "testdata/fragments", "testdata/schema.graphql")
+	loader.UseGraphQLExtensions(".graphql")
+	assert.Equal(t, []string{"Messages"}, names(loader))
+}
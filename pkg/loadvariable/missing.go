@@ -0,0 +1,58 @@
+package loadvariable
+
+import (
+	"os"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// MissingEnvironmentVariables returns the sorted names of all environment variables
+// referenced by ConfigurationVariables anywhere in msg that are unset or empty and
+// have no default value. Like String, an empty variable counts as unset.
+func MissingEnvironmentVariables(msg proto.Message) []string {
+	missing := map[string]struct{}{}
+	walkConfigurationVariables(msg.ProtoReflect(), func(variable *wgpb.ConfigurationVariable) {
+		if variable.GetKind() != wgpb.ConfigurationVariableKind_ENV_CONFIGURATION_VARIABLE {
+			return
+		}
+		name := variable.GetEnvironmentVariableName()
+		if name == "" || variable.GetEnvironmentVariableDefaultValue() != "" || os.Getenv(name) != "" {
+			return
+		}
+		missing[name] = struct{}{}
+	})
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func walkConfigurationVariables(msg protoreflect.Message, fn func(variable *wgpb.ConfigurationVariable)) {
+	if variable, ok := msg.Interface().(*wgpb.ConfigurationVariable); ok {
+		fn(variable)
+		return
+	}
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList() && field.Kind() == protoreflect.MessageKind:
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				walkConfigurationVariables(list.Get(i).Message(), fn)
+			}
+		case field.IsMap() && field.MapValue().Kind() == protoreflect.MessageKind:
+			value.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				walkConfigurationVariables(value.Message(), fn)
+				return true
+			})
+		case !field.IsList() && !field.IsMap() && field.Kind() == protoreflect.MessageKind:
+			walkConfigurationVariables(value.Message(), fn)
+		}
+		return true
+	})
+}
@@ -0,0 +1,47 @@
+package loadvariable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func envVariable(name, defaultValue string) *wgpb.ConfigurationVariable {
+	return &wgpb.ConfigurationVariable{
+		Kind:                            wgpb.ConfigurationVariableKind_ENV_CONFIGURATION_VARIABLE,
+		EnvironmentVariableName:         name,
+		EnvironmentVariableDefaultValue: defaultValue,
+	}
+}
+
+func TestMissingEnvironmentVariables(t *testing.T) {
+	t.Setenv("WG_TEST_SET", "value")
+	t.Setenv("WG_TEST_EMPTY", "")
+
+	config := &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			NodeOptions: &wgpb.NodeOptions{
+				PublicNodeUrl: envVariable("WG_TEST_UNSET", ""),
+				NodeUrl:       envVariable("WG_TEST_SET", ""),
+				Listen: &wgpb.ListenerOptions{
+					Host: envVariable("WG_TEST_WITH_DEFAULT", "localhost"),
+					Port: envVariable("WG_TEST_EMPTY", ""),
+				},
+			},
+			EngineConfiguration: &wgpb.EngineConfiguration{
+				DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+					{CustomRest: &wgpb.DataSourceCustom_REST{Fetch: &wgpb.FetchConfiguration{
+						Url: envVariable("WG_TEST_UNSET", ""),
+						Header: map[string]*wgpb.HTTPHeader{
+							"Authorization": {Values: []*wgpb.ConfigurationVariable{envVariable("WG_TEST_TOKEN", "")}},
+						},
+					}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"WG_TEST_EMPTY", "WG_TEST_TOKEN", "WG_TEST_UNSET"}, MissingEnvironmentVariables(config))
+}
@@ -0,0 +1,29 @@
+package loadvariable
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// Override replaces all ConfigurationVariables anywhere in msg that read one of the
+// environment variables in values with a static variable holding its value. It returns
+// the number of replaced variables.
+func Override(msg proto.Message, values map[string]string) int {
+	replaced := 0
+	walkConfigurationVariables(msg.ProtoReflect(), func(variable *wgpb.ConfigurationVariable) {
+		if variable.GetKind() != wgpb.ConfigurationVariableKind_ENV_CONFIGURATION_VARIABLE {
+			return
+		}
+		value, ok := values[variable.GetEnvironmentVariableName()]
+		if !ok {
+			return
+		}
+		variable.Kind = wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE
+		variable.StaticVariableContent = value
+		variable.EnvironmentVariableName = ""
+		variable.EnvironmentVariableDefaultValue = ""
+		replaced++
+	})
+	return replaced
+}
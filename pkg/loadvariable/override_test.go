@@ -0,0 +1,34 @@
+package loadvariable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestOverride(t *testing.T) {
+	t.Setenv("WG_TEST_URL", "http://localhost:4000")
+
+	config := &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			NodeOptions: &wgpb.NodeOptions{
+				PublicNodeUrl: envVariable("WG_TEST_URL", ""),
+				NodeUrl:       envVariable("WG_TEST_OTHER", "http://localhost:9991"),
+			},
+			EngineConfiguration: &wgpb.EngineConfiguration{
+				DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+					{CustomRest: &wgpb.DataSourceCustom_REST{Fetch: &wgpb.FetchConfiguration{
+						Url: envVariable("WG_TEST_URL", ""),
+					}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, 2, Override(config, map[string]string{"WG_TEST_URL": "http://localhost:5000", "WG_TEST_UNUSED": "value"}))
+	assert.Equal(t, "http://localhost:5000", String(config.Api.NodeOptions.PublicNodeUrl))
+	assert.Equal(t, "http://localhost:5000", String(config.Api.EngineConfiguration.DatasourceConfigurations[0].CustomRest.Fetch.Url))
+	assert.Equal(t, "http://localhost:9991", String(config.Api.NodeOptions.NodeUrl))
+}
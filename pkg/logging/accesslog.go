@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"sync"
+)
+
+type accessLogEntryKey struct{}
+
+// AccessLogEntry collects what the handlers of a request learn about it for the access
+// log, e.g. the operation and the number of upstream requests. All methods are safe for
+// concurrent use and do nothing on a nil entry, the case without an access log.
+type AccessLogEntry struct {
+	mu               sync.Mutex
+	operationName    string
+	upstreamRequests int
+}
+
+// WithAccessLogEntry returns a context carrying a new AccessLogEntry
+func WithAccessLogEntry(ctx context.Context) (context.Context, *AccessLogEntry) {
+	entry := &AccessLogEntry{}
+	return context.WithValue(ctx, accessLogEntryKey{}, entry), entry
+}
+
+// AccessLogEntryFromContext returns the entry set by WithAccessLogEntry, nil if there is none
+func AccessLogEntryFromContext(ctx context.Context) *AccessLogEntry {
+	entry, _ := ctx.Value(accessLogEntryKey{}).(*AccessLogEntry)
+	return entry
+}
+
+func (e *AccessLogEntry) SetOperationName(name string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.operationName = name
+	e.mu.Unlock()
+}
+
+func (e *AccessLogEntry) AddUpstreamRequest() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.upstreamRequests++
+	e.mu.Unlock()
+}
+
+func (e *AccessLogEntry) OperationName() string {
+	if e == nil {
+		return ""
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.operationName
+}
+
+func (e *AccessLogEntry) UpstreamRequests() int {
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.upstreamRequests
+}
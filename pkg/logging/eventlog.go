@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// LastRunLogFilename is the name of the file in the generated directory the
+	// events of the last run are dumped to
+	LastRunLogFilename = "last-run.log"
+
+	DefaultEventLogSize = 500
+)
+
+// LastRunLogFilePath returns the path of the file the events of the last run are dumped to
+func LastRunLogFilePath(wunderGraphDir string) string {
+	return filepath.Join(wunderGraphDir, "generated", LastRunLogFilename)
+}
+
+// EventLog is a ring buffer keeping the last log entries in memory, so they
+// can be inspected after the logger stopped, e.g. on exit. It is safe for
+// concurrent use.
+type EventLog struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+	// written counts all entries ever written, it is the sequence number of the next entry
+	written int
+}
+
+// NewEventLog returns an EventLog keeping the last size entries
+func NewEventLog(size int) *EventLog {
+	if size <= 0 {
+		size = DefaultEventLogSize
+	}
+	return &EventLog{
+		entries: make([][]byte, size),
+	}
+}
+
+// Write stores p as one entry, zap writes every entry at once
+func (e *EventLog) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), bytes.TrimRight(p, "\n")...)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.entries[e.next] = entry
+	e.next = (e.next + 1) % len(e.entries)
+	if e.next == 0 {
+		e.full = true
+	}
+	e.written++
+	return len(p), nil
+}
+
+// EntriesSince returns the stored entries with a sequence number of at least seq, oldest
+// first, and the sequence number of the next entry to pass on the next call. Entries
+// dropped from the buffer in between are skipped.
+func (e *EventLog) EntriesSince(seq int) ([][]byte, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stored := e.next
+	if e.full {
+		stored = len(e.entries)
+	}
+	oldest := e.written - stored
+	if seq < oldest {
+		seq = oldest
+	}
+	if seq >= e.written {
+		return nil, e.written
+	}
+	entries := make([][]byte, 0, e.written-seq)
+	for i := seq; i < e.written; i++ {
+		entries = append(entries, e.entries[i%len(e.entries)])
+	}
+	return entries, e.written
+}
+
+// Entries returns the stored entries, oldest first
+func (e *EventLog) Entries() [][]byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.full {
+		return append([][]byte(nil), e.entries[:e.next]...)
+	}
+	entries := make([][]byte, 0, len(e.entries))
+	entries = append(entries, e.entries[e.next:]...)
+	return append(entries, e.entries[:e.next]...)
+}
+
+// Errors returns the stored entries of level error or above, oldest first
+func (e *EventLog) Errors() [][]byte {
+	var errs [][]byte
+	for _, entry := range e.Entries() {
+		level, err := jsonparser.GetString(entry, "level")
+		if err != nil {
+			continue
+		}
+		var l zapcore.Level
+		if l.UnmarshalText([]byte(level)) == nil && l >= zapcore.ErrorLevel {
+			errs = append(errs, entry)
+		}
+	}
+	return errs
+}
+
+// WriteTo writes the stored entries as JSON lines to w
+func (e *EventLog) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, entry := range e.Entries() {
+		n, err := w.Write(append(entry, '\n'))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Dump writes the stored entries to the file at path, replacing it
+func (e *EventLog) Dump(path string) error {
+	f, err := CreateLogFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := e.WriteTo(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// TeeToEventLog returns a logger which additionally stores all entries of level
+// info and above in eventLog
+func TeeToEventLog(logger *zap.Logger, eventLog *EventLog) *zap.Logger {
+	eventLogCore := zapcore.NewCore(zapJsonEncoder(), zapcore.AddSync(eventLog), zapcore.InfoLevel)
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, eventLogCore)
+	}))
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestEventLog(t *testing.T) {
+	t.Run("keeps the last entries", func(t *testing.T) {
+		eventLog := NewEventLog(3)
+		for i := 0; i < 5; i++ {
+			_, _ = fmt.Fprintf(eventLog, "%d\n", i)
+		}
+		assert.Equal(t, [][]byte{[]byte("2"), []byte("3"), []byte("4")}, eventLog.Entries())
+
+		var buf bytes.Buffer
+		_, err := eventLog.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n3\n4\n", buf.String())
+	})
+
+	t.Run("not full", func(t *testing.T) {
+		eventLog := NewEventLog(3)
+		_, _ = eventLog.Write([]byte("0\n"))
+		assert.Equal(t, [][]byte{[]byte("0")}, eventLog.Entries())
+	})
+
+	t.Run("captures info and errors of the logger", func(t *testing.T) {
+		eventLog := NewEventLog(10)
+		log := TeeToEventLog(zap.NewNop(), eventLog)
+		log.Debug("debug")
+		log.Info("build finished")
+		log.Error("reload failed", zap.String("reason", "invalid config"))
+
+		entries := eventLog.Entries()
+		assert.Len(t, entries, 2)
+		assert.Contains(t, string(entries[0]), `"msg":"build finished"`)
+
+		errs := eventLog.Errors()
+		assert.Len(t, errs, 1)
+		assert.Contains(t, string(errs[0]), `"reason":"invalid config"`)
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		eventLog := NewEventLog(50)
+		log := TeeToEventLog(zap.NewNop(), eventLog)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					log.Info("event", zap.Int("goroutine", i))
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Len(t, eventLog.Entries(), 50)
+	})
+
+	t.Run("entries since", func(t *testing.T) {
+		eventLog := NewEventLog(3)
+		entries, next := eventLog.EntriesSince(0)
+		assert.Empty(t, entries)
+		assert.Equal(t, 0, next)
+
+		_, _ = eventLog.Write([]byte("0\n"))
+		_, _ = eventLog.Write([]byte("1\n"))
+		entries, next = eventLog.EntriesSince(1)
+		assert.Equal(t, [][]byte{[]byte("1")}, entries)
+		assert.Equal(t, 2, next)
+
+		for i := 2; i < 6; i++ {
+			_, _ = fmt.Fprintf(eventLog, "%d\n", i)
+		}
+		// 2 was dropped from the buffer
+		entries, next = eventLog.EntriesSince(next)
+		assert.Equal(t, [][]byte{[]byte("3"), []byte("4"), []byte("5")}, entries)
+		assert.Equal(t, 6, next)
+
+		entries, next = eventLog.EntriesSince(next)
+		assert.Empty(t, entries)
+		assert.Equal(t, 6, next)
+	})
+}
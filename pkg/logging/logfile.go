@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DevLogFilename is the name of the file in the generated logs directory
+// collecting the logs of all components during development
+const DevLogFilename = "dev.log"
+
+// DevLogFilePath returns the path of the development log file
+func DevLogFilePath(wunderGraphDir string) string {
+	return filepath.Join(wunderGraphDir, "generated", "logs", DevLogFilename)
+}
+
+// CreateLogFile creates or truncates the log file at path
+func CreateLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// TeeToWriter returns a logger which additionally writes all entries, including
+// debug entries, as JSON to w
+func TeeToWriter(logger *zap.Logger, w io.Writer) *zap.Logger {
+	fileCore := zapcore.NewCore(zapJsonEncoder(), zapcore.AddSync(w), zapcore.DebugLevel)
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, fileCore)
+	}))
+}
+
+// LineHasRequestID returns true if the log line belongs to the request with the given ID.
+// JSON lines are matched by their request ID field, other lines if they contain the ID.
+func LineHasRequestID(line []byte, requestID string) bool {
+	if requestID == "" {
+		return false
+	}
+	if reqID, err := jsonparser.GetString(line, requestIDField); err == nil {
+		return reqID == requestID
+	}
+	return bytes.Contains(line, []byte(requestID))
+}
+
+// CopyRequestLines writes the lines of r belonging to the request with the given ID to w. With
+// follow it keeps polling r for new lines every pollInterval until ctx is done, a partially
+// written last line is held back until it's complete. Otherwise it returns at the end of r.
+func CopyRequestLines(ctx context.Context, r io.Reader, w io.Writer, requestID string, follow bool, pollInterval time.Duration) error {
+	reader := bufio.NewReader(r)
+	// pending collects a line until it is complete, in follow mode it might still be written
+	var pending []byte
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if len(pending) > 0 && pending[len(pending)-1] == '\n' {
+			if LineHasRequestID(pending, requestID) {
+				if _, err := w.Write(pending); err != nil {
+					return err
+				}
+			}
+			pending = pending[:0]
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if !follow {
+			if LineHasRequestID(pending, requestID) {
+				_, err = w.Write(append(pending, '\n'))
+				return err
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
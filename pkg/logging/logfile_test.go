@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineHasRequestID(t *testing.T) {
+	assert.True(t, LineHasRequestID([]byte(`{"level":"info","reqId":"abc","msg":"done"}`), "abc"))
+	// JSON lines only match by their field, even if the ID is part of another one
+	assert.False(t, LineHasRequestID([]byte(`{"level":"info","reqId":"other","msg":"abc"}`), "abc"))
+	assert.False(t, LineHasRequestID([]byte(`{"level":"info","reqId":"abcd"}`), "abc"))
+	// plain lines, e.g. of the hooks server, match if they contain the ID
+	assert.True(t, LineHasRequestID([]byte("hook preResolve for request abc"), "abc"))
+	assert.False(t, LineHasRequestID([]byte("hook preResolve for request def"), "abc"))
+	assert.False(t, LineHasRequestID([]byte(`{"reqId":""}`), ""))
+	assert.False(t, LineHasRequestID([]byte("any line"), ""))
+}
+
+func TestCopyRequestLines(t *testing.T) {
+	log := `{"reqId":"abc","msg":"first"}
+{"reqId":"def","msg":"other"}
+plain abc
+{"reqId":"abc","msg":"last"}`
+
+	var out bytes.Buffer
+	require.NoError(t, CopyRequestLines(context.Background(), strings.NewReader(log), &out, "abc", false, time.Millisecond))
+	// the unterminated last line is printed at the end of the file
+	assert.Equal(t, "{\"reqId\":\"abc\",\"msg\":\"first\"}\nplain abc\n{\"reqId\":\"abc\",\"msg\":\"last\"}\n", out.String())
+
+	out.Reset()
+	require.NoError(t, CopyRequestLines(context.Background(), strings.NewReader(log), &out, "", false, time.Millisecond))
+	assert.Empty(t, out.String())
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestCopyRequestLinesFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DevLogFilename)
+	w, err := CreateLogFile(path)
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.WriteString("{\"reqId\":\"abc\",\"msg\":\"first\"}\n{\"reqId\":\"abc\",")
+	require.NoError(t, err)
+
+	r, err := os.Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan error)
+	go func() {
+		done <- CopyRequestLines(ctx, r, &out, "abc", true, 10*time.Millisecond)
+	}()
+
+	// the partially written line is held back until it's complete
+	assert.Eventually(t, func() bool {
+		return out.String() == "{\"reqId\":\"abc\",\"msg\":\"first\"}\n"
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "{\"reqId\":\"abc\",\"msg\":\"first\"}\n", out.String())
+
+	_, err = w.WriteString("\"msg\":\"second\"}\n")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return strings.HasSuffix(out.String(), "{\"reqId\":\"abc\",\"msg\":\"second\"}\n")
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Recover must be deferred directly. It recovers from a panic of the goroutine, logs it
+// with its stack and flushes logger, so the cause isn't lost when the process exits.
+// onPanic, if set, receives the panic as an error, e.g. to return it from the goroutine.
+func Recover(logger *zap.Logger, name string, onPanic func(err error)) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	err := fmt.Errorf("panic in %s: %v", name, r)
+	logger.Error("recovered from panic",
+		zap.String("goroutine", name),
+		zap.Any("panic", r),
+		zap.Stack("stack"),
+	)
+	_ = logger.Sync()
+	if onPanic != nil {
+		onPanic(err)
+	}
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecover(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	logger := zap.New(core)
+
+	run := func() (err error) {
+		defer Recover(logger, "worker", func(panicErr error) {
+			err = panicErr
+		})
+		panic("boom")
+	}
+	err := run()
+	require.EqualError(t, err, "panic in worker: boom")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "recovered from panic", entries[0].Message)
+	assert.Contains(t, entries[0].ContextMap()["stack"], "TestRecover")
+
+	assert.NotPanics(t, func() {
+		defer Recover(logger, "idle", nil)
+	})
+	assert.Len(t, logs.All(), 1)
+}
@@ -0,0 +1,176 @@
+package node
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+const (
+	// AccessLogFormatCombined writes lines similar to the combined log format
+	AccessLogFormatCombined = "combined"
+	// AccessLogFormatJSON writes one JSON object per line
+	AccessLogFormatJSON = "json"
+)
+
+// AccessLogFormats lists the formats accepted by WithAccessLog
+var AccessLogFormats = []string{AccessLogFormatCombined, AccessLogFormatJSON}
+
+// accessLogRecord is a line of the access log in AccessLogFormatJSON
+type accessLogRecord struct {
+	Time             string  `json:"time"`
+	RemoteAddr       string  `json:"remoteAddr"`
+	Method           string  `json:"method"`
+	Path             string  `json:"path"`
+	Proto            string  `json:"proto"`
+	Operation        string  `json:"operation,omitempty"`
+	Status           int     `json:"status"`
+	Bytes            int64   `json:"bytes"`
+	DurationMs       float64 `json:"durationMs"`
+	UpstreamRequests int     `json:"upstreamRequests"`
+	UserAgent        string  `json:"userAgent,omitempty"`
+}
+
+// accessLog writes a line per request handled by the node
+type accessLog struct {
+	format string
+	mu     sync.Mutex
+	w      io.Writer
+	// skip excludes requests, e.g. health checks
+	skip func(r *http.Request) bool
+	now  func() time.Time
+}
+
+func newAccessLog(format string, w io.Writer, skip func(r *http.Request) bool) (*accessLog, error) {
+	switch format {
+	case AccessLogFormatCombined, AccessLogFormatJSON:
+	default:
+		return nil, fmt.Errorf("unknown access log format %q, supported formats are %v", format, AccessLogFormats)
+	}
+	return &accessLog{format: format, w: w, skip: skip, now: time.Now}, nil
+}
+
+// skipAccessLog excludes health checks, the status page and the playground from the access log
+func (n *Node) skipAccessLog(r *http.Request) bool {
+	if isHealthCheck(r) || r.URL.Path == rootEndpoint {
+		return true
+	}
+	playgroundPath := n.options.playgroundPath
+	if playgroundPath == "" {
+		playgroundPath = apihandler.DefaultPlaygroundPath
+	}
+	return r.Method == http.MethodGet && r.URL.Path == playgroundPath
+}
+
+func (l *accessLog) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.skip != nil && l.skip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := l.now()
+		ctx, entry := logging.WithAccessLogEntry(r.Context())
+		recorder := &accessLogResponseWriter{ResponseWriter: w}
+		defer func() {
+			l.write(r, entry, recorder, l.now().Sub(start), start)
+		}()
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+	})
+}
+
+func (l *accessLog) write(r *http.Request, entry *logging.AccessLogEntry, recorder *accessLogResponseWriter, duration time.Duration, start time.Time) {
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	record := accessLogRecord{
+		Time:             start.Format(time.RFC3339),
+		RemoteAddr:       r.RemoteAddr,
+		Method:           r.Method,
+		Path:             r.URL.RequestURI(),
+		Proto:            r.Proto,
+		Operation:        entry.OperationName(),
+		Status:           status,
+		Bytes:            recorder.bytes,
+		DurationMs:       float64(duration.Microseconds()) / 1000,
+		UpstreamRequests: entry.UpstreamRequests(),
+		UserAgent:        r.UserAgent(),
+	}
+	var line []byte
+	if l.format == AccessLogFormatJSON {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return
+		}
+		line = append(data, '\n')
+	} else {
+		operation := record.Operation
+		if operation == "" {
+			operation = "-"
+		}
+		line = []byte(fmt.Sprintf("%s - - [%s] %s %d %d %s %s %d %s\n",
+			record.RemoteAddr,
+			start.Format("02/Jan/2006:15:04:05 -0700"),
+			strconv.Quote(r.Method+" "+record.Path+" "+record.Proto),
+			record.Status,
+			record.Bytes,
+			operation,
+			duration.Round(time.Microsecond),
+			record.UpstreamRequests,
+			strconv.Quote(record.UserAgent),
+		))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line)
+}
+
+// accessLogResponseWriter records the status and the size of a response
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessLogResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps streaming responses working
+func (w *accessLogResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack keeps WebSockets working, upgraded connections are logged with status 101
+func (w *accessLogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,59 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+func TestAccessLog(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := logging.AccessLogEntryFromContext(r.Context())
+		entry.SetOperationName("Weather")
+		entry.AddUpstreamRequest()
+		entry.AddUpstreamRequest()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+	n := &Node{log: zap.NewNop()}
+
+	var out bytes.Buffer
+	log, err := newAccessLog(AccessLogFormatJSON, &out, n.skipAccessLog)
+	require.NoError(t, err)
+	log.Handler(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/operations/Weather?city=Berlin", nil))
+	var record accessLogRecord
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, http.MethodGet, record.Method)
+	assert.Equal(t, "/operations/Weather?city=Berlin", record.Path)
+	assert.Equal(t, "HTTP/1.1", record.Proto)
+	assert.Equal(t, "Weather", record.Operation)
+	assert.Equal(t, http.StatusCreated, record.Status)
+	assert.Equal(t, int64(5), record.Bytes)
+	assert.Equal(t, 2, record.UpstreamRequests)
+
+	out.Reset()
+	log, err = newAccessLog(AccessLogFormatCombined, &out, n.skipAccessLog)
+	require.NoError(t, err)
+	log.Handler(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/operations/Weather", nil))
+	line := out.String()
+	assert.True(t, strings.HasSuffix(line, "\n"))
+	assert.Contains(t, line, `"POST /operations/Weather HTTP/1.1" 201 5 Weather`)
+
+	out.Reset()
+	for _, path := range []string{healthCheckEndpoint, readinessEndpoint, livenessEndpoint, "/graphql"} {
+		log.Handler(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	assert.Empty(t, out.String())
+
+	_, err = newAccessLog("apache", &out, nil)
+	assert.Error(t, err)
+}
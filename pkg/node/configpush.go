@@ -0,0 +1,79 @@
+package node
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// ConfigEndpoint replaces the served config with the wundergraph.config.json in the
+// request body, see WithConfigPush
+const ConfigEndpoint = "/config"
+
+// ConfigPushTokenHeader carries the token configured through WithConfigPush
+const ConfigPushTokenHeader = "X-WG-Config-Push-Token"
+
+// maxConfigPushSize limits the size of pushed configs
+const maxConfigPushSize = 64 << 20
+
+// configPushHandler validates the pushed config like a config read from the file system
+// and serves it once it's valid. A broken config never replaces the one being served.
+func (n *Node) configPushHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := n.options.configPushToken; token != "" {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(ConfigPushTokenHeader)), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxConfigPushSize))
+		if err != nil || len(data) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var graphConfig wgpb.WunderGraphConfiguration
+		if err := json.Unmarshal(data, &graphConfig); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		n.applyVariableOverrides(&graphConfig)
+		config, err := CreateConfig(&graphConfig)
+		if err != nil {
+			n.log.Error("could not create pushed config", zap.Error(err))
+			http.Error(w, strings.TrimSpace(err.Error()), http.StatusUnprocessableEntity)
+			return
+		}
+		config.Api.ApiConfigHash, err = configHash(&graphConfig)
+		if err != nil {
+			n.log.Error("could not hash pushed config", zap.Error(err))
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		if err := n.validateConfig(config); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		n.log.Info("config pushed, reloading",
+			zap.String("remoteAddr", r.RemoteAddr),
+			zap.String("apiConfigHash", config.Api.ApiConfigHash),
+		)
+
+		w.WriteHeader(http.StatusAccepted)
+		// the reload closes the server, the response must be sent before
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		n.beginReload()
+		select {
+		case n.configCh <- config:
+		case <-n.ctx.Done():
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigPushHandler(t *testing.T) {
+	n := &Node{log: zap.NewNop(), options: options{configPush: true, configPushToken: "secret"}}
+	handler := n.configPushHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, ConfigEndpoint, strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPut, ConfigEndpoint, strings.NewReader(`not json`))
+	req.Header.Set(ConfigPushTokenHeader, "wrong")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, ConfigEndpoint, strings.NewReader(`not json`))
+	req.Header.Set(ConfigPushTokenHeader, "secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, ConfigEndpoint, nil)
+	req.Header.Set(ConfigPushTokenHeader, "secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestConfigPushHandlerHashesConfig(t *testing.T) {
+	n := &Node{
+		ctx:      context.Background(),
+		log:      zap.NewNop(),
+		options:  options{configPush: true, configPushToken: "secret"},
+		configCh: make(chan WunderNodeConfig, 1),
+	}
+	graphConfig := testGraphConfig("type Query { a: String }", 9991)
+	data, err := json.Marshal(graphConfig)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, ConfigEndpoint, strings.NewReader(string(data)))
+	req.Header.Set(ConfigPushTokenHeader, "secret")
+	rec := httptest.NewRecorder()
+	n.configPushHandler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	// the hash identifies the pushed config in readiness checks and ETags like a loaded one
+	pushed := <-n.configCh
+	expected, err := configHash(graphConfig)
+	require.NoError(t, err)
+	assert.NotEmpty(t, pushed.Api.ApiConfigHash)
+	assert.Equal(t, expected, pushed.Api.ApiConfigHash)
+}
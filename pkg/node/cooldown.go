@@ -0,0 +1,33 @@
+package node
+
+import "time"
+
+// reloadCooldown holds back config reloads for a while after a successful one.
+// All reloads held back during the cooldown are coalesced into a single one
+// once it has passed, which then reads the latest config.
+type reloadCooldown struct {
+	duration time.Duration
+	until    time.Time
+	// pending fires when the cooldown has passed and a reload was held back,
+	// it is nil otherwise
+	pending <-chan time.Time
+}
+
+// hold returns true if a reload must wait for the cooldown
+func (c *reloadCooldown) hold(now time.Time) bool {
+	wait := c.until.Sub(now)
+	if wait <= 0 {
+		return false
+	}
+	if c.pending == nil {
+		c.pending = time.After(wait)
+	}
+	return true
+}
+
+// start starts the cooldown after a successful reload
+func (c *reloadCooldown) start(now time.Time) {
+	if c.duration > 0 {
+		c.until = now.Add(c.duration)
+	}
+}
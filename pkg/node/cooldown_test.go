@@ -0,0 +1,33 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadCooldown(t *testing.T) {
+	disabled := &reloadCooldown{}
+	disabled.start(time.Now())
+	assert.False(t, disabled.hold(time.Now()))
+	assert.Nil(t, disabled.pending)
+
+	c := &reloadCooldown{duration: 20 * time.Millisecond}
+	now := time.Now()
+	assert.False(t, c.hold(now))
+	c.start(now)
+	assert.True(t, c.hold(now))
+	pending := c.pending
+	assert.NotNil(t, pending)
+	assert.True(t, c.hold(now.Add(10*time.Millisecond)))
+	assert.Equal(t, pending, c.pending, "held back reloads are coalesced")
+
+	select {
+	case <-c.pending:
+	case <-time.After(time.Second):
+		t.Fatal("pending reload not released")
+	}
+	c.pending = nil
+	assert.False(t, c.hold(time.Now()))
+}
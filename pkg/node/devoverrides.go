@@ -0,0 +1,180 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// DevOverridesFileName is the default file of WithDevOverrides, relative to the .wundergraph directory
+const DevOverridesFileName = "dev.overrides.json"
+
+// DevOverrides change which data source resolves fields of the schema, e.g. to try a new
+// data source for a single field during a migration without changing the config
+type DevOverrides struct {
+	Fields []FieldOverride `json:"fields"`
+}
+
+// FieldOverride makes DataSource resolve the root field, or always returns Static for it
+type FieldOverride struct {
+	TypeName  string `json:"typeName"`
+	FieldName string `json:"fieldName"`
+	// DataSource is the id of a data source of the config
+	DataSource string          `json:"dataSource,omitempty"`
+	Static     json.RawMessage `json:"static,omitempty"`
+}
+
+func (o FieldOverride) String() string {
+	return o.TypeName + "." + o.FieldName
+}
+
+// LoadDevOverrides reads and validates the overrides at path
+func LoadDevOverrides(path string) (*DevOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var overrides DevOverrides
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	for _, field := range overrides.Fields {
+		if field.TypeName == "" || field.FieldName == "" {
+			return nil, fmt.Errorf("invalid override in %s: typeName and fieldName are required", path)
+		}
+		if (field.DataSource == "") == (len(field.Static) == 0) {
+			return nil, fmt.Errorf("invalid override of %s in %s: set either dataSource or static", field, path)
+		}
+	}
+	return &overrides, nil
+}
+
+// Apply moves the overridden root fields to their new data sources
+func (o *DevOverrides) Apply(engine *wgpb.EngineConfiguration) error {
+	if engine == nil {
+		return errors.New("config has no engine configuration")
+	}
+	for _, field := range o.Fields {
+		target, err := o.target(engine, field)
+		if err != nil {
+			return err
+		}
+		owned := false
+		for _, ds := range engine.DatasourceConfigurations {
+			if ds != target && removeRootField(ds, field.TypeName, field.FieldName) {
+				owned = true
+			}
+		}
+		if !owned && !hasRootField(target, field.TypeName, field.FieldName) {
+			return fmt.Errorf("could not override %s: no data source resolves it", field)
+		}
+		addRootField(target, field.TypeName, field.FieldName)
+	}
+	return nil
+}
+
+func (o *DevOverrides) target(engine *wgpb.EngineConfiguration, field FieldOverride) (*wgpb.DataSourceConfiguration, error) {
+	if len(field.Static) != 0 {
+		ds := &wgpb.DataSourceConfiguration{
+			Kind: wgpb.DataSourceKind_STATIC,
+			Id:   "dev-override-" + field.TypeName + "-" + field.FieldName,
+			CustomStatic: &wgpb.DataSourceCustom_Static{
+				Data: &wgpb.ConfigurationVariable{
+					Kind:                  wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE,
+					StaticVariableContent: string(field.Static),
+				},
+			},
+		}
+		engine.DatasourceConfigurations = append(engine.DatasourceConfigurations, ds)
+		return ds, nil
+	}
+	ids := make([]string, 0, len(engine.DatasourceConfigurations))
+	for _, ds := range engine.DatasourceConfigurations {
+		if ds.Id == field.DataSource {
+			return ds, nil
+		}
+		if ds.Id != "" {
+			ids = append(ids, ds.Id)
+		}
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("could not override %s: unknown data source %q, available: %s", field, field.DataSource, strings.Join(ids, ", "))
+}
+
+func hasRootField(ds *wgpb.DataSourceConfiguration, typeName, fieldName string) bool {
+	for _, node := range ds.RootNodes {
+		if node.TypeName != typeName {
+			continue
+		}
+		for _, name := range node.FieldNames {
+			if name == fieldName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func removeRootField(ds *wgpb.DataSourceConfiguration, typeName, fieldName string) (removed bool) {
+	for _, node := range ds.RootNodes {
+		if node.TypeName != typeName {
+			continue
+		}
+		fieldNames := node.FieldNames[:0]
+		for _, name := range node.FieldNames {
+			if name == fieldName {
+				removed = true
+				continue
+			}
+			fieldNames = append(fieldNames, name)
+		}
+		node.FieldNames = fieldNames
+	}
+	return removed
+}
+
+func addRootField(ds *wgpb.DataSourceConfiguration, typeName, fieldName string) {
+	if hasRootField(ds, typeName, fieldName) {
+		return
+	}
+	for _, node := range ds.RootNodes {
+		if node.TypeName == typeName {
+			node.FieldNames = append(node.FieldNames, fieldName)
+			return
+		}
+	}
+	ds.RootNodes = append(ds.RootNodes, &wgpb.TypeField{TypeName: typeName, FieldNames: []string{fieldName}})
+}
+
+// applyDevOverrides reads the overrides on every load, so changes to the file apply with the next reload
+func (n *Node) applyDevOverrides(graphConfig *wgpb.WunderGraphConfiguration) error {
+	overrides, err := LoadDevOverrides(n.options.devOverridesPath)
+	if err != nil {
+		return err
+	}
+	if err := overrides.Apply(graphConfig.GetApi().GetEngineConfiguration()); err != nil {
+		return err
+	}
+	for _, field := range overrides.Fields {
+		resolver := field.DataSource
+		if resolver == "" {
+			resolver = "static"
+		}
+		n.log.Warn("DEV ONLY: overriding the resolution of field",
+			zap.String("field", field.String()),
+			zap.String("resolvedBy", resolver),
+			zap.String("overridesFile", n.options.devOverridesPath),
+		)
+	}
+	return nil
+}
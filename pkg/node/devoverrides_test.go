@@ -0,0 +1,51 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestDevOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DevOverridesFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"fields":[
+		{"typeName":"Query","fieldName":"users","dataSource":"users-v2"},
+		{"typeName":"Query","fieldName":"flags","static":{"beta":true}}
+	]}`), 0644))
+	overrides, err := LoadDevOverrides(path)
+	require.NoError(t, err)
+
+	legacy := &wgpb.DataSourceConfiguration{
+		Id:        "legacy",
+		RootNodes: []*wgpb.TypeField{{TypeName: "Query", FieldNames: []string{"users", "flags", "posts"}}},
+	}
+	v2 := &wgpb.DataSourceConfiguration{Id: "users-v2"}
+	engine := &wgpb.EngineConfiguration{DatasourceConfigurations: []*wgpb.DataSourceConfiguration{legacy, v2}}
+	require.NoError(t, overrides.Apply(engine))
+
+	assert.Equal(t, []string{"posts"}, legacy.RootNodes[0].FieldNames)
+	assert.Equal(t, []*wgpb.TypeField{{TypeName: "Query", FieldNames: []string{"users"}}}, v2.RootNodes)
+	require.Len(t, engine.DatasourceConfigurations, 3)
+	static := engine.DatasourceConfigurations[2]
+	assert.Equal(t, wgpb.DataSourceKind_STATIC, static.Kind)
+	assert.Equal(t, "Query", static.RootNodes[0].TypeName)
+	assert.Equal(t, []string{"flags"}, static.RootNodes[0].FieldNames)
+	assert.JSONEq(t, `{"beta":true}`, loadvariable.String(static.CustomStatic.Data))
+
+	unknown := &DevOverrides{Fields: []FieldOverride{{TypeName: "Query", FieldName: "users", DataSource: "missing"}}}
+	assert.ErrorContains(t, unknown.Apply(engine), `unknown data source "missing", available: dev-override-Query-flags, legacy, users-v2`)
+
+	unresolved := &DevOverrides{Fields: []FieldOverride{{TypeName: "Query", FieldName: "comments", DataSource: "legacy"}}}
+	assert.ErrorContains(t, unresolved.Apply(engine), "no data source resolves it")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"fields":[{"typeName":"Query","fieldName":"users","dataSource":"a","static":1}]}`), 0644))
+	_, err = LoadDevOverrides(path)
+	assert.Error(t, err)
+}
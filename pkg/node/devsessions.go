@@ -0,0 +1,112 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// DevSessionsFileName is the default file of WithPersistentDevSessions, relative to the .wundergraph directory
+const DevSessionsFileName = "cache/dev-sessions.json"
+
+const devSessionsWarning = "INSECURE, for development only. These keys sign the session cookies of 'wunderctl up', never use them in production."
+
+// devSessionKeys are the secrets of cookie based sessions, a session stays valid as long as they don't change
+type devSessionKeys struct {
+	Warning    string `json:"warning"`
+	HashKey    string `json:"hashKey"`
+	BlockKey   string `json:"blockKey"`
+	CsrfSecret string `json:"csrfSecret"`
+}
+
+func (k devSessionKeys) valid() bool {
+	return len(k.HashKey) == 32 && len(k.BlockKey) == 32 && k.CsrfSecret != ""
+}
+
+// loadDevSessionKeys reads the keys at path. If there are none yet, the keys of the config
+// are stored, or random ones if it has none.
+func loadDevSessionKeys(path string, cookieBased *wgpb.CookieBasedAuthentication) (devSessionKeys, error) {
+	var keys devSessionKeys
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return keys, err
+		}
+		if !keys.valid() {
+			return keys, errors.New("invalid dev session keys")
+		}
+		return keys, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return keys, err
+	}
+
+	keys = devSessionKeys{
+		Warning:    devSessionsWarning,
+		HashKey:    loadvariable.String(cookieBased.HashKey),
+		BlockKey:   loadvariable.String(cookieBased.BlockKey),
+		CsrfSecret: loadvariable.String(cookieBased.CsrfSecret),
+	}
+	if !keys.valid() {
+		if keys.HashKey, err = randomDevSessionKey(); err != nil {
+			return keys, err
+		}
+		if keys.BlockKey, err = randomDevSessionKey(); err != nil {
+			return keys, err
+		}
+		if keys.CsrfSecret, err = randomDevSessionKey(); err != nil {
+			return keys, err
+		}
+	}
+	if data, err = json.MarshalIndent(keys, "", "  "); err != nil {
+		return keys, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return keys, err
+	}
+	return keys, os.WriteFile(path, data, 0600)
+}
+
+// randomDevSessionKey returns 32 random hex characters, the length required for cookie keys
+func randomDevSessionKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// applyDevSessionKeys replaces the cookie keys of the config with the persisted ones, so
+// sessions survive reloads and restarts even if the config is built with new keys
+func (n *Node) applyDevSessionKeys(cookieBased *wgpb.CookieBasedAuthentication) {
+	keys, err := loadDevSessionKeys(n.options.devSessionsPath, cookieBased)
+	if err != nil {
+		n.log.Error("could not load persistent dev sessions, sessions are lost on reload",
+			zap.String("filePath", n.options.devSessionsPath),
+			zap.Error(err),
+		)
+		return
+	}
+	static := func(value string) *wgpb.ConfigurationVariable {
+		return &wgpb.ConfigurationVariable{
+			Kind:                  wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE,
+			StaticVariableContent: value,
+		}
+	}
+	if configured := loadvariable.String(cookieBased.HashKey); configured != "" && configured != keys.HashKey {
+		n.log.Warn("ignoring the configured cookie keys in favor of the persistent dev sessions, delete the file to reset them",
+			zap.String("filePath", n.options.devSessionsPath),
+		)
+	}
+	cookieBased.HashKey = static(keys.HashKey)
+	cookieBased.BlockKey = static(keys.BlockKey)
+	cookieBased.CsrfSecret = static(keys.CsrfSecret)
+}
@@ -0,0 +1,45 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestApplyDevSessionKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DevSessionsFileName)
+	n := &Node{log: zap.NewNop(), options: options{devSessionsPath: path}}
+
+	first := &wgpb.CookieBasedAuthentication{}
+	n.applyDevSessionKeys(first)
+	hashKey := loadvariable.String(first.HashKey)
+	assert.Len(t, hashKey, 32)
+	assert.Len(t, loadvariable.String(first.BlockKey), 32)
+	assert.NotEmpty(t, loadvariable.String(first.CsrfSecret))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// a rebuilt config with new keys keeps the persisted ones
+	rebuilt := &wgpb.CookieBasedAuthentication{
+		HashKey: &wgpb.ConfigurationVariable{
+			Kind:                  wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE,
+			StaticVariableContent: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		},
+	}
+	n.applyDevSessionKeys(rebuilt)
+	assert.Equal(t, hashKey, loadvariable.String(rebuilt.HashKey))
+	assert.Equal(t, loadvariable.String(first.BlockKey), loadvariable.String(rebuilt.BlockKey))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"hashKey":"short"}`), 0600))
+	_, err = loadDevSessionKeys(path, &wgpb.CookieBasedAuthentication{})
+	assert.Error(t, err)
+}
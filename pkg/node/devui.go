@@ -0,0 +1,214 @@
+package node
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/node/nodetemplates"
+	"github.com/wundergraph/wundergraph/pkg/responsecache"
+)
+
+// DevUIEndpoint serves a page showing the state of the dev loop, see WithDevUI
+const DevUIEndpoint = "/__wg"
+
+// maxDevUIErrors limits the recent errors shown by the dev UI
+const maxDevUIErrors = 10
+
+// DevUI configures the page of WithDevUI. Events feeds the live events and the recent errors,
+// Rebuild is called in the background when the rebuild button is clicked, the button is
+// hidden if it's nil.
+type DevUI struct {
+	Events     *logging.EventLog
+	WatchPaths []string
+	Rebuild    func()
+	// AllowRemote serves the page to clients other than loopback ones
+	AllowRemote bool
+}
+
+type devUIStatus struct {
+	// Build is one of "ok", "reloading" and "failed". A build failed if an error was logged
+	// after the served config was applied, the node keeps serving it.
+	Build         string            `json:"build"`
+	ConfigHash    string            `json:"configHash"`
+	ConfiguredAt  int64             `json:"configuredAt"`
+	Errors        []json.RawMessage `json:"errors"`
+	WatchPaths    []string          `json:"watchPaths"`
+	DataSources   []devUIDataSource `json:"dataSources"`
+	PlaygroundURL string            `json:"playgroundUrl,omitempty"`
+	CanRebuild    bool              `json:"canRebuild"`
+}
+
+type devUIDataSource struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+type devUIEvents struct {
+	Events []json.RawMessage `json:"events"`
+	Next   int               `json:"next"`
+}
+
+// registerDevUI serves the dev UI for the config of the server, configuredAt is the time
+// the config was applied
+func (n *Node) registerDevUI(router *mux.Router, api *apihandler.Api, configuredAt time.Time) {
+	ui := n.options.devUI
+	handle := func(path, method string, handler http.HandlerFunc) {
+		router.Handle(DevUIEndpoint+path, devUIGuard(ui.AllowRemote, handler)).Methods(method)
+	}
+
+	handle("", http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		template, err := nodetemplates.GetTemplateByPath(DevUIEndpoint)
+		if err != nil {
+			n.log.Error("GetTemplateByPath", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.Header().Set("Content-Type", "text/html")
+		if err := template.Execute(w, struct{ Endpoint string }{DevUIEndpoint}); err != nil {
+			n.log.Error("template.Execute", zap.Error(err))
+		}
+	})
+
+	handle("/status", http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeDevUIJSON(w, n.devUIStatus(api, configuredAt))
+	})
+
+	handle("/events", http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		after, err := strconv.Atoi(r.URL.Query().Get("after"))
+		if err != nil || after < 0 {
+			after = 0
+		}
+		var events devUIEvents
+		var entries [][]byte
+		if ui.Events != nil {
+			entries, events.Next = ui.Events.EntriesSince(after)
+		}
+		events.Events = rawJSONEntries(entries)
+		writeDevUIJSON(w, events)
+	})
+
+	if ui.Rebuild != nil {
+		handle("/rebuild", http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			n.log.Info("rebuild requested from the dev UI")
+			// the rebuild reloads the node, which closes this server
+			go ui.Rebuild()
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+}
+
+func (n *Node) devUIStatus(api *apihandler.Api, configuredAt time.Time) devUIStatus {
+	ui := n.options.devUI
+	status := devUIStatus{
+		Build:        "ok",
+		ConfigHash:   api.ApiConfigHash,
+		ConfiguredAt: configuredAt.UnixMilli(),
+		Errors:       []json.RawMessage{},
+		WatchPaths:   ui.WatchPaths,
+		DataSources:  []devUIDataSource{},
+		CanRebuild:   ui.Rebuild != nil,
+	}
+	if status.WatchPaths == nil {
+		status.WatchPaths = []string{}
+	}
+	for _, dataSource := range api.EngineConfiguration.GetDatasourceConfigurations() {
+		status.DataSources = append(status.DataSources, devUIDataSource{
+			ID:   dataSource.Id,
+			Kind: dataSource.Kind.String(),
+		})
+	}
+	if api.EnableGraphqlEndpoint && !n.options.disablePlayground {
+		status.PlaygroundURL = n.options.playgroundPath
+		if status.PlaygroundURL == "" {
+			status.PlaygroundURL = apihandler.DefaultPlaygroundPath
+		}
+	}
+	if ui.Events != nil {
+		errs := ui.Events.Errors()
+		if len(errs) > maxDevUIErrors {
+			errs = errs[len(errs)-maxDevUIErrors:]
+		}
+		status.Errors = rawJSONEntries(errs)
+		if len(errs) != 0 {
+			if ts, err := jsonparser.GetInt(errs[len(errs)-1], "time"); err == nil && ts >= status.ConfiguredAt {
+				status.Build = "failed"
+			}
+		}
+	}
+	if atomic.LoadInt32(&n.reloading) == 1 {
+		status.Build = "reloading"
+	}
+	return status
+}
+
+// rawJSONEntries returns the entries of an EventLog that are valid JSON, never nil
+func rawJSONEntries(entries [][]byte) []json.RawMessage {
+	raw := make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		if json.Valid(entry) {
+			raw = append(raw, entry)
+		}
+	}
+	return raw
+}
+
+func writeDevUIJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// devUIGuard rejects requests from other machines unless allowRemote is set, it guards the
+// dev UI and the other dev endpoints changing the node. The Host header
+// must name a loopback address as well, so other sites can't reach the UI through DNS
+// rebinding, and requests changing state must come from the UI itself.
+func devUIGuard(allowRemote bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowRemote && (!isLoopbackHost(r.RemoteAddr) || !isLoopbackHost(r.Host)) {
+			http.Error(w, "only available on loopback addresses", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if origin := r.Header.Get("Origin"); origin != "" {
+				u, err := url.Parse(origin)
+				if err != nil || u.Host != r.Host {
+					http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// cachePurgeHandler drops entries of the response cache, like the other dev endpoints changing
+// the node it only serves loopback clients
+func (n *Node) cachePurgeHandler(responseCache *responsecache.Cache) http.Handler {
+	allowRemote := n.options.devUI != nil && n.options.devUI.AllowRemote
+	return devUIGuard(allowRemote, responseCache.PurgeHandler())
+}
+
+// isLoopbackHost returns true if hostport, with or without a port, is localhost or a loopback IP
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,158 @@
+package node
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/responsecache"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func devUIRequest(method, path string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "127.0.0.1:51234"
+	req.Host = "localhost:9991"
+	return req
+}
+
+func TestDevUI(t *testing.T) {
+	eventLog := logging.NewEventLog(10)
+	rebuilt := make(chan struct{}, 1)
+	n := &Node{log: zap.NewNop(), options: options{devUI: &DevUI{
+		Events:     eventLog,
+		WatchPaths: []string{"/app/.wundergraph/operations"},
+		Rebuild:    func() { rebuilt <- struct{}{} },
+	}}}
+	api := &apihandler.Api{
+		ApiConfigHash:         "abc",
+		EnableGraphqlEndpoint: true,
+		EngineConfiguration: &wgpb.EngineConfiguration{DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+			{Id: "countries", Kind: wgpb.DataSourceKind_GRAPHQL},
+		}},
+	}
+	log := logging.TeeToEventLog(zap.NewNop(), eventLog)
+	log.Info("build finished")
+	configuredAt := time.Now()
+	router := mux.NewRouter()
+	n.registerDevUI(router, api, configuredAt)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, devUIRequest(http.MethodGet, DevUIEndpoint))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "WunderGraph Dev")
+
+	var status devUIStatus
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, devUIRequest(http.MethodGet, DevUIEndpoint+"/status"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "ok", status.Build)
+	assert.Equal(t, "abc", status.ConfigHash)
+	assert.Equal(t, []devUIDataSource{{ID: "countries", Kind: "GRAPHQL"}}, status.DataSources)
+	assert.Equal(t, []string{"/app/.wundergraph/operations"}, status.WatchPaths)
+	assert.Equal(t, apihandler.DefaultPlaygroundPath, status.PlaygroundURL)
+	assert.True(t, status.CanRebuild)
+
+	// errors after the config was applied mean the latest build failed
+	time.Sleep(time.Millisecond)
+	log.Error("could not bundle")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, devUIRequest(http.MethodGet, DevUIEndpoint+"/status"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "failed", status.Build)
+	assert.Len(t, status.Errors, 1)
+
+	var events devUIEvents
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, devUIRequest(http.MethodGet, DevUIEndpoint+"/events?after=1"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	assert.Len(t, events.Events, 1)
+	assert.Contains(t, string(events.Events[0]), "could not bundle")
+	assert.Equal(t, 2, events.Next)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, devUIRequest(http.MethodPost, DevUIEndpoint+"/rebuild"))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	select {
+	case <-rebuilt:
+	case <-time.After(time.Second):
+		t.Fatal("rebuild not called")
+	}
+}
+
+func TestDevUIGuard(t *testing.T) {
+	handler := devUIGuard(false, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, devUIRequest(http.MethodGet, DevUIEndpoint))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req := devUIRequest(http.MethodGet, DevUIEndpoint)
+	req.RemoteAddr = "192.168.1.10:51234"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// a rebound DNS name resolving to loopback
+	req = devUIRequest(http.MethodGet, DevUIEndpoint)
+	req.Host = "attacker.example:9991"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = devUIRequest(http.MethodPost, DevUIEndpoint+"/rebuild")
+	req.Header.Set("Origin", "http://other.localhost:3000")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = devUIRequest(http.MethodPost, DevUIEndpoint+"/rebuild")
+	req.Header.Set("Origin", "http://localhost:9991")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = devUIRequest(http.MethodGet, DevUIEndpoint)
+	req.RemoteAddr = "192.168.1.10:51234"
+	req.Host = "dev-box:9991"
+	rec = httptest.NewRecorder()
+	devUIGuard(true, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCachePurgeHandler(t *testing.T) {
+	n := &Node{log: zap.NewNop()}
+	handler := n.cachePurgeHandler(responsecache.New(zap.NewNop(), responsecache.Options{}))
+	purgeRequest := func() *http.Request {
+		req := devUIRequest(http.MethodPost, cachePurgeEndpoint)
+		req.Body = io.NopCloser(strings.NewReader(`{"keys":["Users"]}`))
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, purgeRequest())
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req := purgeRequest()
+	req.RemoteAddr = "192.168.1.10:51234"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = purgeRequest()
+	req.Header.Set("Origin", "http://attacker.example")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
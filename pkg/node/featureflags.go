@@ -0,0 +1,40 @@
+package node
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// FeatureFlagsEnvKey lists enabled feature flags, separated by commas
+const FeatureFlagsEnvKey = "WG_FEATURE_FLAGS"
+
+// ParseFeatureFlags splits a comma separated list of feature flags, empty entries are ignored
+func ParseFeatureFlags(value string) []string {
+	var flags []string
+	for _, flag := range strings.Split(value, ",") {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// featureFlags merges the flags of the options and the environment
+func (n *Node) featureFlags() map[string]bool {
+	enabled := map[string]bool{}
+	for _, flag := range append(ParseFeatureFlags(os.Getenv(FeatureFlagsEnvKey)), n.options.featureFlags...) {
+		enabled[flag] = true
+	}
+	if len(enabled) != 0 {
+		names := make([]string, 0, len(enabled))
+		for flag := range enabled {
+			names = append(names, flag)
+		}
+		sort.Strings(names)
+		n.log.Info("feature flags enabled", zap.Strings("featureFlags", names))
+	}
+	return enabled
+}
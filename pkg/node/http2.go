@@ -0,0 +1,21 @@
+package node
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// configureHTTP2 serves HTTP/2 next to HTTP/1.1. With TLS it's negotiated via ALPN, without
+// clients use h2c, either with prior knowledge or by upgrading an HTTP/1.1 request.
+func configureHTTP2(server *http.Server, tls bool) error {
+	h2s := &http2.Server{}
+	if tls {
+		return http2.ConfigureServer(server, h2s)
+	}
+	// the idle timeout of the server doesn't apply to hijacked h2c connections
+	h2s.IdleTimeout = server.IdleTimeout
+	server.Handler = h2c.NewHandler(server.Handler, h2s)
+	return nil
+}
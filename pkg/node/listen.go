@@ -0,0 +1,243 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+)
+
+// ParseListenAddr parses a listen address given as host:port or as host, a missing port is
+// replaced by the port of the configured listener
+func ParseListenAddr(addr string, defaultPort uint16) (*apihandler.Listener, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		// not host:port, the whole address is the host
+		host, portStr = addr, ""
+		if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+			host = host[1 : len(host)-1]
+		}
+	}
+	if host == "" {
+		return nil, fmt.Errorf("invalid listen address %q, missing host", addr)
+	}
+	if strings.ContainsAny(host, "[]") || strings.Count(host, ":") == 1 {
+		return nil, fmt.Errorf("invalid listen address %q", addr)
+	}
+	port := defaultPort
+	if portStr != "" {
+		p, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port in listen address %q", addr)
+		}
+		port = uint16(p)
+	}
+	return &apihandler.Listener{Host: host, Port: port}, nil
+}
+
+// bindListeners binds the configured listener or, if set, every address of WithListenAddrs.
+// Listen addresses that can't be bound are logged and skipped as long as one of them binds.
+// The listener of WithListener replaces the configured one and is served in any case.
+func (n *Node) bindListeners(configured *apihandler.Listener) ([]net.Listener, error) {
+	if n.options.listenPort != 0 {
+		listener := *configured
+		listener.Port = n.options.listenPort
+		configured = &listener
+	}
+	if n.options.listener != nil {
+		n.listenerOnce.Do(func() {
+			n.listener = newSharedListener(n.ctx, n.options.listener)
+		})
+		listeners := []net.Listener{n.listener.session()}
+		for _, addr := range n.options.listenAddrs {
+			listener, err := ParseListenAddr(addr, configured.Port)
+			if err == nil {
+				var bound []net.Listener
+				bound, err = n.newListeners(listener)
+				listeners = append(listeners, bound...)
+			}
+			if err != nil {
+				n.log.Warn("could not bind listen address, skipping", zap.String("addr", addr), zap.Error(err))
+			}
+		}
+		return listeners, nil
+	}
+	if len(n.options.listenAddrs) == 0 {
+		listeners, err := n.newListeners(configured)
+		if err != nil {
+			return nil, &StartupError{
+				Phase: StartupPhaseListener,
+				Addr:  net.JoinHostPort(configured.Host, strconv.Itoa(int(configured.Port))),
+				Err:   err,
+			}
+		}
+		return listeners, nil
+	}
+	var listeners []net.Listener
+	var errs []string
+	for _, addr := range n.options.listenAddrs {
+		listener, err := ParseListenAddr(addr, configured.Port)
+		if err == nil {
+			var bound []net.Listener
+			bound, err = n.newListeners(listener)
+			listeners = append(listeners, bound...)
+		}
+		if err != nil {
+			n.log.Warn("could not bind listen address, skipping", zap.String("addr", addr), zap.Error(err))
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(listeners) == 0 {
+		return nil, &StartupError{
+			Phase: StartupPhaseListener,
+			Addr:  strings.Join(n.options.listenAddrs, ", "),
+			Err:   fmt.Errorf("no listen address could be bound: %s", strings.Join(errs, "; ")),
+		}
+	}
+	return listeners, nil
+}
+
+// playgroundURL returns the URL of the playground on the given listener, empty if the
+// playground isn't served
+func (n *Node) playgroundURL(api *apihandler.Api, addr net.Addr) string {
+	if !api.EnableGraphqlEndpoint || n.options.disablePlayground {
+		return ""
+	}
+	path := n.options.playgroundPath
+	if path == "" {
+		path = apihandler.DefaultPlaygroundPath
+	}
+	return n.listenerURL(addr) + path
+}
+
+// healthURL returns the URL of the health check on the given listener
+func (n *Node) healthURL(addr net.Addr) string {
+	return n.listenerURL(addr) + healthCheckEndpoint
+}
+
+func (n *Node) listenerURL(addr net.Addr) string {
+	scheme := "http"
+	if n.certs != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + addr.String()
+}
+
+// writePortFile writes the port of addr to path, e.g. for scripts waiting for the node
+func writePortFile(path string, addr net.Addr) error {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("%s isn't a TCP address", addr)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(tcpAddr.Port)+"\n"), 0644)
+}
+
+// sharedListener accepts the connections of a listener that outlives config reloads and
+// hands them to the session of the current server. http.Server closes its listeners on
+// every reload, sessions make that close stop the server without closing the listener.
+type sharedListener struct {
+	net.Listener
+	ctx   context.Context
+	conns chan net.Conn
+	// done is closed once accepting fails, err is the error
+	done chan struct{}
+	err  error
+}
+
+func newSharedListener(ctx context.Context, l net.Listener) *sharedListener {
+	s := &sharedListener{
+		Listener: l,
+		ctx:      ctx,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+	go s.accept()
+	return s
+}
+
+func (s *sharedListener) accept() {
+	for {
+		conn, err := s.Listener.Accept()
+		if err != nil {
+			s.err = err
+			close(s.done)
+			return
+		}
+		s.handOff(conn)
+	}
+}
+
+// handOff passes conn to the next session accepting a connection
+func (s *sharedListener) handOff(conn net.Conn) {
+	select {
+	case s.conns <- conn:
+	case <-s.ctx.Done():
+		_ = conn.Close()
+	}
+}
+
+func (s *sharedListener) session() *listenerSession {
+	return &listenerSession{shared: s, closed: make(chan struct{})}
+}
+
+// listenerSession is the listener of one server, closing it leaves the shared listener open
+type listenerSession struct {
+	shared    *sharedListener
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *listenerSession) Accept() (net.Conn, error) {
+	select {
+	case <-l.closed:
+		return nil, net.ErrClosed
+	default:
+	}
+	select {
+	case conn := <-l.shared.conns:
+		select {
+		case <-l.closed:
+			// closed while accepting, the connection belongs to the next server
+			go l.shared.handOff(conn)
+			return nil, net.ErrClosed
+		default:
+			return conn, nil
+		}
+	case <-l.closed:
+		return nil, net.ErrClosed
+	case <-l.shared.done:
+		return nil, l.shared.err
+	}
+}
+
+func (l *listenerSession) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *listenerSession) Addr() net.Addr {
+	return l.shared.Addr()
+}
+
+// FreePort asks the OS for a port on host which is free at the time of the call
+func FreePort(host string) (uint16, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("could not select a free port on %s: %w", host, err)
+	}
+	defer l.Close()
+	return uint16(l.Addr().(*net.TCPAddr).Port), nil
+}
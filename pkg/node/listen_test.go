@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+)
+
+func TestParseListenAddr(t *testing.T) {
+	listener, err := ParseListenAddr("192.168.1.10:8080", 9991)
+	require.NoError(t, err)
+	assert.Equal(t, &apihandler.Listener{Host: "192.168.1.10", Port: 8080}, listener)
+
+	listener, err = ParseListenAddr("localhost", 9991)
+	require.NoError(t, err)
+	assert.Equal(t, &apihandler.Listener{Host: "localhost", Port: 9991}, listener)
+
+	listener, err = ParseListenAddr("[::1]", 9991)
+	require.NoError(t, err)
+	assert.Equal(t, &apihandler.Listener{Host: "::1", Port: 9991}, listener)
+
+	for _, addr := range []string{"", ":8080", "localhost:http", "localhost:99999", "[::1"} {
+		_, err := ParseListenAddr(addr, 9991)
+		assert.Error(t, err, addr)
+	}
+}
+
+func TestBindListeners(t *testing.T) {
+	busy, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := New(ctx, BuildInfo{}, "", zap.NewNop())
+	n.options.listenAddrs = []string{busy.Addr().String(), "127.0.0.1:0"}
+
+	listeners, err := n.bindListeners(&apihandler.Listener{Host: "127.0.0.1", Port: 9991})
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	assert.NotEqual(t, busy.Addr().String(), listeners[0].Addr().String())
+	require.NoError(t, listeners[0].Close())
+
+	n.options.listenAddrs = []string{busy.Addr().String()}
+	_, err = n.bindListeners(&apihandler.Listener{Host: "127.0.0.1", Port: 9991})
+	var startupErr *StartupError
+	require.True(t, errors.As(err, &startupErr), "%v", err)
+	assert.Equal(t, StartupPhaseListener, startupErr.Phase)
+	assert.Equal(t, busy.Addr().String(), startupErr.Addr)
+}
+
+func TestBindListenersListenPort(t *testing.T) {
+	port, err := FreePort("127.0.0.1")
+	require.NoError(t, err)
+	require.NotZero(t, port)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := New(ctx, BuildInfo{}, "", zap.NewNop())
+	n.options.listenPort = port
+	listeners, err := n.bindListeners(&apihandler.Listener{Host: "127.0.0.1", Port: 9991})
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	defer listeners[0].Close()
+	assert.Equal(t, int(port), listeners[0].Addr().(*net.TCPAddr).Port)
+	assert.Equal(t, fmt.Sprintf("http://127.0.0.1:%d/health", port), n.healthURL(listeners[0].Addr()))
+}
+
+func TestBindListenersListener(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := New(ctx, BuildInfo{}, "", zap.NewNop())
+	n.options.listener = l
+
+	// every reload serves on the same listener, closing the server of a reload keeps it open
+	for i := 0; i < 2; i++ {
+		listeners, err := n.bindListeners(&apihandler.Listener{Host: "127.0.0.1", Port: 9991})
+		require.NoError(t, err)
+		require.Len(t, listeners, 1)
+		assert.Equal(t, l.Addr(), listeners[0].Addr())
+
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("reload " + strconv.Itoa(i)))
+		})}
+		served := make(chan error, 1)
+		go func() {
+			served <- server.Serve(listeners[0])
+		}()
+
+		res, err := http.Get("http://" + l.Addr().String())
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "reload "+strconv.Itoa(i), string(body))
+
+		require.NoError(t, server.Close())
+		assert.ErrorIs(t, <-served, http.ErrServerClosed)
+	}
+}
+
+func TestWritePortFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "generated", "port")
+	require.NoError(t, writePortFile(path, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9992}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "9992\n", string(data))
+
+	assert.Error(t, writePortFile(path, &net.UnixAddr{Name: "node.sock", Net: "unix"}))
+}
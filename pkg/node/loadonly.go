@@ -0,0 +1,194 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/hooks"
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/pool"
+	"github.com/wundergraph/wundergraph/pkg/validate"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// LoadedNode is a config that has been validated and built, including the schema and
+// the plans of all operations, but is not served
+type LoadedNode struct {
+	Config WunderNodeConfig
+	Routes []Route
+
+	builder       *apihandler.Builder
+	router        *mux.Router
+	streamClosers []chan struct{}
+}
+
+// LoadOnly parses and validates the config at configPath and builds the schema, the plans of
+// all operations and the routes of the API like StartBlocking, but without binding any listener, e.g. for tooling
+// inspecting the config. The config is expected in the generated directory of the WunderGraph
+// directory. Nothing is logged unless WithLogWriter is given. The LoadedNode must be closed.
+func LoadOnly(ctx context.Context, configPath string, opts ...Option) (*LoadedNode, error) {
+	n := &Node{
+		ctx:            ctx,
+		pool:           pool.New(),
+		log:            zap.NewNop(),
+		WundergraphDir: filepath.Dir(filepath.Dir(configPath)),
+	}
+	for i := range opts {
+		opts[i](&n.options)
+	}
+	if n.options.logWriter != nil {
+		n.log = logging.TeeToWriter(n.log, n.options.logWriter)
+	}
+
+	config, err := readConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	n.setApiDevConfigDefaults(config.Api)
+	if valid, messages := validate.ApiConfig(config.Api); !valid {
+		return nil, fmt.Errorf("API config invalid: %s", strings.Join(messages, ", "))
+	}
+
+	api := config.Api
+	hooksClient := hooks.NewClient(api.Options.ServerUrl, n.log)
+	loader := n.newEngineConfigLoader(api, hooksClient)
+	builder := apihandler.NewBuilder(n.pool, n.log, loader, hooksClient, apihandler.BuilderConfig{
+		EnableIntrospection: n.options.enableIntrospection,
+		DevMode:             n.options.devMode,
+	})
+
+	router := mux.NewRouter()
+	internalRouter := router.PathPrefix("/internal").Subrouter()
+	streamClosers, err := builder.BuildAndMountApiHandler(ctx, router, api)
+	loaded := &LoadedNode{
+		Config:        config,
+		builder:       builder,
+		router:        router,
+		streamClosers: streamClosers,
+	}
+	if err != nil {
+		_ = loaded.Close()
+		return nil, fmt.Errorf("BuildAndMountApiHandler: %w", err)
+	}
+	internalClosers, err := apihandler.NewInternalBuilder(n.pool, n.log, hooksClient, loader, n.options.readOnly).BuildAndMountInternalApiHandler(ctx, internalRouter, api)
+	loaded.streamClosers = append(loaded.streamClosers, internalClosers...)
+	if err != nil {
+		_ = loaded.Close()
+		return nil, fmt.Errorf("BuildAndMountInternalApiHandler: %w", err)
+	}
+	n.registerHealthRoutes(router, hooksClient, api.ApiConfigHash)
+
+	loaded.Routes, err = collectRoutes(router)
+	if err != nil {
+		_ = loaded.Close()
+		return nil, err
+	}
+	return loaded, nil
+}
+
+// Operations returns all operations of the config, sorted by name
+func (l *LoadedNode) Operations() []*wgpb.Operation {
+	operations := append([]*wgpb.Operation(nil), l.Config.Api.Operations...)
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Name < operations[j].Name
+	})
+	return operations
+}
+
+// Execute runs the query or mutation with the given name or path in-process, input is the
+// JSON object of its variables. It returns the status code and body of the response. Requests
+// are independent of each other, Execute is safe for concurrent use.
+func (l *LoadedNode) Execute(ctx context.Context, operationName string, input []byte) (int, []byte, error) {
+	operation := findOperation(l.Config.Api, operationName)
+	if operation == nil {
+		return 0, nil, fmt.Errorf("operation %s not found", operationName)
+	}
+	if len(input) == 0 {
+		input = []byte("{}")
+	}
+	operationURL := strings.TrimSuffix(l.Config.Api.Options.PublicNodeUrl, "/") + "/operations/" + operation.Path
+	var req *http.Request
+	var err error
+	switch operation.OperationType {
+	case wgpb.OperationType_QUERY:
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, operationURL+"?"+apihandler.WgVariables+"="+url.QueryEscape(string(input)), nil)
+	case wgpb.OperationType_MUTATION:
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, operationURL, bytes.NewReader(input))
+	default:
+		return 0, nil, fmt.Errorf("operation %s is a %s, only queries and mutations can be executed", operation.Name, operation.OperationType)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res := &scheduledResponse{header: http.Header{}, status: http.StatusOK}
+	l.router.ServeHTTP(res, req)
+	return res.status, res.body.Bytes(), nil
+}
+
+// DataSources returns the data source configurations of the engine
+func (l *LoadedNode) DataSources() []*wgpb.DataSourceConfiguration {
+	if l.Config.Api.EngineConfiguration == nil {
+		return nil
+	}
+	return l.Config.Api.EngineConfiguration.DatasourceConfigurations
+}
+
+func (l *LoadedNode) Close() error {
+	for _, closer := range l.streamClosers {
+		close(closer)
+	}
+	l.streamClosers = nil
+	return l.builder.Close()
+}
+
+// readConfigFile reads the generated config and creates the node config from it
+func readConfigFile(configPath string) (WunderNodeConfig, error) {
+	graphConfig, err := readGraphConfig(configPath)
+	if err != nil {
+		return WunderNodeConfig{}, err
+	}
+	return CreateConfig(graphConfig)
+}
+
+func readGraphConfig(configPath string) (*wgpb.WunderGraphConfiguration, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty config file")
+	}
+	var graphConfig wgpb.WunderGraphConfiguration
+	if err := json.Unmarshal(data, &graphConfig); err != nil {
+		return nil, err
+	}
+	return &graphConfig, nil
+}
+
+// CheckEnvironmentVariables returns an error listing the environment variables referenced
+// by the config at configPath that are unset and have no default value
+func CheckEnvironmentVariables(configPath string) error {
+	graphConfig, err := readGraphConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if missing := loadvariable.MissingEnvironmentVariables(graphConfig); len(missing) > 0 {
+		return fmt.Errorf("required environment variables are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
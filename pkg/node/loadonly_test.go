@@ -0,0 +1,41 @@
+package node
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestLoadOnly(t *testing.T) {
+	port, err := FreePort("127.0.0.1")
+	require.NoError(t, err)
+	generatedDir := filepath.Join(t.TempDir(), "generated")
+	require.NoError(t, os.Mkdir(generatedDir, 0755))
+	configPath := filepath.Join(generatedDir, "wundergraph.config.json")
+
+	writeTestConfig(t, configPath, testGraphConfig("type Query { a: String }", int(port)))
+	loaded, err := LoadOnly(context.Background(), configPath)
+	require.NoError(t, err)
+	defer loaded.Close()
+	assert.NotEmpty(t, loaded.Routes)
+
+	// cookie based auth requires secrets
+	invalid := testGraphConfig("type Query { a: String }", int(port))
+	invalid.Api.AuthenticationConfig.CookieBased.Providers = []*wgpb.AuthProvider{{Id: "github"}}
+	writeTestConfig(t, configPath, invalid)
+	_, err = LoadOnly(context.Background(), configPath)
+	assert.ErrorContains(t, err, "API config invalid")
+
+	// nothing is listening on the port of the config
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))))
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+}
@@ -0,0 +1,32 @@
+package node
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	var logWriter bytes.Buffer
+	n := &Node{log: zap.NewNop()}
+	WithLogger(zap.New(core))(&n.options)
+	WithLogWriter(&logWriter)(&n.options)
+
+	// every config load creates the logger of the node again
+	n.configLogger(zapcore.ErrorLevel).Info("first config")
+	n.configLogger(zapcore.ErrorLevel).Info("reloaded config")
+
+	entries := logs.All()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "reloaded config", entries[1].Message)
+		assert.Equal(t, map[string]interface{}{"component": "@wundergraph/node"}, entries[1].ContextMap())
+	}
+	assert.Equal(t, 1, strings.Count(logWriter.String(), "first config"))
+	assert.Equal(t, 1, strings.Count(logWriter.String(), "reloaded config"))
+}
@@ -0,0 +1,169 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/hooks"
+	"github.com/wundergraph/wundergraph/pkg/watcher"
+)
+
+type mountedConfig struct {
+	prefix     string
+	configPath string
+}
+
+// WithMountedConfig serves the API of an additional config file under the given path prefix,
+// e.g. to run two versions of an API side by side. The config file is watched and
+// reloaded independently of the main config.
+func WithMountedConfig(prefix, configPath string) Option {
+	return func(options *options) {
+		options.mountedConfigs = append(options.mountedConfigs, mountedConfig{
+			prefix:     "/" + strings.Trim(prefix, "/"),
+			configPath: configPath,
+		})
+	}
+}
+
+// mount serves the API of a mounted config. The handler is swapped on every change of the
+// config file, routes of gorilla/mux can't be removed. Mounts outlive reloads of the main
+// config and are closed on shutdown.
+type mount struct {
+	node       *Node
+	prefix     string
+	configPath string
+	log        *zap.Logger
+
+	mu            sync.RWMutex
+	handler       http.Handler
+	builder       *apihandler.Builder
+	streamClosers []chan struct{}
+	builderConfig apihandler.BuilderConfig
+	publicNodeUrl string
+	hosts         []string
+	loadOnce      sync.Once
+}
+
+func (n *Node) newMounts(configs []mountedConfig) []*mount {
+	mounts := make([]*mount, 0, len(configs))
+	for _, config := range configs {
+		mounts = append(mounts, &mount{
+			node:       n,
+			prefix:     config.prefix,
+			configPath: config.configPath,
+			log:        n.log.With(zap.String("mount", config.prefix)),
+			handler:    http.NotFoundHandler(),
+		})
+	}
+	return mounts
+}
+
+func (m *mount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	handler := m.handler
+	m.mu.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// register mounts the handler on the router of the main API. The config is loaded on the first
+// registration only, later ones keep the loaded API and its settings apply with the next change
+// of the config file.
+func (m *mount) register(router *mux.Router, builderConfig apihandler.BuilderConfig, mainApi *apihandler.Api) {
+	m.mu.Lock()
+	m.builderConfig = builderConfig
+	m.publicNodeUrl = strings.TrimSuffix(mainApi.Options.PublicNodeUrl, "/") + m.prefix
+	m.hosts = mainApi.Hosts
+	m.mu.Unlock()
+
+	router.PathPrefix(m.prefix).Handler(http.StripPrefix(m.prefix, m))
+
+	m.loadOnce.Do(func() {
+		if err := m.reload(); err != nil {
+			m.log.Error("could not load mounted config", zap.String("configPath", m.configPath), zap.Error(err))
+		}
+	})
+}
+
+func (m *mount) reload() error {
+	config, err := readConfigFile(m.configPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	api := config.Api
+	// the mount is served by the listener of the main API
+	api.Hosts = m.hosts
+	api.Options.PublicNodeUrl = m.publicNodeUrl
+	m.node.setApiDevConfigDefaults(api)
+
+	hooksClient := hooks.NewClient(api.Options.ServerUrl, m.log)
+	loader := m.node.newEngineConfigLoader(api, hooksClient)
+	builder := apihandler.NewBuilder(m.node.pool, m.log, loader, hooksClient, m.builderConfig)
+
+	router := mux.NewRouter()
+	streamClosers, err := builder.BuildAndMountApiHandler(m.node.ctx, router, api)
+	if err != nil {
+		return fmt.Errorf("BuildAndMountApiHandler: %w", err)
+	}
+
+	m.closeLocked()
+	m.handler = router
+	m.builder = builder
+	m.streamClosers = streamClosers
+
+	fields := []zap.Field{zap.String("configPath", m.configPath)}
+	if !m.builderConfig.DisablePlayground {
+		playgroundPath := m.builderConfig.PlaygroundPath
+		if playgroundPath == "" {
+			playgroundPath = apihandler.DefaultPlaygroundPath
+		}
+		fields = append(fields, zap.String("playground", m.publicNodeUrl+playgroundPath))
+	}
+	m.log.Info("mounted config loaded", fields...)
+	return nil
+}
+
+// watch reloads the mount whenever its config file changes
+func (m *mount) watch(ctx context.Context) error {
+	w := watcher.NewWatcher("mount"+m.prefix, &watcher.Config{
+		WatchPaths: []*watcher.WatchPath{
+			{Path: m.configPath},
+		},
+	}, m.log)
+	return w.Watch(ctx, func(paths []string) error {
+		if err := m.reload(); err != nil {
+			m.log.Error("could not reload mounted config", zap.String("configPath", m.configPath), zap.Error(err))
+		}
+		return nil
+	})
+}
+
+func (m *mount) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closeLocked()
+}
+
+func (m *mount) closeLocked() error {
+	for _, closer := range m.streamClosers {
+		close(closer)
+	}
+	m.streamClosers = nil
+	m.handler = http.NotFoundHandler()
+	if m.builder != nil {
+		builder := m.builder
+		m.builder = nil
+		return builder.Close()
+	}
+	return nil
+}
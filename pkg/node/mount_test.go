@@ -0,0 +1,112 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/pool"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// testGraphConfig returns a config with the given schema, served on port of localhost
+func testGraphConfig(schema string, port int) *wgpb.WunderGraphConfiguration {
+	return &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			EngineConfiguration: &wgpb.EngineConfiguration{GraphqlSchema: schema},
+			NodeOptions: &wgpb.NodeOptions{
+				PublicNodeUrl: staticVariable("http://localhost:" + strconv.Itoa(port)),
+				Listen: &wgpb.ListenerOptions{
+					Host: staticVariable("127.0.0.1"),
+					Port: staticVariable(strconv.Itoa(port)),
+				},
+				Logger: &wgpb.NodeLogging{Level: staticVariable("error")},
+			},
+			ServerOptions: &wgpb.ServerOptions{ServerUrl: staticVariable("")},
+			AuthenticationConfig: &wgpb.ApiAuthenticationConfig{
+				CookieBased: &wgpb.CookieBasedAuthentication{},
+				JwksBased:   &wgpb.JwksBasedAuthentication{},
+				Hooks:       &wgpb.ApiAuthenticationHooks{},
+			},
+		},
+	}
+}
+
+func writeTestConfig(t *testing.T, path string, graphConfig *wgpb.WunderGraphConfiguration) {
+	data, err := json.Marshal(graphConfig)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+}
+
+func (m *mount) currentBuilder() *apihandler.Builder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.builder
+}
+
+func TestMountRegister(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configPath := filepath.Join(t.TempDir(), "v2.json")
+	writeTestConfig(t, configPath, testGraphConfig("type Query { a: String }", 9991))
+
+	n := &Node{ctx: ctx, pool: pool.New(), log: zap.NewNop()}
+	m := n.newMounts([]mountedConfig{{prefix: "/v2", configPath: configPath}})[0]
+	defer m.Close()
+	mainApi := &apihandler.Api{Options: &apihandler.Options{PublicNodeUrl: "http://localhost:9991"}}
+
+	m.register(mux.NewRouter(), apihandler.BuilderConfig{}, mainApi)
+	builder := m.currentBuilder()
+	require.NotNil(t, builder)
+
+	// reloads of the main config attach the loaded mount to the new router without reading its config
+	writeTestConfig(t, configPath, testGraphConfig("type Query { b: String }", 9991))
+	router := mux.NewRouter()
+	m.register(router, apihandler.BuilderConfig{}, mainApi)
+	assert.Same(t, builder, m.currentBuilder())
+
+	var match mux.RouteMatch
+	assert.True(t, router.Match(httptest.NewRequest(http.MethodGet, "/v2/operations/A", nil), &match))
+}
+
+func TestMountWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configPath := filepath.Join(t.TempDir(), "v2.json")
+	writeTestConfig(t, configPath, testGraphConfig("type Query { a: String }", 9991))
+
+	n := &Node{ctx: ctx, pool: pool.New(), log: zap.NewNop(), configCh: make(chan WunderNodeConfig, 1)}
+	m := n.newMounts([]mountedConfig{{prefix: "/v2", configPath: configPath}})[0]
+	defer m.Close()
+	mainBuilder := apihandler.NewBuilder(n.pool, n.log, nil, nil, apihandler.BuilderConfig{})
+	n.builder = mainBuilder
+	m.register(mux.NewRouter(), apihandler.BuilderConfig{}, &apihandler.Api{Options: &apihandler.Options{}})
+	builder := m.currentBuilder()
+	require.NotNil(t, builder)
+
+	go func() {
+		_ = m.watch(ctx)
+	}()
+	// give the watcher time to register the file
+	time.Sleep(200 * time.Millisecond)
+	writeTestConfig(t, configPath, testGraphConfig("type Query { b: String }", 9991))
+
+	// the mount reloads on its own, the main API is neither rebuilt nor restarted
+	assert.Eventually(t, func() bool {
+		return m.currentBuilder() != builder
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Same(t, mainBuilder, n.builder)
+	assert.Len(t, n.configCh, 0)
+}
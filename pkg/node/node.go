@@ -2,13 +2,18 @@ package node
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -18,15 +23,23 @@ import (
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/authentication"
 	"github.com/wundergraph/wundergraph/pkg/engineconfigloader"
 	"github.com/wundergraph/wundergraph/pkg/hooks"
 	"github.com/wundergraph/wundergraph/pkg/httpidletimeout"
+	"github.com/wundergraph/wundergraph/pkg/httpwritetimeout"
 	"github.com/wundergraph/wundergraph/pkg/loadvariable"
 	"github.com/wundergraph/wundergraph/pkg/logging"
 	"github.com/wundergraph/wundergraph/pkg/node/nodetemplates"
+	"github.com/wundergraph/wundergraph/pkg/otlp"
+	"github.com/wundergraph/wundergraph/pkg/persistedqueries"
 	"github.com/wundergraph/wundergraph/pkg/pool"
+	"github.com/wundergraph/wundergraph/pkg/ratelimit"
+	"github.com/wundergraph/wundergraph/pkg/responsecache"
+	"github.com/wundergraph/wundergraph/pkg/tracing"
 	"github.com/wundergraph/wundergraph/pkg/validate"
 	"github.com/wundergraph/wundergraph/pkg/wgpb"
 )
@@ -34,6 +47,9 @@ import (
 const (
 	rootEndpoint        = "/"
 	healthCheckEndpoint = "/health"
+
+	persistedQueriesEndpoint = "/persisted-queries"
+	cachePurgeEndpoint       = "/cache/purge"
 )
 
 func New(ctx context.Context, info BuildInfo, wundergraphDir string, log *zap.Logger) *Node {
@@ -53,6 +69,8 @@ func New(ctx context.Context, info BuildInfo, wundergraphDir string, log *zap.Lo
 			DialDualStack:            true,
 			NoDefaultUserAgentHeader: true,
 		},
+		// buffered so a change is never lost while a reload is running
+		variablesChanged: make(chan struct{}, 1),
 	}
 }
 
@@ -66,7 +84,47 @@ type Node struct {
 	log            *zap.Logger
 	apiClient      *fasthttp.Client
 	options        options
+	mounts         []*mount
 	WundergraphDir string
+
+	cancelScheduledOperations context.CancelFunc
+
+	// lastGoodConfigHash is the hash of the last config that passed validation,
+	// servingStaleConfig is set while a newer config fails to load
+	lastGoodConfigHash string
+	servingStaleConfig bool
+
+	// variableOverrides are set through ConfigVariablesEndpoint and applied to every
+	// config read from the file system, variablesChanged triggers a reload
+	variableOverridesMu sync.Mutex
+	variableOverrides   map[string]string
+	variablesChanged    chan struct{}
+
+	// routes are the routes served with the current config
+	routesMu sync.Mutex
+	routes   []Route
+
+	// certs serves the certificate of WithTLS, nil without TLS
+	certs *certReloader
+
+	// reloading is 1 while a config reload is in progress, see beginReload
+	reloading int32
+
+	// recorder and replayer of WithRecord and WithReplay outlive config reloads,
+	// at most one of them is set
+	recorder *engineconfigloader.Recorder
+	replayer *engineconfigloader.Replayer
+
+	// schemaUpdates notifies the clients of SchemaUpdatesEndpoint of schema changes
+	schemaUpdates schemaUpdates
+
+	// otel exports the spans of requests, nil without WithOTel
+	otel *otlp.Exporter
+
+	// listener hands the connections of WithListener to the server of the current config,
+	// nil without WithListener
+	listenerOnce sync.Once
+	listener     *sharedListener
 }
 
 type options struct {
@@ -90,6 +148,66 @@ type options struct {
 	hooksServerHealthCheck  bool
 	healthCheckTimeout      time.Duration
 	prettyLogging           bool
+	devAuthBypassClaims     map[string]interface{}
+	persistedQueries        bool
+	mountedConfigs          []mountedConfig
+	logWriter               io.Writer
+	logger                  *zap.Logger
+	eventLog                *logging.EventLog
+	tracer                  *tracing.Recorder
+	responseCache           *responsecache.Options
+	latencyInjections       map[string]engineconfigloader.LatencyInjection
+	scheduledOperations     []scheduledOperation
+	upstreamHeaders         http.Header
+	explainOperation        string
+	strictEnv               bool
+	serverTimeouts          ServerTimeouts
+	disablePlayground       bool
+	playgroundPath          string
+	warmPlans               bool
+	rateLimits              map[string]ratelimit.Limit
+	configPush              bool
+	configPushToken         string
+	sseSubscriptions        bool
+	responseHeaders         http.Header
+	devSessionsPath         string
+	devOverridesPath        string
+	operationOverridesPath  string
+	tls                     *tlsOptions
+	reloadCooldown          time.Duration
+	responseTransforms      map[string][]string
+	accessLogFormat         string
+	operationTimeouts       map[string]time.Duration
+	lazyDataSources         bool
+	upstreamProxy           string
+	dataSourceProxies       map[string]string
+	upstreamBase            *url.URL
+	readOnly                bool
+	recordPath              string
+	replayPath              string
+	otel                    *otlp.Config
+	devUI                   *DevUI
+	headerRoutings          map[string]headerRouting
+	listenAddrs             []string
+	listenPort              uint16
+	listener                net.Listener
+	portFile                string
+	featureFlags            []string
+	playgroundDefaults      apihandler.PlaygroundDefaults
+	http2                   bool
+}
+
+type headerRouting struct {
+	header string
+	routes map[string]string
+}
+
+// ServerTimeouts configures the HTTP server of the node, zero disables a timeout
+type ServerTimeouts struct {
+	Read       time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+	ReadHeader time.Duration
 }
 
 type Option func(options *options)
@@ -122,6 +240,156 @@ func WithDevMode() Option {
 	}
 }
 
+// WithDevAuthBypass injects a synthetic user with the given claims into
+// every unauthenticated request. It requires WithDevMode, otherwise the
+// node refuses to start.
+func WithDevAuthBypass(claims map[string]interface{}) Option {
+	return func(options *options) {
+		options.devAuthBypassClaims = claims
+	}
+}
+
+// WithPersistedQueries registers the hashes of all operations in a local
+// persisted query store, written to the generated directory and served
+// at /persisted-queries. The GraphQL endpoint accepts requests by hash.
+func WithPersistedQueries() Option {
+	return func(options *options) {
+		options.persistedQueries = true
+	}
+}
+
+// WithResponseCache caches the responses of GET query operations in memory. Cached
+// responses can be invalidated by surrogate key at /cache/purge.
+func WithResponseCache(opts responsecache.Options) Option {
+	return func(options *options) {
+		options.responseCache = &opts
+	}
+}
+
+// WithLatencyInjection delays all upstream requests of the data source with the given id
+// by delay ± jitter, e.g. to reproduce loading states. Only honored in dev mode.
+func WithLatencyInjection(sourceName string, delay, jitter time.Duration) Option {
+	return func(options *options) {
+		if options.latencyInjections == nil {
+			options.latencyInjections = map[string]engineconfigloader.LatencyInjection{}
+		}
+		options.latencyInjections[sourceName] = engineconfigloader.LatencyInjection{
+			Delay:  delay,
+			Jitter: jitter,
+		}
+	}
+}
+
+// WithResponseTransform rewrites the responses of the data source with the given id with
+// a jq-style expression, see engineconfigloader.ResponseTransform. Responses the transform
+// fails on are passed through unchanged. Only honored in dev mode.
+func WithResponseTransform(sourceName string, jq string) Option {
+	return func(options *options) {
+		if options.responseTransforms == nil {
+			options.responseTransforms = map[string][]string{}
+		}
+		options.responseTransforms[sourceName] = append(options.responseTransforms[sourceName], jq)
+	}
+}
+
+// WithUpstreamHeader sets the header on every outgoing data source request, e.g. to pass
+// a shared dev credential without committing it to the config. Values are never logged.
+// Only honored in dev mode.
+func WithUpstreamHeader(name, value string) Option {
+	return func(options *options) {
+		if options.upstreamHeaders == nil {
+			options.upstreamHeaders = http.Header{}
+		}
+		options.upstreamHeaders.Add(name, value)
+	}
+}
+
+// WithUpstreamProxy sends all data source requests through the proxy at proxyURL instead of
+// the one of the HTTP_PROXY and HTTPS_PROXY environment variables. Hosts excluded by NO_PROXY
+// are still connected directly.
+func WithUpstreamProxy(proxyURL string) Option {
+	return func(options *options) {
+		options.upstreamProxy = proxyURL
+	}
+}
+
+// WithDataSourceProxy sends the requests of the data source with the given id through the
+// proxy at proxyURL, overriding WithUpstreamProxy and the environment for it
+func WithDataSourceProxy(sourceName, proxyURL string) Option {
+	return func(options *options) {
+		if options.dataSourceProxies == nil {
+			options.dataSourceProxies = map[string]string{}
+		}
+		options.dataSourceProxies[sourceName] = proxyURL
+	}
+}
+
+// WithUpstreamBase resolves relative URLs of REST and GraphQL data sources against base, see
+// ParseUpstreamBase. Absolute URLs are kept.
+func WithUpstreamBase(base *url.URL) Option {
+	return func(options *options) {
+		options.upstreamBase = base
+	}
+}
+
+// WithHeaderRouting sends the upstream requests of the data source with the given id to the
+// upstream of routes selected by the value of the header headerName of the client request,
+// e.g. to compare a staging and a local backend from one node. Routes map header values to
+// base URLs replacing the scheme and host of the requests. A source has at most one routing
+// header, later calls replace earlier ones. Only honored in dev mode.
+func WithHeaderRouting(sourceName, headerName string, routes map[string]string) Option {
+	return func(options *options) {
+		if options.headerRoutings == nil {
+			options.headerRoutings = map[string]headerRouting{}
+		}
+		options.headerRoutings[sourceName] = headerRouting{header: headerName, routes: routes}
+	}
+}
+
+// WithRecord writes every upstream request and its response to the cassette at path, the
+// values of sensitive headers are redacted. Only honored in dev mode.
+func WithRecord(path string) Option {
+	return func(options *options) {
+		options.recordPath = path
+	}
+}
+
+// WithReplay answers upstream requests from the cassette at path written by WithRecord.
+// Requests are matched by method, url and body, requests without a recorded interaction
+// fail. Only honored in dev mode.
+func WithReplay(path string) Option {
+	return func(options *options) {
+		options.replayPath = path
+	}
+}
+
+// WithOTel exports spans of request handling, planning, upstream requests and hook calls to
+// the OpenTelemetry collector at endpoint with OTLP over HTTP. Empty arguments fall back to
+// the standard OTEL_* environment variables. Only honored in dev mode.
+func WithOTel(endpoint, serviceName string) Option {
+	return func(options *options) {
+		options.otel = &otlp.Config{Endpoint: endpoint, ServiceName: serviceName}
+	}
+}
+
+// WithDevUI serves a page at DevUIEndpoint showing the build status, recent errors, the watched
+// paths and the datasources of the config with a button to rebuild it. By default it's only
+// served to loopback clients. Only honored in dev mode.
+func WithDevUI(ui DevUI) Option {
+	return func(options *options) {
+		options.devUI = &ui
+	}
+}
+
+// WithExplainOperation prints the execution plan of the operation with the given name
+// to stdout whenever the config is loaded. Only honored in dev mode, the plans of all
+// operations are also served at /explain/<operation>.
+func WithExplainOperation(operationName string) Option {
+	return func(options *options) {
+		options.explainOperation = operationName
+	}
+}
+
 func WithInsecureCookies() Option {
 	return func(options *options) {
 		options.insecureCookies = true
@@ -166,6 +434,262 @@ func WithPrettyLogging(enable bool) Option {
 	}
 }
 
+// WithLogger makes the node log to log after loading a config as well, instead of a logger
+// with the level of the config. It lets embedders route the logs into their own setup.
+func WithLogger(log *zap.Logger) Option {
+	return func(options *options) {
+		options.logger = log
+	}
+}
+
+// WithLogWriter makes the node additionally write all log entries as JSON to w
+func WithLogWriter(w io.Writer) Option {
+	return func(options *options) {
+		options.logWriter = w
+	}
+}
+
+// WithAccessLog writes a line per request to stdout with the method, the operation, the
+// status, the duration and the number of upstream requests, format is one of AccessLogFormats.
+// Health checks and the playground are not logged.
+func WithAccessLog(format string) Option {
+	return func(options *options) {
+		options.accessLogFormat = format
+	}
+}
+
+// WithEventLog makes the node additionally keep its log entries of level info and above in eventLog
+func WithEventLog(eventLog *logging.EventLog) Option {
+	return func(options *options) {
+		options.eventLog = eventLog
+	}
+}
+
+// WithTracer records a span for each (re-)configuration of the server, from receiving
+// the config until the listeners are bound
+func WithTracer(tracer *tracing.Recorder) Option {
+	return func(options *options) {
+		options.tracer = tracer
+	}
+}
+
+// WithStrictEnv makes the node reject configs referencing environment variables that
+// are unset and have no default value, it keeps serving the last known good config instead
+func WithStrictEnv() Option {
+	return func(options *options) {
+		options.strictEnv = true
+	}
+}
+
+// WithServerTimeouts sets the timeouts of the HTTP server, see http.Server. Subscriptions,
+// live queries and WebSocket connections are exempt from the write timeout.
+func WithServerTimeouts(read, write, idle, readHeader time.Duration) Option {
+	return func(options *options) {
+		options.serverTimeouts = ServerTimeouts{
+			Read:       read,
+			Write:      write,
+			Idle:       idle,
+			ReadHeader: readHeader,
+		}
+	}
+}
+
+// WithPlayground enables or disables the GraphQL playground and sets its path, an empty
+// path serves it on GET requests to the GraphQL endpoint
+func WithPlayground(enabled bool, path string) Option {
+	return func(options *options) {
+		options.disablePlayground = !enabled
+		options.playgroundPath = path
+	}
+}
+
+// WithPlaygroundDefaults seeds the initial tab of the playground with the given headers and
+// query, e.g. a placeholder Authorization header. Don't pass secrets, the playground shows them
+// to everyone opening it.
+func WithPlaygroundDefaults(headers map[string]string, query string) Option {
+	return func(options *options) {
+		options.playgroundDefaults = apihandler.PlaygroundDefaults{
+			Headers: headers,
+			Query:   query,
+		}
+	}
+}
+
+// WithListenAddrs binds the node to every address instead of the listener of the config,
+// addresses are given as host:port or as host to use the port of the config
+func WithListenAddrs(addrs ...string) Option {
+	return func(options *options) {
+		options.listenAddrs = addrs
+	}
+}
+
+// WithFeatureFlags enables the operations below operations/flags/<flag>/ of the given flags,
+// next to the ones listed in the FeatureFlagsEnvKey environment variable
+func WithFeatureFlags(flags ...string) Option {
+	return func(options *options) {
+		options.featureFlags = append(options.featureFlags, flags...)
+	}
+}
+
+// WithListenPort binds the node to port instead of the port of the config, listen addresses
+// without a port use it as well
+func WithListenPort(port uint16) Option {
+	return func(options *options) {
+		options.listenPort = port
+	}
+}
+
+// WithListener serves every config on l instead of binding the listener of the config, so
+// a port selected by the OS is kept across config reloads. Listen addresses are bound in
+// addition. The node doesn't close l.
+func WithListener(l net.Listener) Option {
+	return func(options *options) {
+		options.listener = l
+	}
+}
+
+// WithPortFile writes the port the node listens on to path once its listeners are bound
+func WithPortFile(path string) Option {
+	return func(options *options) {
+		options.portFile = path
+	}
+}
+
+// WithReadOnly rejects all mutations with 403 while queries and subscriptions are still served,
+// e.g. to share a dev instance without risking changes to its data
+func WithReadOnly() Option {
+	return func(options *options) {
+		options.readOnly = true
+	}
+}
+
+// WithWarmPlans prepares the plans of the GraphQL endpoint for all operations after
+// loading a config, so the first request of an operation doesn't pay for planning
+func WithWarmPlans() Option {
+	return func(options *options) {
+		options.warmPlans = true
+	}
+}
+
+// WithRateLimit limits the requests of the operation with the given name or path to limit
+// per second with bursts of up to burst requests. Requests exceeding it are rejected with
+// 429 and a Retry-After header, subscriptions count once per connection. Only honored in
+// dev mode.
+func WithRateLimit(operationName string, limit rate.Limit, burst int) Option {
+	return func(options *options) {
+		if options.rateLimits == nil {
+			options.rateLimits = map[string]ratelimit.Limit{}
+		}
+		options.rateLimits[operationName] = ratelimit.Limit{
+			Rate:  limit,
+			Burst: burst,
+		}
+	}
+}
+
+// WithLazyDataSources sets up the connections of data sources on their first request instead
+// of loading the config, so a data source with e.g. an invalid mTLS configuration only fails
+// its own requests. Database engines are always started on first use.
+func WithLazyDataSources() Option {
+	return func(options *options) {
+		options.lazyDataSources = true
+	}
+}
+
+// WithOperationTimeout cancels requests of the query, mutation or function with the given
+// name or path after timeout and responds with 504. Unlike the upstream timeout, it limits the
+// whole request including hooks. Only honored in dev mode.
+func WithOperationTimeout(operationName string, timeout time.Duration) Option {
+	return func(options *options) {
+		if options.operationTimeouts == nil {
+			options.operationTimeouts = map[string]time.Duration{}
+		}
+		options.operationTimeouts[operationName] = timeout
+	}
+}
+
+// WithConfigPush accepts configs pushed to ConfigEndpoint, e.g. by 'wunderctl up --attach'.
+// Pushes must send token in the ConfigPushTokenHeader header, the node refuses to start
+// without one.
+func WithConfigPush(token string) Option {
+	return func(options *options) {
+		options.configPush = true
+		options.configPushToken = token
+	}
+}
+
+// WithSSESubscriptions serves the GraphQL endpoint over the GraphQL over SSE protocol at
+// /graphql/stream and to clients accepting text/event-stream. Only honored in dev mode.
+func WithSSESubscriptions() Option {
+	return func(options *options) {
+		options.sseSubscriptions = true
+	}
+}
+
+// WithResponseHeader sets the header on every response, e.g. to mirror the headers of a
+// production edge. Headers set by the node itself are never replaced. Only honored in
+// dev mode.
+func WithResponseHeader(name, value string) Option {
+	return func(options *options) {
+		if options.responseHeaders == nil {
+			options.responseHeaders = http.Header{}
+		}
+		options.responseHeaders.Add(name, value)
+	}
+}
+
+// WithPersistentDevSessions keeps the keys of cookie based sessions in the file at path,
+// so logins survive reloads and restarts. The keys are insecure by design and take
+// precedence over the ones in the config. Only honored in dev mode.
+func WithPersistentDevSessions(path string) Option {
+	return func(options *options) {
+		options.devSessionsPath = path
+	}
+}
+
+// WithDevOverrides applies the DevOverrides in the file at path to every config read from
+// the file system. Only honored in dev mode.
+func WithDevOverrides(path string) Option {
+	return func(options *options) {
+		options.devOverridesPath = path
+	}
+}
+
+// WithOperationOverrides layers the OperationOverrides in the file at path onto the operations
+// of every config read from the file system, e.g. the ones of OperationOverridesFileName
+func WithOperationOverrides(path string) Option {
+	return func(options *options) {
+		options.operationOverridesPath = path
+	}
+}
+
+// WithTLS serves HTTPS with the certificate and key in the given PEM files, e.g. created
+// by mkcert. Changes to the files are picked up by new connections without a restart.
+func WithTLS(certFile, keyFile string) Option {
+	return func(options *options) {
+		options.tls = &tlsOptions{
+			certFile: certFile,
+			keyFile:  keyFile,
+		}
+	}
+}
+
+// WithHTTP2 serves HTTP/2 next to HTTP/1.1, negotiated via ALPN with WithTLS and as h2c
+// over cleartext without it
+func WithHTTP2() Option {
+	return func(options *options) {
+		options.http2 = true
+	}
+}
+
+// WithReloadCooldown holds back config reloads for cooldown after a successful one.
+// Changes during the cooldown are applied together by a single reload once it has passed.
+func WithReloadCooldown(cooldown time.Duration) Option {
+	return func(options *options) {
+		options.reloadCooldown = cooldown
+	}
+}
+
 func WithForceHttpsRedirects(forceHttpsRedirects bool) Option {
 	return func(options *options) {
 		options.forceHttpsRedirects = forceHttpsRedirects
@@ -191,14 +715,125 @@ func (n *Node) StartBlocking(opts ...Option) error {
 
 	n.options = options
 
+	if options.devAuthBypassClaims != nil && !options.devMode {
+		return &StartupError{Phase: StartupPhaseOptions, Err: errors.New("auth bypass is only allowed in dev mode")}
+	}
+
+	if options.configPush && options.configPushToken == "" {
+		// anyone reaching the node could replace its config, including the upstream URLs
+		return &StartupError{Phase: StartupPhaseOptions, Err: errors.New("config push requires a token")}
+	}
+
+	if options.devSessionsPath != "" && !options.devMode {
+		n.log.Warn("persistent dev sessions are only available in dev mode, ignoring")
+	}
+
+	if options.devOverridesPath != "" && !options.devMode {
+		n.log.Warn("dev overrides are only available in dev mode, ignoring")
+	}
+
+	if options.recordPath != "" && options.replayPath != "" {
+		return &StartupError{Phase: StartupPhaseOptions, Err: errors.New("can't record and replay upstream interactions at the same time")}
+	}
+
+	if options.recordPath != "" || options.replayPath != "" {
+		if !options.devMode {
+			n.log.Warn("recording and replaying upstream interactions is only available in dev mode, ignoring")
+		} else if options.recordPath != "" {
+			n.recorder = engineconfigloader.NewRecorder(options.recordPath)
+			n.log.Info("recording upstream interactions", zap.String("cassette", options.recordPath))
+		} else {
+			cassette, err := engineconfigloader.LoadCassette(options.replayPath)
+			if err != nil {
+				return &StartupError{Phase: StartupPhaseOptions, File: options.replayPath, Err: fmt.Errorf("could not load cassette: %w", err)}
+			}
+			n.replayer = engineconfigloader.NewReplayer(cassette)
+			n.log.Info("replaying upstream interactions",
+				zap.String("cassette", options.replayPath),
+				zap.Int("interactions", len(cassette.Interactions)),
+			)
+		}
+	}
+
 	g := errgroup.Group{}
 
+	if options.otel != nil {
+		if !options.devMode {
+			n.log.Warn("exporting traces is only available in dev mode, ignoring")
+		} else {
+			exporter, err := n.newOTelExporter(*options.otel)
+			if err != nil {
+				return &StartupError{Phase: StartupPhaseOptions, Err: err}
+			}
+			if exporter != nil {
+				// the exporter outlives the servers replaced on reloads
+				n.otel = exporter
+				n.log.Info("exporting traces", zap.String("endpoint", exporter.URL()))
+				g.Go(func() error {
+					exporter.Run(n.ctx)
+					return nil
+				})
+			}
+		}
+	}
+
+	if options.tls != nil {
+		certs, err := newCertReloader(n.log, options.tls.certFile, options.tls.keyFile)
+		if err != nil {
+			return &StartupError{Phase: StartupPhaseTLS, File: options.tls.certFile, Err: err}
+		}
+		// the certificate outlives the servers replaced on reloads
+		n.certs = certs
+		g.Go(func() error {
+			if err := certs.watch(n.ctx); err != nil {
+				n.log.Error("could not watch TLS certificate", zap.String("certFile", options.tls.certFile), zap.Error(err))
+			}
+			return nil
+		})
+	}
+
+	n.mounts = n.newMounts(options.mountedConfigs)
+	for _, m := range n.mounts {
+		m := m
+		g.Go(func() error {
+			if err := m.watch(n.ctx); err != nil {
+				m.log.Error("could not watch mounted config", zap.String("configPath", m.configPath), zap.Error(err))
+			}
+			<-n.ctx.Done()
+			if err := m.Close(); err != nil {
+				m.log.Error("could not close mounted config", zap.String("configPath", m.configPath), zap.Error(err))
+			}
+			return nil
+		})
+	}
+
 	switch {
 	case options.staticConfig != nil:
 		n.log.Info("Api config: static")
 
-		g.Go(func() error {
-			err := n.startServer(*options.staticConfig)
+		if options.configPush {
+			// pushed configs replace the static one through the regular reload
+			g.Go(func() (err error) {
+				defer logging.Recover(n.log, "node-reconfigure", func(panicErr error) { err = panicErr })
+				err = n.reconfigureOnConfigUpdate()
+				if err != nil {
+					n.log.Error("could not reconfigure config update",
+						zap.Error(err),
+					)
+					return err
+				}
+				return nil
+			})
+			select {
+			case n.configCh <- *options.staticConfig:
+			case <-n.ctx.Done():
+			}
+			break
+		}
+
+		g.Go(func() (err error) {
+			defer logging.Recover(n.log, "node-server", func(panicErr error) { err = panicErr })
+			err = n.startServer(*options.staticConfig)
 			if err != nil {
 				n.log.Error("could not start a node",
 					zap.Error(err),
@@ -212,8 +847,9 @@ func (n *Node) StartBlocking(opts ...Option) error {
 			zap.String("config_file_name", *options.fileSystemConfig),
 		)
 		if options.configFileChange != nil {
-			g.Go(func() error {
-				err := n.reconfigureOnConfigUpdate()
+			g.Go(func() (err error) {
+				defer logging.Recover(n.log, "node-reconfigure", func(panicErr error) { err = panicErr })
+				err = n.reconfigureOnConfigUpdate()
 				if err != nil {
 					n.log.Error("could not reconfigure config update",
 						zap.Error(err),
@@ -223,8 +859,9 @@ func (n *Node) StartBlocking(opts ...Option) error {
 				return nil
 			})
 
-			g.Go(func() error {
-				err := n.filePollConfig(*options.fileSystemConfig)
+			g.Go(func() (err error) {
+				defer logging.Recover(n.log, "node-config-poller", func(panicErr error) { err = panicErr })
+				err = n.filePollConfig(*options.fileSystemConfig)
 				if err != nil {
 					n.log.Error("could not load config",
 						zap.Error(err),
@@ -235,7 +872,7 @@ func (n *Node) StartBlocking(opts ...Option) error {
 			})
 		}
 	default:
-		return errors.New("could not start a node. no config present")
+		return &StartupError{Phase: StartupPhaseConfig, Err: errors.New("no config present")}
 	}
 
 	return g.Wait()
@@ -249,6 +886,10 @@ func (n *Node) Shutdown(ctx context.Context) error {
 }
 
 func (n *Node) Close() error {
+	if n.cancelScheduledOperations != nil {
+		n.cancelScheduledOperations()
+		n.cancelScheduledOperations = nil
+	}
 	if n.builder != nil {
 		if err := n.builder.Close(); err != nil {
 			return err
@@ -336,10 +977,63 @@ func (n *Node) HandleGracefulShutdown(gracefulTimeoutInSeconds int) {
 	n.log.Info("WunderNode shutdown complete")
 }
 
-func (n *Node) GetHealthReport(ctx context.Context, hooksClient *hooks.Client) (*HealthCheckReport, bool) {
-	deploymentId := os.Getenv("WG_CLOUD_DEPLOYMENT_ID")
-	commitSHA := os.Getenv("WG_CLOUD_DEPLOYMENT_COMMIT_SHA")
-	commitURL := os.Getenv("WG_CLOUD_DEPLOYMENT_COMMIT_URL")
+// configLogger returns the logger of the node for a loaded config. It's created with the level
+// of the config unless one was injected with WithLogger, which is kept across reloads. The log
+// writer and the event log receive the entries either way.
+func (n *Node) configLogger(logLevel zapcore.Level) *zap.Logger {
+	log := n.options.logger
+	if log == nil {
+		log = logging.New(n.options.prettyLogging, n.options.enableDebugMode, logLevel)
+	}
+	if n.options.logWriter != nil {
+		log = logging.TeeToWriter(log, n.options.logWriter)
+	}
+	if n.options.eventLog != nil {
+		log = logging.TeeToEventLog(log, n.options.eventLog)
+	}
+	return log.With(zap.String("component", "@wundergraph/node"))
+}
+
+// registerHealthRoutes registers the status page and the health checks
+func (n *Node) registerHealthRoutes(router *mux.Router, hooksClient *hooks.Client, configHash string) {
+	router.Handle(rootEndpoint, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		template, err := nodetemplates.GetTemplateByPath(rootEndpoint)
+		if err != nil {
+			n.log.Error("GetTemplateByPath", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		report, healthy := n.GetHealthReport(r.Context(), hooksClient)
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.Header().Set("Content-Type", "text/html")
+
+		if err := template.Execute(w, report); err != nil {
+			n.log.Error("template.Execute", zap.Error(err))
+			return
+		}
+	}))
+
+	router.Handle(healthCheckEndpoint, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, healthy := n.GetHealthReport(r.Context(), hooksClient)
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		_ = json.NewEncoder(w).Encode(report)
+	}))
+	router.Handle(readinessEndpoint, n.readinessHandler(hooksClient, configHash))
+	router.Handle(livenessEndpoint, livenessHandler())
+}
+
+func (n *Node) GetHealthReport(ctx context.Context, hooksClient *hooks.Client) (*HealthCheckReport, bool) {
+	deploymentId := os.Getenv("WG_CLOUD_DEPLOYMENT_ID")
+	commitSHA := os.Getenv("WG_CLOUD_DEPLOYMENT_COMMIT_SHA")
+	commitURL := os.Getenv("WG_CLOUD_DEPLOYMENT_COMMIT_URL")
 	healthCheck := &HealthCheckReport{
 		ServerStatus: "NOT_READY",
 		// For now we assume that the server is ready
@@ -368,14 +1062,22 @@ func (n *Node) GetHealthReport(ctx context.Context, hooksClient *hooks.Client) (
 }
 
 func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
+	endReloadSpan := n.options.tracer.Start(tracing.SpanNodeReload, "node")
+	defer endReloadSpan()
+	// the reload ends once the server listens, or if it fails to start
+	reloadEnded := false
+	defer func() {
+		if !reloadEnded {
+			n.endReload()
+		}
+	}()
+
 	logLevel := nodeConfig.Api.Options.Logging.Level
 	if n.options.enableDebugMode {
 		logLevel = zapcore.DebugLevel
 	}
 
-	n.log = logging.
-		New(n.options.prettyLogging, n.options.enableDebugMode, logLevel).
-		With(zap.String("component", "@wundergraph/node"))
+	n.log = n.configLogger(logLevel)
 
 	router := mux.NewRouter()
 
@@ -396,47 +1098,94 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 
 	var streamClosers []chan struct{}
 
-	n.setApiDevConfigDefaults(nodeConfig.Api)
+	if err := n.validateConfig(nodeConfig); err != nil {
+		return startupError(StartupPhaseConfig, err)
+	}
 
-	valid, messages := validate.ApiConfig(nodeConfig.Api)
+	if err := validateHooksServerURL(nodeConfig.Api.Options.ServerUrl); err != nil {
+		return err
+	}
+	hooksClient := hooks.NewClient(nodeConfig.Api.Options.ServerUrl, n.log)
 
-	if !valid {
-		n.log.Error("API config invalid",
-			zap.Strings("errors", messages),
+	loader := n.newEngineConfigLoader(nodeConfig.Api, hooksClient)
+
+	var devAuthBypassUser *authentication.User
+	if n.options.devMode && n.options.devAuthBypassClaims != nil {
+		user, err := authentication.UserFromClaims(n.options.devAuthBypassClaims)
+		if err != nil {
+			return &StartupError{Phase: StartupPhaseOptions, Err: fmt.Errorf("invalid auth bypass claims: %w", err)}
+		}
+		devAuthBypassUser = user
+		n.log.Warn("DEV AUTH BYPASS enabled: all unauthenticated requests are served with a synthetic identity",
+			zap.String("userId", devAuthBypassUser.UserID),
 		)
-		return errors.New("API config invalid")
 	}
 
-	dialer := &net.Dialer{
-		Timeout:   10 * time.Second,
-		KeepAlive: 90 * time.Second,
+	var persistedQueries *persistedqueries.Store
+	if n.options.persistedQueries {
+		persistedQueries = n.newPersistedQueryStore(nodeConfig.Api)
+		router.Handle(persistedQueriesEndpoint, persistedQueries).Methods(http.MethodGet)
 	}
 
-	defaultTransport := &http.Transport{
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			return dialer.DialContext(ctx, network, addr)
-		},
-		ForceAttemptHTTP2:   true,
-		MaxIdleConns:        1024,
-		IdleConnTimeout:     90 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
+	if n.options.devMode && n.options.fileSystemConfig != nil {
+		router.Handle(ConfigVariablesEndpoint, n.configVariablesHandler()).Methods(http.MethodPatch)
+		router.Handle(SchemaUpdatesEndpoint, n.schemaUpdates.handler()).Methods(http.MethodGet)
 	}
 
-	hooksClient := hooks.NewClient(nodeConfig.Api.Options.ServerUrl, n.log)
+	if n.options.devUI != nil {
+		if n.options.devMode {
+			n.registerDevUI(router, nodeConfig.Api, time.Now())
+		} else {
+			n.log.Warn("the dev UI is only available in dev mode, ignoring")
+		}
+	}
 
-	transportFactory := apihandler.NewApiTransportFactory(nodeConfig.Api, hooksClient, n.options.enableDebugMode)
+	if n.options.configPush {
+		router.Handle(ConfigEndpoint, n.configPushHandler()).Methods(http.MethodPut)
+	}
 
-	n.log.Debug("http.Client.Transport",
-		zap.Bool("enableDebugMode", n.options.enableDebugMode),
-	)
+	var responseCache *responsecache.Cache
+	if n.options.responseCache != nil {
+		responseCache = responsecache.New(n.log, *n.options.responseCache)
+		router.Handle(cachePurgeEndpoint, n.cachePurgeHandler(responseCache)).Methods(http.MethodPost)
+		n.log.Info("response cache enabled",
+			zap.String("purgeEndpoint", cachePurgeEndpoint),
+			zap.Bool("annotatedOnly", n.options.responseCache.AnnotatedOnly),
+		)
+	}
 
-	loader := engineconfigloader.New(n.WundergraphDir, engineconfigloader.NewDefaultFactoryResolver(
-		transportFactory,
-		defaultTransport,
-		n.options.enableDebugMode,
-		n.log,
-		hooksClient,
-	))
+	var rateLimiter *ratelimit.Limiter
+	if len(n.options.rateLimits) != 0 {
+		if n.options.devMode {
+			rateLimiter = ratelimit.New(n.log, n.options.rateLimits)
+			for operationName, limit := range n.options.rateLimits {
+				n.log.Warn("rate limiting operation",
+					zap.String("operation", operationName),
+					zap.Float64("perSecond", float64(limit.Rate)),
+					zap.Int("burst", limit.Burst),
+				)
+			}
+		} else {
+			n.log.Warn("operation rate limits are only available in dev mode, ignoring")
+		}
+	}
+
+	if len(n.options.operationTimeouts) != 0 {
+		if n.options.devMode {
+			for operationName, timeout := range n.options.operationTimeouts {
+				n.log.Info("operation timeout",
+					zap.String("operation", operationName),
+					zap.Duration("timeout", timeout),
+				)
+			}
+		} else {
+			n.log.Warn("operation timeouts are only available in dev mode, ignoring")
+		}
+	}
+
+	if n.options.sseSubscriptions && !n.options.devMode {
+		n.log.Warn("GraphQL over SSE is only available in dev mode, ignoring")
+	}
 
 	builderConfig := apihandler.BuilderConfig{
 		InsecureCookies:            n.options.insecureCookies,
@@ -446,10 +1195,26 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 		GitHubAuthDemoClientID:     n.options.githubAuthDemo.ClientID,
 		GitHubAuthDemoClientSecret: n.options.githubAuthDemo.ClientSecret,
 		DevMode:                    n.options.devMode,
+		DevAuthBypassUser:          devAuthBypassUser,
+		PersistedQueries:           persistedQueries,
+		ResponseCache:              responseCache,
+		RateLimiter:                rateLimiter,
+		OperationTimeouts:          n.options.operationTimeouts,
+		SSESubscriptions:           n.options.sseSubscriptions,
+		DisablePlayground:          n.options.disablePlayground,
+		PlaygroundPath:             n.options.playgroundPath,
+		FeatureFlags:               n.featureFlags(),
+		PlaygroundDefaults:         n.options.playgroundDefaults,
+		ReadOnly:                   n.options.readOnly,
+	}
+
+	// mounts are registered first to take precedence over the catch-all router of the main API
+	for _, m := range n.mounts {
+		m.register(router, builderConfig, nodeConfig.Api)
 	}
 
 	n.builder = apihandler.NewBuilder(n.pool, n.log, loader, hooksClient, builderConfig)
-	internalBuilder := apihandler.NewInternalBuilder(n.pool, n.log, hooksClient, loader)
+	internalBuilder := apihandler.NewInternalBuilder(n.pool, n.log, hooksClient, loader, n.options.readOnly)
 
 	publicClosers, err := n.builder.BuildAndMountApiHandler(n.ctx, router, nodeConfig.Api)
 	if err != nil {
@@ -457,6 +1222,14 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 	}
 	streamClosers = append(streamClosers, publicClosers...)
 
+	if n.options.warmPlans {
+		n.warmPlans()
+	}
+
+	if n.options.devMode && n.options.explainOperation != "" {
+		n.explainOperation(n.options.explainOperation)
+	}
+
 	internalClosers, err := internalBuilder.BuildAndMountInternalApiHandler(n.ctx, internalRouter, nodeConfig.Api)
 	if err != nil {
 		n.log.Error("BuildAndMountInternalApiHandler", zap.Error(err))
@@ -464,56 +1237,66 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 
 	streamClosers = append(streamClosers, internalClosers...)
 
+	scheduleCtx, cancelScheduledOperations := context.WithCancel(n.ctx)
+	n.cancelScheduledOperations = cancelScheduledOperations
+	n.startScheduledOperations(scheduleCtx, router, nodeConfig.Api)
+
 	defer func() {
 		for _, closer := range streamClosers {
 			close(closer)
 		}
 	}()
 
-	router.Handle(rootEndpoint, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		template, err := nodetemplates.GetTemplateByPath(rootEndpoint)
-		if err != nil {
-			n.log.Error("GetTemplateByPath", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	n.registerHealthRoutes(router, hooksClient, nodeConfig.Api.ApiConfigHash)
+	n.setRoutes(router)
 
-		report, healthy := n.GetHealthReport(r.Context(), hooksClient)
-		if !healthy {
-			w.WriteHeader(http.StatusServiceUnavailable)
-		}
-		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-		w.Header().Set("Content-Type", "text/html")
-
-		if err := template.Execute(w, report); err != nil {
-			n.log.Error("template.Execute", zap.Error(err))
-			return
+	var handler http.Handler = router
+	if len(n.options.responseHeaders) != 0 {
+		if n.options.devMode {
+			handler = newResponseHeaderInjector(n.log, n.options.responseHeaders).Handler(handler)
+		} else {
+			n.log.Warn("response headers are only available in dev mode, ignoring")
 		}
-	}))
-
-	router.Handle(healthCheckEndpoint, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		report, healthy := n.GetHealthReport(r.Context(), hooksClient)
-		if !healthy {
-			w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if n.options.serverTimeouts.Write > 0 {
+		// http.Server.WriteTimeout can't be lifted for streaming responses
+		handler = httpwritetimeout.New(n.options.serverTimeouts.Write).Handler(handler)
+	}
+	if n.otel != nil {
+		handler = n.otelHandler(handler)
+	}
+	if n.options.accessLogFormat != "" {
+		accessLog, err := newAccessLog(n.options.accessLogFormat, os.Stdout, n.skipAccessLog)
+		if err != nil {
+			n.log.Warn("ignoring access log", zap.Error(err))
+		} else {
+			handler = accessLog.Handler(handler)
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-		_ = json.NewEncoder(w).Encode(report)
-	}))
+	}
 
 	n.server = &http.Server{
-		Handler: router,
+		Handler: handler,
 		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			ctx = httpwritetimeout.ConnContext(ctx, c)
 			return context.WithValue(ctx, "conn", c)
 		},
+		ReadTimeout:       n.options.serverTimeouts.Read,
+		IdleTimeout:       n.options.serverTimeouts.Idle,
+		ReadHeaderTimeout: n.options.serverTimeouts.ReadHeader,
 		// ErrorLog: log.New(ioutil.Discard, "", log.LstdFlags),
 	}
+	if n.certs != nil {
+		n.server.TLSConfig = n.certs.tlsConfig()
+	}
+	if n.options.http2 {
+		if err := configureHTTP2(n.server, n.certs != nil); err != nil {
+			return startupError(StartupPhaseListener, err)
+		}
+	}
 
 	if n.options.idleTimeout > 0 {
 		opts := []httpidletimeout.Option{
-			httpidletimeout.WithSkip(func(r *http.Request) bool {
-				return r.URL.Path == healthCheckEndpoint
-			}),
+			httpidletimeout.WithSkip(isHealthCheck),
 		}
 		timeoutMiddleware := httpidletimeout.New(n.options.idleTimeout, opts...)
 		router.Use(timeoutMiddleware.Handler)
@@ -525,21 +1308,42 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 		}()
 	}
 
-	listeners, err := n.newListeners(nodeConfig.Api.Options.Listener)
+	listeners, err := n.bindListeners(nodeConfig.Api.Options.Listener)
 	if err != nil {
 		return err
 	}
+	if n.options.portFile != "" {
+		if err := writePortFile(n.options.portFile, listeners[0].Addr()); err != nil {
+			n.log.Warn("could not write port file", zap.String("portFile", n.options.portFile), zap.Error(err))
+		}
+	}
+	n.endReload()
+	reloadEnded = true
+	endReloadSpan()
 
 	g, _ := errgroup.WithContext(n.ctx)
 
 	for _, listener := range listeners {
 		l := listener
 		g.Go(func() error {
-			n.log.Info("listening on",
+			fields := []zap.Field{
 				zap.String("addr", l.Addr().String()),
-			)
-
-			err := n.server.Serve(l)
+				zap.Bool("tls", n.certs != nil),
+				zap.Bool("http2", n.options.http2),
+				zap.String("health", n.healthURL(l.Addr())),
+			}
+			if playgroundURL := n.playgroundURL(nodeConfig.Api, l.Addr()); playgroundURL != "" {
+				fields = append(fields, zap.String("playground", playgroundURL))
+			}
+			n.log.Info("listening on", fields...)
+
+			var err error
+			if n.certs != nil {
+				// the certificate is provided by TLSConfig.GetCertificate
+				err = n.server.ServeTLS(l, "", "")
+			} else {
+				err = n.server.Serve(l)
+			}
 			if err == nil {
 				return nil
 			}
@@ -549,7 +1353,7 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 				)
 				return nil
 			}
-			return err
+			return &StartupError{Phase: StartupPhaseListener, Addr: l.Addr().String(), Err: err}
 		})
 	}
 
@@ -560,12 +1364,192 @@ func (n *Node) startServer(nodeConfig WunderNodeConfig) error {
 	return g.Wait()
 }
 
+func (n *Node) warmPlans() {
+	start := time.Now()
+	warmed := n.builder.WarmPlans(n.ctx)
+	n.log.Info("query plans warmed",
+		zap.Int("plans", warmed),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
+
+func (n *Node) explainOperation(operationName string) {
+	explanation, ok := n.builder.Explanation(operationName)
+	if !ok {
+		n.log.Warn("could not explain operation, it doesn't exist or failed to plan", zap.String("operation", operationName))
+		return
+	}
+	if err := explanation.WriteText(os.Stdout); err != nil {
+		n.log.Error("could not write plan", zap.String("operation", operationName), zap.Error(err))
+	}
+}
+
+// newEngineConfigLoader creates the loader to build the datasources of the given api
+func (n *Node) newEngineConfigLoader(api *apihandler.Api, hooksClient *hooks.Client) *engineconfigloader.EngineConfigLoader {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 90 * time.Second,
+	}
+
+	defaultTransport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        1024,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		Proxy:               http.ProxyFromEnvironment,
+	}
+
+	if n.options.upstreamProxy != "" {
+		proxy, err := engineconfigloader.NewProxyFunc(n.options.upstreamProxy)
+		if err != nil {
+			n.log.Error("ignoring upstream proxy", zap.Error(err))
+		} else {
+			defaultTransport.Proxy = proxy
+			n.log.Debug("sending upstream requests through proxy")
+		}
+	}
+
+	var transportFactory engineconfigloader.ApiTransportFactory = apihandler.NewApiTransportFactory(api, hooksClient, n.options.enableDebugMode)
+
+	if len(n.options.upstreamHeaders) != 0 {
+		if n.options.devMode {
+			transportFactory = engineconfigloader.NewHeaderInjectingTransportFactory(transportFactory, n.options.upstreamHeaders)
+			for name := range n.options.upstreamHeaders {
+				n.log.Warn("injecting header into upstream requests",
+					zap.String("header", name),
+					zap.String("value", "[REDACTED]"),
+				)
+			}
+		} else {
+			n.log.Warn("upstream headers are only available in dev mode, ignoring")
+		}
+	}
+
+	if n.recorder != nil || n.replayer != nil {
+		transportFactory = engineconfigloader.NewCassetteTransportFactory(transportFactory, n.recorder, n.replayer)
+	}
+
+	if n.otel != nil {
+		transportFactory = engineconfigloader.NewTracingTransportFactory(transportFactory)
+	}
+
+	n.log.Debug("http.Client.Transport",
+		zap.Bool("enableDebugMode", n.options.enableDebugMode),
+	)
+
+	resolver := engineconfigloader.NewDefaultFactoryResolver(
+		transportFactory,
+		defaultTransport,
+		n.options.enableDebugMode,
+		n.log,
+		hooksClient,
+	)
+
+	for sourceName, proxyURL := range n.options.dataSourceProxies {
+		proxy, err := engineconfigloader.NewProxyFunc(proxyURL)
+		if err != nil {
+			n.log.Error("ignoring data source proxy", zap.String("dataSourceId", sourceName), zap.Error(err))
+			continue
+		}
+		resolver.UseProxy(sourceName, proxy)
+		n.log.Debug("sending data source requests through proxy", zap.String("dataSourceId", sourceName))
+	}
+
+	if n.options.lazyDataSources {
+		resolver.ConnectLazily()
+		n.log.Debug("connecting to data sources lazily")
+	}
+
+	if n.options.devMode {
+		for sourceName, latency := range n.options.latencyInjections {
+			resolver.InjectLatency(sourceName, latency)
+			n.log.Warn("injecting latency into upstream requests",
+				zap.String("dataSourceId", sourceName),
+				zap.Duration("delay", latency.Delay),
+				zap.Duration("jitter", latency.Jitter),
+			)
+		}
+	} else if len(n.options.latencyInjections) != 0 {
+		n.log.Warn("latency injection is only available in dev mode, ignoring")
+	}
+
+	if n.options.devMode {
+		for sourceName, headerRouting := range n.options.headerRoutings {
+			routing, err := engineconfigloader.ParseHeaderRouting(headerRouting.header, headerRouting.routes)
+			if err != nil {
+				n.log.Error("ignoring header routing", zap.String("dataSourceId", sourceName), zap.Error(err))
+				continue
+			}
+			resolver.RouteByHeader(sourceName, routing)
+			n.log.Warn("routing upstream requests by header",
+				zap.String("dataSourceId", sourceName),
+				zap.String("header", routing.Header),
+				zap.Strings("values", routing.Values()),
+			)
+		}
+	} else if len(n.options.headerRoutings) != 0 {
+		n.log.Warn("header routing is only available in dev mode, ignoring")
+	}
+
+	if n.options.devMode {
+		for sourceName, expressions := range n.options.responseTransforms {
+			for _, expression := range expressions {
+				transform, err := engineconfigloader.ParseResponseTransform(expression)
+				if err != nil {
+					n.log.Error("ignoring response transform", zap.String("dataSourceId", sourceName), zap.Error(err))
+					continue
+				}
+				resolver.TransformResponses(sourceName, transform)
+				n.log.Warn("transforming upstream responses",
+					zap.String("dataSourceId", sourceName),
+					zap.String("transform", transform.Expression),
+				)
+			}
+		}
+	} else if len(n.options.responseTransforms) != 0 {
+		n.log.Warn("response transforms are only available in dev mode, ignoring")
+	}
+
+	return engineconfigloader.New(n.WundergraphDir, resolver)
+}
+
+// newPersistedQueryStore registers all GraphQL operations and writes the
+// resulting hashes to the generated directory
+func (n *Node) newPersistedQueryStore(api *apihandler.Api) *persistedqueries.Store {
+	store := persistedqueries.New(n.log)
+	for _, operation := range api.Operations {
+		if operation.Engine == wgpb.OperationExecutionEngine_ENGINE_NODEJS {
+			continue
+		}
+		hash := store.Register(operation.Content)
+		n.log.Debug("registered persisted query",
+			zap.String("operation", operation.Name),
+			zap.String("hash", hash),
+		)
+	}
+	if !api.EnableGraphqlEndpoint {
+		n.log.Warn("persisted queries are registered but the GraphQL endpoint is disabled, requests by hash are not accepted")
+	}
+	manifestPath := filepath.Join(n.WundergraphDir, "generated", persistedqueries.FileName)
+	if err := store.WriteFile(manifestPath); err != nil {
+		n.log.Error("could not write persisted queries", zap.String("filePath", manifestPath), zap.Error(err))
+	}
+	return store
+}
+
 // setApiDevConfigDefaults sets default values for the api config in dev mode
 func (n *Node) setApiDevConfigDefaults(api *apihandler.Api) {
 	if n.options.devMode {
 
 		// we set these values statically so that auth never drops login sessions during development
 		if api.AuthenticationConfig != nil && api.AuthenticationConfig.CookieBased != nil {
+			if n.options.devSessionsPath != "" {
+				n.applyDevSessionKeys(api.AuthenticationConfig.CookieBased)
+			}
+
 			if csrfSecret := loadvariable.String(api.AuthenticationConfig.CookieBased.CsrfSecret); csrfSecret == "" {
 				api.AuthenticationConfig.CookieBased.CsrfSecret = &wgpb.ConfigurationVariable{
 					Kind:                  wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE,
@@ -624,12 +1608,9 @@ func (n *Node) reconfigureOnConfigUpdate() error {
 			_ = n.Close()
 
 			// in a new routine, startServer is blocking
-			g.Go(func() error {
-				err := n.startServer(config)
-				if err != nil {
-					return err
-				}
-				return nil
+			g.Go(func() (err error) {
+				defer logging.Recover(n.log, "node-server", func(panicErr error) { err = panicErr })
+				return n.startServer(config)
 			})
 
 		case <-ctx.Done():
@@ -638,7 +1619,57 @@ func (n *Node) reconfigureOnConfigUpdate() error {
 	}
 }
 
+// validateConfig validates the api config, the same checks run before a config is served
+func (n *Node) validateConfig(config WunderNodeConfig) error {
+	n.setApiDevConfigDefaults(config.Api)
+	valid, messages := validate.ApiConfig(config.Api)
+	if !valid {
+		n.log.Error("API config invalid",
+			zap.Strings("errors", messages),
+		)
+		return errors.New("API config invalid")
+	}
+	return nil
+}
+
+// configHash identifies a config in logs and health checks, e.g. the last known good one
+func configHash(graphConfig *wgpb.WunderGraphConfiguration) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(graphConfig)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// logStaleConfig reports that the config to be served couldn't be loaded and the node keeps
+// serving the last known good config, if any
+func (n *Node) logStaleConfig(err error) {
+	if n.lastGoodConfigHash == "" {
+		n.log.Error("could not load config, no config is served until it's fixed", zap.Error(err))
+		return
+	}
+	n.servingStaleConfig = true
+	n.log.Error("could not load config, the node keeps serving the last known good config",
+		zap.String("configHash", n.lastGoodConfigHash),
+		zap.Error(err),
+	)
+}
+
 func (n *Node) filePollConfig(filePath string) error {
+	cooldown := &reloadCooldown{duration: n.options.reloadCooldown}
+	reload := func() {
+		if cooldown.hold(time.Now()) {
+			n.log.Debug("config reload held back until the cooldown has passed")
+			return
+		}
+		// a broken config never replaces the one being served
+		if err := n.reloadFileConfig(filePath); err != nil {
+			n.logStaleConfig(err)
+			return
+		}
+		cooldown.start(time.Now())
+	}
 	for {
 		select {
 		case <-n.ctx.Done():
@@ -647,36 +1678,84 @@ func (n *Node) filePollConfig(filePath string) error {
 			if !ok {
 				return nil
 			}
-			err := n.reloadFileConfig(filePath)
-			if err != nil {
-				return err
-			}
+			reload()
+		case <-n.variablesChanged:
+			reload()
+		case <-cooldown.pending:
+			cooldown.pending = nil
+			reload()
 		}
 	}
 }
 
-func (n *Node) reloadFileConfig(filePath string) error {
-	data, err := ioutil.ReadFile(filePath)
+func (n *Node) reloadFileConfig(filePath string) (err error) {
+	n.beginReload()
+	defer func() {
+		if err != nil {
+			n.endReload()
+		}
+	}()
+
+	if n.options.strictEnv {
+		if err := CheckEnvironmentVariables(filePath); err != nil {
+			n.log.Error("reloadFileConfig", zap.String("filePath", filePath), zap.Error(err))
+			return err
+		}
+	}
+
+	graphConfig, err := readGraphConfig(filePath)
 	if err != nil {
-		n.log.Error("reloadFileConfig ioutil.ReadFile", zap.String("filePath", filePath), zap.Error(err))
+		n.log.Error("reloadFileConfig", zap.String("filePath", filePath), zap.Error(err))
 		return err
 	}
-	if len(data) == 0 {
-		return errors.New("empty config file")
+	n.applyVariableOverrides(graphConfig)
+
+	if n.options.upstreamBase != nil {
+		if err := n.applyUpstreamBase(graphConfig); err != nil {
+			n.log.Error("reloadFileConfig", zap.String("upstreamBase", n.options.upstreamBase.String()), zap.Error(err))
+			return err
+		}
+	}
+
+	if n.options.devMode && n.options.devOverridesPath != "" {
+		if err := n.applyDevOverrides(graphConfig); err != nil {
+			n.log.Error("reloadFileConfig", zap.String("overridesFile", n.options.devOverridesPath), zap.Error(err))
+			return err
+		}
+	}
+
+	if n.options.operationOverridesPath != "" {
+		if err := n.applyOperationOverrides(graphConfig); err != nil {
+			n.log.Error("reloadFileConfig", zap.String("overridesFile", n.options.operationOverridesPath), zap.Error(err))
+			return err
+		}
+	}
+
+	config, err := CreateConfig(graphConfig)
+	if err != nil {
+		n.log.Error("reloadFileConfig", zap.String("filePath", filePath), zap.Error(err))
+		return err
 	}
-	var graphConfig wgpb.WunderGraphConfiguration
-	err = json.Unmarshal(data, &graphConfig)
+	config.Api.ApiConfigHash, err = configHash(graphConfig)
 	if err != nil {
-		n.log.Error("reloadFileConfig json.Unmarshal", zap.String("filePath", filePath), zap.Error(err))
+		n.log.Error("reloadFileConfig", zap.String("filePath", filePath), zap.Error(err))
 		return err
 	}
 
-	config, err := CreateConfig(&graphConfig)
-	if err != nil {
-		n.log.Error("reloadFileConfig CreateConfig", zap.String("filePath", filePath), zap.Error(err))
+	if err := n.validateConfig(config); err != nil {
 		return err
 	}
 
+	if n.servingStaleConfig {
+		n.log.Info("config is valid again, replacing the last known good config")
+		n.servingStaleConfig = false
+	}
+	n.lastGoodConfigHash = config.Api.ApiConfigHash
+
+	if n.options.devMode {
+		n.schemaUpdates.publish(graphConfig)
+	}
+
 	n.configCh <- config
 
 	return nil
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/gavv/httpexpect/v2"
+	"github.com/gorilla/mux"
 	"github.com/phayes/freeport"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
@@ -24,6 +25,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/hooks"
 	"github.com/wundergraph/wundergraph/pkg/logging"
 	"github.com/wundergraph/wundergraph/pkg/wgpb"
 )
@@ -819,3 +821,50 @@ type User {
   reviews: [Review]
 }
 `
+
+func TestWarmPlans(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := New(ctx, BuildInfo{}, "", zap.NewNop())
+	api := &apihandler.Api{
+		EngineConfiguration:   federationPlanConfiguration("http://localhost:4001", "http://localhost:4002", "http://localhost:4003"),
+		EnableGraphqlEndpoint: true,
+		Operations: []*wgpb.Operation{
+			warmPlansOperation("MyReviews", federationTestQuery),
+			warmPlansOperation("TopProducts", topProductsQuery),
+		},
+		AuthenticationConfig: &wgpb.ApiAuthenticationConfig{
+			CookieBased: &wgpb.CookieBasedAuthentication{},
+			JwksBased:   &wgpb.JwksBasedAuthentication{},
+			Hooks:       &wgpb.ApiAuthenticationHooks{},
+		},
+		Options: &apihandler.Options{
+			Listener: &apihandler.Listener{Host: "localhost", Port: 9991},
+			Logging:  apihandler.Logging{Level: zap.ErrorLevel},
+		},
+	}
+	n.setApiDevConfigDefaults(api)
+	hooksClient := hooks.NewClient(api.Options.ServerUrl, zap.NewNop())
+	builder := apihandler.NewBuilder(n.pool, zap.NewNop(), n.newEngineConfigLoader(api, hooksClient), hooksClient, apihandler.BuilderConfig{})
+	_, err := builder.BuildAndMountApiHandler(ctx, mux.NewRouter(), api)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, builder.WarmPlans(ctx))
+	// warming again finds the prepared plans
+	assert.Equal(t, 2, builder.WarmPlans(ctx))
+}
+
+func warmPlansOperation(name, content string) *wgpb.Operation {
+	return &wgpb.Operation{
+		Name:                         name,
+		Path:                         name,
+		Content:                      content,
+		OperationType:                wgpb.OperationType_QUERY,
+		HooksConfiguration:           &wgpb.OperationHooksConfiguration{MockResolve: &wgpb.MockResolveHookConfiguration{}},
+		VariablesSchema:              `{}`,
+		ResponseSchema:               `{}`,
+		InterpolationVariablesSchema: `{}`,
+		AuthorizationConfig:          &wgpb.OperationAuthorizationConfig{RoleConfig: &wgpb.OperationRoleConfig{}},
+	}
+}
@@ -10,7 +10,8 @@ var (
 	//go:embed resources
 	res   embed.FS
 	pages = map[string]string{
-		"/": "resources/index.html",
+		"/":     "resources/index.html",
+		"/__wg": "resources/devui.html",
 	}
 )
 
@@ -0,0 +1,136 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// OperationOverridesFileName returns the file of the operation overrides of env, relative to
+// the .wundergraph directory
+func OperationOverridesFileName(env string) string {
+	return "operations.overrides." + env + ".json"
+}
+
+// OperationOverrides layer settings of an environment onto the operations of a config, so
+// operations can e.g. cache or require auth differently without duplicating their files.
+// Operations are keyed by name or path.
+type OperationOverrides struct {
+	Operations map[string]OperationOverride `json:"operations"`
+}
+
+// OperationOverride holds the settings replacing the ones of an operation, in the JSON format
+// of the generated config. Every setting replaces the one of the operation as a whole.
+type OperationOverride struct {
+	CacheConfig          json.RawMessage `json:"cacheConfig,omitempty"`
+	AuthenticationConfig json.RawMessage `json:"authenticationConfig,omitempty"`
+	AuthorizationConfig  json.RawMessage `json:"authorizationConfig,omitempty"`
+	LiveQueryConfig      json.RawMessage `json:"liveQueryConfig,omitempty"`
+}
+
+// operation decodes the settings into the fields of an operation
+func (o OperationOverride) operation() (*wgpb.Operation, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	var operation wgpb.Operation
+	if err := protojson.Unmarshal(data, &operation); err != nil {
+		return nil, err
+	}
+	return &operation, nil
+}
+
+// LoadOperationOverrides reads and validates the overrides at path
+func LoadOperationOverrides(path string) (*OperationOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var overrides OperationOverrides
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	for name, override := range overrides.Operations {
+		operation, err := override.operation()
+		if err != nil {
+			return nil, fmt.Errorf("invalid override of operation %s in %s: %w", name, path, err)
+		}
+		if proto.Size(operation) == 0 {
+			return nil, fmt.Errorf("invalid override of operation %s in %s: no settings", name, path)
+		}
+	}
+	return &overrides, nil
+}
+
+// AppliedOperationOverride lists the settings replaced on an operation
+type AppliedOperationOverride struct {
+	Operation string
+	Settings  []string
+}
+
+// Apply replaces the settings of the overridden operations, it fails if an override matches
+// no operation. The applied overrides are returned sorted by operation name.
+func (o *OperationOverrides) Apply(api *wgpb.UserDefinedApi) ([]AppliedOperationOverride, error) {
+	var applied []AppliedOperationOverride
+	for key, override := range o.Operations {
+		settings, err := override.operation()
+		if err != nil {
+			return nil, err
+		}
+		var operation *wgpb.Operation
+		for _, candidate := range api.GetOperations() {
+			if candidate.Name == key || candidate.Path == key {
+				operation = candidate
+				break
+			}
+		}
+		if operation == nil {
+			return nil, fmt.Errorf("could not override operation %s: no such operation", key)
+		}
+		var names []string
+		target := operation.ProtoReflect()
+		settings.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			target.Set(field, value)
+			names = append(names, field.JSONName())
+			return true
+		})
+		sort.Strings(names)
+		applied = append(applied, AppliedOperationOverride{Operation: operation.Name, Settings: names})
+	}
+	sort.Slice(applied, func(i, j int) bool {
+		return applied[i].Operation < applied[j].Operation
+	})
+	return applied, nil
+}
+
+// applyOperationOverrides reads the overrides on every load, so changes to the file apply with the next reload
+func (n *Node) applyOperationOverrides(graphConfig *wgpb.WunderGraphConfiguration) error {
+	overrides, err := LoadOperationOverrides(n.options.operationOverridesPath)
+	if err != nil {
+		return err
+	}
+	applied, err := overrides.Apply(graphConfig.GetApi())
+	if err != nil {
+		return err
+	}
+	for _, override := range applied {
+		n.log.Info("applied operation override",
+			zap.String("operation", override.Operation),
+			zap.Strings("settings", override.Settings),
+			zap.String("overridesFile", n.options.operationOverridesPath),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestOperationOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, OperationOverridesFileName("staging"))
+	assert.Equal(t, "operations.overrides.staging.json", filepath.Base(path))
+	require.NoError(t, os.WriteFile(path, []byte(`{"operations":{
+		"Invoices":{"cacheConfig":{"enable":true,"maxAge":"60"},"authenticationConfig":{"authRequired":false}},
+		"users/Me":{"liveQueryConfig":{"enable":true,"pollingIntervalSeconds":"2"}}
+	}}`), 0644))
+	overrides, err := LoadOperationOverrides(path)
+	require.NoError(t, err)
+
+	invoices := &wgpb.Operation{
+		Name:                 "Invoices",
+		Path:                 "Invoices",
+		CacheConfig:          &wgpb.OperationCacheConfig{MaxAge: 10, Public: true},
+		AuthenticationConfig: &wgpb.OperationAuthenticationConfig{AuthRequired: true},
+	}
+	me := &wgpb.Operation{Name: "UsersMe", Path: "users/Me"}
+	api := &wgpb.UserDefinedApi{Operations: []*wgpb.Operation{invoices, me}}
+	applied, err := overrides.Apply(api)
+	require.NoError(t, err)
+	assert.Equal(t, []AppliedOperationOverride{
+		{Operation: "Invoices", Settings: []string{"authenticationConfig", "cacheConfig"}},
+		{Operation: "UsersMe", Settings: []string{"liveQueryConfig"}},
+	}, applied)
+
+	// settings are replaced as a whole
+	assert.Equal(t, int64(60), invoices.CacheConfig.MaxAge)
+	assert.True(t, invoices.CacheConfig.Enable)
+	assert.False(t, invoices.CacheConfig.Public)
+	assert.False(t, invoices.AuthenticationConfig.AuthRequired)
+	assert.Equal(t, int64(2), me.LiveQueryConfig.PollingIntervalSeconds)
+
+	missing := &OperationOverrides{Operations: map[string]OperationOverride{"Missing": {CacheConfig: []byte(`{"enable":true}`)}}}
+	_, err = missing.Apply(api)
+	assert.ErrorContains(t, err, "could not override operation Missing: no such operation")
+
+	for _, invalid := range []string{
+		`{"operations":{"Invoices":{"content":"query { a }"}}}`,
+		`{"operations":{"Invoices":{}}}`,
+		`{"operations":{"Invoices":{"cacheConfig":{"maxAge":"soon"}}}}`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(invalid), 0644))
+		_, err = LoadOperationOverrides(path)
+		assert.Error(t, err, invalid)
+	}
+}
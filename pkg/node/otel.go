@@ -0,0 +1,55 @@
+package node
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/otlp"
+)
+
+// otelHandler records a server span for every request but those excluded from the access
+// log. The span is named after the operation of the request once the handlers know it.
+func (n *Node) otelHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.skipAccessLog(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, span := n.otel.StartServer(r, r.Method+" "+r.URL.Path)
+		defer span.End()
+		// the access log shares its entry, it wraps this handler
+		entry := logging.AccessLogEntryFromContext(ctx)
+		if entry == nil {
+			ctx, entry = logging.WithAccessLogEntry(ctx)
+		}
+		recorder := &accessLogResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if operationName := entry.OperationName(); operationName != "" {
+			span.SetName(r.Method + " " + operationName)
+			span.SetAttribute("wundergraph.operation", operationName)
+		}
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.Path)
+		span.SetAttribute("http.status_code", status)
+		span.SetAttribute("wundergraph.upstream_requests", entry.UpstreamRequests())
+		if status >= 500 {
+			span.SetError(errors.New(http.StatusText(status)))
+		}
+	})
+}
+
+// newOTelExporter creates the exporter of WithOTel, nil if exporting is disabled
+func (n *Node) newOTelExporter(config otlp.Config) (*otlp.Exporter, error) {
+	config = otlp.ConfigFromEnv(config)
+	if config.Disabled {
+		n.log.Info("not exporting traces, disabled by " + otlp.SDKDisabledEnvKey)
+		return nil, nil
+	}
+	return otlp.NewExporter(n.log, config)
+}
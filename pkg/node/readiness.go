@@ -0,0 +1,81 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/wundergraph/wundergraph/pkg/hooks"
+)
+
+const (
+	// readinessEndpoint reports whether the node can take traffic, it fails while a config
+	// reload is in progress. livenessEndpoint always succeeds while the server is up.
+	readinessEndpoint = "/health/ready"
+	livenessEndpoint  = "/health/live"
+
+	ReloadStatusIdle       = "IDLE"
+	ReloadStatusInProgress = "IN_PROGRESS"
+)
+
+// ReadinessReport is the response of readinessEndpoint
+type ReadinessReport struct {
+	Status       string `json:"status"`
+	ReloadStatus string `json:"reloadStatus"`
+	// ConfigHash is the hash of the config being served
+	ConfigHash string `json:"configHash,omitempty"`
+	HealthCheckReport
+}
+
+// beginReload marks a config reload as in progress, it ends once the server of the new
+// config listens or loading the config failed
+func (n *Node) beginReload() {
+	atomic.StoreInt32(&n.reloading, 1)
+}
+
+func (n *Node) endReload() {
+	atomic.StoreInt32(&n.reloading, 0)
+}
+
+func (n *Node) reloadStatus() string {
+	if atomic.LoadInt32(&n.reloading) == 1 {
+		return ReloadStatusInProgress
+	}
+	return ReloadStatusIdle
+}
+
+func (n *Node) readinessHandler(hooksClient *hooks.Client, configHash string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, healthy := n.GetHealthReport(r.Context(), hooksClient)
+		readiness := ReadinessReport{
+			Status:            "READY",
+			ReloadStatus:      n.reloadStatus(),
+			ConfigHash:        configHash,
+			HealthCheckReport: *report,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		if !healthy || readiness.ReloadStatus != ReloadStatusIdle {
+			readiness.Status = "NOT_READY"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(readiness)
+	})
+}
+
+func livenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		_, _ = w.Write([]byte(`{"status":"LIVE"}` + "\n"))
+	})
+}
+
+// isHealthCheck returns true for requests to the health, readiness and liveness endpoints
+func isHealthCheck(r *http.Request) bool {
+	switch r.URL.Path {
+	case healthCheckEndpoint, readinessEndpoint, livenessEndpoint:
+		return true
+	}
+	return false
+}
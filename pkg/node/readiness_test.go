@@ -0,0 +1,43 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	n := &Node{log: zap.NewNop()}
+	handler := n.readinessHandler(nil, "abc")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, readinessEndpoint, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var report ReadinessReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "READY", report.Status)
+	assert.Equal(t, ReloadStatusIdle, report.ReloadStatus)
+	assert.Equal(t, "abc", report.ConfigHash)
+
+	n.beginReload()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, readinessEndpoint, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "NOT_READY", report.Status)
+	assert.Equal(t, ReloadStatusInProgress, report.ReloadStatus)
+
+	rec = httptest.NewRecorder()
+	livenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, livenessEndpoint, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	n.endReload()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, readinessEndpoint, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
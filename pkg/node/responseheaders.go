@@ -0,0 +1,100 @@
+package node
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// protectedResponseHeader returns true for headers the node sets for correctness,
+// injecting them would break clients instead of mirroring an edge
+func protectedResponseHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	switch name {
+	case "Content-Type", "Content-Length", "Content-Encoding", "Transfer-Encoding", "Connection", "Vary":
+		return true
+	}
+	return strings.HasPrefix(name, "Access-Control-")
+}
+
+// responseHeaderInjector sets headers on every response unless the handler set them already
+type responseHeaderInjector struct {
+	log     *zap.Logger
+	headers http.Header
+	warned  sync.Map
+}
+
+func newResponseHeaderInjector(log *zap.Logger, headers http.Header) *responseHeaderInjector {
+	injected := http.Header{}
+	for name, values := range headers {
+		if protectedResponseHeader(name) {
+			log.Warn("response header is set by the node and can't be injected, ignoring",
+				zap.String("header", name),
+			)
+			continue
+		}
+		injected[http.CanonicalHeaderKey(name)] = values
+		log.Warn("injecting header into responses",
+			zap.String("header", name),
+			zap.Strings("values", values),
+		)
+	}
+	return &responseHeaderInjector{log: log, headers: injected}
+}
+
+func (i *responseHeaderInjector) Handler(next http.Handler) http.Handler {
+	if len(i.headers) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&headerInjectingResponseWriter{ResponseWriter: w, injector: i}, r)
+	})
+}
+
+// inject runs right before the headers are sent, after the handler had its say
+func (i *responseHeaderInjector) inject(header http.Header) {
+	for name, values := range i.headers {
+		if _, exists := header[name]; exists {
+			if _, warned := i.warned.LoadOrStore(name, struct{}{}); !warned {
+				i.log.Warn("response header already set by the node, not injecting it",
+					zap.String("header", name),
+				)
+			}
+			continue
+		}
+		header[name] = append([]string(nil), values...)
+	}
+}
+
+type headerInjectingResponseWriter struct {
+	http.ResponseWriter
+	injector    *responseHeaderInjector
+	wroteHeader bool
+}
+
+func (w *headerInjectingResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.injector.inject(w.ResponseWriter.Header())
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *headerInjectingResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming responses working
+func (w *headerInjectingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
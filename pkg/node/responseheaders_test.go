@@ -0,0 +1,40 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestResponseHeaderInjector(t *testing.T) {
+	injector := newResponseHeaderInjector(zap.NewNop(), http.Header{
+		"X-Frame-Options":             {"DENY"},
+		"Cache-Control":               {"public"},
+		"content-type":                {"text/plain"},
+		"Access-Control-Allow-Origin": {"*"},
+	})
+	handler := injector.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/stream" {
+			w.Header().Set("Cache-Control", "no-cache")
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "public", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+}
@@ -0,0 +1,133 @@
+package node
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+)
+
+// Route is an HTTP route served by the node
+type Route struct {
+	Methods []string `json:"methods"`
+	Path    string   `json:"path"`
+	// Purpose describes what the route serves, empty if unknown
+	Purpose string `json:"purpose,omitempty"`
+	// Shadowed is true if a route registered before matches the same path and methods, the
+	// route never receives requests
+	Shadowed bool `json:"shadowed,omitempty"`
+}
+
+// Routes returns the routes served with the current config, sorted by path. It's empty
+// until the first config is loaded.
+func (n *Node) Routes() []Route {
+	n.routesMu.Lock()
+	defer n.routesMu.Unlock()
+	return append([]Route(nil), n.routes...)
+}
+
+func (n *Node) setRoutes(router *mux.Router) {
+	routes, err := collectRoutes(router)
+	if err != nil {
+		n.log.Debug("could not collect routes", zap.Error(err))
+	}
+	n.routesMu.Lock()
+	defer n.routesMu.Unlock()
+	n.routes = routes
+}
+
+func collectRoutes(router *mux.Router) ([]Route, error) {
+	var routes []Route
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			// routes without path, e.g. subrouters matching hosts
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		purpose := routePurpose(path)
+		if _, ok := route.GetHandler().(*apihandler.GraphQLPlaygroundHandler); ok {
+			purpose = "GraphQL playground"
+		}
+		routes = append(routes, Route{Methods: methods, Path: path, Purpose: purpose})
+		return nil
+	})
+	// routes are matched in the order they're registered
+	for i := range routes {
+		for j := 0; j < i; j++ {
+			if routes[j].Path == routes[i].Path && methodsOverlap(routes[j].Methods, routes[i].Methods) {
+				routes[i].Shadowed = true
+				break
+			}
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
+	})
+	return routes, err
+}
+
+// methodsOverlap returns true if a request method matches both, no methods match all
+func methodsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, method := range a {
+		for _, other := range b {
+			if method == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routePurpose describes the route with the given path template
+func routePurpose(path string) string {
+	switch path {
+	case rootEndpoint:
+		return "status page"
+	case healthCheckEndpoint:
+		return "health check"
+	case readinessEndpoint:
+		return "readiness check"
+	case livenessEndpoint:
+		return "liveness check"
+	case apihandler.DefaultPlaygroundPath:
+		return "GraphQL endpoint"
+	case "/graphql/stream":
+		return "GraphQL subscriptions over SSE"
+	case persistedQueriesEndpoint:
+		return "persisted queries"
+	case cachePurgeEndpoint:
+		return "response cache purge"
+	case ConfigEndpoint:
+		return "config push"
+	case ConfigVariablesEndpoint:
+		return "config variables"
+	case SchemaUpdatesEndpoint:
+		return "schema updates"
+	case DevUIEndpoint:
+		return "dev UI"
+	}
+	prefixes := []struct {
+		prefix, purpose string
+	}{
+		{"/internal/operations/", "internal operation, called by hooks"},
+		{"/internal/", "internal API, called by hooks"},
+		{"/operations/", "operation"},
+		{"/explain/", "query plan"},
+		{"/webhooks/", "webhook"},
+		{"/s3/", "file upload"},
+		{"/auth/", "authentication"},
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p.prefix) {
+			return p.purpose
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package node
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/operations/Users").Handler(http.NotFoundHandler())
+	router.Methods(http.MethodPost, http.MethodGet).Path("/webhooks/github").Handler(http.NotFoundHandler())
+	// registered later, the first route matches GET requests to the path
+	router.Methods(http.MethodGet).Path("/operations/Users").Handler(http.NotFoundHandler())
+	router.Methods(http.MethodPost).Path("/webhooks/github/push").Handler(http.NotFoundHandler())
+	router.Handle(healthCheckEndpoint, http.NotFoundHandler())
+
+	routes, err := collectRoutes(router)
+	require.NoError(t, err)
+	assert.Equal(t, []Route{
+		{Path: "/health", Purpose: "health check"},
+		{Methods: []string{"GET"}, Path: "/operations/Users", Purpose: "operation"},
+		{Methods: []string{"GET"}, Path: "/operations/Users", Purpose: "operation", Shadowed: true},
+		{Methods: []string{"POST", "GET"}, Path: "/webhooks/github", Purpose: "webhook"},
+		{Methods: []string{"POST"}, Path: "/webhooks/github/push", Purpose: "webhook"},
+	}, routes)
+}
@@ -0,0 +1,153 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// maxScheduledResponseLogSize limits the size of the response body logged for scheduled operations
+const maxScheduledResponseLogSize = 1024
+
+type scheduledOperation struct {
+	operationName string
+	interval      time.Duration
+}
+
+// WithScheduledOperation invokes the query or mutation with the given name or path every
+// interval, e.g. to exercise background jobs without an external cron. The results are
+// logged. Only honored in dev mode.
+func WithScheduledOperation(operationName string, interval time.Duration) Option {
+	return func(options *options) {
+		options.scheduledOperations = append(options.scheduledOperations, scheduledOperation{
+			operationName: operationName,
+			interval:      interval,
+		})
+	}
+}
+
+// startScheduledOperations runs the scheduled operations against handler until
+// ctx is done. Operations are executed in-process through the router of the node.
+func (n *Node) startScheduledOperations(ctx context.Context, handler http.Handler, api *apihandler.Api) {
+	if len(n.options.scheduledOperations) == 0 {
+		return
+	}
+	if !n.options.devMode {
+		n.log.Warn("scheduled operations are only available in dev mode, ignoring")
+		return
+	}
+	for _, scheduled := range n.options.scheduledOperations {
+		operation := findOperation(api, scheduled.operationName)
+		if operation == nil {
+			n.log.Error("scheduled operation not found", zap.String("operation", scheduled.operationName))
+			continue
+		}
+		if operation.Internal {
+			n.log.Error("internal operations can't be scheduled", zap.String("operation", operation.Name))
+			continue
+		}
+		var method string
+		switch operation.OperationType {
+		case wgpb.OperationType_QUERY:
+			method = http.MethodGet
+		case wgpb.OperationType_MUTATION:
+			method = http.MethodPost
+		default:
+			n.log.Error("only queries and mutations can be scheduled", zap.String("operation", operation.Name))
+			continue
+		}
+		n.log.Warn("DEV: scheduled operation",
+			zap.String("operation", operation.Name),
+			zap.Duration("interval", scheduled.interval),
+		)
+		go n.runScheduledOperation(ctx, handler, api, operation, method, scheduled.interval)
+	}
+}
+
+func (n *Node) runScheduledOperation(ctx context.Context, handler http.Handler, api *apihandler.Api, operation *wgpb.Operation, method string, interval time.Duration) {
+	url := strings.TrimSuffix(api.Options.PublicNodeUrl, "/") + "/operations/" + operation.Path
+	log := n.log.With(zap.String("operation", operation.Name))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("scheduled operation stopped")
+			return
+		case <-ticker.C:
+		}
+
+		var body *bytes.Reader
+		if method == http.MethodPost {
+			body = bytes.NewReader([]byte("{}"))
+		} else {
+			body = bytes.NewReader(nil)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			log.Error("could not create request for scheduled operation", zap.Error(err))
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		start := time.Now()
+		res := &scheduledResponse{header: http.Header{}, status: http.StatusOK}
+		handler.ServeHTTP(res, req)
+
+		responseBody := res.body.String()
+		if len(responseBody) > maxScheduledResponseLogSize {
+			responseBody = responseBody[:maxScheduledResponseLogSize] + "..."
+		}
+		fields := []zap.Field{
+			zap.Int("status", res.status),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("response", responseBody),
+		}
+		if res.status >= http.StatusBadRequest {
+			log.Error("scheduled operation failed", fields...)
+		} else {
+			log.Info("scheduled operation executed", fields...)
+		}
+	}
+}
+
+func findOperation(api *apihandler.Api, nameOrPath string) *wgpb.Operation {
+	for _, operation := range api.Operations {
+		if operation.Name == nameOrPath || operation.Path == nameOrPath {
+			return operation
+		}
+	}
+	return nil
+}
+
+// scheduledResponse buffers the response of a scheduled or executed operation
+type scheduledResponse struct {
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *scheduledResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *scheduledResponse) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *scheduledResponse) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(data)
+}
@@ -0,0 +1,45 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestScheduledOperations(t *testing.T) {
+	api := &apihandler.Api{
+		Options: &apihandler.Options{PublicNodeUrl: "http://localhost:9991"},
+		Operations: []*wgpb.Operation{
+			{Name: "Users", Path: "Users", OperationType: wgpb.OperationType_QUERY},
+			{Name: "Tick", Path: "Tick", OperationType: wgpb.OperationType_SUBSCRIPTION},
+		},
+	}
+
+	var calls int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/operations/Users", r.URL.Path)
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	})
+
+	n := &Node{log: zap.NewNop()}
+	WithDevMode()(&n.options)
+	WithScheduledOperation("Users", 10*time.Millisecond)(&n.options)
+	WithScheduledOperation("Tick", 10*time.Millisecond)(&n.options)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.startScheduledOperations(ctx, handler, api)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 2
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+}
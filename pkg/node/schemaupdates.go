@@ -0,0 +1,159 @@
+package node
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wundergraph/wundergraph/pkg/configdiff"
+	"github.com/wundergraph/wundergraph/pkg/httpwritetimeout"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// SchemaUpdatesEndpoint streams server-sent events to dev tooling whenever a reload
+// changes the composed schema, e.g. to rerun codegen or refetch the schema
+const SchemaUpdatesEndpoint = "/schema/updates"
+
+// schemaUpdatesHeartbeat is short enough to keep common proxies from closing idle streams
+const schemaUpdatesHeartbeat = 12 * time.Second
+
+// SchemaUpdate is the data of the update events of SchemaUpdatesEndpoint. Changes lists
+// the changes of the config in the format of wunderctl config diff.
+type SchemaUpdate struct {
+	ID         int      `json:"id"`
+	SchemaHash string   `json:"schemaHash"`
+	Changes    []string `json:"changes,omitempty"`
+}
+
+// schemaUpdates tracks the schema across reloads. The server and its streams are closed on
+// every reload, clients reconnect with the Last-Event-ID of the last event they got and are
+// sent the update they missed.
+type schemaUpdates struct {
+	mu          sync.Mutex
+	config      *wgpb.WunderGraphConfiguration
+	current     SchemaUpdate
+	subscribers map[chan struct{}]struct{}
+}
+
+func schemaHash(config *wgpb.WunderGraphConfiguration) string {
+	sum := sha256.Sum256([]byte(config.GetApi().GetEngineConfiguration().GetGraphqlSchema()))
+	return hex.EncodeToString(sum[:])
+}
+
+// publish records the config about to be served and notifies the subscribers if its schema
+// differs from the one of the previous config
+func (s *schemaUpdates) publish(config *wgpb.WunderGraphConfiguration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.config
+	s.config = config
+	hash := schemaHash(config)
+	if previous == nil {
+		s.current = SchemaUpdate{SchemaHash: hash}
+		return
+	}
+	if hash == s.current.SchemaHash {
+		return
+	}
+	changes := configdiff.Diff(previous, config)
+	update := SchemaUpdate{
+		ID:         s.current.ID + 1,
+		SchemaHash: hash,
+		Changes:    make([]string, 0, len(changes)),
+	}
+	for _, change := range changes {
+		update.Changes = append(update.Changes, change.Section+": "+change.String())
+	}
+	s.current = update
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+			// the subscriber is notified already and reads the latest update
+		}
+	}
+}
+
+func (s *schemaUpdates) subscribe() (chan struct{}, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = map[chan struct{}]struct{}{}
+	}
+	subscriber := make(chan struct{}, 1)
+	s.subscribers[subscriber] = struct{}{}
+	return subscriber, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, subscriber)
+	}
+}
+
+func (s *schemaUpdates) latest() SchemaUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// handler streams a schema event with the current schema to new clients and an update event
+// for every change of the schema. Clients reconnecting with an older Last-Event-ID get the
+// latest update right away.
+func (s *schemaUpdates) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		httpwritetimeout.Disable(r)
+		subscriber, unsubscribe := s.subscribe()
+		defer unsubscribe()
+
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		// reloads take about a second, clients retry sooner than the default of browsers
+		_, _ = fmt.Fprint(w, "retry: 1000\n\n")
+
+		sent := -1
+		if lastEventID, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+			sent = lastEventID
+		}
+		send := func(event string, update SchemaUpdate) {
+			data, _ := json.Marshal(update)
+			_, _ = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", update.ID, event, data)
+			sent = update.ID
+		}
+		if current := s.latest(); sent < 0 {
+			send("schema", current)
+		} else if sent < current.ID {
+			send("update", current)
+		}
+		flusher.Flush()
+
+		ticker := time.NewTicker(schemaUpdatesHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-subscriber:
+				if current := s.latest(); current.ID > sent {
+					send("update", current)
+					flusher.Flush()
+				}
+			case <-ticker.C:
+				_, _ = fmt.Fprint(w, ":\n\n")
+				flusher.Flush()
+			}
+		}
+	})
+}
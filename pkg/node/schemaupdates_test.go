@@ -0,0 +1,74 @@
+package node
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func schemaConfig(schema string, operations ...string) *wgpb.WunderGraphConfiguration {
+	api := &wgpb.UserDefinedApi{
+		EngineConfiguration: &wgpb.EngineConfiguration{GraphqlSchema: schema},
+	}
+	for _, operation := range operations {
+		api.Operations = append(api.Operations, &wgpb.Operation{Name: operation, Path: operation})
+	}
+	return &wgpb.WunderGraphConfiguration{Api: api}
+}
+
+// readEvent returns the lines of the next event, skipping comments and retry hints
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && len(lines) != 0:
+			return lines
+		case line == "", strings.HasPrefix(line, ":"), strings.HasPrefix(line, "retry:"):
+			continue
+		default:
+			lines = append(lines, line)
+		}
+	}
+}
+
+func TestSchemaUpdates(t *testing.T) {
+	var updates schemaUpdates
+	updates.publish(schemaConfig("type Query { a: String }", "A"))
+	server := httptest.NewServer(updates.handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	r := bufio.NewReader(resp.Body)
+	event := readEvent(t, r)
+	assert.Equal(t, []string{"id: 0", "event: schema"}, event[:2])
+
+	// configs with the same schema aren't announced
+	updates.publish(schemaConfig("type Query { a: String }", "A"))
+	updates.publish(schemaConfig("type Query { a: String b: String }", "A", "B"))
+	event = readEvent(t, r)
+	assert.Equal(t, []string{"id: 1", "event: update"}, event[:2])
+	assert.Contains(t, event[2], `"changes":["operations: + B","routes: + GET /operations/B"]`)
+
+	// clients reconnecting after a reload get the update they missed
+	updates.publish(schemaConfig("type Query { b: String }", "B"))
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	reconnected, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer reconnected.Body.Close()
+	event = readEvent(t, bufio.NewReader(reconnected.Body))
+	assert.Equal(t, []string{"id: 2", "event: update"}, event[:2])
+}
@@ -0,0 +1,54 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestKeepServingLastGoodConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configPath := filepath.Join(t.TempDir(), "wundergraph.config.json")
+	configFileChange := make(chan struct{})
+	n := &Node{
+		ctx:              ctx,
+		log:              zap.NewNop(),
+		configCh:         make(chan WunderNodeConfig, 1),
+		variablesChanged: make(chan struct{}, 1),
+		options:          options{configFileChange: configFileChange},
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = n.filePollConfig(configPath)
+	}()
+
+	writeTestConfig(t, configPath, testGraphConfig("type Query { a: String }", 9991))
+	configFileChange <- struct{}{}
+	var served WunderNodeConfig
+	select {
+	case served = <-n.configCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("good config was not served")
+	}
+	require.NotEmpty(t, served.Api.ApiConfigHash)
+	assert.Equal(t, "type Query { a: String }", served.Api.EngineConfiguration.GraphqlSchema)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"api":`), 0644))
+	configFileChange <- struct{}{}
+	// the loop takes the next change once the broken config was handled
+	configFileChange <- struct{}{}
+	cancel()
+	<-done
+
+	assert.Len(t, n.configCh, 0)
+	assert.True(t, n.servingStaleConfig)
+	assert.Equal(t, served.Api.ApiConfigHash, n.lastGoodConfigHash)
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// StartupPhase is the step of starting a node that failed
+type StartupPhase string
+
+const (
+	// StartupPhaseOptions failed because the options contradict each other
+	StartupPhaseOptions StartupPhase = "options"
+	// StartupPhaseTLS failed to load the TLS certificate
+	StartupPhaseTLS StartupPhase = "tls"
+	// StartupPhaseConfig failed because there is no config or it's invalid
+	StartupPhaseConfig StartupPhase = "config"
+	// StartupPhaseHooks failed to set up the client of the hooks server
+	StartupPhaseHooks StartupPhase = "hooks"
+	// StartupPhaseListener failed to bind or serve a listener
+	StartupPhaseListener StartupPhase = "listener"
+)
+
+// StartupError is returned by StartBlocking if the node can't start or can't apply a
+// config. Only the details relevant to Phase are set: Addr for listener errors,
+// HooksServerURL for hooks errors and File for TLS and cassette errors.
+type StartupError struct {
+	Phase          StartupPhase
+	Addr           string
+	HooksServerURL string
+	File           string
+	Err            error
+}
+
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("could not start node (%s): %v", e.Phase, e.Err)
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+func startupError(phase StartupPhase, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*StartupError); ok {
+		return err
+	}
+	return &StartupError{Phase: phase, Err: err}
+}
+
+// validateHooksServerURL rejects hooks server URLs the hooks client can't send requests to,
+// an empty URL is allowed for configs without hooks
+func validateHooksServerURL(serverURL string) error {
+	if serverURL == "" {
+		return nil
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return &StartupError{Phase: StartupPhaseHooks, HooksServerURL: serverURL, Err: err}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return &StartupError{Phase: StartupPhaseHooks, HooksServerURL: serverURL, Err: fmt.Errorf("invalid hooks server url %q, expected an absolute http(s) url", serverURL)}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/apihandler"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestStartupError(t *testing.T) {
+	busy, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	newConfig := func(serverURL string) WunderNodeConfig {
+		return WunderNodeConfig{
+			Api: &apihandler.Api{
+				EngineConfiguration: &wgpb.EngineConfiguration{},
+				AuthenticationConfig: &wgpb.ApiAuthenticationConfig{
+					CookieBased: &wgpb.CookieBasedAuthentication{},
+					JwksBased:   &wgpb.JwksBasedAuthentication{},
+					Hooks:       &wgpb.ApiAuthenticationHooks{},
+				},
+				Options: &apihandler.Options{
+					ServerUrl: serverURL,
+					Listener:  &apihandler.Listener{Host: "127.0.0.1", Port: uint16(port)},
+					Logging:   apihandler.Logging{Level: zap.ErrorLevel},
+				},
+			},
+		}
+	}
+	start := func(opts ...Option) *StartupError {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := New(ctx, BuildInfo{}, "", zap.NewNop()).StartBlocking(opts...)
+		var startupErr *StartupError
+		require.True(t, errors.As(err, &startupErr), "%v", err)
+		return startupErr
+	}
+
+	startupErr := start(WithStaticWunderNodeConfig(newConfig("http://localhost:9992")))
+	assert.Equal(t, StartupPhaseListener, startupErr.Phase)
+	assert.Equal(t, busy.Addr().String(), startupErr.Addr)
+
+	startupErr = start(WithStaticWunderNodeConfig(newConfig("localhost:9992")))
+	assert.Equal(t, StartupPhaseHooks, startupErr.Phase)
+	assert.Equal(t, "localhost:9992", startupErr.HooksServerURL)
+
+	startupErr = start()
+	assert.Equal(t, StartupPhaseConfig, startupErr.Phase)
+
+	startupErr = start(WithTLS("missing.pem", "missing-key.pem"), WithStaticWunderNodeConfig(newConfig("")))
+	assert.Equal(t, StartupPhaseTLS, startupErr.Phase)
+	assert.Equal(t, "missing.pem", startupErr.File)
+
+	// the auth bypass must never be enabled outside of dev mode
+	claims := map[string]interface{}{"sub": "dev"}
+	startupErr = start(WithDevAuthBypass(claims), WithStaticWunderNodeConfig(newConfig("http://localhost:9992")))
+	assert.Equal(t, StartupPhaseOptions, startupErr.Phase)
+	assert.EqualError(t, startupErr.Err, "auth bypass is only allowed in dev mode")
+	startupErr = start(WithDevMode(), WithDevAuthBypass(claims), WithStaticWunderNodeConfig(newConfig("http://localhost:9992")))
+	assert.Equal(t, StartupPhaseListener, startupErr.Phase)
+
+	// pushed configs replace everything, including the upstream URLs
+	startupErr = start(WithConfigPush(""), WithStaticWunderNodeConfig(newConfig("http://localhost:9992")))
+	assert.Equal(t, StartupPhaseOptions, startupErr.Phase)
+	assert.EqualError(t, startupErr.Err, "config push requires a token")
+	startupErr = start(WithConfigPush("secret"), WithStaticWunderNodeConfig(newConfig("http://localhost:9992")))
+	assert.Equal(t, StartupPhaseListener, startupErr.Phase)
+}
@@ -0,0 +1,80 @@
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/watcher"
+)
+
+type tlsOptions struct {
+	certFile string
+	keyFile  string
+}
+
+// certReloader serves the certificate of certFile and keyFile to TLS handshakes and
+// replaces it when the files change. Established connections keep their certificate.
+type certReloader struct {
+	log      *zap.Logger
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(log *zap.Logger, certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{log: log, certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload keeps the current certificate if the new one can't be loaded, e.g. while only
+// one of both files has been replaced
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("could not load TLS certificate %s: %w", r.certFile, err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// watch reloads the certificate on changes of its files until ctx is done
+func (r *certReloader) watch(ctx context.Context) error {
+	w := watcher.NewWatcher("tls", &watcher.Config{
+		WatchPaths: []*watcher.WatchPath{
+			{Path: r.certFile},
+			{Path: r.keyFile},
+		},
+	}, r.log)
+	return w.Watch(ctx, func(paths []string) error {
+		if err := r.reload(); err != nil {
+			r.log.Error("could not reload TLS certificate, keeping the current one", zap.Error(err))
+			return nil
+		}
+		r.log.Info("TLS certificate reloaded", zap.String("certFile", r.certFile))
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package node
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// ParseUpstreamBase validates the base URL relative data source URLs are resolved against. Its
+// path is treated as a directory, so users resolves to /api/users with a base of /api.
+func ParseUpstreamBase(base string) (*url.URL, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream base %q: %w", base, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid upstream base %q, expected an absolute http(s) url", base)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// ResolvedUpstreamURL is a relative URL of a data source resolved against the upstream base
+type ResolvedUpstreamURL struct {
+	DataSourceID string
+	Relative     string
+	URL          string
+}
+
+// ResolveUpstreamURLs replaces the relative fetch and subscription URLs of the REST and GraphQL
+// data sources by absolute ones resolved against base, absolute URLs are kept
+func ResolveUpstreamURLs(engineConfig *wgpb.EngineConfiguration, base *url.URL) ([]ResolvedUpstreamURL, error) {
+	var resolved []ResolvedUpstreamURL
+	resolve := func(ds *wgpb.DataSourceConfiguration, variable *wgpb.ConfigurationVariable) error {
+		value := loadvariable.String(variable)
+		if value == "" {
+			return nil
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid url %q of data source %s: %w", value, ds.Id, err)
+		}
+		if u.IsAbs() || u.Host != "" {
+			return nil
+		}
+		absolute := base.ResolveReference(u).String()
+		variable.Kind = wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE
+		variable.StaticVariableContent = absolute
+		variable.EnvironmentVariableName = ""
+		variable.EnvironmentVariableDefaultValue = ""
+		resolved = append(resolved, ResolvedUpstreamURL{DataSourceID: ds.Id, Relative: value, URL: absolute})
+		return nil
+	}
+	for _, ds := range engineConfig.GetDatasourceConfigurations() {
+		variables := []*wgpb.ConfigurationVariable{
+			ds.GetCustomRest().GetFetch().GetUrl(),
+			ds.GetCustomGraphql().GetFetch().GetUrl(),
+			ds.GetCustomGraphql().GetSubscription().GetUrl(),
+		}
+		for _, variable := range variables {
+			if variable == nil {
+				continue
+			}
+			if err := resolve(ds, variable); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// applyUpstreamBase resolves the relative data source URLs of every loaded config
+func (n *Node) applyUpstreamBase(graphConfig *wgpb.WunderGraphConfiguration) error {
+	resolved, err := ResolveUpstreamURLs(graphConfig.GetApi().GetEngineConfiguration(), n.options.upstreamBase)
+	if err != nil {
+		return err
+	}
+	for _, r := range resolved {
+		n.log.Info("resolved relative data source url",
+			zap.String("dataSourceId", r.DataSourceID),
+			zap.String("relative", r.Relative),
+			zap.String("url", r.URL),
+			zap.String("upstreamBase", n.options.upstreamBase.String()),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func staticVariable(value string) *wgpb.ConfigurationVariable {
+	return &wgpb.ConfigurationVariable{Kind: wgpb.ConfigurationVariableKind_STATIC_CONFIGURATION_VARIABLE, StaticVariableContent: value}
+}
+
+func TestParseUpstreamBase(t *testing.T) {
+	base, err := ParseUpstreamBase("http://localhost:4000/api")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4000/api/", base.String())
+
+	_, err = ParseUpstreamBase("localhost:4000")
+	assert.Error(t, err)
+	_, err = ParseUpstreamBase("/api")
+	assert.Error(t, err)
+}
+
+func TestResolveUpstreamURLs(t *testing.T) {
+	t.Setenv("USERS_URL", "")
+	config := &wgpb.EngineConfiguration{
+		DatasourceConfigurations: []*wgpb.DataSourceConfiguration{
+			{Id: "users", CustomRest: &wgpb.DataSourceCustom_REST{Fetch: &wgpb.FetchConfiguration{Url: &wgpb.ConfigurationVariable{
+				Kind:                            wgpb.ConfigurationVariableKind_ENV_CONFIGURATION_VARIABLE,
+				EnvironmentVariableName:         "USERS_URL",
+				EnvironmentVariableDefaultValue: "users",
+			}}}},
+			{Id: "billing", CustomGraphql: &wgpb.DataSourceCustom_GraphQL{
+				Fetch:        &wgpb.FetchConfiguration{Url: staticVariable("/billing/graphql")},
+				Subscription: &wgpb.GraphQLSubscriptionConfiguration{Url: staticVariable("https://billing.example.com/graphql")},
+			}},
+			{Id: "db", Kind: wgpb.DataSourceKind_POSTGRESQL},
+		},
+	}
+	base, err := ParseUpstreamBase("http://localhost:4000/api")
+	require.NoError(t, err)
+	resolved, err := ResolveUpstreamURLs(config, base)
+	require.NoError(t, err)
+	assert.Equal(t, []ResolvedUpstreamURL{
+		{DataSourceID: "users", Relative: "users", URL: "http://localhost:4000/api/users"},
+		{DataSourceID: "billing", Relative: "/billing/graphql", URL: "http://localhost:4000/billing/graphql"},
+	}, resolved)
+
+	sources := config.DatasourceConfigurations
+	assert.Equal(t, "http://localhost:4000/api/users", loadvariable.String(sources[0].CustomRest.Fetch.Url))
+	assert.Equal(t, "http://localhost:4000/billing/graphql", loadvariable.String(sources[1].CustomGraphql.Fetch.Url))
+	assert.Equal(t, "https://billing.example.com/graphql", loadvariable.String(sources[1].CustomGraphql.Subscription.Url))
+}
@@ -0,0 +1,87 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// ConfigVariablesEndpoint updates the values of configuration variables in dev mode
+const ConfigVariablesEndpoint = "/config/variables"
+
+type configVariablesResponse struct {
+	Variables map[string]string `json:"variables"`
+}
+
+// configVariablesHandler accepts a JSON object mapping environment variable names to
+// values. The values replace the variables reading them in the served config until the
+// node is restarted, the config is re-applied through the regular reload, which restarts
+// the server. The values are echoed, so like the dev UI it only serves loopback clients.
+func (n *Node) configVariablesHandler() http.Handler {
+	allowRemote := n.options.devUI != nil && n.options.devUI.AllowRemote
+	return devUIGuard(allowRemote, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var values map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil || len(values) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		for name := range values {
+			if name == "" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+		}
+
+		n.variableOverridesMu.Lock()
+		if n.variableOverrides == nil {
+			n.variableOverrides = map[string]string{}
+		}
+		names := make([]string, 0, len(values))
+		for name, value := range values {
+			n.variableOverrides[name] = value
+			names = append(names, name)
+		}
+		all := make(map[string]string, len(n.variableOverrides))
+		for name, value := range n.variableOverrides {
+			all[name] = value
+		}
+		n.variableOverridesMu.Unlock()
+
+		sort.Strings(names)
+		n.log.Warn("configuration variables changed at runtime, re-applying config",
+			zap.Strings("variables", names),
+		)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(configVariablesResponse{Variables: all})
+		// the reload closes the server, the response must be sent before
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		select {
+		case n.variablesChanged <- struct{}{}:
+		default:
+			// a reload is already pending and picks up the new values
+		}
+	}))
+}
+
+// applyVariableOverrides replaces the variables of graphConfig set through ConfigVariablesEndpoint
+func (n *Node) applyVariableOverrides(graphConfig *wgpb.WunderGraphConfiguration) {
+	n.variableOverridesMu.Lock()
+	defer n.variableOverridesMu.Unlock()
+	if len(n.variableOverrides) == 0 {
+		return
+	}
+	replaced := loadvariable.Override(graphConfig, n.variableOverrides)
+	n.log.Debug("applied configuration variable overrides",
+		zap.Int("overrides", len(n.variableOverrides)),
+		zap.Int("replaced", replaced),
+	)
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/loadvariable"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func variablesRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, ConfigVariablesEndpoint, strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:51234"
+	req.Host = "localhost:9991"
+	return req
+}
+
+func TestConfigVariablesHandler(t *testing.T) {
+	n := &Node{log: zap.NewNop(), variablesChanged: make(chan struct{}, 1)}
+	handler := n.configVariablesHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, variablesRequest(`{"WG_TEST_URL":"http://localhost:5000"}`))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"variables":{"WG_TEST_URL":"http://localhost:5000"}}`, rec.Body.String())
+	assert.Len(t, n.variablesChanged, 1)
+
+	// a pending reload picks up later changes
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, variablesRequest(`{"WG_TEST_TOKEN":"secret"}`))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, n.variablesChanged, 1)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, variablesRequest(`["WG_TEST_URL"]`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// the response echoes the values, other machines are rejected
+	req := variablesRequest(`{"WG_TEST_URL":"http://attacker.example"}`)
+	req.RemoteAddr = "192.168.1.10:51234"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret")
+	req = variablesRequest(`{"WG_TEST_URL":"http://attacker.example"}`)
+	req.Header.Set("Origin", "http://attacker.example")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	graphConfig := &wgpb.WunderGraphConfiguration{
+		Api: &wgpb.UserDefinedApi{
+			NodeOptions: &wgpb.NodeOptions{
+				PublicNodeUrl: &wgpb.ConfigurationVariable{
+					Kind:                    wgpb.ConfigurationVariableKind_ENV_CONFIGURATION_VARIABLE,
+					EnvironmentVariableName: "WG_TEST_URL",
+				},
+			},
+		},
+	}
+	n.applyVariableOverrides(graphConfig)
+	assert.Equal(t, "http://localhost:5000", loadvariable.String(graphConfig.Api.NodeOptions.PublicNodeUrl))
+}
@@ -2,14 +2,26 @@ package operations
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 const DirectoryName = "operations"
 
+// ExcludeEnvKey holds the comma separated operations the config runner must not load
+const ExcludeEnvKey = "WG_EXCLUDE_OPERATIONS"
+
+// ExtensionsEnvKey holds the comma separated extensions of GraphQL operation files the
+// config runner loads, DefaultGraphQLExtensions if unset
+const ExtensionsEnvKey = "WG_OPERATION_EXTENSIONS"
+
+// DefaultGraphQLExtensions are the extensions of GraphQL operation and fragment files
+var DefaultGraphQLExtensions = []string{".graphql", ".gql"}
+
 func GetPaths(wunderGraphDir string) ([]string, error) {
 	operationsDirectoryAbs := filepath.Join(wunderGraphDir, DirectoryName)
 	var operationFilePaths []string
@@ -30,6 +42,144 @@ func GetPaths(wunderGraphDir string) ([]string, error) {
 	return operationFilePaths, nil
 }
 
+// Validate returns an error listing the files of all operations, TypeScript and GraphQL, that
+// collide with another one. Names are compared case-insensitively, Foo.graphql and foo.graphql
+// are the same file on case-insensitive filesystems and UsersGet in the config otherwise.
+// Excluded operations are ignored, a missing operations directory is valid. GraphQL operations
+// are recognized by graphqlExtensions, DefaultGraphQLExtensions if nil.
+func Validate(wunderGraphDir string, excluded []string, graphqlExtensions []string) error {
+	operationsDirectoryAbs := filepath.Join(wunderGraphDir, DirectoryName)
+	filesByKey := map[string][]string{}
+	err := filepath.Walk(operationsDirectoryAbs, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == operationsDirectoryAbs && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(info.Name(), ".d.ts") {
+			return nil
+		}
+		if _, ok := GraphQLExtension(path, graphqlExtensions); !ok && filepath.Ext(path) != ".ts" {
+			return nil
+		}
+		path, err = filepath.Rel(operationsDirectoryAbs, path)
+		if err != nil {
+			return err
+		}
+		if IsExcluded(path, excluded) {
+			return nil
+		}
+		key := nameKey(path)
+		filesByKey[key] = append(filesByKey[key], filepath.Join(DirectoryName, path))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var conflicts []string
+	for _, files := range filesByKey {
+		if len(files) > 1 {
+			sort.Strings(files)
+			conflicts = append(conflicts, strings.Join(files, ", "))
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return fmt.Errorf("duplicate operation names, rename or exclude one of the files: %s", strings.Join(conflicts, "; "))
+}
+
+// IsExcluded returns true if the operation at mountPath, its path relative to the operations
+// directory without extension, is excluded. Operations are excluded by mount path (users/get)
+// or by name (UsersGet), both are matched case-insensitively.
+func IsExcluded(mountPath string, excluded []string) bool {
+	key := nameKey(mountPath)
+	for _, name := range excluded {
+		if nameKey(name) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// nameKey returns the case-insensitive operation name of a path or name, two operations
+// with the same key are mapped to the same name in the config
+func nameKey(name string) string {
+	name = strings.TrimSuffix(filepath.ToSlash(name), filepath.Ext(name))
+	name = strings.TrimPrefix(name, DirectoryName+"/")
+	return strings.ToLower(strings.ReplaceAll(name, "/", ""))
+}
+
+// Exclude splits the paths returned by GetPaths into the operations to keep and the excluded ones
+func Exclude(paths []string, excluded []string) (kept []string, skipped []string) {
+	for _, path := range paths {
+		if IsExcluded(path, excluded) {
+			skipped = append(skipped, path)
+		} else {
+			kept = append(kept, path)
+		}
+	}
+	return kept, skipped
+}
+
+// ExcludedFromEnv returns the operations excluded via ExcludeEnvKey
+func ExcludedFromEnv() []string {
+	return splitList(os.Getenv(ExcludeEnvKey))
+}
+
+// GraphQLExtensionsFromEnv returns the extensions set via ExtensionsEnvKey
+func GraphQLExtensionsFromEnv() []string {
+	extensions, err := ParseGraphQLExtensions(splitList(os.Getenv(ExtensionsEnvKey)))
+	if err != nil || len(extensions) == 0 {
+		return DefaultGraphQLExtensions
+	}
+	return extensions
+}
+
+// ParseGraphQLExtensions adds the leading dot to extensions given without it. Extensions
+// must consist of a single part, the operation name is the file name without it.
+func ParseGraphQLExtensions(values []string) ([]string, error) {
+	extensions := make([]string, 0, len(values))
+	for _, value := range values {
+		extension := "." + strings.TrimPrefix(strings.TrimSpace(value), ".")
+		if extension == "." || strings.ContainsAny(extension[1:], "./\\") {
+			return nil, fmt.Errorf("invalid operation extension %q, expected e.g. .gql", value)
+		}
+		if extension == ".ts" {
+			return nil, fmt.Errorf("invalid operation extension %q, TypeScript operations are always loaded", value)
+		}
+		extensions = append(extensions, extension)
+	}
+	return extensions, nil
+}
+
+// GraphQLExtension returns the extension of path if it's one of extensions,
+// DefaultGraphQLExtensions if nil
+func GraphQLExtension(path string, extensions []string) (string, bool) {
+	if extensions == nil {
+		extensions = DefaultGraphQLExtensions
+	}
+	ext := filepath.Ext(path)
+	for _, extension := range extensions {
+		if ext == extension {
+			return ext, true
+		}
+	}
+	return "", false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func Cleanup(wunderGraphDir string, paths []string) error {
 	expected := make([]string, 0, len(paths)*2)
 	for _, path := range paths {
@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	write := func(t *testing.T, dir string, paths ...string) {
+		for _, path := range paths {
+			path = filepath.Join(dir, DirectoryName, path)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+			require.NoError(t, os.WriteFile(path, nil, 0644))
+		}
+	}
+
+	t.Run("no operations", func(t *testing.T) {
+		assert.NoError(t, Validate(t.TempDir(), nil, nil))
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "users/get.ts", "users/list.graphql", "users/get.d.ts", "README.md")
+		assert.NoError(t, Validate(dir, nil, nil))
+	})
+
+	t.Run("typescript and graphql", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "users/get.ts", "users/get.graphql", "Other.graphql")
+		err := Validate(dir, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), filepath.Join("operations", "users", "get.graphql")+", "+filepath.Join("operations", "users", "get.ts"))
+		assert.NotContains(t, err.Error(), "Other")
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		dir := t.TempDir()
+		// Foo.graphql and foo.graphql can't both exist on case-insensitive filesystems
+		write(t, dir, "fooBar.graphql", "Foobar.ts", "users/foobar.graphql")
+		err := Validate(dir, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Foobar.ts")
+		assert.Contains(t, err.Error(), "fooBar.graphql")
+		assert.NotContains(t, err.Error(), "users")
+	})
+
+	t.Run("custom extensions", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "users/get.gql", "users/get.graphql", "users/get.query")
+		require.Error(t, Validate(dir, nil, nil))
+		assert.NoError(t, Validate(dir, nil, []string{".query"}))
+	})
+
+	t.Run("excluded", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "users/get.ts", "users/get.graphql")
+		assert.NoError(t, Validate(dir, []string{"UsersGet"}, nil))
+	})
+}
+
+func TestParseGraphQLExtensions(t *testing.T) {
+	extensions, err := ParseGraphQLExtensions([]string{"gql", ".graphql"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gql", ".graphql"}, extensions)
+
+	for _, invalid := range []string{"", ".", ".op.graphql", "ts"} {
+		_, err := ParseGraphQLExtensions([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,264 @@
+// Package operationtest runs operations against a node and compares their responses
+// with the expectations declared in test files. Test files are TypeScript modules in
+// the tests directory of the WunderGraph directory, named *.test.ts, default exporting
+// a list of cases:
+//
+//	export default [
+//		{
+//			name: 'returns the user',
+//			operation: 'users/get',
+//			input: { id: '1' },
+//			expect: { status: 200, response: { data: { user: { id: '1' } } } },
+//		},
+//	];
+package operationtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DirectoryName = "tests"
+	FileSuffix    = ".test.ts"
+)
+
+// loadScript writes the default exports of the bundled test files given as arguments to
+// the file given as first argument
+const loadScript = `const fs = require('fs');
+const path = require('path');
+const [out, ...files] = process.argv.slice(1);
+const cases = {};
+for (const file of files) {
+	const mod = require(path.resolve(file));
+	cases[file] = mod.default ?? mod;
+}
+fs.writeFileSync(out, JSON.stringify(cases));
+`
+
+// Case is a single operation call with its expected response
+type Case struct {
+	// File is the test file declaring the case, relative to the WunderGraph directory
+	File      string          `json:"-"`
+	Name      string          `json:"name"`
+	Operation string          `json:"operation"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Expect    Expectation     `json:"expect"`
+}
+
+// Expectation describes the expected response. Response is matched partially, every
+// field it contains must be present in the response with the same value, other fields
+// are ignored. Arrays must have the same length.
+type Expectation struct {
+	// Status defaults to 200
+	Status   int             `json:"status,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Executor runs the operation with the given name or path with input as variables
+type Executor func(ctx context.Context, operation string, input []byte) (status int, body []byte, err error)
+
+// Result is the outcome of a case, Err is nil if it passed
+type Result struct {
+	Case     Case
+	Err      error
+	Duration time.Duration
+}
+
+// GetPaths returns the test files of the WunderGraph directory relative to it, a missing
+// tests directory has no tests
+func GetPaths(wunderGraphDir string) ([]string, error) {
+	testsDirectoryAbs := filepath.Join(wunderGraphDir, DirectoryName)
+	var paths []string
+	err := filepath.Walk(testsDirectoryAbs, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == testsDirectoryAbs && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), FileSuffix) {
+			return nil
+		}
+		path, err = filepath.Rel(wunderGraphDir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// BundlePath returns the path of the bundled test file relative to the WunderGraph directory,
+// test files are bundled to generated/bundle like operations
+func BundlePath(path string) string {
+	return filepath.Join("generated", "bundle", strings.TrimSuffix(path, filepath.Ext(path))+".js")
+}
+
+// Load runs the bundled test files with node and returns their cases in the order of paths
+func Load(ctx context.Context, wunderGraphDir string, paths []string) ([]Case, error) {
+	out, err := os.CreateTemp("", "wundergraph-tests-*.json")
+	if err != nil {
+		return nil, err
+	}
+	_ = out.Close()
+	defer os.Remove(out.Name())
+
+	args := []string{"-e", loadScript, out.Name()}
+	for _, path := range paths {
+		args = append(args, BundlePath(path))
+	}
+	cmd := exec.CommandContext(ctx, "node", args...)
+	cmd.Dir = wunderGraphDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("could not load test files: %w", err)
+	}
+
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		return nil, err
+	}
+	var casesByFile map[string][]Case
+	if err := json.Unmarshal(data, &casesByFile); err != nil {
+		return nil, fmt.Errorf("test files must default export a list of cases: %w", err)
+	}
+	var cases []Case
+	for _, path := range paths {
+		for i, c := range casesByFile[BundlePath(path)] {
+			c.File = path
+			if c.Name == "" {
+				c.Name = fmt.Sprintf("#%d", i+1)
+			}
+			cases = append(cases, c)
+		}
+	}
+	return cases, nil
+}
+
+// Run executes the cases with up to parallel cases at a time and returns their results in
+// the order of cases. Every case calls the operation with its own input, cases don't share
+// any state besides the node.
+func Run(ctx context.Context, cases []Case, parallel int, execute Executor) []Result {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]Result, len(cases))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range cases {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			err := runCase(ctx, cases[i], execute)
+			results[i] = Result{Case: cases[i], Err: err, Duration: time.Since(start)}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func runCase(ctx context.Context, c Case, execute Executor) error {
+	if c.Operation == "" {
+		return errors.New("case has no operation")
+	}
+	status, body, err := execute(ctx, c.Operation, c.Input)
+	if err != nil {
+		return err
+	}
+	expectedStatus := c.Expect.Status
+	if expectedStatus == 0 {
+		expectedStatus = 200
+	}
+	if status != expectedStatus {
+		return fmt.Errorf("expected status %d, got %d: %s", expectedStatus, status, strings.TrimSpace(string(body)))
+	}
+	if len(c.Expect.Response) == 0 {
+		return nil
+	}
+	var expected, actual interface{}
+	if err := json.Unmarshal(c.Expect.Response, &expected); err != nil {
+		return fmt.Errorf("invalid expected response: %w", err)
+	}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		return fmt.Errorf("response is not JSON: %s", strings.TrimSpace(string(body)))
+	}
+	return Match(expected, actual)
+}
+
+// Match returns an error naming the first path at which actual doesn't match expected, see
+// Expectation for the rules
+func Match(expected, actual interface{}) error {
+	return match("response", expected, actual)
+}
+
+func match(path string, expected, actual interface{}) error {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok {
+			return mismatch(path, expected, actual)
+		}
+		keys := make([]string, 0, len(expected))
+		for key := range expected {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := actual[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, key)
+			}
+			if err := match(path+"."+key, expected[key], value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok {
+			return mismatch(path, expected, actual)
+		}
+		if len(expected) != len(actual) {
+			return fmt.Errorf("%s: expected %d items, got %d", path, len(expected), len(actual))
+		}
+		for i := range expected {
+			if err := match(fmt.Sprintf("%s[%d]", path, i), expected[i], actual[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if expected != actual {
+			return mismatch(path, expected, actual)
+		}
+		return nil
+	}
+}
+
+func mismatch(path string, expected, actual interface{}) error {
+	expectedData, _ := json.Marshal(expected)
+	actualData, _ := json.Marshal(actual)
+	return fmt.Errorf("%s: expected %s, got %s", path, expectedData, actualData)
+}
@@ -0,0 +1,67 @@
+package operationtest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPaths(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := GetPaths(dir)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	for _, path := range []string{"users.test.ts", "users/get.test.ts", "helpers.ts"} {
+		path = filepath.Join(dir, DirectoryName, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+	paths, err = GetPaths(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("tests", "users.test.ts"), filepath.Join("tests", "users", "get.test.ts")}, paths)
+	assert.Equal(t, filepath.Join("generated", "bundle", "tests", "users.test.js"), BundlePath(paths[0]))
+}
+
+func TestMatch(t *testing.T) {
+	decode := func(data string) interface{} {
+		var value interface{}
+		require.NoError(t, json.Unmarshal([]byte(data), &value))
+		return value
+	}
+	actual := decode(`{"data":{"user":{"id":"1","name":"Jens","tags":["a","b"]}}}`)
+
+	assert.NoError(t, Match(decode(`{"data":{"user":{"id":"1"}}}`), actual))
+	assert.NoError(t, Match(decode(`{"data":{"user":{"tags":["a","b"]}}}`), actual))
+	assert.EqualError(t, Match(decode(`{"data":{"user":{"id":"2"}}}`), actual), `response.data.user.id: expected "2", got "1"`)
+	assert.EqualError(t, Match(decode(`{"errors":[]}`), actual), `response.errors: missing`)
+	assert.EqualError(t, Match(decode(`{"data":{"user":{"tags":["a"]}}}`), actual), `response.data.user.tags: expected 1 items, got 2`)
+}
+
+func TestRun(t *testing.T) {
+	cases := []Case{
+		{Name: "passes", Operation: "Echo", Input: json.RawMessage(`{"value":1}`), Expect: Expectation{Response: json.RawMessage(`{"data":{"value":1}}`)}},
+		{Name: "wrong value", Operation: "Echo", Input: json.RawMessage(`{"value":2}`), Expect: Expectation{Response: json.RawMessage(`{"data":{"value":1}}`)}},
+		{Name: "wrong status", Operation: "Missing", Expect: Expectation{Status: 200}},
+		{Name: "no operation"},
+	}
+	execute := func(ctx context.Context, operation string, input []byte) (int, []byte, error) {
+		if operation != "Echo" {
+			return 404, []byte("not found"), nil
+		}
+		return 200, []byte(`{"data":` + string(input) + `}`), nil
+	}
+
+	results := Run(context.Background(), cases, 2, execute)
+	require.Len(t, results, 4)
+	assert.NoError(t, results[0].Err)
+	assert.EqualError(t, results[1].Err, "response.data.value: expected 1, got 2")
+	assert.EqualError(t, results[2].Err, "expected status 200, got 404: not found")
+	assert.EqualError(t, results[3].Err, "case has no operation")
+	assert.Equal(t, "wrong value", results[1].Case.Name)
+}
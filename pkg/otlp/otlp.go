@@ -0,0 +1,319 @@
+// Package otlp exports spans to an OpenTelemetry collector with OTLP over HTTP. Spans are
+// encoded as JSON, so neither the OpenTelemetry SDK nor the protobuf definitions of OTLP
+// are needed.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// the standard environment variables of the OpenTelemetry SDKs honored by ConfigFromEnv
+const (
+	EndpointEnvKey           = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnvKey     = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	HeadersEnvKey            = "OTEL_EXPORTER_OTLP_HEADERS"
+	TracesHeadersEnvKey      = "OTEL_EXPORTER_OTLP_TRACES_HEADERS"
+	ServiceNameEnvKey        = "OTEL_SERVICE_NAME"
+	ResourceAttributesEnvKey = "OTEL_RESOURCE_ATTRIBUTES"
+	SDKDisabledEnvKey        = "OTEL_SDK_DISABLED"
+)
+
+const (
+	DefaultEndpoint    = "http://localhost:4318"
+	DefaultServiceName = "wundergraph-node"
+
+	tracesPath    = "/v1/traces"
+	scopeName     = "github.com/wundergraph/wundergraph"
+	flushInterval = 5 * time.Second
+	maxBatchSize  = 512
+	// maxQueueSize bounds the memory used while the collector is unreachable,
+	// spans beyond it are dropped
+	maxQueueSize = 4 * maxBatchSize
+)
+
+// Config configures an Exporter. Endpoint is the base URL of the collector, spans are sent
+// to its /v1/traces path, TracesEndpoint is used as is.
+type Config struct {
+	Endpoint       string
+	TracesEndpoint string
+	ServiceName    string
+	Headers        map[string]string
+	// ResourceAttributes describe the node, e.g. deployment.environment
+	ResourceAttributes map[string]string
+	Disabled           bool
+}
+
+// ConfigFromEnv fills the fields of config left empty from the standard environment variables
+func ConfigFromEnv(config Config) Config {
+	if config.Endpoint == "" && config.TracesEndpoint == "" {
+		config.Endpoint = os.Getenv(EndpointEnvKey)
+		config.TracesEndpoint = os.Getenv(TracesEndpointEnvKey)
+	}
+	if config.Endpoint == "" && config.TracesEndpoint == "" {
+		config.Endpoint = DefaultEndpoint
+	}
+	resourceAttributes := parseKeyValues(os.Getenv(ResourceAttributesEnvKey))
+	for key, value := range config.ResourceAttributes {
+		resourceAttributes[key] = value
+	}
+	config.ResourceAttributes = resourceAttributes
+	if config.ServiceName == "" {
+		config.ServiceName = os.Getenv(ServiceNameEnvKey)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = resourceAttributes["service.name"]
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+	if config.Headers == nil {
+		config.Headers = parseKeyValues(os.Getenv(HeadersEnvKey))
+		for key, value := range parseKeyValues(os.Getenv(TracesHeadersEnvKey)) {
+			config.Headers[key] = value
+		}
+	}
+	if os.Getenv(SDKDisabledEnvKey) == "true" {
+		config.Disabled = true
+	}
+	return config
+}
+
+// TracesURL returns the URL spans are sent to
+func (c Config) TracesURL() string {
+	if c.TracesEndpoint != "" {
+		return c.TracesEndpoint
+	}
+	endpoint := strings.TrimSuffix(c.Endpoint, "/")
+	if strings.HasSuffix(endpoint, tracesPath) {
+		return endpoint
+	}
+	return endpoint + tracesPath
+}
+
+// parseKeyValues parses the comma separated key=value lists of the environment variables,
+// values are URL encoded
+func parseKeyValues(s string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+			value = unescaped
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// Exporter sends ended spans in batches to the collector. A nil Exporter records nothing,
+// so callers don't have to check whether tracing is enabled. It is safe for concurrent use.
+type Exporter struct {
+	log      *zap.Logger
+	url      string
+	header   http.Header
+	resource resource
+	client   *http.Client
+
+	mu      sync.Mutex
+	queue   []spanData
+	dropped int
+	failing bool
+	flush   chan struct{}
+}
+
+func NewExporter(log *zap.Logger, config Config) (*Exporter, error) {
+	tracesURL := config.TracesURL()
+	u, err := url.Parse(tracesURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", tracesURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q, expected an absolute http(s) url", tracesURL)
+	}
+	header := http.Header{}
+	for name, value := range config.Headers {
+		header.Set(name, value)
+	}
+	header.Set("Content-Type", "application/json")
+	attributes := map[string]interface{}{}
+	for key, value := range config.ResourceAttributes {
+		attributes[key] = value
+	}
+	attributes["service.name"] = config.ServiceName
+	return &Exporter{
+		log:      log,
+		url:      tracesURL,
+		header:   header,
+		resource: resource{Attributes: toAttributes(attributes)},
+		client:   &http.Client{Timeout: 10 * time.Second},
+		flush:    make(chan struct{}, 1),
+	}, nil
+}
+
+// URL returns the URL spans are sent to
+func (e *Exporter) URL() string {
+	return e.url
+}
+
+// Run exports the ended spans every few seconds until ctx is done, the remaining spans are
+// exported before it returns
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			e.export(shutdownCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			e.export(ctx)
+		case <-e.flush:
+			e.export(ctx)
+		}
+	}
+}
+
+func (e *Exporter) add(span spanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) >= maxQueueSize {
+		e.dropped++
+		return
+	}
+	e.queue = append(e.queue, span)
+	if len(e.queue) >= maxBatchSize {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *Exporter) export(ctx context.Context) {
+	e.mu.Lock()
+	queue, dropped := e.queue, e.dropped
+	e.queue, e.dropped = nil, 0
+	e.mu.Unlock()
+	if dropped != 0 {
+		e.log.Warn("dropped spans, the OTLP collector can't keep up", zap.Int("spans", dropped))
+	}
+	for len(queue) != 0 {
+		batch := queue
+		if len(batch) > maxBatchSize {
+			batch = batch[:maxBatchSize]
+		}
+		queue = queue[len(batch):]
+		err := e.send(ctx, batch)
+		e.mu.Lock()
+		// failures are only reported once until the collector is reachable again
+		report := err != nil && !e.failing
+		e.failing = err != nil
+		e.mu.Unlock()
+		if report {
+			e.log.Warn("could not export spans", zap.String("endpoint", e.url), zap.Error(err))
+		} else if err != nil {
+			e.log.Debug("could not export spans", zap.String("endpoint", e.url), zap.Error(err))
+		}
+	}
+}
+
+func (e *Exporter) send(ctx context.Context, spans []spanData) error {
+	data, err := json.Marshal(exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: e.resource,
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = e.header.Clone()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// the JSON encoding of OTLP, see https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#json-protobuf-encoding
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              SpanKind    `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []attribute `json:"attributes,omitempty"`
+	Status            *status     `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// statusCodeError is the code of failed spans, unset spans count as successful
+const statusCodeError = 2
+
+type attribute struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	// IntValue is a string, int64 values don't fit into JSON numbers
+	IntValue  *string `json:"intValue,omitempty"`
+	BoolValue *bool   `json:"boolValue,omitempty"`
+}
@@ -0,0 +1,124 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EndpointEnvKey, "http://collector:4318/")
+	t.Setenv(HeadersEnvKey, "authorization=Bearer%20token,x-tenant=dev")
+	t.Setenv(ResourceAttributesEnvKey, "service.name=from-resource,deployment.environment=dev")
+
+	config := ConfigFromEnv(Config{})
+	assert.Equal(t, "http://collector:4318/v1/traces", config.TracesURL())
+	assert.Equal(t, "from-resource", config.ServiceName)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token", "x-tenant": "dev"}, config.Headers)
+	assert.Equal(t, "dev", config.ResourceAttributes["deployment.environment"])
+
+	// arguments take precedence over the environment
+	config = ConfigFromEnv(Config{Endpoint: "http://localhost:4318", ServiceName: "api"})
+	assert.Equal(t, "http://localhost:4318/v1/traces", config.TracesURL())
+	assert.Equal(t, "api", config.ServiceName)
+
+	t.Setenv(SDKDisabledEnvKey, "true")
+	assert.True(t, ConfigFromEnv(Config{}).Disabled)
+}
+
+func TestExporter(t *testing.T) {
+	requests := make(chan exportRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req exportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer collector.Close()
+
+	exporter, err := NewExporter(zap.NewNop(), Config{Endpoint: collector.URL, ServiceName: "node"})
+	require.NoError(t, err)
+
+	// the upstream continues the trace of the request
+	var traceParent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get(TraceParentHeader)
+	}))
+	defer upstream.Close()
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, func(r *http.Request) string {
+		return "upstream"
+	})}
+
+	r := httptest.NewRequest(http.MethodGet, "/operations/Weather", nil)
+	r.Header.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	ctx, server := exporter.StartServer(r, "GET /operations/Weather")
+	upstreamReq, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+"/?token=secret", nil)
+	resp, err := client.Do(upstreamReq)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	server.SetAttribute("http.status_code", 200)
+	server.End()
+	server.End()
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	exporter.Run(runCtx)
+
+	req := <-requests
+	require.Len(t, req.ResourceSpans, 1)
+	assert.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2, "ending a span twice exports it once")
+	client0, server0 := spans[0], spans[1]
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", server0.TraceID)
+	assert.Equal(t, "b7ad6b7169203331", server0.ParentSpanID)
+	assert.Equal(t, SpanKindServer, server0.Kind)
+	assert.Equal(t, server0.TraceID, client0.TraceID)
+	assert.Equal(t, server0.SpanID, client0.ParentSpanID)
+	assert.Equal(t, SpanKindClient, client0.Kind)
+	assert.Equal(t, "00-"+client0.TraceID+"-"+client0.SpanID+"-01", traceParent)
+	for _, attribute := range client0.Attributes {
+		if attribute.Key == "http.url" {
+			assert.NotContains(t, *attribute.Value.StringValue, "secret")
+		}
+	}
+}
+
+func TestStartWithoutSpan(t *testing.T) {
+	ctx, span := Start(context.Background(), "plan", SpanKindInternal)
+	assert.Nil(t, span)
+	span.SetAttribute("key", "value")
+	span.End()
+	header := http.Header{}
+	Inject(ctx, header)
+	assert.Empty(t, header.Get(TraceParentHeader))
+
+	var exporter *Exporter
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, span = exporter.StartServer(r, "GET /")
+	assert.Nil(t, span)
+}
+
+func TestParseTraceParent(t *testing.T) {
+	_, _, ok := parseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	assert.True(t, ok)
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033-01",
+	} {
+		_, _, ok := parseTraceParent(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
@@ -0,0 +1,221 @@
+package otlp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceParentHeader propagates traces between services, see https://www.w3.org/TR/trace-context/
+const TraceParentHeader = "traceparent"
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Span is an operation of a trace. All methods are safe for concurrent use and do nothing
+// on a nil Span, e.g. in contexts without a trace.
+type Span struct {
+	exporter *Exporter
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu         sync.Mutex
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span of ctx, nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan returns a context carrying span, Start creates its spans as children of it
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// Start starts a child span of the span of ctx. It returns ctx and a nil span if ctx doesn't
+// carry a span, so code paths don't have to know whether tracing is enabled.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	span := parent.exporter.newSpan(name, kind, parent.traceID, parent.spanID)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartServer starts the span of a request received by the node, it continues the trace of
+// the traceparent header of r if there is one
+func (e *Exporter) StartServer(r *http.Request, name string) (context.Context, *Span) {
+	if e == nil {
+		return r.Context(), nil
+	}
+	traceID, parentID, ok := parseTraceParent(r.Header.Get(TraceParentHeader))
+	if !ok {
+		traceID = randomTraceID()
+	}
+	span := e.newSpan(name, SpanKindServer, traceID, parentID)
+	return ContextWithSpan(r.Context(), span), span
+}
+
+func (e *Exporter) newSpan(name string, kind SpanKind, traceID [16]byte, parentID [8]byte) *Span {
+	span := &Span{
+		exporter: e,
+		traceID:  traceID,
+		parentID: parentID,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return span
+}
+
+func randomTraceID() [16]byte {
+	var traceID [16]byte
+	_, _ = rand.Read(traceID[:])
+	return traceID
+}
+
+// SetName replaces the name of the span, e.g. once the operation of a request is known
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+// SetAttribute sets an attribute of the span, value must be a string, an int, an int64 or a bool
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attributes == nil {
+		s.attributes = map[string]interface{}{}
+	}
+	s.attributes[key] = value
+}
+
+// SetError marks the span as failed, a nil err has no effect
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// End ends the span and queues it for the export. Ending a span more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := spanData{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        toAttributes(s.attributes),
+	}
+	if s.parentID != [8]byte{} {
+		data.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if s.err != nil {
+		data.Status = &status{Code: statusCodeError, Message: s.err.Error()}
+	}
+	s.mu.Unlock()
+	s.exporter.add(data)
+}
+
+// Inject sets the traceparent header of the span of ctx on header, so the receiving service
+// continues the trace
+func Inject(ctx context.Context, header http.Header) {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	// spans are always sampled
+	header.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(span.traceID[:]), hex.EncodeToString(span.spanID[:])))
+}
+
+func parseTraceParent(value string) (traceID [16]byte, parentID [8]byte, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == [16]byte{} {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil || parentID == [8]byte{} {
+		return traceID, parentID, false
+	}
+	return traceID, parentID, true
+}
+
+func toAttributes(values map[string]interface{}) []attribute {
+	if len(values) == 0 {
+		return nil
+	}
+	attributes := make([]attribute, 0, len(values))
+	for key, value := range values {
+		var v attributeValue
+		switch value := value.(type) {
+		case string:
+			v.StringValue = &value
+		case int:
+			s := strconv.Itoa(value)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(value, 10)
+			v.IntValue = &s
+		case bool:
+			v.BoolValue = &value
+		default:
+			s := fmt.Sprint(value)
+			v.StringValue = &s
+		}
+		attributes = append(attributes, attribute{Key: key, Value: v})
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes
+}
@@ -0,0 +1,48 @@
+package otlp
+
+import (
+	"errors"
+	"net/http"
+)
+
+// NewTransport records a client span for every request sent through next whose context
+// carries a span and passes the trace on with the traceparent header. name returns the
+// name of the span of a request.
+func NewTransport(next http.RoundTripper, name func(r *http.Request) string) http.RoundTripper {
+	return &transport{next: next, name: name}
+}
+
+type transport struct {
+	next http.RoundTripper
+	name func(r *http.Request) string
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, span := Start(r.Context(), t.name(r), SpanKindClient)
+	if span == nil {
+		return t.next.RoundTrip(r)
+	}
+	defer span.End()
+	r = r.Clone(ctx)
+	Inject(ctx, r.Header)
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.url", redactedURL(r))
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	if resp.StatusCode >= 400 {
+		span.SetError(errors.New(resp.Status))
+	}
+	return resp, nil
+}
+
+// redactedURL leaves out the query and credentials, they might carry secrets
+func redactedURL(r *http.Request) string {
+	u := *r.URL
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
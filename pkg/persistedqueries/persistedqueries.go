@@ -0,0 +1,151 @@
+// Package persistedqueries implements a local store for persisted GraphQL documents
+// and a middleware speaking the Automatic Persisted Queries (APQ) protocol.
+package persistedqueries
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/tidwall/sjson"
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+const (
+	// FileName is the name of the persisted queries manifest in the generated directory
+	FileName = "wundergraph.persisted-queries.json"
+
+	errPersistedQueryNotFound = `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`
+	errHashMismatch           = `{"errors":[{"message":"provided sha does not match query"}]}`
+)
+
+// Hash returns the hex encoded sha256 hash of the document, as used by APQ clients
+func Hash(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+type Store struct {
+	mu        sync.RWMutex
+	documents map[string]string
+	log       *zap.Logger
+}
+
+func New(log *zap.Logger) *Store {
+	return &Store{
+		documents: map[string]string{},
+		log:       log,
+	}
+}
+
+// Register adds the document to the store and returns its hash
+func (s *Store) Register(document string) string {
+	hash := Hash(document)
+	s.mu.Lock()
+	s.documents[hash] = document
+	s.mu.Unlock()
+	return hash
+}
+
+func (s *Store) Get(hash string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	document, ok := s.documents[hash]
+	return document, ok
+}
+
+// Entries returns a copy of all hashes and their documents
+func (s *Store) Entries() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make(map[string]string, len(s.documents))
+	for hash, document := range s.documents {
+		entries[hash] = document
+	}
+	return entries
+}
+
+// WriteFile writes all entries as a JSON object of hash to document to path
+func (s *Store) WriteFile(path string) error {
+	data, err := json.MarshalIndent(s.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ServeHTTP serves all entries of the store
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	_ = json.NewEncoder(w).Encode(s.Entries())
+}
+
+// Handler returns a middleware for GraphQL POST requests implementing APQ. Requests
+// containing only a hash get the query of the stored document, requests containing
+// a query and a hash register the document.
+func (s *Store) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_ = r.Body.Close()
+
+		hash, err := jsonparser.GetString(body, "extensions", "persistedQuery", "sha256Hash")
+		if err != nil || hash == "" {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		requestLogger := s.log.With(logging.WithRequestIDFromContext(r.Context()))
+
+		query, _ := jsonparser.GetString(body, "query")
+		if query != "" {
+			if Hash(query) != hash {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = io.WriteString(w, errHashMismatch)
+				return
+			}
+			s.Register(query)
+			requestLogger.Debug("persisted query registered", zap.String("hash", hash))
+		} else {
+			document, ok := s.Get(hash)
+			if !ok {
+				requestLogger.Debug("persisted query not found", zap.String("hash", hash))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, errPersistedQueryNotFound)
+				return
+			}
+			body, err = sjson.SetBytes(body, "query", document)
+			if err != nil {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			requestLogger.Debug("persisted query hit", zap.String("hash", hash))
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		handler.ServeHTTP(w, r)
+	})
+}
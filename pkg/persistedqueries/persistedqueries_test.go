@@ -0,0 +1,54 @@
+package persistedqueries
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/buger/jsonparser"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	const query = "{me {id}}"
+
+	store := New(zap.NewNop())
+	var received string
+	handler := store.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, _ = jsonparser.GetString(body, "query")
+	}))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+		return rec
+	}
+
+	hashOnly := `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + Hash(query) + `"}}}`
+
+	t.Run("not found", func(t *testing.T) {
+		rec := serve(hashOnly)
+		assert.Equal(t, errPersistedQueryNotFound, rec.Body.String())
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		rec := serve(`{"query":"{other}","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + Hash(query) + `"}}}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("register and hit", func(t *testing.T) {
+		serve(`{"query":"` + query + `","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + Hash(query) + `"}}}`)
+		received = ""
+		serve(hashOnly)
+		assert.Equal(t, query, received)
+	})
+
+	t.Run("without extension", func(t *testing.T) {
+		serve(`{"query":"{plain}"}`)
+		assert.Equal(t, "{plain}", received)
+	})
+}
@@ -0,0 +1,135 @@
+// Package processes tracks the processes started by wunderctl in PID files, so processes
+// orphaned by a crash can be listed and stopped later.
+package processes
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Process is a tracked process
+type Process struct {
+	PID int `json:"pid"`
+	// ParentPID is the PID of the wunderctl process that started it
+	ParentPID int    `json:"parentPid"`
+	Name      string `json:"name"`
+	// Ports the process listens on, as far as they are known when it's started
+	Ports          []int     `json:"ports,omitempty"`
+	WunderGraphDir string    `json:"wunderGraphDir"`
+	StartedAt      time.Time `json:"startedAt"`
+	// Group is true if the process leads its own process group, stopping it stops its
+	// children as well
+	Group bool `json:"group"`
+	// StartTime is the start time of the process as reported by the OS, it tells the process
+	// apart from an unrelated one that reuses its PID later
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// Registry stores a PID file per tracked process in a directory shared by all wunderctl processes
+type Registry struct {
+	dir string
+}
+
+func NewRegistry(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+func (r *Registry) path(pid int) string {
+	return filepath.Join(r.dir, strconv.Itoa(pid)+".json")
+}
+
+// Register writes the PID file of p, recording its start time unless it's already set
+func (r *Registry) Register(p Process) error {
+	if err := os.MkdirAll(r.dir, os.ModePerm); err != nil {
+		return err
+	}
+	if p.StartTime == "" {
+		startTime, err := startTime(p.PID)
+		if err != nil {
+			return err
+		}
+		p.StartTime = startTime
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path(p.PID), data, 0644)
+}
+
+// Unregister removes the PID file of pid, if any
+func (r *Registry) Unregister(pid int) error {
+	err := os.Remove(r.path(pid))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the tracked processes that are still running, sorted by parent and start
+// time. PID files of processes that exited without unregistering, or whose PID now
+// belongs to another process, are removed.
+func (r *Registry) List() ([]Process, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var running []Process
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(r.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var p Process
+		if err := json.Unmarshal(data, &p); err != nil || p.PID <= 0 || !stillRunning(p) {
+			_ = os.Remove(path)
+			continue
+		}
+		running = append(running, p)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		if running[i].ParentPID != running[j].ParentPID {
+			return running[i].ParentPID < running[j].ParentPID
+		}
+		return running[i].StartedAt.Before(running[j].StartedAt)
+	})
+	return running, nil
+}
+
+// Stop terminates p and unregisters it. If p is no longer running, e.g. because its PID
+// was reused by another process, only its PID file is removed.
+func (r *Registry) Stop(p Process) error {
+	if !stillRunning(p) {
+		return r.Unregister(p.PID)
+	}
+	if err := terminate(p); err != nil && alive(p.PID) {
+		return err
+	}
+	return r.Unregister(p.PID)
+}
+
+// stillRunning returns true if p is still running, as opposed to another process with its PID.
+// PID files without a start time, e.g. those written by older versions, only check the PID.
+func stillRunning(p Process) bool {
+	if !alive(p.PID) {
+		return false
+	}
+	if p.StartTime == "" {
+		return true
+	}
+	startTime, err := startTime(p.PID)
+	return err == nil && startTime == p.StartTime
+}
@@ -0,0 +1,98 @@
+package processes
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(t.TempDir())
+
+	list, err := registry.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	self := Process{PID: os.Getpid(), Name: "wunderctl up", Ports: []int{9991}, StartedAt: time.Now()}
+	require.NoError(t, registry.Register(self))
+
+	// exited without unregistering
+	cmd := exec.Command("go", "version")
+	require.NoError(t, cmd.Run())
+	require.NoError(t, registry.Register(Process{PID: cmd.Process.Pid, Name: "hooks-server-runner", StartTime: "1"}))
+
+	list, err = registry.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, self.PID, list[0].PID)
+	assert.Equal(t, []int{9991}, list[0].Ports)
+	assert.NotEmpty(t, list[0].StartTime)
+	assert.NoFileExists(t, registry.path(cmd.Process.Pid))
+
+	require.NoError(t, registry.Unregister(self.PID))
+	require.NoError(t, registry.Unregister(self.PID))
+	list, err = registry.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestRegistryStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+	registry := NewRegistry(t.TempDir())
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	p := Process{PID: cmd.Process.Pid, Name: "config-runner"}
+	require.NoError(t, registry.Register(p))
+
+	require.NoError(t, registry.Stop(p))
+	_ = cmd.Wait()
+	assert.False(t, cmd.ProcessState.Success())
+	assert.NoFileExists(t, registry.path(p.PID))
+}
+
+func TestRegistryReusedPID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+	registry := NewRegistry(t.TempDir())
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	})
+	// the PID file of an earlier process whose PID was reused by sleep
+	p := Process{PID: cmd.Process.Pid, Name: "config-runner", StartTime: "1"}
+	require.NoError(t, registry.Register(p))
+
+	require.NoError(t, registry.Stop(p))
+	assert.NoFileExists(t, registry.path(p.PID))
+
+	require.NoError(t, registry.Register(p))
+	list, err := registry.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.NoFileExists(t, registry.path(p.PID))
+
+	// sleep isn't the tracked process, so it must not have been stopped
+	assert.Never(t, func() bool {
+		select {
+		case <-exited:
+			return true
+		default:
+			return false
+		}
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
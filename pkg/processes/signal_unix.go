@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package processes
+
+import (
+	"errors"
+	"syscall"
+)
+
+// alive returns true if a process with the pid exists, including those of other users
+func alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func terminate(p Process) error {
+	if p.Group {
+		return syscall.Kill(-p.PID, syscall.SIGTERM)
+	}
+	return syscall.Kill(p.PID, syscall.SIGTERM)
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package processes
+
+import (
+	"os"
+)
+
+// alive returns true if a process with the pid exists, finding a process fails on
+// windows if it exited
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+func terminate(p Process) error {
+	process, err := os.FindProcess(p.PID)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
@@ -0,0 +1,29 @@
+package processes
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// startTime returns the start time of the process in clock ticks since boot, field 22 of
+// /proc/<pid>/stat. The command name in field 2 may contain spaces and parentheses, so the
+// fields are counted from its closing parenthesis.
+func startTime(pid int) (string, error) {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return "", err
+	}
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return "", fmt.Errorf("unexpected /proc/%d/stat: %q", pid, stat)
+	}
+	fields := strings.Fields(stat[end+1:])
+	// fields starts with field 3, the state
+	if len(fields) < 20 {
+		return "", fmt.Errorf("unexpected /proc/%d/stat: %q", pid, stat)
+	}
+	return fields[19], nil
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package processes
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// startTime returns the start time of the process as printed by ps, there's no /proc to
+// read it from
+func startTime(pid int) (string, error) {
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	startTime := strings.TrimSpace(string(out))
+	if startTime == "" {
+		return "", fmt.Errorf("no start time for process %d", pid)
+	}
+	return startTime, nil
+}
@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package processes
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// startTime returns the creation time of the process in nanoseconds since the epoch
+func startTime(pid int) (string, error) {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer syscall.CloseHandle(h)
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(creation.Nanoseconds(), 10), nil
+}
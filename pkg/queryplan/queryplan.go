@@ -0,0 +1,177 @@
+// Package queryplan describes the execution plan of an operation: the fetches to
+// the data sources, their order and nesting, without executing the operation.
+package queryplan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/wundergraph/graphql-go-tools/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/pkg/engine/resolve"
+)
+
+type Fetch struct {
+	// Step is the position of the fetch in the execution order, fetches of the same step run in parallel
+	Step int `json:"step"`
+	// Path is the path in the response the fetch is resolved for, e.g. data.users.@.posts
+	Path       string `json:"path"`
+	DataSource string `json:"dataSource"`
+	Method     string `json:"method,omitempty"`
+	URL        string `json:"url,omitempty"`
+	// DependsOn is the step of the fetch providing the data this fetch is nested in
+	DependsOn int  `json:"dependsOn,omitempty"`
+	Batched   bool `json:"batched"`
+	// PerListItem is set if the fetch runs for every item of a list in the response
+	PerListItem bool `json:"perListItem"`
+}
+
+type Explanation struct {
+	Operation string   `json:"operation"`
+	Kind      string   `json:"kind"`
+	Fetches   []*Fetch `json:"fetches"`
+	// Warnings are potential performance problems like N+1 fetches
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Explain walks the prepared plan of the operation
+func Explain(operationName string, preparedPlan plan.Plan) *Explanation {
+	e := &explainer{
+		explanation: &Explanation{Operation: operationName},
+	}
+	switch p := preparedPlan.(type) {
+	case *plan.SynchronousResponsePlan:
+		e.explanation.Kind = "synchronous"
+		if p.Response != nil {
+			e.walk(p.Response.Data, []string{"data"}, 0, false)
+		}
+	case *plan.StreamingResponsePlan:
+		e.explanation.Kind = "streaming"
+		if p.Response != nil && p.Response.InitialResponse != nil {
+			e.walk(p.Response.InitialResponse.Data, []string{"data"}, 0, false)
+		}
+	case *plan.SubscriptionResponsePlan:
+		e.explanation.Kind = "subscription"
+		if p.Response != nil {
+			e.step++
+			trigger := &Fetch{
+				Step:       e.step,
+				Path:       "subscription",
+				DataSource: "subscription trigger",
+			}
+			trigger.Method, trigger.URL = requestTarget(p.Response.Trigger.Input)
+			e.explanation.Fetches = append(e.explanation.Fetches, trigger)
+			if p.Response.Response != nil {
+				e.walk(p.Response.Response.Data, []string{"data"}, e.step, false)
+			}
+		}
+	default:
+		e.explanation.Kind = "unknown"
+	}
+	return e.explanation
+}
+
+type explainer struct {
+	explanation *Explanation
+	step        int
+}
+
+func (e *explainer) walk(node resolve.Node, path []string, parentStep int, inList bool) {
+	switch n := node.(type) {
+	case *resolve.Object:
+		step := parentStep
+		if n.Fetch != nil {
+			e.step++
+			step = e.step
+			e.addFetch(n.Fetch, strings.Join(path, "."), step, parentStep, inList)
+		}
+		for _, field := range n.Fields {
+			e.walk(field.Value, append(path, string(field.Name)), step, inList)
+		}
+	case *resolve.Array:
+		e.walk(n.Item, append(path, "@"), parentStep, true)
+	}
+}
+
+func (e *explainer) addFetch(fetch resolve.Fetch, path string, step, parentStep int, inList bool) {
+	switch f := fetch.(type) {
+	case *resolve.SingleFetch:
+		e.add(f, path, step, parentStep, inList, false)
+	case *resolve.BatchFetch:
+		e.add(f.Fetch, path, step, parentStep, inList, true)
+	case *resolve.ParallelFetch:
+		for _, fetch := range f.Fetches {
+			e.addFetch(fetch, path, step, parentStep, inList)
+		}
+	}
+}
+
+func (e *explainer) add(fetch *resolve.SingleFetch, path string, step, parentStep int, inList, batched bool) {
+	explained := &Fetch{
+		Step:        step,
+		Path:        path,
+		DataSource:  dataSourceName(fetch.DataSourceIdentifier),
+		DependsOn:   parentStep,
+		Batched:     batched,
+		PerListItem: inList && !batched,
+	}
+	explained.Method, explained.URL = requestTarget([]byte(fetch.Input))
+	e.explanation.Fetches = append(e.explanation.Fetches, explained)
+	if explained.PerListItem {
+		e.explanation.Warnings = append(e.explanation.Warnings,
+			fmt.Sprintf("N+1: %s is fetched from %s once per list item, consider batching or fetching the list at once", path, explained.DataSource),
+		)
+	}
+}
+
+// dataSourceName turns identifiers like graphql_datasource.Source into graphql
+func dataSourceName(identifier []byte) string {
+	name := string(identifier)
+	if name == "" {
+		return "unknown"
+	}
+	name = strings.TrimSuffix(name, ".Source")
+	return strings.TrimSuffix(name, "_datasource")
+}
+
+// requestTarget extracts method and url of HTTP based fetches
+func requestTarget(input []byte) (method, url string) {
+	method, _ = jsonparser.GetString(input, "method")
+	url, _ = jsonparser.GetString(input, "url")
+	return method, url
+}
+
+// WriteText renders the explanation in a human readable form
+func (e *Explanation) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Plan of %s (%s)\n", e.Operation, e.Kind)
+	if len(e.Fetches) == 0 {
+		sb.WriteString("  no fetches, the operation is resolved without data sources\n")
+	}
+	for _, fetch := range e.Fetches {
+		fmt.Fprintf(&sb, "  %d. %s at %s", fetch.Step, fetch.DataSource, fetch.Path)
+		if fetch.URL != "" {
+			fmt.Fprintf(&sb, " (%s %s)", fetch.Method, fetch.URL)
+		}
+		var notes []string
+		if fetch.DependsOn != 0 {
+			notes = append(notes, fmt.Sprintf("after %d", fetch.DependsOn))
+		}
+		if fetch.Batched {
+			notes = append(notes, "batched")
+		}
+		if fetch.PerListItem {
+			notes = append(notes, "per list item")
+		}
+		if len(notes) != 0 {
+			fmt.Fprintf(&sb, " [%s]", strings.Join(notes, ", "))
+		}
+		sb.WriteByte('\n')
+	}
+	for _, warning := range e.Warnings {
+		fmt.Fprintf(&sb, "  warning: %s\n", warning)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,51 @@
+package queryplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wundergraph/graphql-go-tools/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/pkg/engine/resolve"
+)
+
+func TestExplain(t *testing.T) {
+	preparedPlan := &plan.SynchronousResponsePlan{
+		Response: &resolve.GraphQLResponse{
+			Data: &resolve.Object{
+				Fetch: &resolve.SingleFetch{
+					Input:                `{"method":"POST","url":"http://users.local/graphql"}`,
+					DataSourceIdentifier: []byte("graphql_datasource.Source"),
+				},
+				Fields: []*resolve.Field{
+					{
+						Name: []byte("users"),
+						Value: &resolve.Array{
+							Item: &resolve.Object{
+								Fetch: &resolve.SingleFetch{
+									Input:                `{"method":"GET","url":"http://posts.local/posts"}`,
+									DataSourceIdentifier: []byte("oas_datasource.Source"),
+								},
+								Fields: []*resolve.Field{
+									{Name: []byte("posts"), Value: &resolve.String{}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	explanation := Explain("Users", preparedPlan)
+	assert.Equal(t, "synchronous", explanation.Kind)
+	assert.Equal(t, []*Fetch{
+		{Step: 1, Path: "data", DataSource: "graphql", Method: "POST", URL: "http://users.local/graphql"},
+		{Step: 2, Path: "data.users.@", DataSource: "oas", Method: "GET", URL: "http://posts.local/posts", DependsOn: 1, PerListItem: true},
+	}, explanation.Fetches)
+	assert.Len(t, explanation.Warnings, 1)
+
+	var sb strings.Builder
+	assert.NoError(t, explanation.WriteText(&sb))
+	assert.Contains(t, sb.String(), "2. oas at data.users.@ (GET http://posts.local/posts) [after 1, per list item]")
+}
@@ -0,0 +1,112 @@
+// Package ratelimit limits the requests of single operations with token buckets, e.g. to
+// exercise the retry logic of clients in dev
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+// Limit is the rate at which a token bucket of size Burst is refilled
+type Limit struct {
+	Rate  rate.Limit
+	Burst int
+}
+
+// Limiter holds a token bucket for every limited operation. Every request takes a token,
+// subscriptions take a single one per connection. It is safe for concurrent use.
+type Limiter struct {
+	log      *zap.Logger
+	limiters map[string]*rate.Limiter
+}
+
+// New creates a Limiter for the given limits by operation name or path
+func New(log *zap.Logger, limits map[string]Limit) *Limiter {
+	limiters := make(map[string]*rate.Limiter, len(limits))
+	for operation, limit := range limits {
+		limiters[operation] = rate.NewLimiter(limit.Rate, limit.Burst)
+	}
+	return &Limiter{
+		log:      log,
+		limiters: limiters,
+	}
+}
+
+// Handler returns next if the operation isn't limited. Otherwise, requests exceeding the
+// limit are rejected with 429 and a Retry-After header.
+func (l *Limiter) Handler(operation *wgpb.Operation, next http.Handler) http.Handler {
+	limiter, ok := l.limiters[operation.Name]
+	if !ok {
+		if limiter, ok = l.limiters[operation.Path]; !ok {
+			return next
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		reservation := limiter.Reserve()
+		delay := reservation.Delay()
+		if delay == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// the request is rejected, it must not take the token
+		reservation.Cancel()
+		retryAfter := RetryAfter(delay)
+		l.log.Warn("operation rate limited",
+			logging.WithRequestIDFromContext(r.Context()),
+			zap.String("operation", operation.Name),
+			zap.Duration("retryAfter", delay),
+		)
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	})
+}
+
+// RetryAfter returns the value of the Retry-After header for delay in whole seconds, at least 1
+func RetryAfter(delay time.Duration) int {
+	seconds := int(math.Ceil(delay.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// ParseRate parses rates in the form <requests>/<unit>, e.g. 5/s, 100/m or 1/500ms
+func ParseRate(value string) (rate.Limit, error) {
+	countValue, unit, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid rate %q, expected <requests>/<unit>", value)
+	}
+	count, err := strconv.ParseFloat(countValue, 64)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid rate %q, requests must be a positive number", value)
+	}
+	var interval time.Duration
+	switch unit {
+	case "s":
+		interval = time.Second
+	case "m":
+		interval = time.Minute
+	case "h":
+		interval = time.Hour
+	default:
+		interval, err = time.ParseDuration(unit)
+		if err != nil || interval <= 0 {
+			return 0, fmt.Errorf("invalid rate %q, unit must be s, m, h or a duration", value)
+		}
+	}
+	return rate.Limit(count / interval.Seconds()), nil
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/wundergraph/wundergraph/pkg/wgpb"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := New(zap.NewNop(), map[string]Limit{
+		"users/get": {Rate: rate.Every(time.Minute), Burst: 2},
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	unlimited := limiter.Handler(&wgpb.Operation{Name: "UsersList", Path: "users/list"}, ok)
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		unlimited.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/users/list", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	limited := limiter.Handler(&wgpb.Operation{Name: "UsersGet", Path: "users/get"}, ok)
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		limited.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/users/get", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	limited.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/users/get", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	// preflight requests don't count
+	rec = httptest.NewRecorder()
+	limited.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/operations/users/get", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestParseRate(t *testing.T) {
+	for value, expected := range map[string]rate.Limit{
+		"5/s":     5,
+		"120/m":   2,
+		"1/500ms": 2,
+	} {
+		limit, err := ParseRate(value)
+		require.NoError(t, err, value)
+		assert.InDelta(t, float64(expected), float64(limit), 0.0001, value)
+	}
+	for _, value := range []string{"5", "0/s", "a/s", "5/d"} {
+		_, err := ParseRate(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,303 @@
+// Package responsecache implements an in-memory cache for the responses of GET query
+// operations with TTL and surrogate key based invalidation.
+package responsecache
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+const (
+	// CacheHeader reports whether a response was served from the cache
+	CacheHeader = "X-Wg-Cache"
+	// SurrogateKeyHeader can be set by a handler to tag a response with additional
+	// space separated surrogate keys
+	SurrogateKeyHeader = "Surrogate-Key"
+
+	DefaultTTL        = time.Minute
+	DefaultMaxEntries = 1000
+)
+
+// DefaultVaryHeaders are part of the cache key unless Options.VaryHeaders is set,
+// so that responses are never shared between users
+var DefaultVaryHeaders = []string{"Authorization", "Cookie"}
+
+type Options struct {
+	// TTL is the duration a response is cached, defaults to DefaultTTL
+	TTL time.Duration
+	// VaryHeaders are the request headers that are part of the cache key, defaults to DefaultVaryHeaders
+	VaryHeaders []string
+	// MaxEntries limits the number of cached responses, defaults to DefaultMaxEntries
+	MaxEntries int
+	// AnnotatedOnly caches only the operations with a TTL of their own, see HandlerWithTTL
+	AnnotatedOnly bool
+}
+
+type entry struct {
+	status        int
+	header        http.Header
+	body          []byte
+	expires       time.Time
+	surrogateKeys []string
+}
+
+type Cache struct {
+	mu         sync.Mutex
+	entries    map[string]*entry
+	surrogates map[string]map[string]struct{}
+	options    Options
+	log        *zap.Logger
+	now        func() time.Time
+}
+
+func New(log *zap.Logger, options Options) *Cache {
+	if options.TTL <= 0 {
+		options.TTL = DefaultTTL
+	}
+	if options.VaryHeaders == nil {
+		options.VaryHeaders = DefaultVaryHeaders
+	}
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = DefaultMaxEntries
+	}
+	return &Cache{
+		entries:    map[string]*entry{},
+		surrogates: map[string]map[string]struct{}{},
+		options:    options,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+// Handler returns a middleware caching the responses of the query operation with the
+// given name. Responses are tagged with the operation name as surrogate key. Only
+// successful GET requests are cached, live queries are passed through.
+func (c *Cache) Handler(operationName string, handler http.Handler) http.Handler {
+	return c.HandlerWithTTL(operationName, 0, handler)
+}
+
+// HandlerWithTTL is like Handler, but caches responses for ttl instead of Options.TTL,
+// e.g. for operations annotated with @cache. A zero ttl uses Options.TTL, unless the cache
+// is AnnotatedOnly, then the responses aren't cached at all.
+func (c *Cache) HandlerWithTTL(operationName string, ttl time.Duration, handler http.Handler) http.Handler {
+	if ttl <= 0 {
+		if c.options.AnnotatedOnly {
+			return handler
+		}
+		ttl = c.options.TTL
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Has("wg_live") {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		requestLogger := c.log.With(
+			logging.WithRequestIDFromContext(r.Context()),
+			zap.String("operation", operationName),
+		)
+
+		key := c.key(operationName, r)
+		if e, ok := c.get(key); ok {
+			requestLogger.Debug("response cache hit")
+			for name, values := range e.header {
+				w.Header()[name] = append([]string(nil), values...)
+			}
+			w.Header().Set(CacheHeader, "HIT")
+			w.WriteHeader(e.status)
+			_, _ = w.Write(e.body)
+			return
+		}
+
+		requestLogger.Debug("response cache miss")
+		w.Header().Set(CacheHeader, "MISS")
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(rec, r)
+
+		if rec.status != http.StatusOK {
+			return
+		}
+		header := w.Header().Clone()
+		header.Del(CacheHeader)
+		surrogateKeys := append([]string{operationName}, strings.Fields(header.Get(SurrogateKeyHeader))...)
+		c.set(key, &entry{
+			status:        rec.status,
+			header:        header,
+			body:          rec.body.Bytes(),
+			surrogateKeys: surrogateKeys,
+		}, ttl)
+	})
+}
+
+// Purge removes all responses tagged with any of the surrogate keys and returns
+// the number of removed responses. Without keys, all responses are removed.
+func (c *Cache) Purge(surrogateKeys ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(surrogateKeys) == 0 {
+		purged := len(c.entries)
+		c.entries = map[string]*entry{}
+		c.surrogates = map[string]map[string]struct{}{}
+		return purged
+	}
+
+	purged := 0
+	for _, surrogateKey := range surrogateKeys {
+		for key := range c.surrogates[surrogateKey] {
+			if _, ok := c.entries[key]; ok {
+				c.deleteLocked(key)
+				purged++
+			}
+		}
+	}
+	return purged
+}
+
+type purgeRequest struct {
+	Keys []string `json:"keys"`
+}
+
+type purgeResponse struct {
+	Purged int `json:"purged"`
+}
+
+// PurgeHandler purges the surrogate keys given as JSON body ({"keys": [...]}) or
+// as key query parameters. Requests without keys purge the whole cache.
+func (c *Cache) PurgeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := r.URL.Query()["key"]
+		if r.Body != nil && r.ContentLength != 0 {
+			var req purgeRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			keys = append(keys, req.Keys...)
+		}
+		purged := c.Purge(keys...)
+		c.log.Debug("response cache purged",
+			logging.WithRequestIDFromContext(r.Context()),
+			zap.Strings("surrogateKeys", keys),
+			zap.Int("purged", purged),
+		)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(purgeResponse{Purged: purged})
+	})
+}
+
+// key is built from the operation, the request path, the sorted query parameters
+// (variables) and the values of the vary headers. The path is taken from the
+// RequestURI as handlers mounted under a prefix see a stripped URL.
+func (c *Cache) key(operationName string, r *http.Request) string {
+	path, _, _ := strings.Cut(r.RequestURI, "?")
+	if path == "" {
+		path = r.URL.Path
+	}
+	var sb strings.Builder
+	sb.WriteString(operationName)
+	sb.WriteByte(' ')
+	sb.WriteString(path)
+	sb.WriteByte('?')
+	// Encode sorts by key
+	sb.WriteString(r.URL.Query().Encode())
+	for _, name := range c.options.VaryHeaders {
+		sb.WriteByte('\n')
+		sb.WriteString(http.CanonicalHeaderKey(name))
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+	return sb.String()
+}
+
+func (c *Cache) get(key string) (*entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		c.deleteLocked(key)
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *Cache) set(key string, e *entry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.options.MaxEntries {
+		c.deleteExpiredLocked()
+		if len(c.entries) >= c.options.MaxEntries {
+			c.log.Debug("response cache full, response not cached", zap.Int("maxEntries", c.options.MaxEntries))
+			return
+		}
+	}
+	if _, ok := c.entries[key]; ok {
+		c.deleteLocked(key)
+	}
+	e.expires = c.now().Add(ttl)
+	c.entries[key] = e
+	for _, surrogateKey := range e.surrogateKeys {
+		keys, ok := c.surrogates[surrogateKey]
+		if !ok {
+			keys = map[string]struct{}{}
+			c.surrogates[surrogateKey] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (c *Cache) deleteLocked(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(c.entries, key)
+	for _, surrogateKey := range e.surrogateKeys {
+		delete(c.surrogates[surrogateKey], key)
+		if len(c.surrogates[surrogateKey]) == 0 {
+			delete(c.surrogates, surrogateKey)
+		}
+	}
+}
+
+func (c *Cache) deleteExpiredLocked() {
+	now := c.now()
+	for key, e := range c.entries {
+		if !now.Before(e.expires) {
+			c.deleteLocked(key)
+		}
+	}
+}
+
+// recorder captures the response while writing it to the client
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
@@ -0,0 +1,101 @@
+package responsecache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	cache := New(zap.NewNop(), Options{TTL: time.Minute})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	handler := cache.Handler("Users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set(SurrogateKeyHeader, "users")
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/operations/Users?id=1&limit=2", nil)
+	assert.Equal(t, "MISS", rec.Header().Get(CacheHeader))
+	rec = serve("/operations/Users?limit=2&id=1", nil)
+	assert.Equal(t, "HIT", rec.Header().Get(CacheHeader))
+	assert.Equal(t, `{"data":{}}`, rec.Body.String())
+	assert.Equal(t, 1, calls)
+
+	t.Run("vary headers", func(t *testing.T) {
+		rec := serve("/operations/Users?id=1&limit=2", http.Header{"Authorization": []string{"Bearer other"}})
+		assert.Equal(t, "MISS", rec.Header().Get(CacheHeader))
+	})
+
+	t.Run("live queries", func(t *testing.T) {
+		rec := serve("/operations/Users?wg_live=true", nil)
+		assert.Empty(t, rec.Header().Get(CacheHeader))
+	})
+
+	t.Run("purge by surrogate key", func(t *testing.T) {
+		assert.Equal(t, 0, cache.Purge("unknown"))
+		assert.Equal(t, 2, cache.Purge("users"))
+		rec := serve("/operations/Users?id=1&limit=2", nil)
+		assert.Equal(t, "MISS", rec.Header().Get(CacheHeader))
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		rec := serve("/operations/Users?id=1&limit=2", nil)
+		assert.Equal(t, "MISS", rec.Header().Get(CacheHeader))
+	})
+}
+
+func TestHandlerWithTTL(t *testing.T) {
+	cache := New(zap.NewNop(), Options{TTL: time.Minute, AnnotatedOnly: true})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	})
+	serve := func(handler http.Handler) string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/operations/Report", nil))
+		return rec.Header().Get(CacheHeader)
+	}
+
+	// operations without a TTL of their own aren't cached
+	assert.Empty(t, serve(cache.Handler("Users", ok)))
+
+	handler := cache.HandlerWithTTL("Report", 10*time.Second, ok)
+	assert.Equal(t, "MISS", serve(handler))
+	assert.Equal(t, "HIT", serve(handler))
+	now = now.Add(11 * time.Second)
+	assert.Equal(t, "MISS", serve(handler))
+}
+
+func TestPurgeHandler(t *testing.T) {
+	cache := New(zap.NewNop(), Options{})
+	handler := cache.Handler("Users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/operations/Users", nil))
+
+	rec := httptest.NewRecorder()
+	cache.PurgeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cache/purge", strings.NewReader(`{"keys":["Users"]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"purged":1}`, rec.Body.String())
+}
@@ -0,0 +1,78 @@
+package s3uploadclient
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// progressLogInterval is the minimum time between two progress logs of a file
+const progressLogInterval = time.Second
+
+const (
+	phaseReceiving = "receiving"
+	phaseUploading = "uploading"
+)
+
+// uploadProgress logs how many bytes of a file were transferred in a phase, at most once
+// per progressLogInterval. It counts writes of the received file and reads by minio, which
+// reads from PutObjectOptions.Progress as many bytes as it uploaded.
+type uploadProgress struct {
+	log         *zap.Logger
+	phase       string
+	total       int64
+	transferred int64
+	started     time.Time
+	lastLogged  time.Time
+	now         func() time.Time
+}
+
+func newUploadProgress(log *zap.Logger, phase string, total int64) *uploadProgress {
+	p := &uploadProgress{
+		log:   log,
+		phase: phase,
+		total: total,
+		now:   time.Now,
+	}
+	p.started = p.now()
+	p.lastLogged = p.started
+	return p
+}
+
+func (p *uploadProgress) Write(data []byte) (int, error) {
+	p.add(len(data))
+	return len(data), nil
+}
+
+func (p *uploadProgress) Read(data []byte) (int, error) {
+	p.add(len(data))
+	return len(data), nil
+}
+
+func (p *uploadProgress) add(n int) {
+	p.transferred += int64(n)
+	if now := p.now(); now.Sub(p.lastLogged) >= progressLogInterval {
+		p.lastLogged = now
+		p.log.Info("upload progress", p.fields()...)
+	}
+}
+
+// done logs the bytes transferred in total
+func (p *uploadProgress) done() {
+	p.log.Info("upload progress done", append(p.fields(), zap.Duration("duration", p.now().Sub(p.started)))...)
+}
+
+func (p *uploadProgress) fields() []zap.Field {
+	fields := []zap.Field{
+		zap.String("phase", p.phase),
+		zap.Int64("bytesTransferred", p.transferred),
+	}
+	// the size of received files isn't known upfront
+	if p.total > 0 {
+		fields = append(fields,
+			zap.Int64("bytesTotal", p.total),
+			zap.Int64("percent", p.transferred*100/p.total),
+		)
+	}
+	return fields
+}
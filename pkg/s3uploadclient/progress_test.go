@@ -0,0 +1,42 @@
+package s3uploadclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestUploadProgress(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	now := time.Unix(0, 0)
+	p := newUploadProgress(zap.New(core), phaseUploading, 400)
+	p.now = func() time.Time { return now }
+	p.started, p.lastLogged = now, now
+
+	_, _ = p.Read(make([]byte, 100))
+	assert.Equal(t, 0, logs.FilterMessage("upload progress").Len(), "logged before the interval passed")
+
+	now = now.Add(progressLogInterval)
+	_, _ = p.Read(make([]byte, 100))
+	progress := logs.FilterMessage("upload progress").All()
+	require.Len(t, progress, 1)
+	assert.Equal(t, map[string]interface{}{
+		"phase":            phaseUploading,
+		"bytesTransferred": int64(200),
+		"bytesTotal":       int64(400),
+		"percent":          int64(50),
+	}, progress[0].ContextMap())
+
+	_, _ = p.Write(make([]byte, 200))
+	now = now.Add(time.Second / 2)
+	p.done()
+	done := logs.FilterMessage("upload progress done").All()
+	require.Len(t, done, 1)
+	assert.Equal(t, int64(400), done[0].ContextMap()["bytesTransferred"])
+	assert.Equal(t, time.Duration(1500)*time.Millisecond, done[0].ContextMap()["duration"])
+}
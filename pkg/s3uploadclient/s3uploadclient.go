@@ -25,6 +25,7 @@ import (
 
 	"github.com/wundergraph/wundergraph/pkg/authentication"
 	"github.com/wundergraph/wundergraph/pkg/hooks"
+	"github.com/wundergraph/wundergraph/pkg/logging"
 	"github.com/wundergraph/wundergraph/pkg/pool"
 )
 
@@ -40,6 +41,7 @@ type S3UploadClient struct {
 	name           string
 	pool           *pool.Pool
 	logger         *zap.Logger
+	reportProgress bool
 }
 
 type preparedProfile struct {
@@ -84,6 +86,9 @@ type Options struct {
 	// Client name, must be set when using hooks because the name
 	// is part of the hook URL.
 	Name string
+	// ReportProgress logs the progress of receiving and uploading each file, meant
+	// for debugging large uploads in development
+	ReportProgress bool
 }
 
 type UploadResponse struct {
@@ -164,6 +169,7 @@ func NewS3UploadClient(endpoint string, s3Options Options) (*S3UploadClient, err
 		name:           s3Options.Name,
 		pool:           pool.New(),
 		logger:         s3Options.Logger,
+		reportProgress: s3Options.ReportProgress,
 	}
 
 	err = s.createBucket()
@@ -216,17 +222,25 @@ func (s *S3UploadClient) uploadToS3(ctx context.Context, r *http.Request, part *
 		_ = os.Remove(tmp.Name())
 	}()
 
-	written, err := io.Copy(tmp, part)
+	var dst io.Writer = tmp
+	received := s.progress(ctx, part, phaseReceiving, 0)
+	if received != nil {
+		dst = io.MultiWriter(tmp, received)
+	}
+	written, err := io.Copy(dst, part)
 	if err != nil {
 		return nil, err
 	}
+	if received != nil {
+		received.done()
+	}
 
 	filename, err := s.preUpload(ctx, r, part, tmp, written)
 	if err != nil {
 		return nil, err
 	}
 
-	info, err := s.client.FPutObject(ctx, s.bucketName, filename, tmp.Name(), minio.PutObjectOptions{
+	putOptions := minio.PutObjectOptions{
 		ContentType: contentType,
 		UserMetadata: map[string]string{
 			"metadata":           fileMetadataFromRequest(r),
@@ -234,14 +248,34 @@ func (s *S3UploadClient) uploadToS3(ctx context.Context, r *http.Request, part *
 			"original-extension": extension,
 			"original-size":      strconv.FormatInt(written, 10),
 		},
-	})
+	}
+	uploaded := s.progress(ctx, part, phaseUploading, written)
+	if uploaded != nil {
+		putOptions.Progress = uploaded
+	}
+	info, err := s.client.FPutObject(ctx, s.bucketName, filename, tmp.Name(), putOptions)
 	if err != nil {
 		return nil, err
 	}
+	if uploaded != nil {
+		uploaded.done()
+	}
 
 	return &info, nil
 }
 
+// progress returns nil unless progress reporting is enabled
+func (s *S3UploadClient) progress(ctx context.Context, part *multipart.Part, phase string, total int64) *uploadProgress {
+	if !s.reportProgress || s.logger == nil {
+		return nil
+	}
+	return newUploadProgress(s.logger.With(
+		logging.WithRequestIDFromContext(ctx),
+		zap.String("provider", s.name),
+		zap.String("fileName", part.FileName()),
+	), phase, total)
+}
+
 func (s *S3UploadClient) preUpload(ctx context.Context, r *http.Request, part *multipart.Part, tempFile *os.File, fileSize int64) (string, error) {
 	profileName, profile, err := s.uploadProfile(r)
 	if err != nil {
@@ -1,12 +1,18 @@
 package scriptrunner
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	gocmd "github.com/go-cmd/cmd"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
+
+	"github.com/wundergraph/wundergraph/pkg/processes"
 )
 
 type Config struct {
@@ -19,6 +25,16 @@ type Config struct {
 	FirstRunEnv   []string
 	AbsWorkingDir string
 	Logger        *zap.Logger
+	// LogWriter additionally receives all output lines of the script, if set. Lines logged
+	// because of StructuredOutput are left to Logger, which is expected to be teed into it.
+	LogWriter io.Writer
+	// Registry tracks the script processes while they run, if set
+	Registry *processes.Registry
+	// Ports the script listens on, they are recorded in the Registry
+	Ports []int
+	// StructuredOutput logs output lines of the script that aren't JSON objects with Logger
+	// instead of printing them, so the output is JSON throughout if Logger logs JSON
+	StructuredOutput bool
 }
 
 type ScriptRunner struct {
@@ -32,6 +48,10 @@ type ScriptRunner struct {
 	firstRun      bool
 	cmdDoneChan   chan struct{}
 	log           *zap.Logger
+	logWriter     io.Writer
+	registry      *processes.Registry
+	ports         []int
+	structured    bool
 	cmd           *gocmd.Cmd
 }
 
@@ -44,6 +64,10 @@ func NewScriptRunner(config *Config) *ScriptRunner {
 		executable:    config.Executable,
 		scriptArgs:    config.ScriptArgs,
 		scriptEnv:     config.ScriptEnv,
+		logWriter:     config.LogWriter,
+		registry:      config.Registry,
+		ports:         config.Ports,
+		structured:    config.StructuredOutput,
 		firstRun:      true,
 	}
 }
@@ -104,6 +128,10 @@ func (b *ScriptRunner) Run(ctx context.Context) chan struct{} {
 		cmdDir:     b.absWorkingDir,
 		scriptArgs: b.scriptArgs,
 		scriptEnv:  b.scriptEnv,
+		logWriter:  b.logWriter,
+	}
+	if b.structured {
+		cmdOptions.structuredLog = b.log.With(zap.String("runnerName", b.name))
 	}
 
 	if b.firstRun {
@@ -115,18 +143,19 @@ func (b *ScriptRunner) Run(ctx context.Context) chan struct{} {
 	b.cmd = cmd
 	b.cmdDoneChan = doneChan
 
-	// the script is either stopped by cancelling the context or when the script is done
+	// the script is either stopped by cancelling the context or when the script is done.
+	// The context may be canceled after a newer run replaced b.cmd, it must only stop its own.
 	go func() {
 		select {
 		case <-ctx.Done():
-			err := b.cmd.Stop()
+			err := cmd.Stop()
 			if err != nil {
 				b.log.Error("Stopping runner failed",
 					zap.String("runnerName", b.name),
 					zap.Error(err),
 				)
 			}
-			status := b.cmd.Status()
+			status := cmd.Status()
 			b.log.Debug("Script runner context cancelled",
 				zap.String("runnerName", b.name),
 				zap.Int("exit", status.Exit),
@@ -134,8 +163,8 @@ func (b *ScriptRunner) Run(ctx context.Context) chan struct{} {
 				zap.Int64("stopTs", status.StopTs),
 				zap.Bool("complete", status.Complete),
 			)
-		case <-b.cmd.Done():
-			status := b.cmd.Status()
+		case <-cmd.Done():
+			status := cmd.Status()
 			// exit code == -1 means the script was killed by a signal
 			// this is intentional and not an error and happens
 			// when we re-start the process after a watched file has changed
@@ -177,14 +206,58 @@ func (b *ScriptRunner) Run(ctx context.Context) chan struct{} {
 		zap.Error(err),
 	)
 
+	if b.registry != nil {
+		go b.track(cmd)
+	}
+
 	return doneChan
 }
 
+// track registers the process of cmd as soon as it's started and unregisters it when it's done
+func (b *ScriptRunner) track(cmd *gocmd.Cmd) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cmd.Done():
+			return
+		case <-ticker.C:
+		}
+		pid := cmd.Status().PID
+		if pid == 0 {
+			continue
+		}
+		err := b.registry.Register(processes.Process{
+			PID:            pid,
+			ParentPID:      os.Getpid(),
+			Name:           b.name,
+			Ports:          b.ports,
+			WunderGraphDir: b.absWorkingDir,
+			StartedAt:      time.Now(),
+			// go-cmd starts every script in its own process group
+			Group: true,
+		})
+		if err != nil {
+			b.log.Debug("Could not track runner process",
+				zap.String("runnerName", b.name),
+				zap.Error(err),
+			)
+			return
+		}
+		<-cmd.Done()
+		_ = b.registry.Unregister(pid)
+		return
+	}
+}
+
 type CmdOptions struct {
 	executable string
 	cmdDir     string
 	scriptArgs []string
 	scriptEnv  []string
+	logWriter  io.Writer
+	// structuredLog logs lines that aren't JSON objects, if set
+	structuredLog *zap.Logger
 }
 
 // newCmd creates a new command to run the bundler script.
@@ -213,16 +286,49 @@ func newCmd(options CmdOptions) (*gocmd.Cmd, chan struct{}) {
 					cmd.Stdout = nil
 					continue
 				}
-				fmt.Println(line)
+				writeLine(options, os.Stdout, line)
 			case line, open := <-cmd.Stderr:
 				if !open {
 					cmd.Stderr = nil
 					continue
 				}
-				fmt.Fprintln(os.Stderr, line)
+				writeLine(options, os.Stderr, line)
 			}
 		}
 	}()
 
 	return cmd, doneChan
 }
+
+// writeLine prints an output line of a script to w and the log writer. Lines logged with the
+// structured log are not written to the log writer again, the log is teed into it already.
+func writeLine(options CmdOptions, w io.Writer, line string) {
+	logged := printLine(options.structuredLog, w, line)
+	if options.logWriter != nil && !logged {
+		fmt.Fprintln(options.logWriter, line)
+	}
+}
+
+// printLine prints an output line of a script to w. With a structured log lines that are
+// JSON objects already, e.g. of the pino loggers of the SDK, are printed as they are and
+// all others are logged, output on stderr as warning. It returns true if the line was logged.
+func printLine(structuredLog *zap.Logger, w io.Writer, line string) bool {
+	if structuredLog == nil || isJSONObject(line) {
+		fmt.Fprintln(w, line)
+		return false
+	}
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	if w == os.Stderr {
+		structuredLog.Warn(line, zap.String("stream", "stderr"))
+		return true
+	}
+	structuredLog.Info(line, zap.String("stream", "stdout"))
+	return true
+}
+
+func isJSONObject(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed))
+}
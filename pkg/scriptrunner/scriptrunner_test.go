@@ -0,0 +1,53 @@
+package scriptrunner
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/wundergraph/wundergraph/pkg/logging"
+)
+
+func TestPrintLine(t *testing.T) {
+	var out bytes.Buffer
+	printLine(nil, &out, "plain output")
+	assert.Equal(t, "plain output\n", out.String())
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	structuredLog := zap.New(core)
+	out.Reset()
+	printLine(structuredLog, &out, `{"level":"info","msg":"from pino"}`)
+	printLine(structuredLog, &out, "console.log output")
+	printLine(structuredLog, &out, "  ")
+	assert.Equal(t, "{\"level\":\"info\",\"msg\":\"from pino\"}\n", out.String())
+
+	entries := logs.All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "console.log output", entries[0].Message)
+		assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+		assert.Equal(t, "stdout", entries[0].ContextMap()["stream"])
+	}
+}
+
+func TestWriteLineDevLog(t *testing.T) {
+	var devLog bytes.Buffer
+	options := CmdOptions{
+		logWriter:     &devLog,
+		structuredLog: logging.TeeToWriter(zap.NewNop(), &devLog),
+	}
+	writeLine(options, os.Stdout, "console.log output")
+	writeLine(options, os.Stdout, `{"level":"info","msg":"from pino"}`)
+	assert.Equal(t, 1, strings.Count(devLog.String(), "console.log output"))
+	assert.Equal(t, 1, strings.Count(devLog.String(), "from pino"))
+
+	// without a structured log every line is written as it is
+	devLog.Reset()
+	writeLine(CmdOptions{logWriter: &devLog}, os.Stdout, "console.log output")
+	assert.Equal(t, "console.log output\n", devLog.String())
+}
@@ -0,0 +1,141 @@
+// Package tracing records the phases of the development loop as spans in the Chrome
+// Trace Event Format, the resulting file can be opened in chrome://tracing or Perfetto.
+package tracing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	SpanBundle           = "bundle"
+	SpanConfigRun        = "config-run"
+	SpanOperationsBundle = "operations-bundle"
+	SpanHookRestart      = "hook-restart"
+	SpanNodeReload       = "node-reload"
+)
+
+// Event is a trace event, see https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type Event struct {
+	Name string `json:"name"`
+	// Cat is the category of the event
+	Cat string `json:"cat,omitempty"`
+	// Ph is the type of the event, X for complete events and M for metadata
+	Ph string `json:"ph"`
+	// Ts is the start of the event in microseconds
+	Ts int64 `json:"ts"`
+	// Dur is the duration of complete events in microseconds
+	Dur  int64                  `json:"dur,omitempty"`
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []Event `json:"traceEvents"`
+	DisplayTimeUnit string  `json:"displayTimeUnit"`
+}
+
+// Recorder collects spans. Every lane is rendered as a separate thread, spans of the
+// same lane must not overlap. A nil Recorder records nothing, so callers don't have
+// to check whether tracing is enabled. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	events []Event
+	lanes  map[string]int
+	now    func() time.Time
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		start: time.Now(),
+		lanes: map[string]int{},
+		now:   time.Now,
+	}
+}
+
+// Start starts a span with the given name in lane and returns the function ending it.
+// Ending a span more than once has no effect.
+func (r *Recorder) Start(name, lane string) (end func()) {
+	if r == nil {
+		return func() {}
+	}
+	start := r.now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.add(name, lane, start, r.now())
+		})
+	}
+}
+
+func (r *Recorder) add(name, lane string, start, end time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tid, ok := r.lanes[lane]
+	if !ok {
+		tid = len(r.lanes) + 1
+		r.lanes[lane] = tid
+	}
+	r.events = append(r.events, Event{
+		Name: name,
+		Cat:  lane,
+		Ph:   "X",
+		Ts:   start.Sub(r.start).Microseconds(),
+		Dur:  end.Sub(start).Microseconds(),
+		Pid:  os.Getpid(),
+		Tid:  tid,
+	})
+}
+
+// Events returns the recorded spans ordered by their start, preceded by the names of the lanes
+func (r *Recorder) Events() []Event {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]Event, 0, len(r.lanes)+len(r.events))
+	for lane, tid := range r.lanes {
+		events = append(events, Event{
+			Name: "thread_name",
+			Ph:   "M",
+			Pid:  os.Getpid(),
+			Tid:  tid,
+			Args: map[string]interface{}{"name": lane},
+		})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Tid < events[j].Tid
+	})
+	spans := append([]Event(nil), r.events...)
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Ts < spans[j].Ts
+	})
+	return append(events, spans...)
+}
+
+// WriteFile writes the recorded spans to path, replacing it
+func (r *Recorder) WriteFile(path string) error {
+	if r == nil {
+		return nil
+	}
+	data, err := json.Marshal(traceFile{
+		TraceEvents:     r.Events(),
+		DisplayTimeUnit: "ms",
+	})
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
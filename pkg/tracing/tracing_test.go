@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	now := r.start
+	r.now = func() time.Time { return now }
+
+	endBundle := r.Start(SpanBundle, "config-bundler")
+	now = now.Add(10 * time.Millisecond)
+	endRun := r.Start(SpanConfigRun, "config-runner")
+	now = now.Add(5 * time.Millisecond)
+	endRun()
+	endBundle()
+	endBundle()
+
+	events := r.Events()
+	require.Len(t, events, 4)
+
+	assert.Equal(t, "M", events[0].Ph)
+	// lanes are numbered in the order their first span ends
+	assert.Equal(t, "config-runner", events[0].Args["name"])
+	assert.Equal(t, "config-bundler", events[1].Args["name"])
+
+	assert.Equal(t, Event{Name: SpanBundle, Cat: "config-bundler", Ph: "X", Ts: 0, Dur: 15000, Pid: os.Getpid(), Tid: 2}, events[2])
+	assert.Equal(t, Event{Name: SpanConfigRun, Cat: "config-runner", Ph: "X", Ts: 10000, Dur: 5000, Pid: os.Getpid(), Tid: 1}, events[3])
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, r.WriteFile(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var file traceFile
+	require.NoError(t, json.Unmarshal(data, &file))
+	assert.Len(t, file.TraceEvents, 4)
+	assert.Equal(t, "ms", file.DisplayTimeUnit)
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Start(SpanNodeReload, "node")()
+	assert.Nil(t, r.Events())
+	assert.NoError(t, r.WriteFile(filepath.Join(t.TempDir(), "trace.json")))
+}
@@ -0,0 +1,127 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Op describes a set of file operations
+type Op uint32
+
+const (
+	Create Op = 1 << iota
+	Write
+	Remove
+	Rename
+)
+
+var opNames = []struct {
+	op   Op
+	name string
+}{
+	{Create, "CREATE"},
+	{Write, "WRITE"},
+	{Remove, "REMOVE"},
+	{Rename, "RENAME"},
+}
+
+// String returns the names of the operations in o separated by |, e.g. CREATE|WRITE
+func (o Op) String() string {
+	var names []string
+	for _, op := range opNames {
+		if o&op.op != 0 {
+			names = append(names, op.name)
+		}
+	}
+	if len(names) == 0 {
+		return "NONE"
+	}
+	return strings.Join(names, "|")
+}
+
+// Event is a change of a watched path reported by a Backend
+type Event struct {
+	Name string
+	Op   Op
+}
+
+// Backend notifies about changes of the paths added to it. Directories report
+// changes of their direct entries.
+type Backend interface {
+	Add(path string) error
+	Remove(path string) error
+	Events() <-chan Event
+	Errors() <-chan error
+	Close() error
+}
+
+// BackendName selects a Backend implementation
+type BackendName string
+
+const (
+	// BackendFsnotify uses fsnotify, i.e. inotify on Linux and kqueue on BSD and macOS
+	BackendFsnotify BackendName = "fsnotify"
+	// BackendKqueue talks to kqueue directly, only available on BSD and macOS
+	BackendKqueue BackendName = "kqueue"
+	// BackendPolling stats all paths periodically, it works on every platform and file system
+	BackendPolling BackendName = "polling"
+)
+
+var backendNames = []BackendName{BackendFsnotify, BackendKqueue, BackendPolling}
+
+// defaultBackend is used for watchers without Config.Backend
+var defaultBackend = BackendFsnotify
+
+// BackendNames returns the names of all backends, e.g. for flag descriptions
+func BackendNames() []string {
+	names := make([]string, 0, len(backendNames))
+	for _, name := range backendNames {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDefaultBackend changes the backend of all watchers without Config.Backend
+func SetDefaultBackend(name string) error {
+	backend, err := parseBackendName(name)
+	if err != nil {
+		return err
+	}
+	defaultBackend = backend
+	return nil
+}
+
+func parseBackendName(name string) (BackendName, error) {
+	for _, backend := range backendNames {
+		if string(backend) == name {
+			return backend, nil
+		}
+	}
+	return "", fmt.Errorf("unknown watch backend %q, expected one of %s", name, strings.Join(BackendNames(), ", "))
+}
+
+func newBackend(name BackendName, pollInterval time.Duration) (Backend, error) {
+	if name == "" {
+		name = defaultBackend
+	}
+	switch name {
+	case BackendFsnotify:
+		return newFsnotifyBackend()
+	case BackendKqueue:
+		return newKqueueBackend()
+	case BackendPolling:
+		return newPollingBackend(pollInterval), nil
+	default:
+		return nil, fmt.Errorf("unknown watch backend %q", name)
+	}
+}
+
+// isWatchLimitError returns true if err indicates that the OS doesn't allow watching more paths
+func isWatchLimitError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"github.com/fsnotify/fsnotify"
+)
+
+type fsnotifyBackend struct {
+	watcher *fsnotify.Watcher
+	events  chan Event
+	done    chan struct{}
+}
+
+func newFsnotifyBackend() (*fsnotifyBackend, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	b := &fsnotifyBackend{
+		watcher: watcher,
+		events:  make(chan Event),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+func (b *fsnotifyBackend) run() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case evt, ok := <-b.watcher.Events:
+			if !ok {
+				return
+			}
+			var op Op
+			if evt.Op&fsnotify.Create != 0 {
+				op |= Create
+			}
+			if evt.Op&fsnotify.Write != 0 {
+				op |= Write
+			}
+			if evt.Op&fsnotify.Remove != 0 {
+				op |= Remove
+			}
+			if evt.Op&fsnotify.Rename != 0 {
+				op |= Rename
+			}
+			if op == 0 {
+				continue
+			}
+			select {
+			case b.events <- Event{Name: evt.Name, Op: op}:
+			case <-b.done:
+				return
+			}
+		}
+	}
+}
+
+func (b *fsnotifyBackend) Add(path string) error {
+	return b.watcher.Add(path)
+}
+
+func (b *fsnotifyBackend) Remove(path string) error {
+	return b.watcher.Remove(path)
+}
+
+func (b *fsnotifyBackend) Events() <-chan Event {
+	return b.events
+}
+
+func (b *fsnotifyBackend) Errors() <-chan error {
+	return b.watcher.Errors
+}
+
+func (b *fsnotifyBackend) Close() error {
+	close(b.done)
+	return b.watcher.Close()
+}
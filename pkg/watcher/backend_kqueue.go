@@ -0,0 +1,179 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package watcher
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+)
+
+const kqueueFflags = syscall.NOTE_DELETE | syscall.NOTE_WRITE | syscall.NOTE_EXTEND | syscall.NOTE_ATTRIB | syscall.NOTE_RENAME
+
+type kqueuePath struct {
+	path string
+	// entries of a directory, nil for files
+	entries map[string]struct{}
+}
+
+// kqueueBackend registers a vnode filter for every path with kqueue, bypassing fsnotify
+type kqueueBackend struct {
+	kq     int
+	mu     sync.Mutex
+	fds    map[int]*kqueuePath
+	paths  map[string]int
+	events chan Event
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newKqueueBackend() (Backend, error) {
+	kq, err := syscall.Kqueue()
+	if err != nil {
+		return nil, err
+	}
+	b := &kqueueBackend{
+		kq:     kq,
+		fds:    map[int]*kqueuePath{},
+		paths:  map[string]int{},
+		events: make(chan Event),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+func (b *kqueueBackend) Add(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.paths[path]; ok {
+		return nil
+	}
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(fd, &stat); err != nil {
+		_ = syscall.Close(fd)
+		return err
+	}
+	var change syscall.Kevent_t
+	syscall.SetKevent(&change, fd, syscall.EVFILT_VNODE, syscall.EV_ADD|syscall.EV_CLEAR|syscall.EV_ENABLE)
+	change.Fflags = kqueueFflags
+	if _, err := syscall.Kevent(b.kq, []syscall.Kevent_t{change}, nil, nil); err != nil {
+		_ = syscall.Close(fd)
+		return err
+	}
+	watched := &kqueuePath{path: path}
+	if stat.Mode&syscall.S_IFMT == syscall.S_IFDIR {
+		watched.entries = dirEntries(path)
+	}
+	b.fds[fd] = watched
+	b.paths[path] = fd
+	return nil
+}
+
+func (b *kqueueBackend) Remove(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(path)
+	return nil
+}
+
+func (b *kqueueBackend) removeLocked(path string) {
+	fd, ok := b.paths[path]
+	if !ok {
+		return
+	}
+	// closing the descriptor removes its filters from the queue
+	_ = syscall.Close(fd)
+	delete(b.paths, path)
+	delete(b.fds, fd)
+}
+
+func (b *kqueueBackend) Events() <-chan Event {
+	return b.events
+}
+
+func (b *kqueueBackend) Errors() <-chan error {
+	return b.errors
+}
+
+func (b *kqueueBackend) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for path := range b.paths {
+		b.removeLocked(path)
+	}
+	return nil
+}
+
+func (b *kqueueBackend) run() {
+	defer syscall.Close(b.kq)
+	// the timeout allows to notice Close
+	timeout := syscall.NsecToTimespec(int64(100 * 1e6))
+	received := make([]syscall.Kevent_t, 16)
+	for {
+		select {
+		case <-b.done:
+			return
+		default:
+		}
+		n, err := syscall.Kevent(b.kq, nil, received, &timeout)
+		if err != nil {
+			if errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			select {
+			case b.errors <- err:
+			case <-b.done:
+			}
+			return
+		}
+		for _, evt := range b.translate(received[:n]) {
+			select {
+			case b.events <- evt:
+			case <-b.done:
+				return
+			}
+		}
+	}
+}
+
+func (b *kqueueBackend) translate(received []syscall.Kevent_t) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var events []Event
+	for _, kevent := range received {
+		watched, ok := b.fds[int(kevent.Ident)]
+		if !ok {
+			continue
+		}
+		switch {
+		case kevent.Fflags&syscall.NOTE_DELETE != 0:
+			events = append(events, Event{Name: watched.path, Op: Remove})
+			b.removeLocked(watched.path)
+		case kevent.Fflags&syscall.NOTE_RENAME != 0:
+			events = append(events, Event{Name: watched.path, Op: Rename})
+			b.removeLocked(watched.path)
+		case watched.entries != nil:
+			// a write to a directory is a change of its entries
+			entries := dirEntries(watched.path)
+			events = append(events, diffEntries(watched.path, watched.entries, entries, func(path string) bool {
+				_, ok := b.paths[path]
+				return ok
+			})...)
+			watched.entries = entries
+		default:
+			events = append(events, Event{Name: watched.path, Op: Write})
+		}
+	}
+	return events
+}
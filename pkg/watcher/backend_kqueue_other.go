@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package watcher
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func newKqueueBackend() (Backend, error) {
+	return nil, fmt.Errorf("the kqueue watch backend is not available on %s", runtime.GOOS)
+}
@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+type polledPath struct {
+	stamp string
+	// entries of a directory, nil for files
+	entries map[string]struct{}
+}
+
+// pollingBackend periodically stats its paths. It's used as a Backend on its own and
+// for paths which could not be registered with the OS watcher.
+type pollingBackend struct {
+	mu     sync.Mutex
+	paths  map[string]*polledPath
+	events chan Event
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newPollingBackend(interval time.Duration) *pollingBackend {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	b := &pollingBackend{
+		paths:  map[string]*polledPath{},
+		events: make(chan Event),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+	go b.run(interval)
+	return b
+}
+
+func (b *pollingBackend) Add(path string) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	polled := &polledPath{}
+	polled.stamp, _ = computeStamp(path, stat)
+	if stat.IsDir() {
+		polled.entries = dirEntries(path)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paths[path] = polled
+	return nil
+}
+
+func (b *pollingBackend) Remove(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.paths, path)
+	return nil
+}
+
+func (b *pollingBackend) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.paths)
+}
+
+func (b *pollingBackend) Events() <-chan Event {
+	return b.events
+}
+
+func (b *pollingBackend) Errors() <-chan error {
+	return b.errors
+}
+
+func (b *pollingBackend) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	return nil
+}
+
+func (b *pollingBackend) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			for _, evt := range b.poll() {
+				select {
+				case b.events <- evt:
+				case <-b.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// poll returns the events of all paths that changed since the last call
+func (b *pollingBackend) poll() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var events []Event
+	for path, polled := range b.paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			events = append(events, Event{Name: path, Op: Remove})
+			delete(b.paths, path)
+			continue
+		}
+		stamp, _ := computeStamp(path, stat)
+		if stamp == polled.stamp {
+			continue
+		}
+		polled.stamp = stamp
+		if !stat.IsDir() {
+			events = append(events, Event{Name: path, Op: Write})
+			continue
+		}
+		entries := dirEntries(path)
+		events = append(events, diffEntries(path, polled.entries, entries, func(path string) bool {
+			_, ok := b.paths[path]
+			return ok
+		})...)
+		polled.entries = entries
+	}
+	return events
+}
+
+// dirEntries returns the names of the direct entries of the directory
+func dirEntries(path string) map[string]struct{} {
+	entries := map[string]struct{}{}
+	des, err := os.ReadDir(path)
+	if err != nil {
+		return entries
+	}
+	for _, de := range des {
+		entries[de.Name()] = struct{}{}
+	}
+	return entries
+}
+
+// diffEntries returns Create events for new entries and Remove events for removed entries
+// of dir. Removed entries which are tracked report their removal on their own.
+func diffEntries(dir string, previous, current map[string]struct{}, isTracked func(path string) bool) []Event {
+	var events []Event
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			events = append(events, Event{Name: filepath.Join(dir, name), Op: Create})
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; ok {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if !isTracked(path) {
+			events = append(events, Event{Name: path, Op: Remove})
+		}
+	}
+	return events
+}
@@ -0,0 +1,45 @@
+package watcher
+
+import "sync"
+
+// Pause holds back the changes of all watchers sharing it while paused. Changes are still
+// collected, so resuming reports everything changed in the meantime at once.
+type Pause struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+func NewPause() *Pause {
+	return &Pause{}
+}
+
+// Toggle pauses or resumes the watchers and returns true if they are paused afterwards
+func (p *Pause) Toggle() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed != nil {
+		close(p.resumed)
+		p.resumed = nil
+		return false
+	}
+	p.resumed = make(chan struct{})
+	return true
+}
+
+// Paused returns true if the watchers are paused
+func (p *Pause) Paused() bool {
+	return p.wait() != nil
+}
+
+// wait returns a channel closed on resume, nil if p is nil or not paused
+func (p *Pause) wait() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed == nil {
+		return nil
+	}
+	return p.resumed
+}
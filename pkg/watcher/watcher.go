@@ -3,6 +3,7 @@ package watcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -10,10 +11,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bep/debounce"
-	"github.com/fsnotify/fsnotify"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -29,7 +30,7 @@ var debounceDelay = 20 * time.Millisecond
 
 func newPathSet() *pathSet {
 	return &pathSet{
-		paths: map[string]struct{}{},
+		paths: map[string]Op{},
 	}
 }
 
@@ -38,30 +39,59 @@ type WatchPath struct {
 	Path     string
 }
 
+// Change is a path that has changed with the operations that changed it
+type Change struct {
+	Path string
+	Op   Op
+}
+
 // pathSet is used to collect paths that have changed and flush them all at once
 // when the watch function is triggered.
 type pathSet struct {
 	mu    sync.RWMutex
-	paths map[string]struct{}
+	paths map[string]Op
 }
 
 // Add a path to the set
-func (p *pathSet) Add(path string) {
+func (p *pathSet) Add(path string, op Op) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.paths[path] = struct{}{}
+	p.paths[path] |= op
 }
 
-// Flush the stored paths and clear the path set.
-func (p *pathSet) Flush() (paths []string) {
+// Flush the stored changes sorted by path and clear the path set.
+func (p *pathSet) Flush() (changes []Change) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	for path := range p.paths {
-		paths = append(paths, path)
+	for path, op := range p.paths {
+		changes = append(changes, Change{Path: path, Op: op})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	p.paths = map[string]Op{}
+	return changes
+}
+
+// maxDescribedChanges limits the number of changes listed by DescribeChanges
+const maxDescribedChanges = 5
+
+// DescribeChanges lists the operations and paths of changes, e.g. "WRITE operations/getUser.graphql".
+// Paths are made relative to baseDir if they are inside of it.
+func DescribeChanges(changes []Change, baseDir string) string {
+	descriptions := make([]string, 0, len(changes))
+	for i, change := range changes {
+		if i == maxDescribedChanges {
+			descriptions = append(descriptions, fmt.Sprintf("and %d more", len(changes)-maxDescribedChanges))
+			break
+		}
+		path := change.Path
+		if rel, err := filepath.Rel(baseDir, path); baseDir != "" && err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		descriptions = append(descriptions, change.Op.String()+" "+filepath.ToSlash(path))
 	}
-	sort.Strings(paths)
-	p.paths = map[string]struct{}{}
-	return paths
+	return strings.Join(descriptions, ", ")
 }
 
 type Config struct {
@@ -69,6 +99,18 @@ type Config struct {
 	WatchPaths []*WatchPath
 	// IgnorePaths is the list of patterns to ignore for changes.
 	IgnorePaths []string
+	// MaxWatches limits the number of paths registered with the OS watcher. Further paths
+	// are polled instead. Zero means no limit other than the one enforced by the OS.
+	MaxWatches int
+	// PollInterval is the interval to check paths that could not be registered with the OS watcher
+	// and of the polling backend. Defaults to one second.
+	PollInterval time.Duration
+	// Backend selects the backend, defaults to the one set by SetDefaultBackend or fsnotify
+	Backend BackendName
+	// NewBackend overrides Backend, e.g. to use a fake backend in tests
+	NewBackend func() (Backend, error)
+	// Pause holds back changes while paused, nil never pauses
+	Pause *Pause
 }
 
 type Watcher struct {
@@ -85,26 +127,109 @@ func NewWatcher(name string, config *Config, log *zap.Logger) *Watcher {
 	}
 }
 
-// Watch function
+// Watch calls fn with the changed paths, see WatchChanges
 func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) error {
-	watcher, err := fsnotify.NewWatcher()
+	return b.WatchChanges(ctx, func(changes []Change) error {
+		paths := make([]string, len(changes))
+		for i, change := range changes {
+			paths[i] = change.Path
+		}
+		return fn(paths)
+	})
+}
+
+// WatchChanges calls fn with the changes of the watched paths until ctx is done or fn
+// returns an error. Changes in quick succession are reported together.
+func (b *Watcher) WatchChanges(ctx context.Context, fn func(changes []Change) error) error {
+	newBackendFn := b.config.NewBackend
+	if newBackendFn == nil {
+		newBackendFn = func() (Backend, error) {
+			return newBackend(b.config.Backend, b.config.PollInterval)
+		}
+	}
+	watcher, err := newBackendFn()
 	if err != nil {
 		return err
 	}
 	defer watcher.Close()
+	// Paths exceeding MaxWatches or the limit of the OS are polled
+	poller := newPollingBackend(b.config.PollInterval)
+	defer poller.Close()
+	watched := 0
+	limitReported := false
+	reportLimit := func(path string, err error) {
+		if limitReported {
+			return
+		}
+		limitReported = true
+		if err != nil {
+			b.log.Warn("OS file watch limit reached, falling back to polling for remaining paths",
+				zap.String("watcherName", b.name),
+				zap.Int("osLimit", osWatchLimit()),
+				zap.Int("watched", watched),
+				zap.String("path", path),
+				zap.String("help", watchLimitHint),
+				zap.Error(err),
+			)
+			return
+		}
+		b.log.Warn("Configured file watch limit reached, falling back to polling for remaining paths",
+			zap.String("watcherName", b.name),
+			zap.Int("maxWatches", b.config.MaxWatches),
+			zap.String("path", path),
+		)
+	}
+	add := func(path string) error {
+		if b.config.MaxWatches > 0 && watched >= b.config.MaxWatches {
+			reportLimit(path, nil)
+			// paths vanishing in the meantime are reported by their parent directory
+			_ = poller.Add(path)
+			return nil
+		}
+		if err := watcher.Add(path); err != nil {
+			if isWatchLimitError(err) {
+				reportLimit(path, err)
+				_ = poller.Add(path)
+				return nil
+			}
+			return err
+		}
+		watched++
+		return nil
+	}
 	// Trigger is debounced to group events together
 	errorCh := make(chan error)
 	pathset := newPathSet()
 	debounce := debounce.New(debounceDelay)
-	trigger := func(path string) {
-		pathset.Add(path)
-		debounce(func() {
-			paths := pathset.Flush()
-			b.log.Debug("File change detected", zap.String("watcherName", b.name), zap.Strings("paths", paths))
-			if err := fn(paths); err != nil {
-				errorCh <- err
+	// while paused the changes stay in the path set until a single flush on resume
+	var waitingForResume int32
+	var flush func()
+	flush = func() {
+		if resumed := b.config.Pause.wait(); resumed != nil {
+			if atomic.CompareAndSwapInt32(&waitingForResume, 0, 1) {
+				go func() {
+					select {
+					case <-ctx.Done():
+					case <-resumed:
+						atomic.StoreInt32(&waitingForResume, 0)
+						debounce(flush)
+					}
+				}()
 			}
-		})
+			return
+		}
+		changes := pathset.Flush()
+		if len(changes) == 0 {
+			return
+		}
+		b.log.Debug("File change detected", zap.String("watcherName", b.name), zap.String("changes", DescribeChanges(changes, "")))
+		if err := fn(changes); err != nil {
+			errorCh <- err
+		}
+	}
+	trigger := func(path string, op Op) {
+		pathset.Add(path, op)
+		debounce(flush)
 	}
 	// Avoid duplicate events by checking the stamp of the file. This allows us
 	// to bring down the debounce delay to trigger events faster.
@@ -142,16 +267,18 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 		}
 		// Remove the path and emit an update
 		watcher.Remove(path)
+		poller.Remove(path)
 		// Trigger an update
-		trigger(path)
+		trigger(path, Rename)
 		return nil
 	}
 	// Remove the file or directory from the watcher.
 	// We intentionally ignore errors for this case.
 	remove := func(path string) error {
 		watcher.Remove(path)
+		poller.Remove(path)
 		// Trigger an update
-		trigger(path)
+		trigger(path, Remove)
 		return nil
 	}
 	// Watching a file or directory as long as it's not inside .gitignore.
@@ -171,7 +298,7 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 		if isDuplicate(path, stat) {
 			return nil
 		}
-		err = watcher.Add(path)
+		err = add(path)
 		if err != nil {
 			return err
 		}
@@ -187,11 +314,11 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 					return err
 				}
 			}
-			trigger(path)
+			trigger(path, Create)
 			return nil
 		}
 		// Otherwise, trigger the create
-		trigger(path)
+		trigger(path, Create)
 		return nil
 	}
 	// A file or directory has been updated. Notify our matchers.
@@ -208,7 +335,7 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 			return nil
 		}
 		// Trigger an update
-		trigger(path)
+		trigger(path, Write)
 		return nil
 	}
 
@@ -230,7 +357,13 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 			if err := shouldIgnore(path, de); err != nil {
 				return err
 			}
-			watcher.Add(path)
+			if err := add(path); err != nil {
+				b.log.Debug("could not watch path",
+					zap.String("watcherName", b.name),
+					zap.String("path", path),
+					zap.Error(err),
+				)
+			}
 			return nil
 		}); err != nil {
 			return err
@@ -240,6 +373,35 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 	// Watch for file events!
 	// Note: The FAQ currently says it needs to be in a separate Go routine
 	// https://github.com/fsnotify/fsnotify#faq, so we'll do that.
+	handle := func(evt Event) error {
+		// Sometimes the event name can be empty on Linux during deletes. Ignore
+		// those events.
+		if evt.Name == "" {
+			return nil
+		}
+
+		// Switch over the operations
+		switch op := evt.Op; {
+
+		// Handle rename events
+		case op&Rename != 0:
+			return rename(evt.Name)
+
+		// Handle remove events
+		case op&Remove != 0:
+			return remove(evt.Name)
+
+		// Handle create events
+		case op&Create != 0:
+			return create(evt.Name)
+
+		// Handle write events
+		case op&Write != 0:
+			return write(evt.Name)
+		}
+		return nil
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
 		for {
@@ -248,41 +410,17 @@ func (b *Watcher) Watch(ctx context.Context, fn func(paths []string) error) erro
 				return nil
 			case err := <-errorCh:
 				return err
-			case err := <-watcher.Errors:
+			case err := <-watcher.Errors():
 				return err
-			case evt := <-watcher.Events:
-				// Sometimes the event name can be empty on Linux during deletes. Ignore
-				// those events.
-				if evt.Name == "" {
-					continue
+			case err := <-poller.Errors():
+				return err
+			case evt := <-watcher.Events():
+				if err := handle(evt); err != nil {
+					return err
 				}
-
-				// Switch over the operations
-				switch op := evt.Op; {
-
-				// Handle rename events
-				case op&fsnotify.Rename != 0:
-					if err := rename(evt.Name); err != nil {
-						return err
-					}
-
-				// Handle remove events
-				case op&fsnotify.Remove != 0:
-					if err := remove(evt.Name); err != nil {
-						return err
-					}
-
-				// Handle create events
-				case op&fsnotify.Create != 0:
-					if err := create(evt.Name); err != nil {
-						return err
-					}
-
-				// Handle write events
-				case op&fsnotify.Write != 0:
-					if err := write(evt.Name); err != nil {
-						return err
-					}
+			case evt := <-poller.Events():
+				if err := handle(evt); err != nil {
+					return err
 				}
 			}
 		}
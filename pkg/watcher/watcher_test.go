@@ -0,0 +1,220 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatchFallsBackToPolling(t *testing.T) {
+	dir := t.TempDir()
+	watchedDir := filepath.Join(dir, "watched")
+	require.NoError(t, os.Mkdir(watchedDir, os.ModePerm))
+	file := filepath.Join(dir, "datasources.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("a"), 0644))
+
+	w := NewWatcher("test", &Config{
+		WatchPaths:   []*WatchPath{{Path: watchedDir}, {Path: file}},
+		MaxWatches:   1,
+		PollInterval: 10 * time.Millisecond,
+	}, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan []string, 1)
+	go func() {
+		_ = w.Watch(ctx, func(paths []string) error {
+			select {
+			case changed <- paths:
+			default:
+			}
+			return nil
+		})
+	}()
+
+	// only watchedDir is registered with the OS watcher, the file is polled
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("changed"), 0644))
+
+	select {
+	case paths := <-changed:
+		assert.Contains(t, paths, file)
+	case <-ctx.Done():
+		t.Fatal("change of polled file not detected")
+	}
+}
+
+func TestIsWatchLimitError(t *testing.T) {
+	assert.True(t, isWatchLimitError(&os.PathError{Op: "inotify_add_watch", Err: syscall.ENOSPC}))
+	assert.False(t, isWatchLimitError(os.ErrNotExist))
+}
+
+type fakeBackend struct {
+	added  chan string
+	events chan Event
+	errors chan error
+}
+
+func (f *fakeBackend) Add(path string) error {
+	f.added <- path
+	return nil
+}
+func (f *fakeBackend) Remove(path string) error { return nil }
+func (f *fakeBackend) Events() <-chan Event     { return f.events }
+func (f *fakeBackend) Errors() <-chan error     { return f.errors }
+func (f *fakeBackend) Close() error             { return nil }
+
+func TestWatchWithFakeBackend(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "wundergraph.config.ts")
+	require.NoError(t, os.WriteFile(file, []byte("a"), 0644))
+
+	backend := &fakeBackend{
+		added:  make(chan string, 1),
+		events: make(chan Event),
+		errors: make(chan error),
+	}
+	w := NewWatcher("test", &Config{
+		WatchPaths: []*WatchPath{{Path: file}},
+		NewBackend: func() (Backend, error) {
+			return backend, nil
+		},
+	}, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan []string, 1)
+	go func() {
+		_ = w.Watch(ctx, func(paths []string) error {
+			changed <- paths
+			return nil
+		})
+	}()
+
+	assert.Equal(t, file, <-backend.added)
+	backend.events <- Event{Name: file, Op: Write}
+
+	select {
+	case paths := <-changed:
+		assert.Equal(t, []string{file}, paths)
+	case <-ctx.Done():
+		t.Fatal("event of fake backend not handled")
+	}
+}
+
+func TestPollingBackend(t *testing.T) {
+	dir := t.TempDir()
+	b := newPollingBackend(10 * time.Millisecond)
+	defer b.Close()
+	require.NoError(t, b.Add(dir))
+
+	file := filepath.Join(dir, "operation.graphql")
+	require.NoError(t, os.WriteFile(file, []byte("a"), 0644))
+
+	select {
+	case evt := <-b.Events():
+		assert.Equal(t, Event{Name: file, Op: Create}, evt)
+	case <-time.After(5 * time.Second):
+		t.Fatal("creation of file not detected")
+	}
+}
+
+func TestSetDefaultBackend(t *testing.T) {
+	defer func() {
+		defaultBackend = BackendFsnotify
+	}()
+	assert.NoError(t, SetDefaultBackend("polling"))
+	assert.Equal(t, BackendPolling, defaultBackend)
+	assert.Error(t, SetDefaultBackend("inotify"))
+}
+
+func TestPathSetMergesOps(t *testing.T) {
+	set := newPathSet()
+	set.Add("/app/b.ts", Create)
+	set.Add("/app/a.ts", Write)
+	set.Add("/app/b.ts", Write)
+	assert.Equal(t, []Change{{Path: "/app/a.ts", Op: Write}, {Path: "/app/b.ts", Op: Create | Write}}, set.Flush())
+	assert.Empty(t, set.Flush())
+}
+
+func TestDescribeChanges(t *testing.T) {
+	base := filepath.Join(string(filepath.Separator), "app", ".wundergraph")
+	changes := []Change{
+		{Path: filepath.Join(base, "operations", "getUser.graphql"), Op: Write},
+		{Path: filepath.Join(base, "operations", ".getUser.graphql.swp"), Op: Create | Remove},
+		{Path: filepath.Join(string(filepath.Separator), "tmp", "shared.ts"), Op: Rename},
+	}
+	assert.Equal(t, "WRITE operations/getUser.graphql, CREATE|REMOVE operations/.getUser.graphql.swp, RENAME /tmp/shared.ts", DescribeChanges(changes, base))
+
+	for i := 0; i < 5; i++ {
+		changes = append(changes, Change{Path: filepath.Join(base, "a.ts"), Op: Write})
+	}
+	assert.Contains(t, DescribeChanges(changes, base), ", and 3 more")
+	assert.Equal(t, "NONE", Op(0).String())
+}
+
+func TestPause(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.graphql"), filepath.Join(dir, "second.graphql")
+	require.NoError(t, os.WriteFile(first, []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("b"), 0644))
+
+	backend := &fakeBackend{
+		added:  make(chan string, 3),
+		events: make(chan Event),
+		errors: make(chan error),
+	}
+	pause := NewPause()
+	w := NewWatcher("test", &Config{
+		WatchPaths: []*WatchPath{{Path: dir}},
+		NewBackend: func() (Backend, error) {
+			return backend, nil
+		},
+		Pause: pause,
+	}, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan []string, 2)
+	go func() {
+		_ = w.Watch(ctx, func(paths []string) error {
+			changed <- paths
+			return nil
+		})
+	}()
+	for i := 0; i < 3; i++ {
+		<-backend.added
+	}
+
+	require.True(t, pause.Toggle())
+	assert.True(t, pause.Paused())
+	backend.events <- Event{Name: first, Op: Write}
+	backend.events <- Event{Name: second, Op: Write}
+	select {
+	case paths := <-changed:
+		t.Fatalf("changes reported while paused: %v", paths)
+	case <-time.After(10 * debounceDelay):
+	}
+
+	require.False(t, pause.Toggle())
+	select {
+	case paths := <-changed:
+		assert.Equal(t, []string{first, second}, paths)
+	case <-ctx.Done():
+		t.Fatal("changes not reported on resume")
+	}
+	select {
+	case paths := <-changed:
+		t.Fatalf("changes reported twice: %v", paths)
+	case <-time.After(10 * debounceDelay):
+	}
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+// +build darwin
+
+package watcher
+
+import "syscall"
+
+const watchLimitHint = "raise the open file limit e.g. with 'ulimit -n 10240'"
+
+// osWatchLimit returns the maximum number of open files or -1 if unknown,
+// kqueue requires an open file descriptor for each watched path
+func osWatchLimit() int {
+	var rlimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+		return -1
+	}
+	return int(rlimit.Cur)
+}
@@ -0,0 +1,25 @@
+//go:build linux
+// +build linux
+
+package watcher
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+const watchLimitHint = "raise the inotify limit e.g. with 'sudo sysctl fs.inotify.max_user_watches=524288'"
+
+// osWatchLimit returns the maximum number of inotify watches per user or -1 if unknown
+func osWatchLimit() int {
+	data, err := os.ReadFile("/proc/sys/fs/inotify/max_user_watches")
+	if err != nil {
+		return -1
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return -1
+	}
+	return limit
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package watcher
+
+const watchLimitHint = "raise the limit of open files or watches of your operating system"
+
+// osWatchLimit returns -1 because the limit can't be determined on this platform
+func osWatchLimit() int {
+	return -1
+}
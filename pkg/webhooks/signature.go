@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Provider selects the scheme used to sign test payloads
+type Provider string
+
+const (
+	// ProviderGitHub signs like GitHub, matching the HMAC_SHA256 verifier with
+	// the default header and prefix
+	ProviderGitHub Provider = "github"
+	// ProviderStripe signs like Stripe, the signature covers the timestamp and the payload
+	ProviderStripe Provider = "stripe"
+)
+
+// Providers lists the supported providers
+var Providers = []Provider{ProviderGitHub, ProviderStripe}
+
+// HMACSHA256 returns the hex encoded HMAC-SHA256 of body, as checked by the
+// HMAC_SHA256 webhook verifier of the node
+func HMACSHA256(secret, body []byte) string {
+	hash := hmac.New(sha256.New, secret)
+	_, _ = hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// SignatureHeader returns the name and the value of the header provider sends
+// along with body, signed with secret at now
+func SignatureHeader(provider Provider, secret, body []byte, now time.Time) (name, value string, err error) {
+	switch provider {
+	case ProviderGitHub:
+		return "X-Hub-Signature-256", "sha256=" + HMACSHA256(secret, body), nil
+	case ProviderStripe:
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		signed := append([]byte(timestamp+"."), body...)
+		return "Stripe-Signature", "t=" + timestamp + ",v1=" + HMACSHA256(secret, signed), nil
+	}
+	return "", "", fmt.Errorf("unknown webhook provider %q, supported providers are %v", provider, Providers)
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureHeader(t *testing.T) {
+	secret := []byte("It's a Secret to Everybody")
+	body := []byte("Hello, World!")
+
+	// example from the GitHub documentation on validating webhook deliveries
+	name, value, err := SignatureHeader(ProviderGitHub, secret, body, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "X-Hub-Signature-256", name)
+	assert.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", value)
+
+	now := time.Unix(1700000000, 0)
+	name, value, err = SignatureHeader(ProviderStripe, secret, body, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Stripe-Signature", name)
+	assert.Equal(t, "t=1700000000,v1="+HMACSHA256(secret, []byte("1700000000.Hello, World!")), value)
+
+	_, _, err = SignatureHeader("gitlab", secret, body, now)
+	assert.Error(t, err)
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "github", Name("webhooks/github.ts"))
+}
@@ -8,6 +8,9 @@ import (
 
 const WebhookDirectoryName = "webhooks"
 
+// ExcludeEnvKey holds the comma separated webhooks the config runner must not register
+const ExcludeEnvKey = "WG_EXCLUDE_WEBHOOKS"
+
 func GetWebhooks(wunderGraphDir string) ([]string, error) {
 	webhooksDirectoryAbs := filepath.Join(wunderGraphDir, WebhookDirectoryName)
 	des, err := os.ReadDir(webhooksDirectoryAbs)
@@ -23,3 +26,31 @@ func GetWebhooks(wunderGraphDir string) ([]string, error) {
 	}
 	return webhookFilePaths, nil
 }
+
+// Exclude splits the paths returned by GetWebhooks into the webhooks to keep and the
+// excluded ones. Webhooks are excluded by their name, the filename without extension.
+func Exclude(paths []string, excluded []string) (kept []string, skipped []string) {
+	for _, path := range paths {
+		if contains(excluded, Name(path)) {
+			skipped = append(skipped, path)
+		} else {
+			kept = append(kept, path)
+		}
+	}
+	return kept, skipped
+}
+
+// Name returns the name of the webhook at a path returned by GetWebhooks, it is
+// part of the route of the webhook, /webhooks/<name>
+func Name(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}